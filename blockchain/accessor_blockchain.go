@@ -338,6 +338,16 @@ func (blockchain *BlockChain) GetBestStateBeaconFeatureStateDBByHeight(height ui
 	return statedb.NewWithPrefixTrie(rootHash, statedb.NewDatabaseAccessWarper(db))
 }
 
+// GetBestStateBeaconSlashStateDBByHeight returns the beacon slash state of the
+// beacon block at height
+func (blockchain *BlockChain) GetBestStateBeaconSlashStateDBByHeight(height uint64, db incdb.Database) (*statedb.StateDB, error) {
+	rootHash, err := blockchain.GetBeaconSlashRootHash(blockchain.GetBeaconBestState(), height)
+	if err != nil {
+		return nil, fmt.Errorf("Beacon Slash State DB not found, height %+v, error %+v", height, err)
+	}
+	return statedb.NewWithPrefixTrie(rootHash, statedb.NewDatabaseAccessWarper(db))
+}
+
 func (blockchain *BlockChain) GetBNBChainID() string {
 	return blockchain.GetConfig().ChainParams.BNBRelayingHeaderChainID
 }
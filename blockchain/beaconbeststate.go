@@ -31,6 +31,16 @@ type BeaconRootHash struct {
 	SlashStateDBRootHash     common.Hash
 }
 
+// Hash is the state root hash the next block header commits to
+func (bRH *BeaconRootHash) Hash() common.Hash {
+	return common.Keccak256(
+		bRH.ConsensusStateDBRootHash[:],
+		bRH.FeatureStateDBRootHash[:],
+		bRH.RewardStateDBRootHash[:],
+		bRH.SlashStateDBRootHash[:],
+	)
+}
+
 type BeaconBestState struct {
 	BestBlockHash                          common.Hash                                `json:"BestBlockHash"`         // The hash of the block.
 	PreviousBestBlockHash                  common.Hash                                `json:"PreviousBestBlockHash"` // The hash of the block. [remove]
@@ -71,6 +81,9 @@ type BeaconBestState struct {
 	ShardHandle         map[byte]bool            `json:"ShardHandle"` // lock sync.RWMutex
 	// Number of blocks produced by producers in epoch
 	NumOfBlocksByProducers map[string]uint64 `json:"NumOfBlocksByProducers"`
+	// Number of blocks validators voted for in epoch, out of the blocks whose voters are recorded
+	NumOfVotesByValidators map[string]uint64 `json:"NumOfVotesByValidators"`
+	NumOfVotedBlocks       uint64            `json:"NumOfVotedBlocks"`
 	BlockInterval          time.Duration
 	BlockMaxCreateTime     time.Duration
 	//================================ StateDB Method
@@ -563,6 +576,17 @@ func (beaconBestState *BeaconBestState) GetBlockTime() int64 {
 	return beaconBestState.BestBlock.Header.Timestamp
 }
 
+// GetStateRootHash returns the hash of the state roots of this view
+func (beaconBestState *BeaconBestState) GetStateRootHash() common.Hash {
+	rootHash := BeaconRootHash{
+		ConsensusStateDBRootHash: beaconBestState.ConsensusStateDBRootHash,
+		FeatureStateDBRootHash:   beaconBestState.FeatureStateDBRootHash,
+		RewardStateDBRootHash:    beaconBestState.RewardStateDBRootHash,
+		SlashStateDBRootHash:     beaconBestState.SlashStateDBRootHash,
+	}
+	return rootHash.Hash()
+}
+
 func (beaconBestState *BeaconBestState) GetAllCommitteeValidatorCandidate() (map[byte][]incognitokey.CommitteePublicKey, map[byte][]incognitokey.CommitteePublicKey, []incognitokey.CommitteePublicKey, []incognitokey.CommitteePublicKey, []incognitokey.CommitteePublicKey, []incognitokey.CommitteePublicKey, []incognitokey.CommitteePublicKey, []incognitokey.CommitteePublicKey, error) {
 	SC := make(map[byte][]incognitokey.CommitteePublicKey)
 	SPV := make(map[byte][]incognitokey.CommitteePublicKey)
@@ -724,6 +748,14 @@ func (blockchain *BlockChain) GetBeaconFeatureRootHash(beaconbestState *BeaconBe
 	return bRH.FeatureStateDBRootHash, nil
 }
 
+func (blockchain *BlockChain) GetBeaconSlashRootHash(beaconbestState *BeaconBestState, height uint64) (common.Hash, error) {
+	bRH, e := blockchain.GetBeaconRootsHash(beaconbestState.consensusStateDB.Copy(), height)
+	if e != nil {
+		return common.Hash{}, e
+	}
+	return bRH.SlashStateDBRootHash, nil
+}
+
 func (blockchain *BlockChain) GetBeaconRootsHash(stateDB *statedb.StateDB, height uint64) (*BeaconRootHash, error) {
 	h, e := blockchain.GetBeaconBlockHashByHeight(blockchain.BeaconChain.GetFinalView(), blockchain.BeaconChain.GetBestView(), height)
 	if e != nil {
@@ -45,6 +45,9 @@ type BeaconHeader struct {
 	//for version 2
 	Proposer    string `json:"Proposer"`
 	ProposeTime int64  `json:"ProposeTime"`
+
+	// hash of the state roots of the previous block, from BCHeightBreakPointStateRoot
+	PrevStateRootHash common.Hash `json:"PrevStateRootHash"`
 }
 
 type ShardState struct {
@@ -192,6 +195,9 @@ func (beaconHeader *BeaconHeader) toString() string {
 		res += beaconHeader.Proposer
 		res += fmt.Sprintf("%v", beaconHeader.ProposeTime)
 	}
+	if !beaconHeader.PrevStateRootHash.IsEqual(&common.Hash{}) {
+		res += beaconHeader.PrevStateRootHash.String()
+	}
 	return res
 }
 
@@ -0,0 +1,291 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// MaxStateSyncCommitteeSwaps is the largest number of beacon committee swaps
+// returned to a peer at once
+const MaxStateSyncCommitteeSwaps = 50
+
+// beaconCommitteeSwap is a finalized beacon block swapping the beacon
+// committee, with the committee and pending validators after it. The block
+// is signed by the committee before it and commits to the new committee in
+// its header and in its beacon swap confirm instruction.
+type beaconCommitteeSwap struct {
+	Block                  *BeaconBlock
+	BeaconCommittee        []string
+	BeaconPendingValidator []string
+}
+
+// StateSyncAnchor is a beacon committee verified by this node, from the
+// committee of its final beacon view then through the signed committee swaps
+// after it. Height is the height of the last block verified.
+type StateSyncAnchor struct {
+	Height    uint64
+	Committee []incognitokey.CommitteePublicKey
+
+	startHeight uint64
+}
+
+// committeeSwapIndex tracks the height up to which every beacon committee swap
+// is stored, swaps before it are indexed in background from the stored blocks
+type committeeSwapIndex struct {
+	lock   sync.Mutex
+	height uint64
+}
+
+// NewBeaconStateSyncAnchor returns the beacon committee of the final view of
+// this node
+func (blockchain *BlockChain) NewBeaconStateSyncAnchor() *StateSyncAnchor {
+	finalView := blockchain.BeaconChain.GetFinalViewState()
+	return &StateSyncAnchor{
+		Height:      finalView.BeaconHeight,
+		Committee:   finalView.GetBeaconCommittee(),
+		startHeight: finalView.BeaconHeight,
+	}
+}
+
+// VerifyBeaconCommitteeSwaps follows the beacon committee swaps returned by a
+// peer from anchor. Each swap must be signed by the committee before it, so a
+// swap left out by the peer makes the next one, or the synced block, fail.
+// The verified swaps are stored for the peers syncing from this node.
+func (blockchain *BlockChain) VerifyBeaconCommitteeSwaps(anchor *StateSyncAnchor, swapsData [][]byte) (*StateSyncAnchor, error) {
+	res := &StateSyncAnchor{Height: anchor.Height, Committee: anchor.Committee, startHeight: anchor.startHeight}
+	batch := blockchain.GetBeaconChainDatabase().NewBatch()
+	for _, data := range swapsData {
+		swap := &beaconCommitteeSwap{}
+		if err := json.Unmarshal(data, swap); err != nil {
+			return nil, err
+		}
+		if swap.Block == nil || swap.Block.GetHeight() <= res.Height {
+			return nil, fmt.Errorf("beacon committee swap is not after height %v", res.Height)
+		}
+		committee, err := blockchain.verifyBeaconCommitteeSwap(swap, res.Committee)
+		if err != nil {
+			return nil, err
+		}
+		if err := rawdbv2.StoreBeaconCommitteeSwap(batch, swap.Block.GetHeight(), data); err != nil {
+			return nil, err
+		}
+		res = &StateSyncAnchor{Height: swap.Block.GetHeight(), Committee: committee, startHeight: anchor.startHeight}
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// verifyBeaconCommitteeSwap checks a swap is signed by committee and returns
+// the committee after it
+func (blockchain *BlockChain) verifyBeaconCommitteeSwap(swap *beaconCommitteeSwap, committee []incognitokey.CommitteePublicKey) ([]incognitokey.CommitteePublicKey, error) {
+	block := swap.Block
+	if err := blockchain.BeaconChain.ValidateBlockSignatures(block, committee); err != nil {
+		return nil, err
+	}
+	inst, ok := getBeaconSwapConfirmInstruction(block)
+	if !ok {
+		return nil, fmt.Errorf("beacon block %v does not swap the beacon committee", block.GetHeight())
+	}
+	if err := checkBeaconSwapConfirmInstruction(inst, swap.BeaconCommittee); err != nil {
+		return nil, fmt.Errorf("beacon block %v: %v", block.GetHeight(), err)
+	}
+	validators := append(append([]string{}, swap.BeaconCommittee...), swap.BeaconPendingValidator...)
+	if hash, ok := verifyHashFromStringArray(validators, block.Header.BeaconCommitteeAndValidatorRoot); !ok {
+		return nil, fmt.Errorf("beacon block %v commits to committee root %v but got %v", block.GetHeight(), block.Header.BeaconCommitteeAndValidatorRoot, hash)
+	}
+	return incognitokey.CommitteeBase58KeyListToStruct(swap.BeaconCommittee)
+}
+
+func getBeaconSwapConfirmInstruction(block *BeaconBlock) ([]string, bool) {
+	for _, inst := range block.Body.Instructions {
+		if len(inst) == 5 && inst[0] == strconv.Itoa(metadata.BeaconSwapConfirmMeta) {
+			return inst, true
+		}
+	}
+	return nil, false
+}
+
+// checkBeaconSwapConfirmInstruction checks the bridge addresses of committee
+// are the ones of the beacon swap confirm instruction, in any order
+func checkBeaconSwapConfirmInstruction(inst []string, committee []string) error {
+	numVals, _, err := base58.Base58Check{}.Decode(inst[3])
+	if err != nil {
+		return err
+	}
+	if big.NewInt(0).SetBytes(numVals).Cmp(big.NewInt(int64(len(committee)))) != 0 {
+		return fmt.Errorf("swap confirm instruction has %v validators but got %v", big.NewInt(0).SetBytes(numVals), len(committee))
+	}
+	confirmed, _, err := base58.Base58Check{}.Decode(inst[4])
+	if err != nil {
+		return err
+	}
+	addrs, err := parseAndConcatPubkeys(committee)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sortBridgeAddresses(confirmed), sortBridgeAddresses(addrs)) {
+		return fmt.Errorf("committee does not match the swap confirm instruction")
+	}
+	return nil
+}
+
+func sortBridgeAddresses(addrs []byte) []byte {
+	list := [][]byte{}
+	for i := 0; i+20 <= len(addrs); i += 20 {
+		list = append(list, addrs[i:i+20])
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i], list[j]) < 0
+	})
+	return bytes.Join(list, nil)
+}
+
+// newBeaconCommitteeSwap reads the committee after block from the beacon
+// consensus state at consensusRoot
+func (blockchain *BlockChain) newBeaconCommitteeSwap(block *BeaconBlock, consensusRoot common.Hash) (*beaconCommitteeSwap, error) {
+	consensusStateDB, err := statedb.NewWithPrefixTrie(consensusRoot, statedb.NewDatabaseAccessWarper(blockchain.GetBeaconChainDatabase()))
+	if err != nil {
+		return nil, err
+	}
+	committee, err := incognitokey.CommitteeKeyListToString(statedb.GetBeaconCommittee(consensusStateDB))
+	if err != nil {
+		return nil, err
+	}
+	pendingValidator, err := incognitokey.CommitteeKeyListToString(statedb.GetBeaconSubstituteValidator(consensusStateDB))
+	if err != nil {
+		return nil, err
+	}
+	return &beaconCommitteeSwap{Block: block, BeaconCommittee: committee, BeaconPendingValidator: pendingValidator}, nil
+}
+
+func (blockchain *BlockChain) storeBeaconCommitteeSwap(db incdb.KeyValueWriter, block *BeaconBlock, consensusRoot common.Hash) error {
+	if _, ok := getBeaconSwapConfirmInstruction(block); !ok {
+		return nil
+	}
+	swap, err := blockchain.newBeaconCommitteeSwap(block, consensusRoot)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(swap)
+	if err != nil {
+		return err
+	}
+	return rawdbv2.StoreBeaconCommitteeSwap(db, block.GetHeight(), data)
+}
+
+// storeFinalizedBeaconCommitteeSwaps stores the swaps of the blocks finalized
+// from height fromHeight to toHeight, if every swap before them is stored.
+// The state of the block being stored is read at consensusRoot since its roots
+// are not written yet.
+func (blockchain *BlockChain) storeFinalizedBeaconCommitteeSwaps(batch incdb.Batch, fromHeight uint64, toHeight uint64, blocks []*BeaconBlock, blockHash common.Hash, consensusRoot common.Hash) error {
+	blockchain.committeeSwapIndex.lock.Lock()
+	defer blockchain.committeeSwapIndex.lock.Unlock()
+	if blockchain.committeeSwapIndex.height < fromHeight || toHeight <= blockchain.committeeSwapIndex.height {
+		return nil
+	}
+	for _, block := range blocks {
+		root := consensusRoot
+		if *block.Hash() != blockHash {
+			roots, err := GetBeaconRootsHashByBlockHash(blockchain.GetBeaconChainDatabase(), *block.Hash())
+			if err != nil {
+				return err
+			}
+			root = roots.ConsensusStateDBRootHash
+		}
+		if err := blockchain.storeBeaconCommitteeSwap(batch, block, root); err != nil {
+			return err
+		}
+	}
+	if err := rawdbv2.StoreBeaconCommitteeSwapIndexHeight(batch, toHeight); err != nil {
+		return err
+	}
+	blockchain.committeeSwapIndex.height = toHeight
+	return nil
+}
+
+// indexBeaconCommitteeSwaps stores the swaps of the blocks finalized before the
+// swap index existed, one epoch at a time since the beacon committee is only
+// swapped by the last block of an epoch. It stops at the first block whose
+// state can not be read, e.g. pruned.
+func (blockchain *BlockChain) indexBeaconCommitteeSwaps() {
+	db := blockchain.GetBeaconChainDatabase()
+	epoch := blockchain.config.ChainParams.Epoch
+	for {
+		select {
+		case <-blockchain.cQuitSync:
+			return
+		default:
+		}
+		done, err := func() (bool, error) {
+			blockchain.committeeSwapIndex.lock.Lock()
+			defer blockchain.committeeSwapIndex.lock.Unlock()
+			finalHeight := blockchain.BeaconChain.GetFinalView().GetHeight()
+			height := blockchain.committeeSwapIndex.height
+			if height >= finalHeight {
+				return true, nil
+			}
+			next := (height/epoch + 1) * epoch
+			if next > finalHeight {
+				next = finalHeight
+			} else {
+				block, err := blockchain.GetBeaconBlockByHeightV1(next)
+				if err != nil {
+					return false, err
+				}
+				roots, err := GetBeaconRootsHashByBlockHash(db, *block.Hash())
+				if err != nil {
+					return false, err
+				}
+				if err := blockchain.storeBeaconCommitteeSwap(db, block, roots.ConsensusStateDBRootHash); err != nil {
+					return false, err
+				}
+			}
+			if err := rawdbv2.StoreBeaconCommitteeSwapIndexHeight(db, next); err != nil {
+				return false, err
+			}
+			blockchain.committeeSwapIndex.height = next
+			return false, nil
+		}()
+		if err != nil {
+			Logger.log.Errorf("Index beacon committee swaps after height %v error %v", blockchain.committeeSwapIndex.height, err)
+			return
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// getBeaconCommitteeSwaps returns the stored swaps from height fromHeight to
+// toHeight, and whether more swaps are left
+func (blockchain *BlockChain) getBeaconCommitteeSwaps(fromHeight uint64, toHeight uint64) ([][]byte, bool, error) {
+	blockchain.committeeSwapIndex.lock.Lock()
+	indexHeight := blockchain.committeeSwapIndex.height
+	blockchain.committeeSwapIndex.lock.Unlock()
+	if indexHeight < toHeight {
+		return nil, false, fmt.Errorf("beacon committee swaps indexed up to height %v only", indexHeight)
+	}
+	swaps, err := rawdbv2.GetBeaconCommitteeSwaps(blockchain.GetBeaconChainDatabase(), fromHeight, toHeight, MaxStateSyncCommitteeSwaps+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(swaps) > MaxStateSyncCommitteeSwaps {
+		return swaps[:MaxStateSyncCommitteeSwaps], true, nil
+	}
+	return swaps, false, nil
+}
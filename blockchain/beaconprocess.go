@@ -171,6 +171,9 @@ func (blockchain *BlockChain) InsertBeaconBlock(beaconBlock *BeaconBlock, should
 	}
 	// updateNumOfBlocksByProducers updates number of blocks produced by producers
 	newBestState.updateNumOfBlocksByProducers(beaconBlock, blockchain.config.ChainParams.Epoch)
+	// update number of blocks validators voted for, the committee of the previous view signed the previous block
+	voters, isRecorded := blockchain.getParticipationVoters(beaconBlock.Body.Instructions, common.BeaconChainDataBaseID, &curView.BestBlock, curView.BeaconCommittee)
+	newBestState.updateNumOfVotesByValidators(beaconBlock, voters, isRecorded, blockchain.config.ChainParams.Epoch)
 
 	// newBeaconCommittee, newAllShardCommittee, err := snapshotCommittee(newBestState.BeaconCommittee, newBestState.ShardCommittee)
 	// if err != nil {
@@ -214,12 +217,16 @@ func (blockchain *BlockChain) InsertBeaconBlock(beaconBlock *BeaconBlock, should
 	// 		metrics.Time:             beaconBlock.Header.Timestamp,
 	// 	})
 	// }
+	if err := blockchain.indexBlockParticipation(blockchain.GetBeaconChainDatabase(), common.BeaconChainDataBaseID, beaconBlock, curView.BeaconCommittee); err != nil {
+		Logger.log.Errorf("BEACON | Index participation of block %+v error: %+v", beaconBlock.Header.Height, err)
+	}
+	blockchain.equivocationPool.removeIncluded(beaconBlock.Body.Instructions)
 	Logger.log.Infof("BEACON | Finish Insert new Beacon Block %+v, with hash %+v", beaconBlock.Header.Height, *beaconBlock.Hash())
 	if beaconBlock.Header.Height%50 == 0 {
 		BLogger.log.Debugf("Inserted beacon height: %d", beaconBlock.Header.Height)
 	}
-	go blockchain.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.NewBeaconBlockTopic, beaconBlock))
-	go blockchain.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.BeaconBeststateTopic, newBestState))
+	blockchain.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.NewBeaconBlockTopic, beaconBlock))
+	blockchain.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.BeaconBeststateTopic, newBestState))
 
 	// For masternode: broadcast new committee to highways
 	// if notifyHighway {
@@ -251,6 +258,23 @@ func (beaconBestState *BeaconBestState) updateNumOfBlocksByProducers(beaconBlock
 	}
 }
 
+// updateNumOfVotesByValidators updates number of blocks validators voted for
+// in epoch, voters are recorded by beaconBlock for the previous block if
+// isRecorded
+func (beaconBestState *BeaconBestState) updateNumOfVotesByValidators(beaconBlock *BeaconBlock, voters []string, isRecorded bool, chainParamEpoch uint64) {
+	if beaconBlock.GetHeight()%chainParamEpoch == 1 || beaconBestState.NumOfVotesByValidators == nil {
+		beaconBestState.NumOfVotesByValidators = make(map[string]uint64)
+		beaconBestState.NumOfVotedBlocks = 0
+	}
+	if !isRecorded {
+		return
+	}
+	beaconBestState.NumOfVotedBlocks++
+	for _, voter := range voters {
+		beaconBestState.NumOfVotesByValidators[voter]++
+	}
+}
+
 /*
 	VerifyPreProcessingBeaconBlock
 	This function DOES NOT verify new block with best state
@@ -366,6 +390,8 @@ func (blockchain *BlockChain) verifyPreProcessingBeaconBlockForSigning(curView *
 	bridgeInstructions := [][]string{}
 	acceptedBlockRewardInstructions := [][]string{}
 	stopAutoStakingInstructions := [][]string{}
+	equivocationInstructions := [][]string{}
+	equivocationEvidenceKeys := make(map[common.Hash]struct{})
 	statefulActionsByShardID := map[byte][][]string{}
 	rewardForCustodianByEpoch := map[common.Hash]uint64{}
 
@@ -439,6 +465,7 @@ func (blockchain *BlockChain) verifyPreProcessingBeaconBlockForSigning(curView *
 				acceptedBlockRewardInstructions = append(acceptedBlockRewardInstructions, acceptedBlockRewardInstruction)
 				stopAutoStakingInstructions = append(stopAutoStakingInstructions, stopAutoStakingInstruction...)
 				validStakePublicKeys = append(validStakePublicKeys, tempValidStakePublicKeys...)
+				equivocationInstructions = append(equivocationInstructions, blockchain.getEquivocationInstructionsFromShardBlock(curView, shardBlock, shardID, equivocationEvidenceKeys)...)
 				// group stateful actions by shardID
 				_, found := statefulActionsByShardID[shardID]
 				if !found {
@@ -467,6 +494,21 @@ func (blockchain *BlockChain) verifyPreProcessingBeaconBlockForSigning(curView *
 	if len(rewardByEpochInstruction) != 0 {
 		tempInstruction = append(tempInstruction, rewardByEpochInstruction...)
 	}
+	if beaconBlock.Header.Height >= blockchain.config.ChainParams.BCHeightBreakPointEquivocation {
+		tempInstruction = append(tempInstruction, equivocationInstructions...)
+		beaconEquivocationInstructions, err := blockchain.verifyEquivocationInstructions(curView.slashStateDB, beaconBlock.Body.Instructions, common.BeaconChainDataBaseID, curView.BeaconCommittee, beaconBlock.Header.Height)
+		if err != nil {
+			return err
+		}
+		tempInstruction = append(tempInstruction, beaconEquivocationInstructions...)
+	}
+	if beaconBlock.Header.Height >= blockchain.config.ChainParams.BCHeightBreakPointParticipation {
+		participationInstructions, err := blockchain.verifyParticipationInstructions(beaconBlock.Body.Instructions, common.BeaconChainDataBaseID, &curView.BestBlock, curView.BeaconCommittee)
+		if err != nil {
+			return err
+		}
+		tempInstruction = append(tempInstruction, participationInstructions...)
+	}
 	tempInstructionArr := []string{}
 	for _, strs := range tempInstruction {
 		tempInstructionArr = append(tempInstructionArr, strs...)
@@ -516,6 +558,9 @@ func (beaconBestState *BeaconBestState) verifyBestStateWithBeaconBlock(blockchai
 	if beaconBestState.BeaconHeight+1 != beaconBlock.Header.Height {
 		return NewBlockChainError(WrongBlockHeightError, errors.New("block height of new block should be :"+strconv.Itoa(int(beaconBlock.Header.Height+1))))
 	}
+	if err := blockchain.verifyPrevStateRootHash(beaconBlock.Header.Height, beaconBlock.Header.PrevStateRootHash, beaconBestState.GetStateRootHash()); err != nil {
+		return err
+	}
 	if beaconBlock.Header.Height%chainParamEpoch == 1 && beaconBestState.Epoch+1 != beaconBlock.Header.Epoch {
 		return NewBlockChainError(WrongEpochError, fmt.Errorf("Expect beacon block height %+v has epoch %+v but get %+v", beaconBlock.Header.Height, beaconBestState.Epoch+1, beaconBlock.Header.Epoch))
 	}
@@ -863,6 +908,7 @@ func (beaconBestState *BeaconBestState) initBeaconBestState(genesisBeaconBlock *
 	}
 	beaconBestState.Epoch = 1
 	beaconBestState.NumOfBlocksByProducers = make(map[string]uint64)
+	beaconBestState.NumOfVotesByValidators = make(map[string]uint64)
 	return nil
 }
 
@@ -1532,6 +1578,7 @@ func (blockchain *BlockChain) processStoreBeaconBlock(
 	newFinalView := blockchain.BeaconChain.multiView.GetFinalView()
 
 	storeBlock := newFinalView.GetBlock()
+	newFinalHeight := storeBlock.GetHeight()
 
 	finalizedBlocks := []*BeaconBlock{}
 	for finalView == nil || storeBlock.GetHeight() > finalView.GetHeight() {
@@ -1560,6 +1607,12 @@ func (blockchain *BlockChain) processStoreBeaconBlock(
 		Logger.log.Debug("process beacon block", finalizedBlocks[i].Header.Height)
 		processBeaconForConfirmmingCrossShard(blockchain, finalizedBlocks[i], newBestState.LastCrossShardState)
 	}
+	if finalView != nil {
+		err := blockchain.storeFinalizedBeaconCommitteeSwaps(batch, finalView.GetHeight(), newFinalHeight, finalizedBlocks, blockHash, consensusRootHash)
+		if err != nil {
+			return NewBlockChainError(StoreBeaconBlockError, err)
+		}
+	}
 
 	err = blockchain.BackupBeaconViews(batch)
 	if err != nil {
@@ -1570,6 +1623,7 @@ func (blockchain *BlockChain) processStoreBeaconBlock(
 		return NewBlockChainError(StoreBeaconBlockError, err)
 	}
 	beaconStoreBlockTimer.UpdateSince(startTimeProcessStoreBeaconBlock)
+	blockchain.startStatePruning(common.BeaconChainDataBaseID, blockchain.BeaconChain.GetFinalView().GetHeight())
 
 	if !blockchain.config.ChainParams.IsBackup {
 		return nil
@@ -75,6 +75,9 @@ func (blockchain *BlockChain) NewBlockBeacon(curView *BeaconBestState, version i
 	beaconBlock.Header.Epoch = epoch
 	beaconBlock.Header.Round = round
 	beaconBlock.Header.PreviousBlockHash = beaconBestState.BestBlockHash
+	if beaconBlock.Header.Height >= blockchain.config.ChainParams.BCHeightBreakPointStateRoot {
+		beaconBlock.Header.PrevStateRootHash = curView.GetStateRootHash()
+	}
 	BLogger.log.Infof("Producing block: %d (epoch %d)", beaconBlock.Header.Height, beaconBlock.Header.Epoch)
 	//=====END Build Header Essential Data=====
 	//============Build body===================
@@ -98,7 +101,7 @@ func (blockchain *BlockChain) NewBlockBeacon(curView *BeaconBestState, version i
 		}
 	}
 
-	tempShardState, stakeInstructions, swapInstructions, bridgeInstructions, acceptedRewardInstructions, stopAutoStakingInstructions := blockchain.GetShardState(beaconBestState, rewardForCustodianByEpoch, portalParams)
+	tempShardState, stakeInstructions, swapInstructions, bridgeInstructions, acceptedRewardInstructions, stopAutoStakingInstructions, equivocationInstructions := blockchain.GetShardState(beaconBestState, rewardForCustodianByEpoch, portalParams)
 
 	Logger.log.Infof("In NewBlockBeacon tempShardState: %+v", tempShardState)
 	tempInstruction, err := beaconBestState.GenerateInstruction(
@@ -112,6 +115,13 @@ func (blockchain *BlockChain) NewBlockBeacon(curView *BeaconBestState, version i
 	if len(rewardByEpochInstruction) != 0 {
 		tempInstruction = append(tempInstruction, rewardByEpochInstruction...)
 	}
+	if beaconBlock.Header.Height >= blockchain.config.ChainParams.BCHeightBreakPointEquivocation {
+		tempInstruction = append(tempInstruction, equivocationInstructions...)
+		tempInstruction = append(tempInstruction, blockchain.buildEquivocationInstructions(beaconBestState.slashStateDB, common.BeaconChainDataBaseID, beaconBestState.BeaconCommittee, beaconBlock.Header.Height)...)
+	}
+	if beaconBlock.Header.Height >= blockchain.config.ChainParams.BCHeightBreakPointParticipation {
+		tempInstruction = append(tempInstruction, blockchain.buildParticipationInstructions(common.BeaconChainDataBaseID, &beaconBestState.BestBlock, beaconBestState.BeaconCommittee)...)
+	}
 	beaconBlock.Body.Instructions = tempInstruction
 	beaconBlock.Body.ShardState = tempShardState
 	if len(beaconBlock.Body.Instructions) != 0 {
@@ -246,12 +256,15 @@ func (blockchain *BlockChain) NewBlockBeacon(curView *BeaconBestState, version i
 // 4. bridge instructions
 // 5. accepted reward instructions
 // 6. stop auto staking instructions
-func (blockchain *BlockChain) GetShardState(beaconBestState *BeaconBestState, rewardForCustodianByEpoch map[common.Hash]uint64, portalParams PortalParams) (map[byte][]ShardState, [][]string, map[byte][][]string, [][]string, [][]string, [][]string) {
+// 7. equivocation instructions
+func (blockchain *BlockChain) GetShardState(beaconBestState *BeaconBestState, rewardForCustodianByEpoch map[common.Hash]uint64, portalParams PortalParams) (map[byte][]ShardState, [][]string, map[byte][][]string, [][]string, [][]string, [][]string, [][]string) {
 	shardStates := make(map[byte][]ShardState)
 	validStakeInstructions := [][]string{}
 	validStakePublicKeys := []string{}
 	validStopAutoStakingInstructions := [][]string{}
 	validSwapInstructions := make(map[byte][][]string)
+	equivocationInstructions := [][]string{}
+	equivocationEvidenceKeys := make(map[common.Hash]struct{})
 	//Get shard to beacon block from pool
 	allShardBlocks := blockchain.GetShardBlockForBeaconProducer(beaconBestState.BestShardHeight)
 	keys := []int{}
@@ -277,6 +290,7 @@ func (blockchain *BlockChain) GetShardState(beaconBestState *BeaconBestState, re
 			acceptedRewardInstructions = append(acceptedRewardInstructions, acceptedRewardInstruction)
 			validStopAutoStakingInstructions = append(validStopAutoStakingInstructions, stopAutoStakingInstruction...)
 			validStakePublicKeys = append(validStakePublicKeys, tempValidStakePublicKeys...)
+			equivocationInstructions = append(equivocationInstructions, blockchain.getEquivocationInstructionsFromShardBlock(beaconBestState, shardBlock, shardID, equivocationEvidenceKeys)...)
 
 			// group stateful actions by shardID
 			_, found := statefulActionsByShardID[shardID]
@@ -291,7 +305,7 @@ func (blockchain *BlockChain) GetShardState(beaconBestState *BeaconBestState, re
 	// build stateful instructions
 	statefulInsts := blockchain.buildStatefulInstructions(beaconBestState, beaconBestState.featureStateDB, statefulActionsByShardID, beaconBestState.BeaconHeight+1, rewardForCustodianByEpoch, portalParams)
 	bridgeInstructions = append(bridgeInstructions, statefulInsts...)
-	return shardStates, validStakeInstructions, validSwapInstructions, bridgeInstructions, acceptedRewardInstructions, validStopAutoStakingInstructions
+	return shardStates, validStakeInstructions, validSwapInstructions, bridgeInstructions, acceptedRewardInstructions, validStopAutoStakingInstructions, equivocationInstructions
 }
 
 // GetShardStateFromBlock get state (information) from shard-to-beacon block
@@ -26,6 +26,9 @@ func (blockchain *BlockChain) collectStatefulActions(
 		if inst[0] == SetAction || inst[0] == StakeAction || inst[0] == SwapAction || inst[0] == RandomAction || inst[0] == AssignAction {
 			continue
 		}
+		if inst[0] == EquivocationAction || inst[0] == ParticipationAction {
+			continue
+		}
 
 		metaType, err := strconv.Atoi(inst[0])
 		if err != nil {
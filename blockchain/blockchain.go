@@ -35,7 +35,10 @@ type BlockChain struct {
 
 	IsTest bool
 
-	beaconViewCache *lru.Cache
+	beaconViewCache    *lru.Cache
+	statePruner        statePruner
+	equivocationPool   equivocationPool
+	committeeSwapIndex committeeSwapIndex
 }
 
 // Config is a descriptor which specifies the blockchain instance configuration.
@@ -61,6 +64,12 @@ type Config struct {
 	Server            Server
 	ConsensusEngine   ConsensusEngine
 	Highway           Highway
+	// PruneState is the number of finalized views whose state is kept, older
+	// states are pruned except epoch checkpoints. Zero disables pruning.
+	PruneState uint64
+	// StateSync downloads the state of a recent finalized view from peers when
+	// a chain starts from genesis, instead of processing every block
+	StateSync bool
 
 	relayShardLck sync.Mutex
 }
@@ -72,9 +81,6 @@ func NewBlockChain(config *Config, isTest bool) *BlockChain {
 	bc.IsTest = isTest
 	bc.beaconViewCache, _ = lru.New(100)
 	bc.cQuitSync = make(chan struct{})
-	bc.GetBeaconBestState().Params = make(map[string]string)
-	bc.GetBeaconBestState().ShardCommittee = make(map[byte][]incognitokey.CommitteePublicKey)
-	bc.GetBeaconBestState().ShardPendingValidator = make(map[byte][]incognitokey.CommitteePublicKey)
 	return bc
 }
 
@@ -100,6 +106,12 @@ func (blockchain *BlockChain) Init(config *Config) error {
 		return err
 	}
 	blockchain.cQuitSync = make(chan struct{})
+	indexHeight, err := rawdbv2.GetBeaconCommitteeSwapIndexHeight(blockchain.GetBeaconChainDatabase())
+	if err != nil {
+		return err
+	}
+	blockchain.committeeSwapIndex.height = indexHeight
+	go blockchain.indexBeaconCommitteeSwaps()
 	return nil
 }
 
@@ -147,6 +159,13 @@ func (blockchain *BlockChain) InitChainState() error {
 		Logger.log.Infof("Init Shard View shardID %+v, height %+v", shardID, blockchain.ShardChain[shardID].GetFinalViewHeight())
 	}
 
+	// publish reorgs once the views are restored
+	if blockchain.config.PubSubManager != nil {
+		blockchain.BeaconChain.multiView.SetPublisher(common.BeaconChainDataBaseID, blockchain.config.PubSubManager)
+		for shardID, shardChain := range blockchain.ShardChain {
+			shardChain.multiView.SetPublisher(shardID, blockchain.config.PubSubManager)
+		}
+	}
 	return nil
 }
 
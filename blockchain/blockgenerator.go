@@ -68,16 +68,19 @@ func (blockGenerator *BlockGenerator) RemoveTransactionV2Worker(cRemoveTx <-chan
 		time.Sleep(time.Nanosecond)
 	}
 }
+
+// GetPendingTxsV2 returns the pending transactions of a shard, of every shard
+// if shardID is 255, in the order of the tx selection policy of the mempool.
+// Only the transactions delivered to the block generator are returned.
 func (blockGenerator *BlockGenerator) GetPendingTxsV2(shardID byte) []metadata.Transaction {
-	blockGenerator.mtx.Lock()
-	defer blockGenerator.mtx.Unlock()
+	orderedTxs := blockGenerator.txPool.GetPendingTxs(shardID)
+	blockGenerator.mtx.RLock()
+	defer blockGenerator.mtx.RUnlock()
 	pendingTxs := []metadata.Transaction{}
-	for _, tx := range blockGenerator.PendingTxs {
-		txShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
-		if shardID != 255 && txShardID != shardID {
-			continue
+	for _, tx := range orderedTxs {
+		if _, ok := blockGenerator.PendingTxs[*tx.Hash()]; ok {
+			pendingTxs = append(pendingTxs, tx)
 		}
-		pendingTxs = append(pendingTxs, tx)
 	}
 	return pendingTxs
 }
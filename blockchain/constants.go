@@ -310,14 +310,27 @@ func ReadKey(v1, v2 []byte) {
 // -------------- FOR INSTRUCTION --------------
 // Action for instruction
 const (
-	SetAction     = "set"
-	SwapAction    = "swap"
-	RandomAction  = "random"
-	StakeAction   = "stake"
-	AssignAction  = "assign"
-	StopAutoStake = "stopautostake"
+	SetAction           = "set"
+	SwapAction          = "swap"
+	RandomAction        = "random"
+	StakeAction         = "stake"
+	AssignAction        = "assign"
+	StopAutoStake       = "stopautostake"
+	EquivocationAction  = "equivocation"
+	ParticipationAction = "participation"
 )
 
+// Reasons of slash levels, a level with no reason is of missed blocks
+const (
+	SlashMissedBlocks = "missedblocks" // percent of the blocks a producer did not produce in epoch
+	SlashMissedVotes  = "missedvotes"  // percent of the blocks a validator did not vote for in epoch
+	SlashEquivocation = "equivocation" // a validator signed two conflicting blocks, MinRange is not used
+)
+
+// SlashDowntime is the reason of the producers blacklisted by a swap
+// instruction, for missed blocks or votes
+const SlashDowntime = "downtime"
+
 var (
 	shardInsertBlockTimer                  = metrics.NewRegisteredTimer("shard/insert", nil)
 	shardVerifyPreprocesingTimer           = metrics.NewRegisteredTimer("shard/verify/preprocessing", nil)
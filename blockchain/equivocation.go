@@ -0,0 +1,215 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+// maxEquivocationEvidences is the number of evidences of a chain kept in pool,
+// the oldest are dropped first
+const maxEquivocationEvidences = 64
+
+// equivocationPool keeps the evidences of double signing found by the
+// consensus engine until a block carries them.
+// Equivocation instruction format:
+//
+//	["equivocation" chainID validator evidence], chainID is -1 for the beacon
+//	and validator is the committee public key in base58 of the member the
+//	evidence is against
+type equivocationPool struct {
+	mtx       sync.Mutex
+	evidences map[int][]string // chainID -> evidences
+}
+
+func (pool *equivocationPool) add(chainID int, evidence string) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	if pool.evidences == nil {
+		pool.evidences = make(map[int][]string)
+	}
+	if common.IndexOfStr(evidence, pool.evidences[chainID]) != -1 {
+		return
+	}
+	pool.evidences[chainID] = append(pool.evidences[chainID], evidence)
+	if len(pool.evidences[chainID]) > maxEquivocationEvidences {
+		pool.evidences[chainID] = pool.evidences[chainID][1:]
+	}
+}
+
+func (pool *equivocationPool) get(chainID int) []string {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	return append([]string{}, pool.evidences[chainID]...)
+}
+
+// remove drops the evidences of chainID which are in evidences
+func (pool *equivocationPool) remove(chainID int, evidences []string) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	remaining := []string{}
+	for _, evidence := range pool.evidences[chainID] {
+		if common.IndexOfStr(evidence, evidences) == -1 {
+			remaining = append(remaining, evidence)
+		}
+	}
+	pool.evidences[chainID] = remaining
+}
+
+// removeIncluded drops the evidences of the equivocation instructions of a
+// block inserted in chain
+func (pool *equivocationPool) removeIncluded(instructions [][]string) {
+	evidences := make(map[int][]string)
+	for _, inst := range instructions {
+		if len(inst) != 4 || inst[0] != EquivocationAction {
+			continue
+		}
+		chainID, err := strconv.Atoi(inst[1])
+		if err != nil {
+			continue
+		}
+		evidences[chainID] = append(evidences[chainID], inst[3])
+	}
+	for chainID, chainEvidences := range evidences {
+		pool.remove(chainID, chainEvidences)
+	}
+}
+
+// AddEquivocationEvidence adds an evidence found by the consensus engine of
+// the shard, the next blocks the node produces carry it to the beacon
+func (chain *ShardChain) AddEquivocationEvidence(evidence string) {
+	chain.Blockchain.equivocationPool.add(chain.shardID, evidence)
+}
+
+// AddEquivocationEvidence adds an evidence found by the consensus engine of
+// the beacon, the next blocks the node produces carry it
+func (chain *BeaconChain) AddEquivocationEvidence(evidence string) {
+	chain.Blockchain.equivocationPool.add(common.BeaconChainDataBaseID, evidence)
+}
+
+// NewBlockFromHeader returns a block of chainID, -1 for the beacon, with the
+// header of an equivocation evidence and an empty body. Its hash is the hash
+// of the block the header was taken from.
+func NewBlockFromHeader(chainID int, header []byte) (common.BlockInterface, error) {
+	if chainID == common.BeaconChainDataBaseID {
+		block := NewBeaconBlock()
+		if err := json.Unmarshal(header, &block.Header); err != nil {
+			return nil, err
+		}
+		return block, nil
+	}
+	block := NewShardBlock()
+	if err := json.Unmarshal(header, &block.Header); err != nil {
+		return nil, err
+	}
+	if int(block.Header.ShardID) != chainID {
+		return nil, fmt.Errorf("header of shard %v, expect %v", block.Header.ShardID, chainID)
+	}
+	return block, nil
+}
+
+// buildEquivocationInstructions returns the equivocation instructions of the
+// evidences in pool which a block of chainID at height can carry against
+// members of committee, the others are dropped from pool
+func (blockchain *BlockChain) buildEquivocationInstructions(slashStateDB *statedb.StateDB, chainID int, committee []incognitokey.CommitteePublicKey, height uint64) [][]string {
+	instructions := [][]string{}
+	invalidEvidences := []string{}
+	for _, evidence := range blockchain.equivocationPool.get(chainID) {
+		validator, _, err := blockchain.validateEquivocationEvidence(slashStateDB, evidence, chainID, committee, height)
+		if err != nil {
+			Logger.log.Errorf("Drop equivocation evidence of chain %v: %+v", chainID, err)
+			invalidEvidences = append(invalidEvidences, evidence)
+			continue
+		}
+		instructions = append(instructions, []string{EquivocationAction, strconv.Itoa(chainID), validator, evidence})
+	}
+	blockchain.equivocationPool.remove(chainID, invalidEvidences)
+	return instructions
+}
+
+// verifyEquivocationInstructions checks the equivocation instructions of
+// chainID in instructions, of a block at height, against committee and
+// returns them
+func (blockchain *BlockChain) verifyEquivocationInstructions(slashStateDB *statedb.StateDB, instructions [][]string, chainID int, committee []incognitokey.CommitteePublicKey, height uint64) ([][]string, error) {
+	equivocationInstructions := [][]string{}
+	evidenceKeys := make(map[common.Hash]struct{})
+	for _, inst := range instructions {
+		if len(inst) == 0 || inst[0] != EquivocationAction {
+			continue
+		}
+		if len(inst) != 4 || inst[1] != strconv.Itoa(chainID) {
+			continue
+		}
+		validator, evidenceKey, err := blockchain.validateEquivocationEvidence(slashStateDB, inst[3], chainID, committee, height)
+		if err != nil {
+			return nil, NewBlockChainError(EquivocationInstructionError, err)
+		}
+		if _, ok := evidenceKeys[evidenceKey]; ok {
+			return nil, NewBlockChainError(EquivocationInstructionError, errors.New("evidence is carried twice"))
+		}
+		evidenceKeys[evidenceKey] = struct{}{}
+		if validator != inst[2] {
+			return nil, NewBlockChainError(EquivocationInstructionError, fmt.Errorf("evidence is against %v, not %v", validator, inst[2]))
+		}
+		equivocationInstructions = append(equivocationInstructions, inst)
+	}
+	return equivocationInstructions, nil
+}
+
+// getEquivocationInstructionsFromShardBlock returns the equivocation
+// instructions of shardBlock whose evidence is valid against the committee of
+// the shard in curView, the beacon carries them. evidenceKeys are the keys of
+// the evidences the previous shard blocks of the beacon block carry, the
+// repeats are dropped.
+func (blockchain *BlockChain) getEquivocationInstructionsFromShardBlock(curView *BeaconBestState, shardBlock *ShardBlock, shardID byte, evidenceKeys map[common.Hash]struct{}) [][]string {
+	instructions := [][]string{}
+	for _, inst := range shardBlock.Body.Instructions {
+		if len(inst) != 4 || inst[0] != EquivocationAction || inst[1] != strconv.Itoa(int(shardID)) {
+			continue
+		}
+		validator, evidenceKey, err := blockchain.validateEquivocationEvidence(curView.slashStateDB, inst[3], int(shardID), curView.ShardCommittee[shardID], shardBlock.GetHeight())
+		if err != nil || validator != inst[2] {
+			Logger.log.Errorf("Drop equivocation instruction of shard %v block %v: %+v", shardID, shardBlock.GetHeight(), err)
+			continue
+		}
+		if _, ok := evidenceKeys[evidenceKey]; ok {
+			continue
+		}
+		evidenceKeys[evidenceKey] = struct{}{}
+		instructions = append(instructions, inst)
+	}
+	return instructions
+}
+
+// validateEquivocationEvidence checks that evidence, carried by a block of
+// chainID at height, proves a member of committee signed two conflicting
+// blocks, that it is not older than an epoch of blocks, when the committee
+// may have been swapped, and that no beacon block slashed for its
+// equivocation yet. It returns the member and the key of the equivocation.
+func (blockchain *BlockChain) validateEquivocationEvidence(slashStateDB *statedb.StateDB, evidence string, chainID int, committee []incognitokey.CommitteePublicKey, height uint64) (string, common.Hash, error) {
+	validator, evidenceHeight, err := blockchain.config.ConsensusEngine.ValidateEquivocationEvidence(evidence, chainID, committee)
+	if err != nil {
+		return "", common.Hash{}, err
+	}
+	if evidenceHeight > height || evidenceHeight+blockchain.config.ChainParams.Epoch < height {
+		return "", common.Hash{}, fmt.Errorf("evidence of height %v can not be carried by a block of height %v", evidenceHeight, height)
+	}
+	evidenceKey, err := blockchain.config.ConsensusEngine.GetEquivocationEvidenceKey(evidence)
+	if err != nil {
+		return "", common.Hash{}, err
+	}
+	isUsed, err := statedb.IsEquivocationEvidenceUsed(slashStateDB, evidenceKey)
+	if err != nil {
+		return "", common.Hash{}, err
+	}
+	if isUsed {
+		return "", common.Hash{}, errors.New("equivocation is already slashed for")
+	}
+	return validator, evidenceKey, nil
+}
@@ -0,0 +1,205 @@
+package blockchain
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+// equivocationTestEngine takes the evidences as "validator/height", or as
+// "validator/height/encoding" for another evidence of the same equivocation,
+// an evidence is valid if its validator is not empty
+type equivocationTestEngine struct {
+	stateSyncTestEngine
+}
+
+func (engine *equivocationTestEngine) ValidateEquivocationEvidence(evidence string, chainID int, committee []incognitokey.CommitteePublicKey) (string, uint64, error) {
+	parts := strings.Split(evidence, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return "", 0, errors.New("invalid evidence")
+	}
+	height, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, err
+	}
+	return parts[0], height, nil
+}
+
+func (engine *equivocationTestEngine) GetEquivocationEvidenceKey(evidence string) (common.Hash, error) {
+	parts := strings.Split(evidence, "/")
+	if len(parts) < 2 {
+		return common.Hash{}, errors.New("invalid evidence")
+	}
+	return common.HashH([]byte(parts[0] + "/" + parts[1])), nil
+}
+
+func newEquivocationTestChain(t *testing.T) (*BlockChain, *statedb.StateDB) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_equivocation_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diskDB, err := incdb.Open("leveldb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slashStateDB, err := statedb.NewWithPrefixTrie(common.EmptyRoot, statedb.NewDatabaseAccessWarper(diskDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc := &BlockChain{config: Config{
+		ConsensusEngine: &equivocationTestEngine{},
+		ChainParams: &Params{
+			Epoch:       100,
+			SlashLevels: []SlashLevel{{PunishedEpoches: 10, Reason: SlashEquivocation}},
+		},
+	}}
+	return bc, slashStateDB
+}
+
+func TestBlockChain_validateEquivocationEvidence(t *testing.T) {
+	bc, slashStateDB := newEquivocationTestChain(t)
+	if err := statedb.StoreEquivocationEvidences(slashStateDB, 1, []common.Hash{common.HashH([]byte("v2/150"))}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		evidence string
+		height   uint64
+		want     string
+		wantErr  bool
+	}{
+		{name: "valid", evidence: "v1/150", height: 200, want: "v1"},
+		{name: "evidence of the carrying block height", evidence: "v1/200", height: 200, want: "v1"},
+		{name: "evidence of an epoch ago", evidence: "v1/100", height: 200, want: "v1"},
+		{name: "evidence older than an epoch", evidence: "v1/99", height: 200, wantErr: true},
+		{name: "evidence above the carrying block", evidence: "v1/201", height: 200, wantErr: true},
+		{name: "evidence already slashed for", evidence: "v2/150", height: 200, wantErr: true},
+		{name: "other evidence of an equivocation slashed for", evidence: "v2/150/other", height: 200, wantErr: true},
+		{name: "invalid evidence", evidence: "/150", height: 200, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := bc.validateEquivocationEvidence(slashStateDB, tt.evidence, 0, nil, tt.height)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateEquivocationEvidence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("validateEquivocationEvidence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockChain_verifyEquivocationInstructions(t *testing.T) {
+	bc, slashStateDB := newEquivocationTestChain(t)
+	tests := []struct {
+		name         string
+		instructions [][]string
+		want         int
+		wantErr      bool
+	}{
+		{
+			name: "instructions of the chain",
+			instructions: [][]string{
+				{EquivocationAction, "0", "v1", "v1/150"},
+				{EquivocationAction, "1", "v2", "v2/150"},
+				{SwapAction},
+			},
+			want: 1,
+		},
+		{
+			name: "evidence carried twice",
+			instructions: [][]string{
+				{EquivocationAction, "0", "v1", "v1/150"},
+				{EquivocationAction, "0", "v1", "v1/150"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "two evidences of an equivocation",
+			instructions: [][]string{
+				{EquivocationAction, "0", "v1", "v1/150"},
+				{EquivocationAction, "0", "v1", "v1/150/other"},
+			},
+			wantErr: true,
+		},
+		{
+			name:         "evidence against another validator",
+			instructions: [][]string{{EquivocationAction, "0", "v2", "v1/150"}},
+			wantErr:      true,
+		},
+		{
+			name:         "evidence too old",
+			instructions: [][]string{{EquivocationAction, "0", "v1", "v1/50"}},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bc.verifyEquivocationInstructions(slashStateDB, tt.instructions, 0, nil, 200)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyEquivocationInstructions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("verifyEquivocationInstructions() = %v, want %v instructions", got, tt.want)
+			}
+		})
+	}
+}
+
+// an equivocation slashes its validator once, the repeats of its evidence or
+// its other evidences in the same block or in the next ones are ignored
+func TestBlockChain_processForSlashingEquivocationOnce(t *testing.T) {
+	bc, slashStateDB := newEquivocationTestChain(t)
+	beaconBlock := NewBeaconBlock()
+	beaconBlock.Header.Height = 5
+	beaconBlock.Body.Instructions = [][]string{
+		{EquivocationAction, "0", "v1", "v1/150"},
+		{EquivocationAction, "0", "v1", "v1/150/other"},
+	}
+	if err := bc.processForSlashing(slashStateDB, beaconBlock); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := slashStateDB.Commit(true); err != nil {
+		t.Fatal(err)
+	}
+	if isUsed, err := statedb.IsEquivocationEvidenceUsed(slashStateDB, common.HashH([]byte("v1/150"))); err != nil || !isUsed {
+		t.Fatalf("evidence is not stored as used: %v", err)
+	}
+	if producersBlackList := statedb.GetProducersBlackList(slashStateDB, 5); producersBlackList["v1"] != 10 {
+		t.Fatalf("v1 is punished %v epoches, want 10", producersBlackList["v1"])
+	}
+	// v1 serves a part of its punishment, then the evidence comes again
+	if err := statedb.StoreProducersBlackList(slashStateDB, 5, map[string]uint8{"v1": 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := slashStateDB.Commit(true); err != nil {
+		t.Fatal(err)
+	}
+	beaconBlock.Header.Height = 6
+	beaconBlock.Body.Instructions = [][]string{{EquivocationAction, "0", "v1", "v1/150/other"}}
+	if err := bc.processForSlashing(slashStateDB, beaconBlock); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := slashStateDB.Commit(true); err != nil {
+		t.Fatal(err)
+	}
+	if producersBlackList := statedb.GetProducersBlackList(slashStateDB, 6); producersBlackList["v1"] != 3 {
+		t.Errorf("v1 is punished %v epoches, want 3", producersBlackList["v1"])
+	}
+	if _, _, err := bc.validateEquivocationEvidence(slashStateDB, "v1/150", 0, nil, 200); err == nil {
+		t.Error("an evidence slashed for is valid")
+	}
+	if _, _, err := bc.validateEquivocationEvidence(slashStateDB, "v1/150/other", 0, nil, 200); err == nil {
+		t.Error("another evidence of an equivocation slashed for is valid")
+	}
+}
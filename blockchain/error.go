@@ -178,6 +178,9 @@ const (
 	GetShardBlockHeightByHashError
 	GetShardBlockByHashError
 	ResponsedTransactionFromBeaconInstructionsError
+	EquivocationInstructionError
+	ParticipationInstructionError
+	StateRootHashError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -346,6 +349,9 @@ var ErrCodeMessage = map[int]struct {
 	GetListOutputCoinsByKeysetError:                   {-2000, "Get List Output Coins By Keyset Error"},
 	GetTotalLockedCollateralError:                     {-3000, "Get Total Locked Collateral Error"},
 	ResponsedTransactionFromBeaconInstructionsError:   {-3100, "Build Transaction Response From Beacon Instructions Error"},
+	EquivocationInstructionError:                      {-3200, "Equivocation Instruction Error"},
+	ParticipationInstructionError:                     {-3201, "Participation Instruction Error"},
+	StateRootHashError:                                {-3300, "State Root Hash Error"},
 }
 
 type BlockChainError struct {
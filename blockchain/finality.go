@@ -0,0 +1,55 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+// BlockFinality is the position of a block relative to the best and the final
+// view of its chain
+type BlockFinality struct {
+	// IsFinalized is true if the block is on the final chain, it can not be
+	// reverted anymore
+	IsFinalized bool
+	// Confirmations is the number of blocks of the best chain from the block,
+	// the block included, 0 if the block is not on the best chain
+	Confirmations uint64
+	// FinalHeight is the height of the final view of the chain
+	FinalHeight uint64
+}
+
+// GetBlockFinality returns the finality of the block of a chain, -1 for beacon,
+// with the given hash and height
+func (blockchain *BlockChain) GetBlockFinality(chainID int, hash common.Hash, height uint64) (*BlockFinality, error) {
+	var finalView, bestView multiview.View
+	if chainID == common.BeaconChainDataBaseID {
+		finalView, bestView = blockchain.BeaconChain.GetFinalView(), blockchain.BeaconChain.GetBestView()
+	} else {
+		if chainID < 0 || chainID >= len(blockchain.ShardChain) {
+			return nil, fmt.Errorf("shard %v not exist", chainID)
+		}
+		finalView, bestView = blockchain.ShardChain[chainID].GetFinalView(), blockchain.ShardChain[chainID].GetBestView()
+	}
+	finality := &BlockFinality{FinalHeight: finalView.GetHeight()}
+	if height > bestView.GetHeight() {
+		return finality, nil
+	}
+	var bestHash *common.Hash
+	var err error
+	if chainID == common.BeaconChainDataBaseID {
+		bestHash, err = blockchain.GetBeaconBlockHashByHeight(finalView, bestView, height)
+	} else {
+		bestHash, err = blockchain.GetShardBlockHashByHeight(finalView, bestView, height)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if *bestHash != hash {
+		return finality, nil
+	}
+	finality.Confirmations = bestView.GetHeight() - height + 1
+	finality.IsFinalized = height <= finalView.GetHeight()
+	return finality, nil
+}
@@ -27,6 +27,9 @@ type TxPool interface {
 	EmptyPool() bool
 	MaybeAcceptTransactionForBlockProducing(metadata.Transaction, int64, *ShardBestState) (*metadata.TxDesc, error)
 	MaybeAcceptBatchTransactionForBlockProducing(byte, []metadata.Transaction, int64, *ShardBestState) ([]*metadata.TxDesc, error)
+	// GetPendingTxs returns the txs of a shard, of every shard if shardID is 255,
+	// in the order of the tx selection policy
+	GetPendingTxs(shardID byte) []metadata.Transaction
 	//CheckTransactionFee
 	// CheckTransactionFee(tx metadata.Transaction) (uint64, error)
 	// Check tx validate by it self
@@ -41,6 +44,10 @@ type ConsensusEngine interface {
 	ValidateProducerPosition(blk common.BlockInterface, lastProposerIdx int, committee []incognitokey.CommitteePublicKey, minCommitteeSize int) error
 	ValidateProducerSig(block common.BlockInterface, consensusType string) error
 	ValidateBlockCommitteSig(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) error
+	ValidateEquivocationEvidence(evidence string, chainID int, committee []incognitokey.CommitteePublicKey) (string, uint64, error)
+	GetEquivocationEvidenceKey(evidence string) (common.Hash, error)
+	ExtractBridgeValidationData(block common.BlockInterface) ([][]byte, []int, error)
+	GetNodeMiningPublicKeys() []*incognitokey.CommitteePublicKey
 	// GetCurrentMiningPublicKey() (string, string)
 	// GetCurrentValidators() []*consensus.Validator
 	// GetOneValidatorForEachConsensusProcess() map[int]*consensus.Validator
@@ -9,6 +9,7 @@ import (
 type SlashLevel struct {
 	MinRange        uint8
 	PunishedEpoches uint8
+	Reason          string // SlashMissedBlocks if empty, SlashMissedVotes or SlashEquivocation
 }
 type PortalCollateral struct {
 	ExternalTokenID string
@@ -87,6 +88,9 @@ type Params struct {
 	BCHeightBreakPointNewZKP         uint64
 	PortalETHContractAddressStr      string // smart contract of ETH for portal
 	BCHeightBreakPointPortalV3       uint64
+	BCHeightBreakPointEquivocation   uint64 // blocks carry equivocation instructions from this beacon height
+	BCHeightBreakPointParticipation  uint64 // blocks carry participation instructions from this beacon height
+	BCHeightBreakPointStateRoot      uint64 // block headers commit the state roots of their parent from this beacon height
 }
 
 type GenesisParams struct {
@@ -222,6 +226,9 @@ func SetupParam() {
 			//SlashLevel{MinRange: 20, PunishedEpoches: 1},
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
+			//SlashLevel{MinRange: 50, PunishedEpoches: 1, Reason: SlashMissedVotes},
+			//SlashLevel{MinRange: 90, PunishedEpoches: 3, Reason: SlashMissedVotes},
+			//SlashLevel{PunishedEpoches: 10, Reason: SlashEquivocation},
 		},
 		CheckForce:                     false,
 		ChainVersion:                   "version-chain-test.json",
@@ -254,16 +261,19 @@ func SetupParam() {
 				MinUnlockOverRateCollaterals:         25,
 			},
 		},
-		PortalTokens:                initPortalTokensForTestNet(),
-		EpochBreakPointSwapNewKey:   TestnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:      1,
-		IsBackup:                    false,
-		PreloadAddress:              "",
-		BCHeightBreakPointNewZKP:    2300000, //TODO: change this value when deployed testnet
-		ETHRemoveBridgeSigEpoch:     21920,
+		PortalTokens:              initPortalTokensForTestNet(),
+		EpochBreakPointSwapNewKey: TestnetReplaceCommitteeEpoch,
+		ReplaceStakingTxHeight:    1,
+		IsBackup:                  false,
+		PreloadAddress:            "",
+		BCHeightBreakPointNewZKP:  2300000, //TODO: change this value when deployed testnet
+		ETHRemoveBridgeSigEpoch:   21920,
 
-		PortalETHContractAddressStr: "0x6D53de7aFa363F779B5e125876319695dC97171E", // todo: update sc address
-		BCHeightBreakPointPortalV3:  30158,
+		PortalETHContractAddressStr:     "0x6D53de7aFa363F779B5e125876319695dC97171E", // todo: update sc address
+		BCHeightBreakPointPortalV3:      30158,
+		BCHeightBreakPointEquivocation:  2300000, //TODO: change this value when deployed testnet
+		BCHeightBreakPointParticipation: 2300000, //TODO: change this value when deployed testnet
+		BCHeightBreakPointStateRoot:     2300000, //TODO: change this value when deployed testnet
 	}
 	// END TESTNET
 
@@ -314,6 +324,9 @@ func SetupParam() {
 			//SlashLevel{MinRange: 20, PunishedEpoches: 1},
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
+			//SlashLevel{MinRange: 50, PunishedEpoches: 1, Reason: SlashMissedVotes},
+			//SlashLevel{MinRange: 90, PunishedEpoches: 3, Reason: SlashMissedVotes},
+			//SlashLevel{PunishedEpoches: 10, Reason: SlashEquivocation},
 		},
 		CheckForce:                     false,
 		ChainVersion:                   "version-chain-test-2.json",
@@ -345,15 +358,18 @@ func SetupParam() {
 				MinPortalFee:                         100,
 			},
 		},
-		PortalTokens:                initPortalTokensForTestNet(),
-		EpochBreakPointSwapNewKey:   TestnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:      1,
-		IsBackup:                    false,
-		PreloadAddress:              "",
-		BCHeightBreakPointNewZKP:    1148608, //TODO: change this value when deployed testnet2
-		ETHRemoveBridgeSigEpoch:     2085,
-		PortalETHContractAddressStr: "0xF7befD2806afD96D3aF76471cbCa1cD874AA1F46",   // todo: update sc address
-		BCHeightBreakPointPortalV3:  1328816,
+		PortalTokens:                    initPortalTokensForTestNet(),
+		EpochBreakPointSwapNewKey:       TestnetReplaceCommitteeEpoch,
+		ReplaceStakingTxHeight:          1,
+		IsBackup:                        false,
+		PreloadAddress:                  "",
+		BCHeightBreakPointNewZKP:        1148608, //TODO: change this value when deployed testnet2
+		ETHRemoveBridgeSigEpoch:         2085,
+		PortalETHContractAddressStr:     "0xF7befD2806afD96D3aF76471cbCa1cD874AA1F46", // todo: update sc address
+		BCHeightBreakPointPortalV3:      1328816,
+		BCHeightBreakPointEquivocation:  1400000, //TODO: change this value when deployed testnet2
+		BCHeightBreakPointParticipation: 1400000, //TODO: change this value when deployed testnet2
+		BCHeightBreakPointStateRoot:     1400000, //TODO: change this value when deployed testnet2
 	}
 	// END TESTNET-2
 
@@ -402,6 +418,9 @@ func SetupParam() {
 			//SlashLevel{MinRange: 20, PunishedEpoches: 1},
 			//SlashLevel{MinRange: 50, PunishedEpoches: 2},
 			//SlashLevel{MinRange: 75, PunishedEpoches: 3},
+			//SlashLevel{MinRange: 50, PunishedEpoches: 1, Reason: SlashMissedVotes},
+			//SlashLevel{MinRange: 90, PunishedEpoches: 3, Reason: SlashMissedVotes},
+			//SlashLevel{PunishedEpoches: 10, Reason: SlashEquivocation},
 		},
 		CheckForce:                     false,
 		ChainVersion:                   "version-chain-main.json",
@@ -433,15 +452,18 @@ func SetupParam() {
 				MinPortalFee:                         100,
 			},
 		},
-		PortalTokens:                initPortalTokensForMainNet(),
-		EpochBreakPointSwapNewKey:   MainnetReplaceCommitteeEpoch,
-		ReplaceStakingTxHeight:      559380,
-		IsBackup:                    false,
-		PreloadAddress:              "",
-		BCHeightBreakPointNewZKP:    934858,
-		ETHRemoveBridgeSigEpoch:     1973,
-		PortalETHContractAddressStr: "", // todo: update sc address
-		BCHeightBreakPointPortalV3:  40, // todo: should update before deploying
+		PortalTokens:                    initPortalTokensForMainNet(),
+		EpochBreakPointSwapNewKey:       MainnetReplaceCommitteeEpoch,
+		ReplaceStakingTxHeight:          559380,
+		IsBackup:                        false,
+		PreloadAddress:                  "",
+		BCHeightBreakPointNewZKP:        934858,
+		ETHRemoveBridgeSigEpoch:         1973,
+		PortalETHContractAddressStr:     "",      // todo: update sc address
+		BCHeightBreakPointPortalV3:      40,      // todo: should update before deploying
+		BCHeightBreakPointEquivocation:  1500000, // todo: should update before deploying
+		BCHeightBreakPointParticipation: 1500000, // todo: should update before deploying
+		BCHeightBreakPointStateRoot:     1500000, // todo: should update before deploying
 	}
 	if IsTestNet {
 		if !IsTestNet2 {
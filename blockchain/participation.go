@@ -0,0 +1,130 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+// The validation data of a block is not part of its hash and every committer
+// combines the votes it received, so nodes may store different voters for the
+// same block. The voters a chain counts are the ones a block records for its
+// previous block, all the nodes count them the same way.
+// Participation instruction format:
+//
+//	["participation" chainID height blockHash validationData], chainID is -1
+//	for the beacon, height and blockHash are of the previous block and
+//	validationData is the one of it the producer has
+//
+// The validation data is checked against the committee of the block, so the
+// previous block is not recorded if another committee signed it.
+//
+// Any validation data with the votes of 2/3 of the committee is valid, so the
+// producer chooses which subset of the voters is recorded: it may leave out
+// the votes of up to 1/3 of the committee, and the ones it left out count as
+// missed. The slash levels of SlashMissedVotes are meant to catch the members
+// missing most of the votes of an epoch, which one producer alone can not
+// cause.
+
+// withValidationData returns a copy of block with validationData
+func withValidationData(block common.BlockInterface, validationData string) (common.BlockInterface, error) {
+	switch blk := block.(type) {
+	case *ShardBlock:
+		newBlock := *blk
+		newBlock.ValidationData = validationData
+		return &newBlock, nil
+	case *BeaconBlock:
+		newBlock := *blk
+		newBlock.ValidationData = validationData
+		return &newBlock, nil
+	}
+	return nil, fmt.Errorf("unknown block type %T", block)
+}
+
+// buildParticipationInstructions returns the participation instruction of
+// prevBlock of chainID if committee signed it
+func (blockchain *BlockChain) buildParticipationInstructions(chainID int, prevBlock common.BlockInterface, committee []incognitokey.CommitteePublicKey) [][]string {
+	if prevBlock == nil || len(prevBlock.GetValidationField()) == 0 {
+		return [][]string{}
+	}
+	if err := blockchain.config.ConsensusEngine.ValidateBlockCommitteSig(prevBlock, committee); err != nil {
+		Logger.log.Debugf("Chain %v block %v is not recorded for participation: %+v", chainID, prevBlock.GetHeight(), err)
+		return [][]string{}
+	}
+	return [][]string{{
+		ParticipationAction,
+		strconv.Itoa(chainID),
+		strconv.FormatUint(prevBlock.GetHeight(), 10),
+		prevBlock.Hash().String(),
+		prevBlock.GetValidationField(),
+	}}
+}
+
+// verifyParticipationInstructions checks the participation instruction of
+// chainID in instructions, if any, against prevBlock and committee and returns
+// it
+func (blockchain *BlockChain) verifyParticipationInstructions(instructions [][]string, chainID int, prevBlock common.BlockInterface, committee []incognitokey.CommitteePublicKey) ([][]string, error) {
+	participationInstructions := [][]string{}
+	for _, inst := range instructions {
+		if len(inst) == 0 || inst[0] != ParticipationAction {
+			continue
+		}
+		if len(inst) != 5 || inst[1] != strconv.Itoa(chainID) {
+			continue
+		}
+		if len(participationInstructions) != 0 {
+			return nil, NewBlockChainError(ParticipationInstructionError, errors.New("more than one participation instruction"))
+		}
+		if inst[2] != strconv.FormatUint(prevBlock.GetHeight(), 10) || inst[3] != prevBlock.Hash().String() {
+			return nil, NewBlockChainError(ParticipationInstructionError, fmt.Errorf("participation of block %v %v, expect %v %v", inst[2], inst[3], prevBlock.GetHeight(), prevBlock.Hash().String()))
+		}
+		block, err := withValidationData(prevBlock, inst[4])
+		if err != nil {
+			return nil, NewBlockChainError(ParticipationInstructionError, err)
+		}
+		if err := blockchain.config.ConsensusEngine.ValidateBlockCommitteSig(block, committee); err != nil {
+			return nil, NewBlockChainError(ParticipationInstructionError, err)
+		}
+		participationInstructions = append(participationInstructions, inst)
+	}
+	return participationInstructions, nil
+}
+
+// getParticipationVoters returns the members of committee which voted for
+// prevBlock according to the participation instruction of chainID in
+// instructions, and whether there is one
+func (blockchain *BlockChain) getParticipationVoters(instructions [][]string, chainID int, prevBlock common.BlockInterface, committee []incognitokey.CommitteePublicKey) ([]string, bool) {
+	for _, inst := range instructions {
+		if len(inst) != 5 || inst[0] != ParticipationAction || inst[1] != strconv.Itoa(chainID) {
+			continue
+		}
+		if inst[2] != strconv.FormatUint(prevBlock.GetHeight(), 10) || inst[3] != prevBlock.Hash().String() {
+			return nil, false
+		}
+		block, err := withValidationData(prevBlock, inst[4])
+		if err != nil {
+			return nil, false
+		}
+		_, validatorsIdx, err := blockchain.config.ConsensusEngine.ExtractBridgeValidationData(block)
+		if err != nil {
+			Logger.log.Errorf("Chain %v can not extract voters of block %v: %+v", chainID, inst[2], err)
+			return nil, false
+		}
+		voters := []string{}
+		for _, idx := range validatorsIdx {
+			if idx < 0 || idx >= len(committee) {
+				continue
+			}
+			voter, err := committee[idx].ToBase58()
+			if err != nil {
+				continue
+			}
+			voters = append(voters, voter)
+		}
+		return voters, true
+	}
+	return nil, false
+}
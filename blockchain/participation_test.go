@@ -0,0 +1,242 @@
+package blockchain
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+// participationTestEngine takes the validation data of the blocks as the
+// comma separated indexes of their voters, "bad" is not signed by the
+// committee. nodeKeys are the mining keys of the node.
+type participationTestEngine struct {
+	stateSyncTestEngine
+	nodeKeys []*incognitokey.CommitteePublicKey
+}
+
+func (engine *participationTestEngine) GetNodeMiningPublicKeys() []*incognitokey.CommitteePublicKey {
+	return engine.nodeKeys
+}
+
+func (engine *participationTestEngine) ValidateBlockCommitteSig(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	if block.GetValidationField() == "" || block.GetValidationField() == "bad" {
+		return errors.New("not signed by committee")
+	}
+	return nil
+}
+
+func (engine *participationTestEngine) ExtractBridgeValidationData(block common.BlockInterface) ([][]byte, []int, error) {
+	validatorsIdx := []int{}
+	for _, idxStr := range strings.Split(block.GetValidationField(), ",") {
+		idx, err := strconv.Atoi(idxStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		validatorsIdx = append(validatorsIdx, idx)
+	}
+	return nil, validatorsIdx, nil
+}
+
+func newParticipationTestBlock(height uint64, validationData string) *ShardBlock {
+	block := NewShardBlock()
+	block.Header.Height = height
+	block.ValidationData = validationData
+	return block
+}
+
+func newParticipationInstruction(chainID int, block common.BlockInterface, validationData string) []string {
+	return []string{ParticipationAction, strconv.Itoa(chainID), strconv.FormatUint(block.GetHeight(), 10), block.Hash().String(), validationData}
+}
+
+func TestBlockChain_buildParticipationInstructions(t *testing.T) {
+	bc := &BlockChain{config: Config{ConsensusEngine: &participationTestEngine{}}}
+	committee := newStateSyncTestCommittee(1, 2, 3)
+	prevBlock := newParticipationTestBlock(9, "0,2")
+	want := [][]string{newParticipationInstruction(0, prevBlock, "0,2")}
+	if got := bc.buildParticipationInstructions(0, prevBlock, committee); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildParticipationInstructions() = %v, want %v", got, want)
+	}
+	// the previous block is signed by another committee
+	if got := bc.buildParticipationInstructions(0, newParticipationTestBlock(9, "bad"), committee); len(got) != 0 {
+		t.Errorf("buildParticipationInstructions() = %v, want none", got)
+	}
+}
+
+func TestBlockChain_verifyParticipationInstructions(t *testing.T) {
+	bc := &BlockChain{config: Config{ConsensusEngine: &participationTestEngine{}}}
+	committee := newStateSyncTestCommittee(1, 2, 3)
+	prevBlock := newParticipationTestBlock(9, "0,1,2")
+	otherBlock := newParticipationTestBlock(8, "0,1,2")
+	tests := []struct {
+		name         string
+		instructions [][]string
+		want         int
+		wantErr      bool
+	}{
+		{name: "no participation", instructions: [][]string{{SwapAction}}, want: 0},
+		{
+			// the producer records the subset of the votes it chose
+			name:         "subset of the votes",
+			instructions: [][]string{newParticipationInstruction(0, prevBlock, "0,2")},
+			want:         1,
+		},
+		{
+			name:         "participation of another chain",
+			instructions: [][]string{newParticipationInstruction(1, prevBlock, "bad")},
+			want:         0,
+		},
+		{
+			name:         "participation of another block",
+			instructions: [][]string{newParticipationInstruction(0, otherBlock, "0,2")},
+			wantErr:      true,
+		},
+		{
+			name:         "validation data not signed by committee",
+			instructions: [][]string{newParticipationInstruction(0, prevBlock, "bad")},
+			wantErr:      true,
+		},
+		{
+			name: "two participation instructions",
+			instructions: [][]string{
+				newParticipationInstruction(0, prevBlock, "0,2"),
+				newParticipationInstruction(0, prevBlock, "0,1"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bc.verifyParticipationInstructions(tt.instructions, 0, prevBlock, committee)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyParticipationInstructions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("verifyParticipationInstructions() = %v, want %v instructions", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockChain_getParticipationVoters(t *testing.T) {
+	bc := &BlockChain{config: Config{ConsensusEngine: &participationTestEngine{}}}
+	committee := newStateSyncTestCommittee(1, 2, 3)
+	members := []string{}
+	for _, member := range committee {
+		key, err := member.ToBase58()
+		if err != nil {
+			t.Fatal(err)
+		}
+		members = append(members, key)
+	}
+	prevBlock := newParticipationTestBlock(9, "0,1,2")
+	tests := []struct {
+		name           string
+		instructions   [][]string
+		wantVoters     []string
+		wantIsRecorded bool
+	}{
+		{name: "no participation", instructions: [][]string{{SwapAction}}},
+		{
+			name:           "voters of the block",
+			instructions:   [][]string{newParticipationInstruction(0, prevBlock, "0,2")},
+			wantVoters:     []string{members[0], members[2]},
+			wantIsRecorded: true,
+		},
+		{
+			name:           "indexes out of the committee are ignored",
+			instructions:   [][]string{newParticipationInstruction(0, prevBlock, "1,3,-1")},
+			wantVoters:     []string{members[1]},
+			wantIsRecorded: true,
+		},
+		{
+			name:         "participation of another block",
+			instructions: [][]string{newParticipationInstruction(0, newParticipationTestBlock(8, "0"), "0")},
+		},
+		{
+			name:         "participation of another chain",
+			instructions: [][]string{newParticipationInstruction(1, prevBlock, "0")},
+		},
+		{
+			name:         "invalid validation data",
+			instructions: [][]string{newParticipationInstruction(0, prevBlock, "x")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVoters, gotIsRecorded := bc.getParticipationVoters(tt.instructions, 0, prevBlock, committee)
+			if gotIsRecorded != tt.wantIsRecorded {
+				t.Fatalf("getParticipationVoters() isRecorded = %v, want %v", gotIsRecorded, tt.wantIsRecorded)
+			}
+			if !reflect.DeepEqual(gotVoters, tt.wantVoters) {
+				t.Errorf("getParticipationVoters() = %v, want %v", gotVoters, tt.wantVoters)
+			}
+		})
+	}
+}
+
+var participationTestSlashLevels = []SlashLevel{
+	{MinRange: 20, PunishedEpoches: 1},
+	{MinRange: 50, PunishedEpoches: 2},
+	{MinRange: 50, PunishedEpoches: 1, Reason: SlashMissedVotes},
+	{MinRange: 75, PunishedEpoches: 3, Reason: SlashMissedVotes},
+	{PunishedEpoches: 10, Reason: SlashEquivocation},
+}
+
+func TestBlockChain_getPunishedEpoches(t *testing.T) {
+	bc := &BlockChain{config: Config{ChainParams: &Params{SlashLevels: participationTestSlashLevels}}}
+	tests := []struct {
+		name           string
+		reason         string
+		missingPercent uint8
+		want           uint8
+	}{
+		{name: "under the lowest level", reason: SlashMissedBlocks, missingPercent: 19, want: 0},
+		{name: "levels without reason are of missed blocks", reason: SlashMissedBlocks, missingPercent: 20, want: 1},
+		{name: "highest level reached", reason: SlashMissedBlocks, missingPercent: 100, want: 2},
+		{name: "levels of another reason are ignored", reason: SlashMissedVotes, missingPercent: 20, want: 0},
+		{name: "missed votes", reason: SlashMissedVotes, missingPercent: 74, want: 1},
+		{name: "highest level of missed votes", reason: SlashMissedVotes, missingPercent: 75, want: 3},
+		{name: "equivocation", reason: SlashEquivocation, missingPercent: 100, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bc.getPunishedEpoches(tt.reason, tt.missingPercent); got != tt.want {
+				t.Errorf("getPunishedEpoches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockChain_buildBadProducersWithPunishment(t *testing.T) {
+	bc := &BlockChain{config: Config{ChainParams: &Params{SlashLevels: participationTestSlashLevels}}}
+	view := NewBeaconBestState()
+	view.BestBlock = *NewBeaconBlock()
+	// 12 blocks in epoch, 3 expected of each producer
+	view.NumOfBlocksByProducers = map[string]uint64{"a": 4, "b": 4, "c": 1, "d": 3}
+	view.NumOfVotedBlocks = 10
+	view.NumOfVotesByValidators = map[string]uint64{"a": 10, "b": 2, "c": 10, "d": 9}
+	multiView := multiview.NewMultiView()
+	if !multiView.AddView(view) {
+		t.Fatal("add view failed")
+	}
+	bc.BeaconChain = NewBeaconChain(multiView, nil, bc, common.BeaconChainKey)
+
+	// c missed 66% of its blocks, b 80% of the votes and d 10% of the votes
+	want := map[string]uint8{"b": 3, "c": 2}
+	if got := bc.buildBadProducersWithPunishment(true, -1, []string{"a", "b", "c", "d"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildBadProducersWithPunishment() = %v, want %v", got, want)
+	}
+	if got := bc.buildBadProducersWithPunishment(true, -1, []string{}); len(got) != 0 {
+		t.Errorf("buildBadProducersWithPunishment() of no committee = %v, want none", got)
+	}
+	bc.config.ChainParams.SlashLevels = nil
+	if got := bc.buildBadProducersWithPunishment(true, -1, []string{"a", "b", "c", "d"}); len(got) != 0 {
+		t.Errorf("buildBadProducersWithPunishment() without slash levels = %v, want none", got)
+	}
+}
@@ -0,0 +1,262 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/trie"
+)
+
+// MinPruneStateViews is the smallest number of finalized views whose state can
+// be kept with pruning enabled
+const MinPruneStateViews = 10
+
+// StatePruningStatus reports the state pruning of one chain, the beacon chain
+// uses common.BeaconChainDataBaseID as ChainID
+type StatePruningStatus struct {
+	ChainID            int
+	IsRunning          bool
+	LastPruneTime      int64
+	LastFinalHeight    uint64
+	RetainedRoots      int
+	MarkedNodes        int
+	PrunedNodes        uint64
+	ReclaimedSize      uint64
+	TotalPrunedNodes   uint64
+	TotalReclaimedSize uint64
+	LastError          string
+}
+
+type statePruner struct {
+	lock   sync.Mutex
+	status map[int]*StatePruningStatus
+}
+
+// GetPruneState returns the number of finalized views whose state is kept, zero
+// when pruning is disabled
+func (blockchain *BlockChain) GetPruneState() uint64 {
+	return blockchain.config.PruneState
+}
+
+// GetStatePruningStatus returns the pruning status of every chain already
+// pruned at least once, or currently being pruned
+func (blockchain *BlockChain) GetStatePruningStatus() []StatePruningStatus {
+	blockchain.statePruner.lock.Lock()
+	defer blockchain.statePruner.lock.Unlock()
+	res := []StatePruningStatus{}
+	if status, ok := blockchain.statePruner.status[common.BeaconChainDataBaseID]; ok {
+		res = append(res, *status)
+	}
+	for shardID := 0; shardID < common.MaxShardNumber; shardID++ {
+		if status, ok := blockchain.statePruner.status[shardID]; ok {
+			res = append(res, *status)
+		}
+	}
+	return res
+}
+
+// startStatePruning starts pruning the state of a chain in background once its
+// final view moved at least one epoch since the last pruning. Nothing is done
+// if pruning is disabled or already running for this chain.
+func (blockchain *BlockChain) startStatePruning(chainID int, finalHeight uint64) {
+	keep := blockchain.config.PruneState
+	// leveldb closes the database to back it up, pruning would fail meanwhile
+	if keep == 0 || blockchain.config.ChainParams.IsBackup || finalHeight <= keep {
+		return
+	}
+	blockchain.statePruner.lock.Lock()
+	defer blockchain.statePruner.lock.Unlock()
+	if blockchain.statePruner.status == nil {
+		blockchain.statePruner.status = make(map[int]*StatePruningStatus)
+	}
+	status, ok := blockchain.statePruner.status[chainID]
+	if !ok {
+		status = &StatePruningStatus{ChainID: chainID}
+		blockchain.statePruner.status[chainID] = status
+	}
+	if status.IsRunning || (status.LastFinalHeight != 0 && finalHeight < status.LastFinalHeight+blockchain.config.ChainParams.Epoch) {
+		return
+	}
+	status.IsRunning = true
+	go blockchain.pruneState(chainID, finalHeight)
+}
+
+func (blockchain *BlockChain) pruneState(chainID int, finalHeight uint64) {
+	start := time.Now()
+	roots, marked, nodes, size, err := blockchain.pruneChainState(chainID, finalHeight)
+
+	blockchain.statePruner.lock.Lock()
+	defer blockchain.statePruner.lock.Unlock()
+	status := blockchain.statePruner.status[chainID]
+	status.IsRunning = false
+	status.LastPruneTime = time.Now().Unix()
+	status.RetainedRoots = roots
+	status.MarkedNodes = marked
+	status.PrunedNodes = nodes
+	status.ReclaimedSize = uint64(size)
+	status.TotalPrunedNodes += nodes
+	status.TotalReclaimedSize += uint64(size)
+	if err != nil {
+		status.LastError = err.Error()
+		Logger.log.Errorf("Prune state of chain %v at final height %v failed, %v", chainID, finalHeight, err)
+		return
+	}
+	status.LastError = ""
+	status.LastFinalHeight = finalHeight
+	Logger.log.Infof("Pruned state of chain %v at final height %v, kept %v roots, deleted %v nodes (%v) in %v",
+		chainID, finalHeight, roots, nodes, size, time.Since(start))
+}
+
+// pruneChainState marks the tries of every root to keep then sweeps the other
+// trie nodes of the chain database. New blocks are only blocked while a batch
+// of nodes is deleted, the roots of the views inserted meanwhile are marked
+// before each batch.
+func (blockchain *BlockChain) pruneChainState(chainID int, finalHeight uint64) (int, int, uint64, common.StorageSize, error) {
+	var (
+		db         incdb.Database
+		insertLock *sync.Mutex
+		roots      []common.Hash
+		viewRoots  func() []common.Hash
+		err        error
+	)
+	if chainID == common.BeaconChainDataBaseID {
+		db = blockchain.GetBeaconChainDatabase()
+		insertLock = &blockchain.BeaconChain.insertLock
+		viewRoots = blockchain.beaconViewStateRoots
+		roots, err = blockchain.beaconRetainedStateRoots(finalHeight)
+	} else {
+		shardID := byte(chainID)
+		db = blockchain.GetShardChainDatabase(shardID)
+		insertLock = &blockchain.ShardChain[shardID].insertLock
+		viewRoots = func() []common.Hash { return blockchain.shardViewStateRoots(shardID) }
+		roots, err = blockchain.shardRetainedStateRoots(shardID, finalHeight)
+	}
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	pruner := trie.NewPruner(db)
+	for _, root := range append(roots, viewRoots()...) {
+		if err := pruner.Mark(root); err != nil {
+			return len(roots), pruner.Marked(), 0, 0, fmt.Errorf("mark root %v: %v", root.String(), err)
+		}
+	}
+	nodes, size, err := pruner.Sweep(func() error {
+		insertLock.Lock()
+		for _, root := range viewRoots() {
+			if err := pruner.Mark(root); err != nil {
+				insertLock.Unlock()
+				return fmt.Errorf("mark view root %v: %v", root.String(), err)
+			}
+		}
+		return nil
+	}, insertLock.Unlock)
+	return len(roots), pruner.Marked(), nodes, size, err
+}
+
+// retainedStateHeights returns the finalized heights whose state is kept. The
+// last PruneState finalized heights, and every height from keepFrom on, must
+// be found. The epoch checkpoints and the genesis height before them are kept
+// if they are stored, a node started from a backup may not have them.
+func (blockchain *BlockChain) retainedStateHeights(finalHeight uint64, keepFrom uint64) (checkpoints []uint64, from uint64) {
+	from = uint64(1)
+	if finalHeight > blockchain.config.PruneState {
+		from = finalHeight - blockchain.config.PruneState + 1
+	}
+	if keepFrom > 0 && keepFrom < from {
+		from = keepFrom
+	}
+	if from > 1 {
+		checkpoints = append(checkpoints, 1)
+	}
+	epoch := blockchain.config.ChainParams.Epoch
+	for height := epoch; height < from; height += epoch {
+		checkpoints = append(checkpoints, height)
+	}
+	return checkpoints, from
+}
+
+// retainedStateRoots returns the state roots of the retained heights, rootsAt
+// returns the roots of the finalized block at a height
+func (blockchain *BlockChain) retainedStateRoots(finalHeight uint64, keepFrom uint64, rootsAt func(height uint64) ([]common.Hash, error)) ([]common.Hash, error) {
+	checkpoints, from := blockchain.retainedStateHeights(finalHeight, keepFrom)
+	roots := []common.Hash{}
+	for _, height := range checkpoints {
+		if r, err := rootsAt(height); err == nil {
+			roots = append(roots, r...)
+		}
+	}
+	for height := from; height <= finalHeight; height++ {
+		r, err := rootsAt(height)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, r...)
+	}
+	return roots, nil
+}
+
+func (blockchain *BlockChain) beaconRetainedStateRoots(finalHeight uint64) ([]common.Hash, error) {
+	// shard blocks are processed against the beacon state at their beacon
+	// height, keep every beacon state the shards synced by this node may need
+	keepFrom := uint64(0)
+	for _, shardID := range blockchain.GetShardIDs() {
+		view := blockchain.ShardChain[shardID].GetFinalView().(*ShardBestState)
+		if view.ShardHeight > 1 && (keepFrom == 0 || view.BeaconHeight < keepFrom) {
+			keepFrom = view.BeaconHeight
+		}
+	}
+	db := blockchain.GetBeaconChainDatabase()
+	return blockchain.retainedStateRoots(finalHeight, keepFrom, func(height uint64) ([]common.Hash, error) {
+		hash, err := rawdbv2.GetFinalizedBeaconBlockHashByIndex(db, height)
+		if err != nil {
+			return nil, fmt.Errorf("beacon block hash at height %v: %v", height, err)
+		}
+		rootHash, err := GetBeaconRootsHashByBlockHash(db, *hash)
+		if err != nil {
+			return nil, fmt.Errorf("beacon root hash at height %v: %v", height, err)
+		}
+		return []common.Hash{rootHash.ConsensusStateDBRootHash, rootHash.FeatureStateDBRootHash,
+			rootHash.RewardStateDBRootHash, rootHash.SlashStateDBRootHash}, nil
+	})
+}
+
+func (blockchain *BlockChain) shardRetainedStateRoots(shardID byte, finalHeight uint64) ([]common.Hash, error) {
+	db := blockchain.GetShardChainDatabase(shardID)
+	return blockchain.retainedStateRoots(finalHeight, 0, func(height uint64) ([]common.Hash, error) {
+		hash, err := rawdbv2.GetFinalizedShardBlockHashByIndex(db, shardID, height)
+		if err != nil {
+			return nil, fmt.Errorf("shard %v block hash at height %v: %v", shardID, height, err)
+		}
+		rootHash, err := GetShardRootsHashByBlockHash(db, shardID, *hash)
+		if err != nil {
+			return nil, fmt.Errorf("shard %v root hash at height %v: %v", shardID, height, err)
+		}
+		return []common.Hash{rootHash.ConsensusStateDBRootHash, rootHash.TransactionStateDBRootHash,
+			rootHash.FeatureStateDBRootHash, rootHash.RewardStateDBRootHash, rootHash.SlashStateDBRootHash}, nil
+	})
+}
+
+func (blockchain *BlockChain) beaconViewStateRoots() []common.Hash {
+	roots := []common.Hash{}
+	for _, v := range blockchain.BeaconChain.multiView.GetAllViewsWithBFS() {
+		view := v.(*BeaconBestState)
+		roots = append(roots, view.ConsensusStateDBRootHash, view.FeatureStateDBRootHash,
+			view.RewardStateDBRootHash, view.SlashStateDBRootHash)
+	}
+	return roots
+}
+
+func (blockchain *BlockChain) shardViewStateRoots(shardID byte) []common.Hash {
+	roots := []common.Hash{}
+	for _, v := range blockchain.ShardChain[shardID].multiView.GetAllViewsWithBFS() {
+		view := v.(*ShardBestState)
+		roots = append(roots, view.ConsensusStateDBRootHash, view.TransactionStateDBRootHash,
+			view.FeatureStateDBRootHash, view.RewardStateDBRootHash, view.SlashStateDBRootHash)
+	}
+	return roots
+}
@@ -33,6 +33,17 @@ type ShardRootHash struct {
 	SlashStateDBRootHash       common.Hash
 }
 
+// Hash is the state root hash the next block header commits to
+func (sRH *ShardRootHash) Hash() common.Hash {
+	return common.Keccak256(
+		sRH.ConsensusStateDBRootHash[:],
+		sRH.TransactionStateDBRootHash[:],
+		sRH.FeatureStateDBRootHash[:],
+		sRH.RewardStateDBRootHash[:],
+		sRH.SlashStateDBRootHash[:],
+	)
+}
+
 type ShardBestState struct {
 	BestBlockHash          common.Hash                       `json:"BestBlockHash"` // hash of block.
 	BestBlock              *ShardBlock                       `json:"-"`             // block data
@@ -55,6 +66,9 @@ type ShardBestState struct {
 	ConsensusAlgorithm     string                            `json:"ConsensusAlgorithm"`
 	// Number of blocks produced by producers in epoch
 	NumOfBlocksByProducers map[string]uint64 `json:"NumOfBlocksByProducers"`
+	// Number of blocks validators voted for in epoch, out of the blocks whose voters are recorded
+	NumOfVotesByValidators map[string]uint64 `json:"NumOfVotesByValidators"`
+	NumOfVotedBlocks       uint64            `json:"NumOfVotedBlocks"`
 	BlockInterval          time.Duration
 	BlockMaxCreateTime     time.Duration
 	MetricBlockHeight      uint64
@@ -108,6 +122,18 @@ func (shardBestState *ShardBestState) GetBlockTime() int64 {
 	return shardBestState.BestBlock.Header.Timestamp
 }
 
+// GetStateRootHash returns the hash of the state roots of this view
+func (shardBestState *ShardBestState) GetStateRootHash() common.Hash {
+	rootHash := ShardRootHash{
+		ConsensusStateDBRootHash:   shardBestState.ConsensusStateDBRootHash,
+		TransactionStateDBRootHash: shardBestState.TransactionStateDBRootHash,
+		FeatureStateDBRootHash:     shardBestState.FeatureStateDBRootHash,
+		RewardStateDBRootHash:      shardBestState.RewardStateDBRootHash,
+		SlashStateDBRootHash:       shardBestState.SlashStateDBRootHash,
+	}
+	return rootHash.Hash()
+}
+
 // var bestStateShardMap = make(map[byte]*ShardBestState)
 
 func NewShardBestState() *ShardBestState {
@@ -46,6 +46,9 @@ type ShardHeader struct {
 	//for version 2
 	Proposer    string
 	ProposeTime int64
+
+	// hash of the state roots of the previous block, from BCHeightBreakPointStateRoot
+	PrevStateRootHash common.Hash `json:"PrevStateRootHash"`
 }
 
 type ShardBody struct {
@@ -341,6 +344,9 @@ func (shardHeader *ShardHeader) String() string {
 		res += shardHeader.Proposer
 		res += fmt.Sprintf("%v", shardHeader.ProposeTime)
 	}
+	if !shardHeader.PrevStateRootHash.IsEqual(&common.Hash{}) {
+		res += shardHeader.PrevStateRootHash.String()
+	}
 	return res
 }
 
@@ -170,6 +170,9 @@ func (blockchain *BlockChain) InsertShardBlock(shardBlock *ShardBlock, shouldVal
 	Logger.log.Infof("SHARD %+v | Update NumOfBlocksByProducers, block height %+v with hash %+v \n", shardID, blockHeight, blockHash)
 	// update number of blocks produced by producers to shard best state
 	newBestState.updateNumOfBlocksByProducers(shardBlock)
+	// update number of blocks validators voted for, the committee of the previous view signed the previous block
+	voters, isRecorded := blockchain.getParticipationVoters(shardBlock.Body.Instructions, int(shardID), curView.BestBlock, curView.ShardCommittee)
+	newBestState.updateNumOfVotesByValidators(shardBlock, voters, isRecorded)
 
 	//========Post verification: verify new beaconstate with corresponding block
 	if shouldValidate {
@@ -195,23 +198,20 @@ func (blockchain *BlockChain) InsertShardBlock(shardBlock *ShardBlock, shouldVal
 		return err
 	}
 	blockchain.removeOldDataAfterProcessingShardBlock(shardBlock, shardID)
-	go blockchain.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.NewShardblockTopic, shardBlock))
-	go blockchain.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.ShardBeststateTopic, newBestState))
+	if err := blockchain.indexBlockParticipation(blockchain.GetShardChainDatabase(shardID), int(shardID), shardBlock, curView.ShardCommittee); err != nil {
+		Logger.log.Errorf("SHARD %+v | Index participation of block %+v error: %+v", shardID, blockHeight, err)
+	}
+	blockchain.equivocationPool.removeIncluded(shardBlock.Body.Instructions)
+	blockchain.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.NewShardblockTopic, shardBlock))
+	blockchain.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.ShardBeststateTopic, newBestState))
 	Logger.log.Infof("SHARD %+v | Finish Insert new block %d, with hash %+v 🔗", shardBlock.Header.ShardID, shardBlock.Header.Height, blockHash)
 	return nil
 }
 
 // updateNumOfBlocksByProducers updates number of blocks produced by producers to shard best state
 func (shardBestState *ShardBestState) updateNumOfBlocksByProducers(shardBlock *ShardBlock) {
-	isSwapInstContained := false
-	for _, inst := range shardBlock.Body.Instructions {
-		if len(inst) > 0 && inst[0] == SwapAction {
-			isSwapInstContained = true
-			break
-		}
-	}
 	producer := shardBlock.GetProducerPubKeyStr()
-	if isSwapInstContained {
+	if isSwapInstContained(shardBlock) {
 		// reset number of blocks produced by producers
 		shardBestState.NumOfBlocksByProducers = map[string]uint64{
 			producer: 1,
@@ -227,6 +227,33 @@ func (shardBestState *ShardBestState) updateNumOfBlocksByProducers(shardBlock *S
 	}
 }
 
+// updateNumOfVotesByValidators updates number of blocks validators voted for
+// in epoch to shard best state, voters are recorded by shardBlock for the
+// previous block if isRecorded
+func (shardBestState *ShardBestState) updateNumOfVotesByValidators(shardBlock *ShardBlock, voters []string, isRecorded bool) {
+	if isSwapInstContained(shardBlock) || shardBestState.NumOfVotesByValidators == nil {
+		// reset number of blocks voted for by validators
+		shardBestState.NumOfVotesByValidators = make(map[string]uint64)
+		shardBestState.NumOfVotedBlocks = 0
+	}
+	if !isRecorded {
+		return
+	}
+	shardBestState.NumOfVotedBlocks++
+	for _, voter := range voters {
+		shardBestState.NumOfVotesByValidators[voter]++
+	}
+}
+
+func isSwapInstContained(shardBlock *ShardBlock) bool {
+	for _, inst := range shardBlock.Body.Instructions {
+		if len(inst) > 0 && inst[0] == SwapAction {
+			return true
+		}
+	}
+	return false
+}
+
 // verifyPreProcessingShardBlock DOES NOT verify new block with best state
 // DO NOT USE THIS with GENESIS BLOCK
 // Verification condition:
@@ -473,6 +500,26 @@ func (blockchain *BlockChain) verifyPreProcessingShardBlockForSigning(curView *S
 	if err != nil {
 		return NewBlockChainError(GenerateInstructionError, err)
 	}
+	if beaconHeight >= blockchain.config.ChainParams.BCHeightBreakPointEquivocation {
+		// the used evidences are those of the beacon block of the shard block,
+		// every node checks them against the same state
+		slashStateDB, err := blockchain.GetBestStateBeaconSlashStateDBByHeight(beaconHeight, blockchain.GetBeaconChainDatabase())
+		if err != nil {
+			return NewBlockChainError(EquivocationInstructionError, err)
+		}
+		equivocationInstructions, err := blockchain.verifyEquivocationInstructions(slashStateDB, shardBlock.Body.Instructions, int(shardID), curView.ShardCommittee, shardBlock.GetHeight())
+		if err != nil {
+			return err
+		}
+		instructions = append(instructions, equivocationInstructions...)
+	}
+	if beaconHeight >= blockchain.config.ChainParams.BCHeightBreakPointParticipation {
+		participationInstructions, err := blockchain.verifyParticipationInstructions(shardBlock.Body.Instructions, int(shardID), curView.BestBlock, curView.ShardCommittee)
+		if err != nil {
+			return err
+		}
+		instructions = append(instructions, participationInstructions...)
+	}
 	totalInstructions := []string{}
 	for _, value := range txInstructions {
 		totalInstructions = append(totalInstructions, value...)
@@ -584,6 +631,9 @@ func (shardBestState *ShardBestState) verifyBestStateWithShardBlock(blockchain *
 	if shardBlock.Header.BeaconHeight < shardBestState.BeaconHeight {
 		return NewBlockChainError(ShardBestStateBeaconHeightNotCompatibleError, fmt.Errorf("Shard Block contain invalid beacon height, current beacon height %+v but get %+v ", shardBestState.BeaconHeight, shardBlock.Header.BeaconHeight))
 	}
+	if err := blockchain.verifyPrevStateRootHash(shardBlock.Header.BeaconHeight, shardBlock.Header.PrevStateRootHash, shardBestState.GetStateRootHash()); err != nil {
+		return err
+	}
 	shardVerifyWithBestStateTimer.UpdateSince(startTimeVerifyBestStateWithShardBlock)
 	Logger.log.Debugf("SHARD %+v | Finish VerifyBestStateWithShardBlock Block with height %+v at hash %+v", shardBlock.Header.ShardID, shardBlock.Header.Height, shardBlock.Hash().String())
 	return nil
@@ -721,6 +771,7 @@ func (shardBestState *ShardBestState) initShardBestState(blockchain *BlockChain,
 	}
 	shardBestState.ConsensusAlgorithm = common.BlsConsensus
 	shardBestState.NumOfBlocksByProducers = make(map[string]uint64)
+	shardBestState.NumOfVotesByValidators = make(map[string]uint64)
 	//statedb===========================START
 	dbAccessWarper := statedb.NewDatabaseAccessWarper(db)
 	shardBestState.consensusStateDB, err = statedb.NewWithPrefixTrie(common.EmptyRoot, dbAccessWarper)
@@ -1221,6 +1272,7 @@ func (blockchain *BlockChain) processStoreShardBlock(newShardState *ShardBestSta
 	if err := batchData.Write(); err != nil {
 		return NewBlockChainError(StoreShardBlockError, err)
 	}
+	blockchain.startStatePruning(int(shardID), blockchain.ShardChain[shardID].GetFinalView().GetHeight())
 
 	if !blockchain.config.ChainParams.IsBackup {
 		return nil
@@ -153,6 +153,16 @@ func (blockchain *BlockChain) NewBlockShard(curView *ShardBestState, version int
 	if err != nil {
 		return nil, NewBlockChainError(GenerateInstructionError, err)
 	}
+	if beaconHeight >= blockchain.config.ChainParams.BCHeightBreakPointEquivocation {
+		slashStateDB, err := blockchain.GetBestStateBeaconSlashStateDBByHeight(beaconHeight, blockchain.GetBeaconChainDatabase())
+		if err != nil {
+			return nil, NewBlockChainError(EquivocationInstructionError, err)
+		}
+		instructions = append(instructions, blockchain.buildEquivocationInstructions(slashStateDB, int(shardID), curView.ShardCommittee, curView.ShardHeight+1)...)
+	}
+	if beaconHeight >= blockchain.config.ChainParams.BCHeightBreakPointParticipation {
+		instructions = append(instructions, blockchain.buildParticipationInstructions(int(shardID), curView.BestBlock, curView.ShardCommittee)...)
+	}
 	if len(instructions) != 0 {
 		Logger.log.Info("Shard Producer: Instruction", instructions)
 	}
@@ -185,6 +195,9 @@ func (blockchain *BlockChain) NewBlockShard(curView *ShardBestState, version int
 		TotalTxsFee:       totalTxsFee,
 		ConsensusType:     curView.ConsensusAlgorithm,
 	}
+	if beaconHeight >= blockchain.config.ChainParams.BCHeightBreakPointStateRoot {
+		newShardBlock.Header.PrevStateRootHash = curView.GetStateRootHash()
+	}
 	//============Update Shard BestState=============
 	// startStep = time.Now()
 	newShardBestState, err := shardBestState.updateShardBestState(blockchain, newShardBlock, beaconBlocks, committeeChange)
@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"encoding/json"
+	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
 	"sort"
 	"strings"
@@ -30,11 +31,15 @@ func (blockchain *BlockChain) buildBadProducersWithPunishment(
 		return make(map[string]uint8)
 	}
 	numOfBlocksByProducers := map[string]uint64{}
+	numOfVotesByValidators := map[string]uint64{}
+	numOfVotedBlocks := uint64(0)
 	if isBeacon {
 		if blockchain.GetBeaconBestState() == nil {
 			numOfBlocksByProducers = make(map[string]uint64)
 		} else {
 			numOfBlocksByProducers = blockchain.GetBeaconBestState().NumOfBlocksByProducers
+			numOfVotesByValidators = blockchain.GetBeaconBestState().NumOfVotesByValidators
+			numOfVotedBlocks = blockchain.GetBeaconBestState().NumOfVotedBlocks
 		}
 
 	} else {
@@ -42,6 +47,8 @@ func (blockchain *BlockChain) buildBadProducersWithPunishment(
 			numOfBlocksByProducers = make(map[string]uint64)
 		} else {
 			numOfBlocksByProducers = blockchain.GetBestStateShard(byte(shardID)).NumOfBlocksByProducers
+			numOfVotesByValidators = blockchain.GetBestStateShard(byte(shardID)).NumOfVotesByValidators
+			numOfVotedBlocks = blockchain.GetBestStateShard(byte(shardID)).NumOfVotedBlocks
 		}
 
 	}
@@ -57,31 +64,48 @@ func (blockchain *BlockChain) buildBadProducersWithPunishment(
 	}
 	expectedNumBlkByEachProducer := numBlkPerEpoch / uint64(committeeLen)
 
-	if expectedNumBlkByEachProducer == 0 {
-		return badProducersWithPunishment
-	}
 	for _, producer := range committee {
-		numBlk, found := numOfBlocksByProducers[producer]
-		if !found {
-			numBlk = 0
-		}
-		if numBlk >= expectedNumBlkByEachProducer {
-			continue
-		}
-		missingPercent := uint8((-(numBlk - expectedNumBlkByEachProducer) * 100) / expectedNumBlkByEachProducer)
-		var selectedSlLev *SlashLevel
-		for _, slLev := range slashLevels {
-			if missingPercent >= slLev.MinRange {
-				selectedSlLev = &slLev
+		punishedEpoches := uint8(0)
+		// missed blocks
+		numBlk := numOfBlocksByProducers[producer]
+		if expectedNumBlkByEachProducer != 0 && numBlk < expectedNumBlkByEachProducer {
+			missingPercent := uint8(((expectedNumBlkByEachProducer - numBlk) * 100) / expectedNumBlkByEachProducer)
+			punishedEpoches = blockchain.getPunishedEpoches(SlashMissedBlocks, missingPercent)
+		}
+		// missed votes
+		numVotes := numOfVotesByValidators[producer]
+		if numOfVotedBlocks != 0 && numVotes < numOfVotedBlocks {
+			missingPercent := uint8(((numOfVotedBlocks - numVotes) * 100) / numOfVotedBlocks)
+			if epoches := blockchain.getPunishedEpoches(SlashMissedVotes, missingPercent); epoches > punishedEpoches {
+				punishedEpoches = epoches
 			}
 		}
-		if selectedSlLev != nil {
-			badProducersWithPunishment[producer] = selectedSlLev.PunishedEpoches
+		if punishedEpoches != 0 {
+			badProducersWithPunishment[producer] = punishedEpoches
 		}
 	}
 	return sortMapStringUint8Keys(badProducersWithPunishment)
 }
 
+// getPunishedEpoches returns the punishment of the highest slash level of
+// reason which missingPercent reaches, 0 if none
+func (blockchain *BlockChain) getPunishedEpoches(reason string, missingPercent uint8) uint8 {
+	punishedEpoches := uint8(0)
+	for _, slLev := range blockchain.config.ChainParams.SlashLevels {
+		slLevReason := slLev.Reason
+		if slLevReason == "" {
+			slLevReason = SlashMissedBlocks
+		}
+		if slLevReason != reason || missingPercent < slLev.MinRange {
+			continue
+		}
+		if slLev.PunishedEpoches > punishedEpoches {
+			punishedEpoches = slLev.PunishedEpoches
+		}
+	}
+	return punishedEpoches
+}
+
 func (blockchain *BlockChain) getUpdatedProducersBlackList(slashStateDB *statedb.StateDB, isBeacon bool, shardID int, committee []string, beaconHeight uint64) (map[string]uint8, error) {
 	producersBlackList := statedb.GetProducersBlackList(slashStateDB, beaconHeight)
 	if isBeacon {
@@ -111,6 +135,8 @@ func (blockchain *BlockChain) processForSlashing(slashStateDB *statedb.StateDB,
 	fliterPunishedProducersFinished := []string{}
 	beaconHeight := beaconBlock.GetHeight()
 	producersBlackList := statedb.GetProducersBlackList(slashStateDB, beaconHeight-1)
+	reasons := make(map[string]string)
+	evidenceKeys := []common.Hash{}
 	chainParamEpoch := blockchain.config.ChainParams.Epoch
 	newBeaconHeight := beaconBlock.GetHeight()
 	if newBeaconHeight%uint64(chainParamEpoch) == 0 { // end of epoch
@@ -129,6 +155,32 @@ func (blockchain *BlockChain) processForSlashing(slashStateDB *statedb.StateDB,
 		if len(inst) == 0 {
 			continue
 		}
+		if inst[0] == EquivocationAction && len(inst) == 4 {
+			// an equivocation slashes once, whatever evidence of it is carried
+			evidenceKey, err := blockchain.config.ConsensusEngine.GetEquivocationEvidenceKey(inst[3])
+			if err != nil {
+				return err
+			}
+			isUsed, err := statedb.IsEquivocationEvidenceUsed(slashStateDB, evidenceKey)
+			if err != nil {
+				return err
+			}
+			if isUsed || common.IndexOfHash(evidenceKey, evidenceKeys) != -1 {
+				continue
+			}
+			evidenceKeys = append(evidenceKeys, evidenceKey)
+			// MinRange of the equivocation levels is not used
+			punishedEpoches := blockchain.getPunishedEpoches(SlashEquivocation, 100)
+			if punishedEpoches == 0 {
+				continue
+			}
+			epoches, found := producersBlackList[inst[2]]
+			if !found || epoches < punishedEpoches {
+				producersBlackList[inst[2]] = punishedEpoches
+				reasons[inst[2]] = SlashEquivocation
+			}
+			continue
+		}
 		if inst[0] != SwapAction {
 			continue
 		}
@@ -152,6 +204,7 @@ func (blockchain *BlockChain) processForSlashing(slashStateDB *statedb.StateDB,
 			epoches, found := producersBlackList[producer]
 			if !found || epoches < punishedEpoches {
 				producersBlackList[producer] = punishedEpoches
+				reasons[producer] = SlashDowntime
 			}
 		}
 	}
@@ -168,6 +221,9 @@ func (blockchain *BlockChain) processForSlashing(slashStateDB *statedb.StateDB,
 		}
 	}
 	statedb.RemoveProducerBlackList(slashStateDB, fliterPunishedProducersFinished)
-	err = statedb.StoreProducersBlackList(slashStateDB, beaconHeight, producersBlackList)
-	return err
+	err = statedb.StoreProducersBlackList(slashStateDB, beaconHeight, producersBlackList, reasons)
+	if err != nil {
+		return err
+	}
+	return statedb.StoreEquivocationEvidences(slashStateDB, beaconHeight, evidenceKeys)
 }
@@ -0,0 +1,149 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+)
+
+// Names of the state databases of a view
+const (
+	ConsensusStateDB   = "consensus"
+	TransactionStateDB = "transaction"
+	FeatureStateDB     = "feature"
+	RewardStateDB      = "reward"
+	SlashStateDB       = "slash"
+)
+
+// StateRootAt is the root of the state database StateDB recorded for the
+// finalized block of a chain at Height, with the roots of every state database
+// of the chain at this block
+type StateRootAt struct {
+	ChainID        int
+	Height         uint64
+	BlockHash      common.Hash
+	StateDB        string
+	Root           common.Hash
+	BeaconRootHash *BeaconRootHash `json:",omitempty"`
+	ShardRootHash  *ShardRootHash  `json:",omitempty"`
+}
+
+// GetDefaultStateDB returns the state database storing an object type, the
+// transaction database only exists on shard chains
+func GetDefaultStateDB(objectType int) string {
+	switch objectType {
+	case statedb.CommitteeObjectType, statedb.StakerObjectType:
+		return ConsensusStateDB
+	case statedb.CommitteeRewardObjectType, statedb.RewardRequestObjectType:
+		return RewardStateDB
+	case statedb.BlackListProducerObjectType:
+		return SlashStateDB
+	case statedb.SerialNumberObjectType, statedb.CommitmentObjectType, statedb.CommitmentIndexObjectType,
+		statedb.CommitmentLengthObjectType, statedb.SNDerivatorObjectType, statedb.OutputCoinObjectType,
+		statedb.TokenObjectType, statedb.TokenTransactionObjectType:
+		return TransactionStateDB
+	default:
+		return FeatureStateDB
+	}
+}
+
+// GetStateRootAt returns the root of a state database of a chain at the
+// finalized block of the given height, at the final view if height is zero.
+// Heights above the final view are rejected, a state can only be queried once
+// its block can not be reverted.
+func (blockchain *BlockChain) GetStateRootAt(chainID int, height uint64, stateDBName string) (*StateRootAt, error) {
+	target := &StateRootAt{ChainID: chainID, StateDB: stateDBName}
+	if chainID == common.BeaconChainDataBaseID {
+		finalHeight := blockchain.BeaconChain.GetFinalViewHeight()
+		if height == 0 {
+			height = finalHeight
+		}
+		if height > finalHeight {
+			return nil, fmt.Errorf("beacon height %v not finalized, final height %v", height, finalHeight)
+		}
+		db := blockchain.GetBeaconChainDatabase()
+		hash, err := rawdbv2.GetFinalizedBeaconBlockHashByIndex(db, height)
+		if err != nil {
+			return nil, fmt.Errorf("beacon block hash at height %v: %v", height, err)
+		}
+		rootHash, err := GetBeaconRootsHashByBlockHash(db, *hash)
+		if err != nil {
+			return nil, fmt.Errorf("beacon root hash at height %v: %v", height, err)
+		}
+		target.Height, target.BlockHash, target.BeaconRootHash = height, *hash, rootHash
+		switch stateDBName {
+		case ConsensusStateDB:
+			target.Root = rootHash.ConsensusStateDBRootHash
+		case FeatureStateDB:
+			target.Root = rootHash.FeatureStateDBRootHash
+		case RewardStateDB:
+			target.Root = rootHash.RewardStateDBRootHash
+		case SlashStateDB:
+			target.Root = rootHash.SlashStateDBRootHash
+		default:
+			return nil, fmt.Errorf("beacon chain has no %v state", stateDBName)
+		}
+		return target, nil
+	}
+
+	if chainID < 0 || chainID >= blockchain.GetActiveShardNumber() {
+		return nil, fmt.Errorf("shard %v not exist", chainID)
+	}
+	shardID := byte(chainID)
+	finalHeight := blockchain.ShardChain[shardID].GetFinalViewHeight()
+	if height == 0 {
+		height = finalHeight
+	}
+	if height > finalHeight {
+		return nil, fmt.Errorf("shard %v height %v not finalized, final height %v", shardID, height, finalHeight)
+	}
+	db := blockchain.GetShardChainDatabase(shardID)
+	hash, err := rawdbv2.GetFinalizedShardBlockHashByIndex(db, shardID, height)
+	if err != nil {
+		return nil, fmt.Errorf("shard %v block hash at height %v: %v", shardID, height, err)
+	}
+	rootHash, err := GetShardRootsHashByBlockHash(db, shardID, *hash)
+	if err != nil {
+		return nil, fmt.Errorf("shard %v root hash at height %v: %v", shardID, height, err)
+	}
+	target.Height, target.BlockHash, target.ShardRootHash = height, *hash, rootHash
+	switch stateDBName {
+	case ConsensusStateDB:
+		target.Root = rootHash.ConsensusStateDBRootHash
+	case TransactionStateDB:
+		target.Root = rootHash.TransactionStateDBRootHash
+	case FeatureStateDB:
+		target.Root = rootHash.FeatureStateDBRootHash
+	case RewardStateDB:
+		target.Root = rootHash.RewardStateDBRootHash
+	case SlashStateDB:
+		target.Root = rootHash.SlashStateDBRootHash
+	default:
+		return nil, fmt.Errorf("shard chain has no %v state", stateDBName)
+	}
+	return target, nil
+}
+
+// GetStateDBAt opens a state database of a chain at the finalized block of the
+// given height, see GetStateRootAt. The trie nodes of old states are removed by
+// state pruning, such states can not be opened anymore.
+func (blockchain *BlockChain) GetStateDBAt(chainID int, height uint64, stateDBName string) (*statedb.StateDB, *StateRootAt, error) {
+	root, err := blockchain.GetStateRootAt(chainID, height, stateDBName)
+	if err != nil {
+		return nil, nil, err
+	}
+	db := blockchain.GetBeaconChainDatabase()
+	if chainID != common.BeaconChainDataBaseID {
+		db = blockchain.GetShardChainDatabase(byte(chainID))
+	}
+	stateDB, err := statedb.NewWithPrefixTrie(root.Root, statedb.NewDatabaseAccessWarper(db))
+	if err != nil {
+		if blockchain.config.PruneState > 0 {
+			return nil, nil, fmt.Errorf("%v state of chain %v at height %v not found, it may have been pruned, %v", stateDBName, chainID, root.Height, err)
+		}
+		return nil, nil, fmt.Errorf("%v state of chain %v at height %v not found, %v", stateDBName, chainID, root.Height, err)
+	}
+	return stateDB, root, nil
+}
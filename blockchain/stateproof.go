@@ -0,0 +1,146 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+// StateProof is the proof of a state object to a signed block header. Proof
+// links the object to Root.Root, the roots of every state database at Root
+// hash to the PrevStateRootHash of the header of NextBlock, the block right
+// after Root.BlockHash, and NextBlock is signed by the committee of the chain.
+type StateProof struct {
+	Root      *StateRootAt
+	NextBlock common.BlockInterface
+	Proof     *statedb.StateProof
+}
+
+// GetStateProof returns the merkle proof of the state object stored at key in
+// a state database of a chain, at the finalized block of the given height or
+// at the final view if height is zero, with the block after it committing to
+// its state roots. Only states whose next block is from the state root break
+// point can be proven, and the final view only once its next block is known.
+func (blockchain *BlockChain) GetStateProof(chainID int, height uint64, stateDBName string, key common.Hash) (*StateProof, error) {
+	stateDB, target, err := blockchain.GetStateDBAt(chainID, height, stateDBName)
+	if err != nil {
+		return nil, err
+	}
+	nextBlock, err := blockchain.getStateProofNextBlock(target)
+	if err != nil {
+		return nil, err
+	}
+	if getBlockBeaconHeight(nextBlock) < blockchain.config.ChainParams.BCHeightBreakPointStateRoot {
+		return nil, fmt.Errorf("block headers commit the state roots from beacon height %v only", blockchain.config.ChainParams.BCHeightBreakPointStateRoot)
+	}
+	proof, err := stateDB.GetStateProof(key)
+	if err != nil {
+		return nil, err
+	}
+	return &StateProof{Root: target, NextBlock: nextBlock, Proof: proof}, nil
+}
+
+// getStateProofNextBlock returns the block after the finalized block of
+// target, from the views of the chain if target is the final view
+func (blockchain *BlockChain) getStateProofNextBlock(target *StateRootAt) (common.BlockInterface, error) {
+	if target.ChainID == common.BeaconChainDataBaseID {
+		if target.Height == blockchain.BeaconChain.GetFinalViewHeight() {
+			return getStateSyncNextBlock(blockchain.BeaconChain.multiView, blockchain.BeaconChain.GetFinalView())
+		}
+		hash, err := rawdbv2.GetFinalizedBeaconBlockHashByIndex(blockchain.GetBeaconChainDatabase(), target.Height+1)
+		if err != nil {
+			return nil, fmt.Errorf("beacon block hash at height %v: %v", target.Height+1, err)
+		}
+		block, _, err := blockchain.GetBeaconBlockByHash(*hash)
+		if err != nil {
+			return nil, err
+		}
+		return block, nil
+	}
+	shardID := byte(target.ChainID)
+	if target.Height == blockchain.ShardChain[shardID].GetFinalViewHeight() {
+		return getStateSyncNextBlock(blockchain.ShardChain[shardID].multiView, blockchain.ShardChain[shardID].GetFinalView())
+	}
+	hash, err := rawdbv2.GetFinalizedShardBlockHashByIndex(blockchain.GetShardChainDatabase(shardID), shardID, target.Height+1)
+	if err != nil {
+		return nil, fmt.Errorf("shard %v block hash at height %v: %v", shardID, target.Height+1, err)
+	}
+	block, _, err := blockchain.GetShardBlockByHashWithShardID(*hash, shardID)
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// getBlockBeaconHeight returns the beacon height a block is gated on
+func getBlockBeaconHeight(block common.BlockInterface) uint64 {
+	if shardBlock, ok := block.(*ShardBlock); ok {
+		return shardBlock.Header.BeaconHeight
+	}
+	return block.GetHeight()
+}
+
+// VerifyStateProof checks proof from its object up to the signatures of its
+// next block by committee, the committee of the chain at that block. The
+// committee is not in the proof, the caller must know it, e.g. from the
+// signed beacon committee swaps. Signatures are checked with engine.
+func VerifyStateProof(engine ConsensusEngine, committee []incognitokey.CommitteePublicKey, proof *StateProof) error {
+	if proof == nil || proof.Root == nil || proof.NextBlock == nil {
+		return errors.New("incomplete state proof")
+	}
+	root := proof.Root
+	var prevBlockHash, prevStateRootHash, stateRootHash common.Hash
+	var roots map[string]common.Hash
+	switch block := proof.NextBlock.(type) {
+	case *BeaconBlock:
+		if root.ChainID != common.BeaconChainDataBaseID || root.BeaconRootHash == nil {
+			return fmt.Errorf("beacon block proves no state of chain %v", root.ChainID)
+		}
+		prevBlockHash, prevStateRootHash = block.Header.PreviousBlockHash, block.Header.PrevStateRootHash
+		stateRootHash = root.BeaconRootHash.Hash()
+		roots = map[string]common.Hash{
+			ConsensusStateDB: root.BeaconRootHash.ConsensusStateDBRootHash,
+			FeatureStateDB:   root.BeaconRootHash.FeatureStateDBRootHash,
+			RewardStateDB:    root.BeaconRootHash.RewardStateDBRootHash,
+			SlashStateDB:     root.BeaconRootHash.SlashStateDBRootHash,
+		}
+	case *ShardBlock:
+		if root.ChainID != int(block.Header.ShardID) || root.ShardRootHash == nil {
+			return fmt.Errorf("shard %v block proves no state of chain %v", block.Header.ShardID, root.ChainID)
+		}
+		prevBlockHash, prevStateRootHash = block.Header.PreviousBlockHash, block.Header.PrevStateRootHash
+		stateRootHash = root.ShardRootHash.Hash()
+		roots = map[string]common.Hash{
+			ConsensusStateDB:   root.ShardRootHash.ConsensusStateDBRootHash,
+			TransactionStateDB: root.ShardRootHash.TransactionStateDBRootHash,
+			FeatureStateDB:     root.ShardRootHash.FeatureStateDBRootHash,
+			RewardStateDB:      root.ShardRootHash.RewardStateDBRootHash,
+			SlashStateDB:       root.ShardRootHash.SlashStateDBRootHash,
+		}
+	default:
+		return fmt.Errorf("unknown block type %T", proof.NextBlock)
+	}
+	if proof.NextBlock.GetHeight() != root.Height+1 || prevBlockHash != root.BlockHash {
+		return fmt.Errorf("block %v at height %v is not after block %v at height %v", proof.NextBlock.Hash(), proof.NextBlock.GetHeight(), root.BlockHash, root.Height)
+	}
+	if prevStateRootHash == (common.Hash{}) {
+		return fmt.Errorf("block %v does not commit to the state roots", proof.NextBlock.Hash())
+	}
+	if prevStateRootHash != stateRootHash {
+		return fmt.Errorf("block %v commits to state root hash %v but get %v", proof.NextBlock.Hash(), prevStateRootHash, stateRootHash)
+	}
+	if r, ok := roots[root.StateDB]; !ok || r != root.Root {
+		return fmt.Errorf("%v state root %v is not in the state roots", root.StateDB, root.Root)
+	}
+	if err := engine.ValidateProducerSig(proof.NextBlock, common.BlsConsensus); err != nil {
+		return err
+	}
+	if err := engine.ValidateBlockCommitteSig(proof.NextBlock, committee); err != nil {
+		return err
+	}
+	return statedb.VerifyStateProof(root.Root, proof.Proof)
+}
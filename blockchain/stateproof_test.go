@@ -0,0 +1,87 @@
+package blockchain
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+)
+
+func newStateProofTest(t *testing.T) *StateProof {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_stateproof_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := incdb.Open("leveldb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateDB, err := statedb.NewWithPrefixTrie(common.EmptyRoot, statedb.NewDatabaseAccessWarper(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := statedb.StoreSerialNumbers(stateDB, common.PRVCoinID, [][]byte{[]byte("sn1"), []byte("sn2")}, 0); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateDB.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := stateDB.GetStateProof(statedb.GenerateSerialNumberObjectKey(common.PRVCoinID, 0, []byte("sn1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootHash := &ShardRootHash{
+		ConsensusStateDBRootHash:   common.HashH([]byte("consensus")),
+		TransactionStateDBRootHash: root,
+		FeatureStateDBRootHash:     common.HashH([]byte("feature")),
+	}
+	target := &StateRootAt{ChainID: 0, Height: 10, BlockHash: common.HashH([]byte("block")), StateDB: TransactionStateDB, Root: root, ShardRootHash: rootHash}
+	nextBlock := NewShardBlock()
+	nextBlock.Header.Height = 11
+	nextBlock.Header.PreviousBlockHash = target.BlockHash
+	nextBlock.Header.PrevStateRootHash = rootHash.Hash()
+	nextBlock.ValidationData = stateSyncTestSignature(newStateSyncTestCommittee(1, 2, 3))
+	return &StateProof{Root: target, NextBlock: nextBlock, Proof: proof}
+}
+
+func TestVerifyStateProof(t *testing.T) {
+	committee := newStateSyncTestCommittee(1, 2, 3)
+	tests := []struct {
+		name    string
+		forge   func(proof *StateProof)
+		wantErr bool
+	}{
+		{name: "valid proof", forge: func(proof *StateProof) {}},
+		{name: "forged value", forge: func(proof *StateProof) { proof.Proof.Value = []byte("forged") }, wantErr: true},
+		{name: "not signed by committee", forge: func(proof *StateProof) {
+			proof.NextBlock.(*ShardBlock).ValidationData = stateSyncTestSignature(newStateSyncTestCommittee(1, 2, 4))
+		}, wantErr: true},
+		{name: "root not committed", forge: func(proof *StateProof) {
+			proof.Root.ShardRootHash.TransactionStateDBRootHash = common.HashH([]byte("forged"))
+			proof.Root.Root = proof.Root.ShardRootHash.TransactionStateDBRootHash
+		}, wantErr: true},
+		{name: "root of another state", forge: func(proof *StateProof) { proof.Root.StateDB = FeatureStateDB }, wantErr: true},
+		{name: "no state root commitment", forge: func(proof *StateProof) {
+			proof.NextBlock.(*ShardBlock).Header.PrevStateRootHash = common.Hash{}
+		}, wantErr: true},
+		{name: "block not after state", forge: func(proof *StateProof) { proof.NextBlock.(*ShardBlock).Header.Height = 12 }, wantErr: true},
+		{name: "block of another parent", forge: func(proof *StateProof) {
+			proof.NextBlock.(*ShardBlock).Header.PreviousBlockHash = common.HashH([]byte("other"))
+		}, wantErr: true},
+		{name: "block of another shard", forge: func(proof *StateProof) { proof.NextBlock.(*ShardBlock).Header.ShardID = 1 }, wantErr: true},
+		{name: "beacon block for shard state", forge: func(proof *StateProof) { proof.NextBlock = NewBeaconBlock() }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof := newStateProofTest(t)
+			tt.forge(proof)
+			if err := VerifyStateProof(&stateSyncTestEngine{}, committee, proof); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyStateProof() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+// StateSyncTarget is a finalized view of a peer whose state tries are
+// downloaded by a node syncing the state of a chain instead of processing all
+// its blocks. The beacon chain uses common.BeaconChainDataBaseID as ChainID.
+type StateSyncTarget struct {
+	ChainID      int
+	Height       uint64
+	BlockHash    common.Hash
+	BeaconHeight uint64
+	Roots        []common.Hash
+
+	beaconView      *BeaconBestState
+	beaconBlock     *BeaconBlock
+	nextBeaconBlock *BeaconBlock
+	shardView       *ShardBestState
+	shardBlock      *ShardBlock
+	nextShardBlock  *ShardBlock
+}
+
+// StateSyncView is returned to a peer syncing the state of a chain: the final
+// view of the chain and its block, a block after it committing to the state
+// roots of the view, all json encoded, and for the beacon chain the committee
+// swaps from the final beacon height of the peer. When there are more than
+// MaxStateSyncCommitteeSwaps swaps only the first ones are returned, without
+// view.
+type StateSyncView struct {
+	View           []byte
+	Block          []byte
+	NextBlock      []byte
+	CommitteeSwaps [][]byte
+}
+
+// GetStateSyncView returns the final view of a chain for a peer syncing its
+// state, with the beacon committee swaps after fromBeaconHeight
+func (blockchain *BlockChain) GetStateSyncView(chainID int, fromBeaconHeight uint64) (*StateSyncView, error) {
+	var (
+		view      multiview.View
+		block     interface{}
+		nextBlock interface{}
+		err       error
+	)
+	res := &StateSyncView{}
+	if chainID == common.BeaconChainDataBaseID {
+		beaconView := blockchain.BeaconChain.GetFinalView().(*BeaconBestState)
+		var more bool
+		res.CommitteeSwaps, more, err = blockchain.getBeaconCommitteeSwaps(fromBeaconHeight+1, beaconView.BeaconHeight)
+		if err != nil {
+			return nil, err
+		}
+		if more {
+			return res, nil
+		}
+		view, block = beaconView, &beaconView.BestBlock
+		nextBlock, err = getStateSyncNextBlock(blockchain.BeaconChain.multiView, view)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if chainID < 0 || chainID >= len(blockchain.ShardChain) {
+			return nil, fmt.Errorf("invalid chain %v", chainID)
+		}
+		shardView := blockchain.ShardChain[chainID].GetFinalView().(*ShardBestState)
+		view, block = shardView, shardView.BestBlock
+		nextBlock, err = getStateSyncNextBlock(blockchain.ShardChain[chainID].multiView, view)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if res.View, err = json.Marshal(view); err != nil {
+		return nil, err
+	}
+	if res.Block, err = json.Marshal(block); err != nil {
+		return nil, err
+	}
+	if res.NextBlock, err = json.Marshal(nextBlock); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// getStateSyncNextBlock returns the block of a view right after final, its
+// header commits to the state roots of final
+func getStateSyncNextBlock(multiView *multiview.MultiView, final multiview.View) (common.BlockInterface, error) {
+	view := multiView.GetBestView()
+	for view != nil && view.GetHeight() > final.GetHeight()+1 {
+		view = multiView.GetViewByHash(*view.GetPreviousHash())
+	}
+	if view == nil || view.GetHeight() != final.GetHeight()+1 || *view.GetPreviousHash() != *final.GetHash() {
+		return nil, fmt.Errorf("no block after the final view at height %v yet", final.GetHeight())
+	}
+	return view.GetBlock(), nil
+}
+
+// verifyPrevStateRootHash checks a block header commits to the state roots of
+// the view before it from the state root break point, and to nothing before
+func (blockchain *BlockChain) verifyPrevStateRootHash(beaconHeight uint64, prevStateRootHash common.Hash, expected common.Hash) error {
+	if beaconHeight < blockchain.config.ChainParams.BCHeightBreakPointStateRoot {
+		if prevStateRootHash != (common.Hash{}) {
+			return NewBlockChainError(StateRootHashError, fmt.Errorf("unexpected state root hash %v before beacon height %v", prevStateRootHash, blockchain.config.ChainParams.BCHeightBreakPointStateRoot))
+		}
+		return nil
+	}
+	if prevStateRootHash != expected {
+		return NewBlockChainError(StateRootHashError, fmt.Errorf("expect state root hash %v but get %v", expected, prevStateRootHash))
+	}
+	return nil
+}
+
+// GetTrieNode returns a trie node of the state of a chain. Nothing else of the
+// chain database can be read this way, the value must hash to the key.
+func (blockchain *BlockChain) GetTrieNode(chainID int, hash common.Hash) ([]byte, error) {
+	var db incdb.Database
+	if chainID == common.BeaconChainDataBaseID {
+		db = blockchain.GetBeaconChainDatabase()
+	} else {
+		if chainID < 0 || chainID >= len(blockchain.ShardChain) {
+			return nil, fmt.Errorf("invalid chain %v", chainID)
+		}
+		db = blockchain.GetShardChainDatabase(byte(chainID))
+	}
+	data, err := db.Get(hash[:])
+	if err != nil {
+		return nil, err
+	}
+	if common.Keccak256Hash(data) != hash {
+		return nil, fmt.Errorf("%v is not a trie node", hash.String())
+	}
+	return data, nil
+}
+
+// NewStateSyncTarget decodes the view and blocks returned by GetStateSyncView
+// of a peer and checks they match. The next block must commit to the state
+// roots of the view, once its signatures are verified the roots are trusted.
+func (blockchain *BlockChain) NewStateSyncTarget(chainID int, syncView *StateSyncView) (*StateSyncTarget, error) {
+	target := &StateSyncTarget{ChainID: chainID}
+	if chainID == common.BeaconChainDataBaseID {
+		view, block, nextBlock := NewBeaconBestState(), NewBeaconBlock(), NewBeaconBlock()
+		if err := json.Unmarshal(syncView.View, view); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(syncView.Block, block); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(syncView.NextBlock, nextBlock); err != nil {
+			return nil, err
+		}
+		if view.BestBlockHash != *block.Hash() {
+			return nil, fmt.Errorf("view block %v but got block %v", view.BestBlockHash.String(), block.Hash().String())
+		}
+		view.BestBlock = *block
+		if err := checkStateSyncNextBlock(block, nextBlock, nextBlock.Header.PrevStateRootHash, view.GetStateRootHash()); err != nil {
+			return nil, err
+		}
+		target.Height, target.BlockHash = block.GetHeight(), *block.Hash()
+		target.Roots = []common.Hash{view.ConsensusStateDBRootHash, view.FeatureStateDBRootHash,
+			view.RewardStateDBRootHash, view.SlashStateDBRootHash}
+		target.beaconView, target.beaconBlock, target.nextBeaconBlock = view, block, nextBlock
+		return target, nil
+	}
+	if chainID < 0 || chainID >= len(blockchain.ShardChain) {
+		return nil, fmt.Errorf("invalid chain %v", chainID)
+	}
+	view, block, nextBlock := NewShardBestState(), NewShardBlock(), NewShardBlock()
+	if err := json.Unmarshal(syncView.View, view); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(syncView.Block, block); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(syncView.NextBlock, nextBlock); err != nil {
+		return nil, err
+	}
+	if view.BestBlockHash != *block.Hash() || int(block.Header.ShardID) != chainID || view.ShardID != block.Header.ShardID {
+		return nil, fmt.Errorf("view block %v of shard %v but got block %v of shard %v",
+			view.BestBlockHash.String(), view.ShardID, block.Hash().String(), block.Header.ShardID)
+	}
+	view.BestBlock = block
+	if nextBlock.Header.ShardID != block.Header.ShardID {
+		return nil, fmt.Errorf("next block of shard %v but got shard %v", block.Header.ShardID, nextBlock.Header.ShardID)
+	}
+	if err := checkStateSyncNextBlock(block, nextBlock, nextBlock.Header.PrevStateRootHash, view.GetStateRootHash()); err != nil {
+		return nil, err
+	}
+	target.Height, target.BlockHash, target.BeaconHeight = block.GetHeight(), *block.Hash(), view.BeaconHeight
+	target.Roots = []common.Hash{view.ConsensusStateDBRootHash, view.TransactionStateDBRootHash,
+		view.FeatureStateDBRootHash, view.RewardStateDBRootHash, view.SlashStateDBRootHash}
+	target.shardView, target.shardBlock, target.nextShardBlock = view, block, nextBlock
+	return target, nil
+}
+
+// checkStateSyncNextBlock checks nextBlock follows block and commits to the
+// state roots hash of its view
+func checkStateSyncNextBlock(block common.BlockInterface, nextBlock common.BlockInterface, prevStateRootHash common.Hash, stateRootHash common.Hash) error {
+	if nextBlock.GetPrevHash() != *block.Hash() || nextBlock.GetHeight() != block.GetHeight()+1 {
+		return fmt.Errorf("block %v at height %v does not follow block %v", nextBlock.Hash().String(), nextBlock.GetHeight(), block.Hash().String())
+	}
+	if prevStateRootHash == (common.Hash{}) {
+		return fmt.Errorf("block %v commits to no state root", nextBlock.Hash().String())
+	}
+	if prevStateRootHash != stateRootHash {
+		return fmt.Errorf("block %v commits to state root hash %v but the view has %v", nextBlock.Hash().String(), prevStateRootHash.String(), stateRootHash.String())
+	}
+	return nil
+}
+
+// GetBeaconStateSyncHeight returns the height of the beacon view synced from a
+// peer, zero if the node processed every beacon block
+func (blockchain *BlockChain) GetBeaconStateSyncHeight() uint64 {
+	view, err := blockchain.getBeaconStateSyncView()
+	if err != nil {
+		return 0
+	}
+	return view.BeaconHeight
+}
+
+func (blockchain *BlockChain) getBeaconStateSyncView() (*BeaconBestState, error) {
+	data, err := rawdbv2.GetBeaconStateSyncView(blockchain.GetBeaconChainDatabase())
+	if err != nil {
+		return nil, err
+	}
+	view := NewBeaconBestState()
+	if err := json.Unmarshal(data, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// InstallStateSyncTarget replaces the views of a chain by the view of target,
+// once every trie node of its state roots has been downloaded. Nothing sent by
+// the peer is trusted:
+//
+//   - the block after the view must be signed by a committee this node
+//     verified: anchor for the beacon chain, built from the final beacon view
+//     through the signed beacon committee swaps, the shard committee of the
+//     beacon state at the beacon height of a shard view
+//   - this block commits to the view block by its previous hash and to every
+//     state root of the view by its previous state root hash
+//   - the committee read from the downloaded consensus trie must be that same
+//     committee
+//
+// Blocks after target are then synced and processed as usual.
+func (blockchain *BlockChain) InstallStateSyncTarget(target *StateSyncTarget, anchor *StateSyncAnchor, db incdb.Database) error {
+	for _, root := range target.Roots {
+		if root == (common.Hash{}) || root == common.EmptyRoot {
+			continue
+		}
+		if ok, err := db.Has(root[:]); err != nil || !ok {
+			return fmt.Errorf("state root %v not synced", root.String())
+		}
+	}
+	if target.ChainID == common.BeaconChainDataBaseID {
+		return blockchain.installBeaconStateSyncTarget(target, anchor)
+	}
+	return blockchain.installShardStateSyncTarget(target)
+}
+
+func (blockchain *BlockChain) installBeaconStateSyncTarget(target *StateSyncTarget, anchor *StateSyncAnchor) error {
+	blockchain.BeaconChain.insertLock.Lock()
+	defer blockchain.BeaconChain.insertLock.Unlock()
+	if blockchain.BeaconChain.GetBestViewHeight() >= target.Height {
+		return fmt.Errorf("beacon already at height %v", blockchain.BeaconChain.GetBestViewHeight())
+	}
+	if anchor == nil || anchor.Height > target.Height {
+		return fmt.Errorf("no verified beacon committee at height %v", target.Height)
+	}
+	db := blockchain.GetBeaconChainDatabase()
+	view, block := target.beaconView, target.beaconBlock
+	// the next block is signed by the committee of the view
+	if err := blockchain.BeaconChain.ValidateBlockSignatures(target.nextBeaconBlock, anchor.Committee); err != nil {
+		return err
+	}
+	// the view is restored from the block stored by hash, which is not reachable
+	// from the finalized index until the state is verified
+	if err := rawdbv2.StoreBeaconBlockByHash(db, target.BlockHash, block); err != nil {
+		return err
+	}
+	if err := view.RestoreBeaconViewStateFromHash(blockchain); err != nil {
+		return err
+	}
+	if err := checkStateSyncCommittee(anchor.Committee, view.BeaconCommittee); err != nil {
+		return err
+	}
+	consensusStateDB := view.consensusStateDB.Copy()
+	view.AutoStaking = NewMapStringBool()
+	view.AutoStaking.data = statedb.GetMapAutoStaking(consensusStateDB, blockchain.GetShardIDs())
+	if err := view.verifyPostProcessingBeaconBlock(block, nil); err != nil {
+		return err
+	}
+
+	viewData, err := json.Marshal(view)
+	if err != nil {
+		return err
+	}
+	batch := db.NewBatch()
+	bRH := BeaconRootHash{
+		ConsensusStateDBRootHash: view.ConsensusStateDBRootHash,
+		FeatureStateDBRootHash:   view.FeatureStateDBRootHash,
+		RewardStateDBRootHash:    view.RewardStateDBRootHash,
+		SlashStateDBRootHash:     view.SlashStateDBRootHash,
+	}
+	if err := rawdbv2.StoreBeaconRootsHash(batch, target.BlockHash, bRH); err != nil {
+		return err
+	}
+	if err := rawdbv2.StoreFinalizedBeaconBlockHashByIndex(batch, target.Height, target.BlockHash); err != nil {
+		return err
+	}
+	if err := rawdbv2.StoreBeaconStateSyncView(batch, viewData); err != nil {
+		return err
+	}
+	allViewsData, _ := json.Marshal([]*BeaconBestState{view})
+	if err := rawdbv2.StoreBeaconViews(batch, allViewsData); err != nil {
+		return err
+	}
+	// the swaps up to the view are stored while verifying the anchor
+	blockchain.committeeSwapIndex.lock.Lock()
+	defer blockchain.committeeSwapIndex.lock.Unlock()
+	indexed := blockchain.committeeSwapIndex.height >= anchor.startHeight
+	if indexed {
+		if err := rawdbv2.StoreBeaconCommitteeSwapIndexHeight(batch, target.Height); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if indexed {
+		blockchain.committeeSwapIndex.height = target.Height
+	}
+	blockchain.BeaconChain.multiView.Reset()
+	if !blockchain.BeaconChain.multiView.AddView(view) {
+		return errors.New("add synced beacon view failed")
+	}
+	Logger.log.Infof("Synced beacon state at height %v, block %v", target.Height, target.BlockHash.String())
+	return nil
+}
+
+func (blockchain *BlockChain) installShardStateSyncTarget(target *StateSyncTarget) error {
+	shardID := byte(target.ChainID)
+	shardChain := blockchain.ShardChain[shardID]
+	shardChain.insertLock.Lock()
+	defer shardChain.insertLock.Unlock()
+	if shardChain.GetBestViewHeight() >= target.Height {
+		return fmt.Errorf("shard %v already at height %v", shardID, shardChain.GetBestViewHeight())
+	}
+	// shard blocks are processed against the beacon state at their beacon
+	// height, the beacon must be finalized up to this view first
+	if target.BeaconHeight > blockchain.BeaconChain.GetFinalView().GetHeight() {
+		return fmt.Errorf("beacon height %v of shard %v view not finalized yet", target.BeaconHeight, shardID)
+	}
+	view, block := target.shardView, target.shardBlock
+	anchor, err := blockchain.getStateSyncShardCommittee(shardID, target.BeaconHeight)
+	if err != nil {
+		return err
+	}
+	// the next block is signed by the committee of the view
+	if err := shardChain.ValidateBlockSignatures(target.nextShardBlock, anchor); err != nil {
+		return err
+	}
+	// cross shard blocks confirmed by the beacon blocks skipped by its state sync
+	// can not be found anymore, the shard view must have processed them all
+	if beaconView, err := blockchain.getBeaconStateSyncView(); err == nil {
+		if target.BeaconHeight < beaconView.BeaconHeight {
+			return fmt.Errorf("beacon height %v of shard %v view is before the beacon state sync height %v", target.BeaconHeight, shardID, beaconView.BeaconHeight)
+		}
+		for fromShard, toShards := range beaconView.LastCrossShardState {
+			if toShards[shardID] > view.BestCrossShard[fromShard] {
+				return fmt.Errorf("shard %v view has not processed cross shard block %v of shard %v", shardID, toShards[shardID], fromShard)
+			}
+		}
+	}
+
+	db := blockchain.GetShardChainDatabase(shardID)
+	if err := view.InitStateRootHash(db, blockchain); err != nil {
+		return err
+	}
+	if err := view.RestoreCommittee(shardID, blockchain); err != nil {
+		return err
+	}
+	if err := checkStateSyncCommittee(anchor, view.ShardCommittee); err != nil {
+		return err
+	}
+	stakingTx, err := blockchain.GetShardStakingTx(view)
+	if err != nil {
+		return err
+	}
+	view.StakingTx = NewMapStringString()
+	view.StakingTx.data = stakingTx
+	if err := view.RestorePendingValidators(shardID, blockchain); err != nil {
+		return err
+	}
+	if err := blockchain.verifyPostProcessingShardBlock(view, block, shardID); err != nil {
+		return err
+	}
+
+	batch := db.NewBatch()
+	sRH := ShardRootHash{
+		ConsensusStateDBRootHash:   view.ConsensusStateDBRootHash,
+		FeatureStateDBRootHash:     view.FeatureStateDBRootHash,
+		RewardStateDBRootHash:      view.RewardStateDBRootHash,
+		SlashStateDBRootHash:       view.SlashStateDBRootHash,
+		TransactionStateDBRootHash: view.TransactionStateDBRootHash,
+	}
+	if err := rawdbv2.StoreShardRootsHash(batch, shardID, target.BlockHash, sRH); err != nil {
+		return err
+	}
+	if err := rawdbv2.StoreShardBlock(batch, target.BlockHash, block); err != nil {
+		return err
+	}
+	if err := rawdbv2.StoreFinalizedShardBlockHashByIndex(batch, shardID, target.Height, target.BlockHash); err != nil {
+		return err
+	}
+	if err := rawdbv2.StoreShardBestState(batch, shardID, []*ShardBestState{view}); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	shardChain.multiView.Reset()
+	if !shardChain.multiView.AddView(view) {
+		return fmt.Errorf("add synced shard %v view failed", shardID)
+	}
+	Logger.log.Infof("Synced shard %v state at height %v, block %v", shardID, target.Height, target.BlockHash.String())
+	return nil
+}
+
+// getStateSyncShardCommittee returns the committee of a shard in the beacon
+// consensus state at beaconHeight, which this node verified
+func (blockchain *BlockChain) getStateSyncShardCommittee(shardID byte, beaconHeight uint64) ([]incognitokey.CommitteePublicKey, error) {
+	roots, err := blockchain.GetBeaconRootsHashFromBlockHeight(beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	consensusStateDB, err := statedb.NewWithPrefixTrie(roots.ConsensusStateDBRootHash, statedb.NewDatabaseAccessWarper(blockchain.GetBeaconChainDatabase()))
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetOneShardCommittee(consensusStateDB, shardID), nil
+}
+
+// checkStateSyncCommittee checks the committee read from a downloaded state is
+// the committee which signed the block after it
+func checkStateSyncCommittee(anchor []incognitokey.CommitteePublicKey, synced []incognitokey.CommitteePublicKey) error {
+	anchorKeys, err := incognitokey.CommitteeKeyListToString(anchor)
+	if err != nil {
+		return err
+	}
+	syncedKeys, err := incognitokey.CommitteeKeyListToString(synced)
+	if err != nil {
+		return err
+	}
+	if len(anchorKeys) == 0 || !reflect.DeepEqual(anchorKeys, syncedKeys) {
+		return fmt.Errorf("synced committee %v does not match the verified committee %v", syncedKeys, anchorKeys)
+	}
+	return nil
+}
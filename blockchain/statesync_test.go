@@ -0,0 +1,310 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+// stateSyncTestEngine accepts a block whose validation data is the
+// signature of the committee by stateSyncTestSignature
+type stateSyncTestEngine struct{}
+
+func (engine *stateSyncTestEngine) ValidateProducerPosition(blk common.BlockInterface, lastProposerIdx int, committee []incognitokey.CommitteePublicKey, minCommitteeSize int) error {
+	return nil
+}
+
+func (engine *stateSyncTestEngine) ValidateProducerSig(block common.BlockInterface, consensusType string) error {
+	return nil
+}
+
+func (engine *stateSyncTestEngine) ValidateBlockCommitteSig(block common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	if len(committee) == 0 || block.GetValidationField() != stateSyncTestSignature(committee) {
+		return errors.New("not signed by committee")
+	}
+	return nil
+}
+
+func (engine *stateSyncTestEngine) ValidateEquivocationEvidence(evidence string, chainID int, committee []incognitokey.CommitteePublicKey) (string, uint64, error) {
+	return "", 0, errors.New("not supported")
+}
+
+func (engine *stateSyncTestEngine) GetEquivocationEvidenceKey(evidence string) (common.Hash, error) {
+	return common.Hash{}, errors.New("not supported")
+}
+
+func (engine *stateSyncTestEngine) ExtractBridgeValidationData(block common.BlockInterface) ([][]byte, []int, error) {
+	return nil, nil, errors.New("not supported")
+}
+
+func (engine *stateSyncTestEngine) GetNodeMiningPublicKeys() []*incognitokey.CommitteePublicKey {
+	return nil
+}
+
+func stateSyncTestSignature(committee []incognitokey.CommitteePublicKey) string {
+	keys, _ := incognitokey.CommitteeKeyListToString(committee)
+	return strings.Join(keys, ",")
+}
+
+func newStateSyncTestCommittee(seeds ...byte) []incognitokey.CommitteePublicKey {
+	committee := []incognitokey.CommitteePublicKey{}
+	for _, seed := range seeds {
+		privateKey, err := crypto.ToECDSA(common.HashB([]byte{seed}))
+		if err != nil {
+			panic(err)
+		}
+		committee = append(committee, incognitokey.CommitteePublicKey{
+			IncPubKey: []byte{seed},
+			MiningPubKey: map[string][]byte{
+				common.BlsConsensus:    {seed},
+				common.BridgeConsensus: crypto.CompressPubkey(&privateKey.PublicKey),
+			},
+		})
+	}
+	return committee
+}
+
+func Test_checkStateSyncCommittee(t *testing.T) {
+	tests := []struct {
+		name    string
+		anchor  []incognitokey.CommitteePublicKey
+		synced  []incognitokey.CommitteePublicKey
+		wantErr bool
+	}{
+		{name: "same committee", anchor: newStateSyncTestCommittee(1, 2, 3), synced: newStateSyncTestCommittee(1, 2, 3)},
+		{name: "forged member", anchor: newStateSyncTestCommittee(1, 2, 3), synced: newStateSyncTestCommittee(1, 2, 4), wantErr: true},
+		{name: "other order", anchor: newStateSyncTestCommittee(1, 2, 3), synced: newStateSyncTestCommittee(3, 2, 1), wantErr: true},
+		{name: "missing member", anchor: newStateSyncTestCommittee(1, 2, 3), synced: newStateSyncTestCommittee(1, 2), wantErr: true},
+		{name: "no anchor", anchor: newStateSyncTestCommittee(), synced: newStateSyncTestCommittee(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkStateSyncCommittee(tt.anchor, tt.synced); (err != nil) != tt.wantErr {
+				t.Errorf("checkStateSyncCommittee() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newStateSyncTestChain(t *testing.T, finalHeight uint64, committee []incognitokey.CommitteePublicKey) *BlockChain {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_statesync_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := incdb.Open("leveldb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc := &BlockChain{config: Config{
+		ConsensusEngine: &stateSyncTestEngine{},
+		DataBase:        map[int]incdb.Database{common.BeaconChainDataBaseID: db},
+		ChainParams:     &Params{BCHeightBreakPointStateRoot: 100},
+	}}
+	finalView := NewBeaconBestState()
+	finalView.BestBlock = *NewBeaconBlock()
+	finalView.BestBlock.Header.Height = finalHeight
+	finalView.BeaconHeight = finalHeight
+	finalView.BeaconCommittee = committee
+	multiView := multiview.NewMultiView()
+	if !multiView.AddView(finalView) {
+		t.Fatal("add final view failed")
+	}
+	bc.BeaconChain = NewBeaconChain(multiView, nil, bc, common.BeaconChainKey)
+	return bc
+}
+
+// newStateSyncTestSwap returns a block at height signed by signers which swaps
+// the beacon committee to committee
+func newStateSyncTestSwap(t *testing.T, height uint64, signers []incognitokey.CommitteePublicKey, committee []incognitokey.CommitteePublicKey, pending []incognitokey.CommitteePublicKey) []byte {
+	committeeStr, _ := incognitokey.CommitteeKeyListToString(committee)
+	pendingStr, _ := incognitokey.CommitteeKeyListToString(pending)
+	inst, err := buildBeaconSwapConfirmInstruction(committeeStr, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := NewBeaconBlock()
+	block.Header.Height = height
+	block.Header.BeaconCommitteeAndValidatorRoot, _ = generateHashFromStringArray(append(append([]string{}, committeeStr...), pendingStr...))
+	block.Body.Instructions = [][]string{inst}
+	block.ValidationData = stateSyncTestSignature(signers)
+	data, err := json.Marshal(&beaconCommitteeSwap{Block: block, BeaconCommittee: committeeStr, BeaconPendingValidator: pendingStr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestBlockChain_VerifyBeaconCommitteeSwaps(t *testing.T) {
+	BLogger.Init(common.NewBackend(nil).Logger("test", true))
+	genesis := newStateSyncTestCommittee(1, 2, 3)
+	second := newStateSyncTestCommittee(2, 3, 4)
+	third := newStateSyncTestCommittee(4, 5, 6)
+	forged := newStateSyncTestCommittee(7, 8, 9)
+	pending := newStateSyncTestCommittee(10)
+	// the swap confirm instruction lists the committee of the block, which
+	// says another one
+	unconfirmed := newStateSyncTestSwap(t, 100, genesis, second, pending)
+	swap := &beaconCommitteeSwap{}
+	json.Unmarshal(unconfirmed, swap)
+	swap.BeaconCommittee, _ = incognitokey.CommitteeKeyListToString(forged)
+	validators := append(append([]string{}, swap.BeaconCommittee...), swap.BeaconPendingValidator...)
+	swap.Block.Header.BeaconCommitteeAndValidatorRoot, _ = generateHashFromStringArray(validators)
+	unconfirmed, _ = json.Marshal(swap)
+	// the pending validators are not the ones of the committee root
+	swap = &beaconCommitteeSwap{}
+	json.Unmarshal(newStateSyncTestSwap(t, 100, genesis, second, pending), swap)
+	swap.BeaconPendingValidator, _ = incognitokey.CommitteeKeyListToString(forged)
+	otherRoot, _ := json.Marshal(swap)
+	tests := []struct {
+		name          string
+		swaps         [][]byte
+		wantHeight    uint64
+		wantCommittee []incognitokey.CommitteePublicKey
+		wantErr       bool
+	}{
+		{name: "no swap", wantHeight: 10, wantCommittee: genesis},
+		{
+			name:          "two swaps",
+			swaps:         [][]byte{newStateSyncTestSwap(t, 100, genesis, second, pending), newStateSyncTestSwap(t, 200, second, third, pending)},
+			wantHeight:    200,
+			wantCommittee: third,
+		},
+		{name: "swap left out", swaps: [][]byte{newStateSyncTestSwap(t, 200, second, third, pending)}, wantErr: true},
+		{name: "signed by a forged committee", swaps: [][]byte{newStateSyncTestSwap(t, 100, forged, forged, pending)}, wantErr: true},
+		{name: "committee root of other validators", swaps: [][]byte{otherRoot}, wantErr: true},
+		{name: "committee not confirmed", swaps: [][]byte{unconfirmed}, wantErr: true},
+		{name: "swap before the anchor", swaps: [][]byte{newStateSyncTestSwap(t, 5, genesis, second, pending)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := newStateSyncTestChain(t, 10, genesis)
+			anchor, err := bc.VerifyBeaconCommitteeSwaps(bc.NewBeaconStateSyncAnchor(), tt.swaps)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyBeaconCommitteeSwaps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if anchor.Height != tt.wantHeight {
+				t.Errorf("VerifyBeaconCommitteeSwaps() height = %v, want %v", anchor.Height, tt.wantHeight)
+			}
+			if err := checkStateSyncCommittee(tt.wantCommittee, anchor.Committee); err != nil {
+				t.Errorf("VerifyBeaconCommitteeSwaps() committee: %v", err)
+			}
+			// the verified swaps are served to the next peers
+			stored, err := rawdbv2.GetBeaconCommitteeSwaps(bc.GetBeaconChainDatabase(), 0, tt.wantHeight, MaxStateSyncCommitteeSwaps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(stored) != len(tt.swaps) {
+				t.Errorf("stored %v swaps, want %v", len(stored), len(tt.swaps))
+			}
+		})
+	}
+}
+
+func Test_checkStateSyncNextBlock(t *testing.T) {
+	block := NewBeaconBlock()
+	block.Header.Height = 10
+	rootHash := (&BeaconRootHash{ConsensusStateDBRootHash: common.HashH([]byte("consensus"))}).Hash()
+	newNextBlock := func(height uint64, prevHash common.Hash) *BeaconBlock {
+		nextBlock := NewBeaconBlock()
+		nextBlock.Header.Height = height
+		nextBlock.Header.PreviousBlockHash = prevHash
+		return nextBlock
+	}
+	tests := []struct {
+		name              string
+		nextBlock         *BeaconBlock
+		prevStateRootHash common.Hash
+		wantErr           bool
+	}{
+		{name: "next block", nextBlock: newNextBlock(11, *block.Hash()), prevStateRootHash: rootHash},
+		{name: "other parent", nextBlock: newNextBlock(11, common.HashH([]byte("other"))), prevStateRootHash: rootHash, wantErr: true},
+		{name: "other height", nextBlock: newNextBlock(12, *block.Hash()), prevStateRootHash: rootHash, wantErr: true},
+		{name: "no state root", nextBlock: newNextBlock(11, *block.Hash()), wantErr: true},
+		{name: "other state root", nextBlock: newNextBlock(11, *block.Hash()), prevStateRootHash: common.HashH([]byte("other")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkStateSyncNextBlock(block, tt.nextBlock, tt.prevStateRootHash, rootHash); (err != nil) != tt.wantErr {
+				t.Errorf("checkStateSyncNextBlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBlockChain_verifyPrevStateRootHash(t *testing.T) {
+	bc := &BlockChain{config: Config{ChainParams: &Params{BCHeightBreakPointStateRoot: 100}}}
+	rootHash := common.HashH([]byte("roots"))
+	tests := []struct {
+		name              string
+		beaconHeight      uint64
+		prevStateRootHash common.Hash
+		wantErr           bool
+	}{
+		{name: "before break point", beaconHeight: 99},
+		{name: "root before break point", beaconHeight: 99, prevStateRootHash: rootHash, wantErr: true},
+		{name: "root", beaconHeight: 100, prevStateRootHash: rootHash},
+		{name: "no root", beaconHeight: 100, wantErr: true},
+		{name: "other root", beaconHeight: 101, prevStateRootHash: common.HashH([]byte("other")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := bc.verifyPrevStateRootHash(tt.beaconHeight, tt.prevStateRootHash, rootHash); (err != nil) != tt.wantErr {
+				t.Errorf("verifyPrevStateRootHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// a peer sends a beacon view with its own committee and a next block signed by
+// it, the node refuses it before storing anything since its final view has
+// another committee
+func TestBlockChain_installBeaconStateSyncTargetForgedCommittee(t *testing.T) {
+	anchor := newStateSyncTestCommittee(1, 2, 3)
+	forged := newStateSyncTestCommittee(4, 5, 6)
+	bc := newStateSyncTestChain(t, 10, anchor)
+
+	block := NewBeaconBlock()
+	block.Header.Height = 1000
+	block.Header.PreviousBlockHash = common.HashH([]byte("forged"))
+	view := NewBeaconBestState()
+	view.BestBlock = *block
+	view.BestBlockHash = *block.Hash()
+	view.BeaconCommittee = forged
+	nextBlock := NewBeaconBlock()
+	nextBlock.Header.Height = 1001
+	nextBlock.Header.PreviousBlockHash = *block.Hash()
+	nextBlock.Header.PrevStateRootHash = view.GetStateRootHash()
+	nextBlock.ValidationData = stateSyncTestSignature(forged)
+	target := &StateSyncTarget{
+		ChainID:         common.BeaconChainDataBaseID,
+		Height:          block.GetHeight(),
+		BlockHash:       *block.Hash(),
+		beaconView:      view,
+		beaconBlock:     block,
+		nextBeaconBlock: nextBlock,
+	}
+	if err := bc.installBeaconStateSyncTarget(target, bc.NewBeaconStateSyncAnchor()); err == nil {
+		t.Fatal("beacon state signed by a forged committee is installed")
+	}
+	// an anchor verified past the target does not sign it
+	if err := bc.installBeaconStateSyncTarget(target, &StateSyncAnchor{Height: 2000, Committee: forged}); err == nil {
+		t.Fatal("beacon state installed with an anchor after it")
+	}
+	if bc.BeaconChain.GetBestView().GetHeight() != 10 {
+		t.Fatalf("beacon best view moved to height %v", bc.BeaconChain.GetBestView().GetHeight())
+	}
+}
@@ -0,0 +1,92 @@
+package blockchain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+const (
+	FeePriorityTxSelectionPolicy = "feepriority"
+	FIFOTxSelectionPolicy        = "fifo"
+)
+
+// PendingTx is a transaction waiting in the mempool with what a
+// TxSelectionPolicy needs to order it
+type PendingTx struct {
+	Tx      metadata.Transaction
+	ShardID byte
+	// FeePerKB is the fee per KB of the transaction in PRV, a token fee is
+	// converted with the PDE pool price of the token when the transaction
+	// arrives
+	FeePerKB uint64
+	// ArrivalTime is when the transaction reached the mempool
+	ArrivalTime time.Time
+}
+
+// TxSelectionPolicy orders the pending transactions, the block producer picks
+// them in this order until the block is full. The mempool keeps its
+// transactions indexed in this order, ties are broken by arrival order.
+type TxSelectionPolicy interface {
+	Name() string
+	// Less reports whether a is picked before b
+	Less(a, b *PendingTx) bool
+}
+
+// NewTxSelectionPolicy returns the policy with the given name
+func NewTxSelectionPolicy(name string) (TxSelectionPolicy, error) {
+	switch name {
+	case FeePriorityTxSelectionPolicy:
+		return FeePriorityPolicy{}, nil
+	case FIFOTxSelectionPolicy:
+		return FIFOPolicy{}, nil
+	}
+	return nil, fmt.Errorf("unknown tx selection policy %v", name)
+}
+
+// FeePriorityPolicy picks the transactions paying the highest fee per KB first,
+// the oldest first at equal fee
+type FeePriorityPolicy struct{}
+
+func (FeePriorityPolicy) Name() string {
+	return FeePriorityTxSelectionPolicy
+}
+
+func (FeePriorityPolicy) Less(a, b *PendingTx) bool {
+	if a.FeePerKB != b.FeePerKB {
+		return a.FeePerKB > b.FeePerKB
+	}
+	return a.ArrivalTime.Before(b.ArrivalTime)
+}
+
+// FIFOPolicy picks the transactions in arrival order
+type FIFOPolicy struct{}
+
+func (FIFOPolicy) Name() string {
+	return FIFOTxSelectionPolicy
+}
+
+func (FIFOPolicy) Less(a, b *PendingTx) bool {
+	return a.ArrivalTime.Before(b.ArrivalTime)
+}
+
+// GetTxFeePerKBInPRV returns the fee per KB of tx in PRV. A token fee which can
+// not be converted, because the token has no PDE pool with PRV, counts as zero.
+func GetTxFeePerKBInPRV(tx metadata.Transaction, beaconHeight int64, stateDB *statedb.StateDB) uint64 {
+	fee := tx.GetTxFee()
+	if tx.GetType() == common.TxCustomTokenPrivacyType && tx.GetTxFeeToken() > 0 && stateDB != nil {
+		feeToken, err := metadata.ConvertPrivacyTokenToNativeToken(tx.GetTxFeeToken(), tx.GetTokenID(), beaconHeight, stateDB)
+		if err == nil {
+			fee += uint64(math.Ceil(feeToken))
+		}
+	}
+	size := tx.GetTxActualSize()
+	if size == 0 {
+		size = 1
+	}
+	return fee / size
+}
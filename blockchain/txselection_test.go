@@ -0,0 +1,43 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTxSelectionPolicy_Less(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Second)
+	tests := []struct {
+		name   string
+		policy string
+		a      *PendingTx
+		b      *PendingTx
+		want   bool
+	}{
+		{name: "fee priority, higher fee first", policy: FeePriorityTxSelectionPolicy, a: &PendingTx{FeePerKB: 10, ArrivalTime: now}, b: &PendingTx{FeePerKB: 5, ArrivalTime: earlier}, want: true},
+		{name: "fee priority, lower fee after", policy: FeePriorityTxSelectionPolicy, a: &PendingTx{FeePerKB: 5, ArrivalTime: earlier}, b: &PendingTx{FeePerKB: 10, ArrivalTime: now}, want: false},
+		{name: "fee priority, older first at equal fee", policy: FeePriorityTxSelectionPolicy, a: &PendingTx{FeePerKB: 5, ArrivalTime: earlier}, b: &PendingTx{FeePerKB: 5, ArrivalTime: now}, want: true},
+		{name: "fee priority, tie", policy: FeePriorityTxSelectionPolicy, a: &PendingTx{FeePerKB: 5, ArrivalTime: now}, b: &PendingTx{FeePerKB: 5, ArrivalTime: now}, want: false},
+		{name: "fifo, older first whatever the fee", policy: FIFOTxSelectionPolicy, a: &PendingTx{FeePerKB: 1, ArrivalTime: earlier}, b: &PendingTx{FeePerKB: 10, ArrivalTime: now}, want: true},
+		{name: "fifo, newer after", policy: FIFOTxSelectionPolicy, a: &PendingTx{FeePerKB: 10, ArrivalTime: now}, b: &PendingTx{FeePerKB: 1, ArrivalTime: earlier}, want: false},
+		{name: "fifo, tie", policy: FIFOTxSelectionPolicy, a: &PendingTx{FeePerKB: 10, ArrivalTime: now}, b: &PendingTx{FeePerKB: 1, ArrivalTime: now}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewTxSelectionPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if policy.Name() != tt.policy {
+				t.Fatalf("policy %v, want %v", policy.Name(), tt.policy)
+			}
+			if got := policy.Less(tt.a, tt.b); got != tt.want {
+				t.Errorf("Less() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := NewTxSelectionPolicy("random"); err == nil {
+		t.Error("unknown policy should be refused")
+	}
+}
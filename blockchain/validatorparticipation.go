@@ -0,0 +1,277 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+// Number of heights a query of validator participation covers by default and
+// at most
+const (
+	DefaultParticipationHeightRange = 1000
+	MaxParticipationHeightRange     = 10000
+)
+
+// BlockParticipation is the signing participation of the committee of a block,
+// indexed from the validation data of the block the node inserted. It may
+// differ from the voters a later block records for slashing, see
+// ParticipationAction.
+type BlockParticipation struct {
+	Height        uint64
+	Epoch         uint64
+	BlockHash     common.Hash
+	CommitteeHash common.Hash // hash of the committee which signed the block
+	Signers       []int       // indexes in the committee of the validators which signed the block
+}
+
+// ValidatorParticipation is the signing participation of a validator over
+// heights of a chain, the blocks signed by a committee the validator is not a
+// member of are not counted
+type ValidatorParticipation struct {
+	ChainID            int
+	CommitteePublicKey string
+	MiningPubKey       string
+	FromHeight         uint64
+	ToHeight           uint64
+	Expected           uint64
+	Signed             uint64
+	Missed             uint64
+	NotIndexed         uint64 // blocks of the heights the node has no participation of
+	Epochs             []*EpochParticipation
+}
+
+type EpochParticipation struct {
+	Epoch         uint64
+	FromHeight    uint64
+	ToHeight      uint64
+	Expected      uint64
+	Signed        uint64
+	Missed        uint64
+	MissedHeights []uint64
+}
+
+func getParticipationMetricPrefix(chainID int) string {
+	if chainID == common.BeaconChainDataBaseID {
+		return "participation/" + common.BeaconChainKey
+	}
+	return "participation/" + common.GetShardChainKey(byte(chainID))
+}
+
+// indexBlockParticipation stores the participation of committee, which signed
+// block of chainID, in db and counts the signatures and the missed ones of the
+// chain in metrics
+func (blockchain *BlockChain) indexBlockParticipation(db incdb.Database, chainID int, block common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	if len(block.GetValidationField()) == 0 || len(committee) == 0 {
+		return nil
+	}
+	_, validatorsIdx, err := blockchain.config.ConsensusEngine.ExtractBridgeValidationData(block)
+	if err != nil {
+		return err
+	}
+	committeeStr, err := incognitokey.CommitteeKeyListToString(committee)
+	if err != nil {
+		return err
+	}
+	committeeData, err := json.Marshal(committeeStr)
+	if err != nil {
+		return err
+	}
+	committeeHash := common.HashH(committeeData)
+	has, err := rawdbv2.HasParticipationCommittee(db, committeeHash)
+	if err != nil {
+		return err
+	}
+	if !has {
+		if err := rawdbv2.StoreParticipationCommittee(db, committeeHash, committeeData); err != nil {
+			return err
+		}
+	}
+	participation := &BlockParticipation{
+		Height:        block.GetHeight(),
+		Epoch:         block.GetCurrentEpoch(),
+		BlockHash:     *block.Hash(),
+		CommitteeHash: committeeHash,
+		Signers:       validatorsIdx,
+	}
+	participationData, err := json.Marshal(participation)
+	if err != nil {
+		return err
+	}
+	if err := rawdbv2.StoreBlockParticipation(db, chainID, participation.Height, participation.BlockHash, participationData); err != nil {
+		return err
+	}
+
+	// the metrics are per chain, a series per validator would grow with every
+	// committee, the participation of a validator is queried from the index.
+	// Only the mining keys of the node have their own series.
+	prefix := getParticipationMetricPrefix(chainID)
+	metrics.GetOrRegisterGauge(prefix+"/signers", nil).Update(int64(len(validatorsIdx)))
+	metrics.GetOrRegisterGauge(prefix+"/committee", nil).Update(int64(len(committee)))
+	signed := make(map[int]bool)
+	for _, idx := range validatorsIdx {
+		if idx >= 0 && idx < len(committee) {
+			signed[idx] = true
+		}
+	}
+	metrics.GetOrRegisterCounter(prefix+"/signed", nil).Inc(int64(len(signed)))
+	metrics.GetOrRegisterCounter(prefix+"/missed", nil).Inc(int64(len(committee) - len(signed)))
+	for _, nodeKey := range blockchain.config.ConsensusEngine.GetNodeMiningPublicKeys() {
+		blsKey := nodeKey.MiningPubKey[common.BlsConsensus]
+		if len(blsKey) == 0 {
+			continue
+		}
+		for idx := range committee {
+			if !bytes.Equal(committee[idx].MiningPubKey[common.BlsConsensus], blsKey) {
+				continue
+			}
+			nodePrefix := prefix + "/node/" + nodeKey.GetMiningKeyBase58(common.BlsConsensus)
+			if signed[idx] {
+				metrics.GetOrRegisterCounter(nodePrefix+"/signed", nil).Inc(1)
+			} else {
+				metrics.GetOrRegisterCounter(nodePrefix+"/missed", nil).Inc(1)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// GetValidatorParticipation returns the signing participation, per epoch, of
+// the validator of key, its committee public key or its bls mining key in
+// base58, in the blocks of chainID, -1 for the beacon, from fromHeight to
+// toHeight of the best chain. toHeight is the best height if 0 and fromHeight
+// is DefaultParticipationHeightRange heights before toHeight if 0.
+// The participation is summed from the records of the blocks at query time,
+// which bounds the heights to MaxParticipationHeightRange. Totals stored by
+// epoch would keep counting the blocks of the forks the node dropped, the
+// records are looked up by the hashes of the best chain instead.
+func (blockchain *BlockChain) GetValidatorParticipation(chainID int, key string, fromHeight uint64, toHeight uint64) (*ValidatorParticipation, error) {
+	var db incdb.Database
+	var finalView, bestView multiview.View
+	var getBlockHashByHeight func(finalView, bestView multiview.View, height uint64) (*common.Hash, error)
+	if chainID == common.BeaconChainDataBaseID {
+		db = blockchain.GetBeaconChainDatabase()
+		finalView, bestView = blockchain.BeaconChain.GetFinalView(), blockchain.BeaconChain.GetBestView()
+		getBlockHashByHeight = blockchain.GetBeaconBlockHashByHeight
+	} else {
+		if chainID < 0 || chainID >= blockchain.GetActiveShardNumber() {
+			return nil, fmt.Errorf("chain %v not found", chainID)
+		}
+		db = blockchain.GetShardChainDatabase(byte(chainID))
+		finalView, bestView = blockchain.ShardChain[chainID].GetFinalView(), blockchain.ShardChain[chainID].GetBestView()
+		getBlockHashByHeight = blockchain.GetShardBlockHashByHeight
+	}
+	if toHeight == 0 {
+		toHeight = bestView.GetHeight()
+	}
+	if fromHeight == 0 {
+		fromHeight = 1
+		if toHeight > DefaultParticipationHeightRange {
+			fromHeight = toHeight - DefaultParticipationHeightRange + 1
+		}
+	}
+	if fromHeight > toHeight {
+		return nil, fmt.Errorf("invalid heights from %v to %v", fromHeight, toHeight)
+	}
+	if toHeight > bestView.GetHeight() {
+		return nil, fmt.Errorf("height %v is above best height %v", toHeight, bestView.GetHeight())
+	}
+	if toHeight-fromHeight >= MaxParticipationHeightRange {
+		return nil, fmt.Errorf("heights from %v to %v are more than %v", fromHeight, toHeight, MaxParticipationHeightRange)
+	}
+
+	result := &ValidatorParticipation{
+		ChainID:    chainID,
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+		Epochs:     []*EpochParticipation{},
+	}
+	memberIdx := make(map[common.Hash]int) // committee hash -> index of the validator, -1 if not a member
+	for height := fromHeight; height <= toHeight; height++ {
+		hash, err := getBlockHashByHeight(finalView, bestView, height)
+		if err != nil {
+			return nil, err
+		}
+		participationData, err := rawdbv2.GetBlockParticipation(db, chainID, height, *hash)
+		if err != nil {
+			result.NotIndexed++
+			continue
+		}
+		participation := &BlockParticipation{}
+		if err := json.Unmarshal(participationData, participation); err != nil {
+			return nil, err
+		}
+		idx, ok := memberIdx[participation.CommitteeHash]
+		if !ok {
+			if idx, err = blockchain.getParticipationMemberIndex(db, participation.CommitteeHash, key, result); err != nil {
+				return nil, err
+			}
+			memberIdx[participation.CommitteeHash] = idx
+		}
+		if idx == -1 {
+			continue
+		}
+		var epoch *EpochParticipation
+		if len(result.Epochs) != 0 && result.Epochs[len(result.Epochs)-1].Epoch == participation.Epoch {
+			epoch = result.Epochs[len(result.Epochs)-1]
+		} else {
+			epoch = &EpochParticipation{Epoch: participation.Epoch, FromHeight: height, MissedHeights: []uint64{}}
+			result.Epochs = append(result.Epochs, epoch)
+		}
+		epoch.ToHeight = height
+		epoch.Expected++
+		result.Expected++
+		isSigned := false
+		for _, signer := range participation.Signers {
+			if signer == idx {
+				isSigned = true
+				break
+			}
+		}
+		if isSigned {
+			epoch.Signed++
+			result.Signed++
+		} else {
+			epoch.Missed++
+			result.Missed++
+			epoch.MissedHeights = append(epoch.MissedHeights, height)
+		}
+	}
+	return result, nil
+}
+
+// getParticipationMemberIndex returns the index of the validator of key in
+// the committee of committeeHash, -1 if it is not a member, and sets its keys
+// in result
+func (blockchain *BlockChain) getParticipationMemberIndex(db incdb.Database, committeeHash common.Hash, key string, result *ValidatorParticipation) (int, error) {
+	committeeData, err := rawdbv2.GetParticipationCommittee(db, committeeHash)
+	if err != nil {
+		return -1, err
+	}
+	committeeStr := []string{}
+	if err := json.Unmarshal(committeeData, &committeeStr); err != nil {
+		return -1, err
+	}
+	for i, memberStr := range committeeStr {
+		member := incognitokey.CommitteePublicKey{}
+		if err := member.FromBase58(memberStr); err != nil {
+			return -1, errors.New("invalid committee of participation")
+		}
+		miningKey := member.GetMiningKeyBase58(common.BlsConsensus)
+		if memberStr == key || miningKey == key {
+			result.CommitteePublicKey = memberStr
+			result.MiningPubKey = miningKey
+			return i, nil
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,246 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/metrics"
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+// participationTestDB is an in-memory database with the methods the
+// participation index uses, the others panic
+type participationTestDB struct {
+	incdb.Database
+	data map[string][]byte
+}
+
+func newParticipationTestDB() *participationTestDB {
+	return &participationTestDB{data: make(map[string][]byte)}
+}
+
+func (db *participationTestDB) Has(key []byte) (bool, error) {
+	_, ok := db.data[string(key)]
+	return ok, nil
+}
+
+func (db *participationTestDB) Get(key []byte) ([]byte, error) {
+	value, ok := db.data[string(key)]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return value, nil
+}
+
+func (db *participationTestDB) Put(key []byte, value []byte) error {
+	db.data[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (db *participationTestDB) Delete(key []byte) error {
+	delete(db.data, string(key))
+	return nil
+}
+
+func newValidatorParticipationTestBlock(height uint64, epoch uint64, validationData string) *BeaconBlock {
+	block := NewBeaconBlock()
+	block.Header.Height = height
+	block.Header.Epoch = epoch
+	block.ValidationData = validationData
+	return block
+}
+
+func TestBlockChain_indexBlockParticipation(t *testing.T) {
+	db := newParticipationTestDB()
+	bc := &BlockChain{config: Config{ConsensusEngine: &participationTestEngine{}}}
+	committee := newStateSyncTestCommittee(1, 2, 3)
+	block := newValidatorParticipationTestBlock(5, 1, "0,2")
+	if err := bc.indexBlockParticipation(db, common.BeaconChainDataBaseID, block, committee); err != nil {
+		t.Fatal(err)
+	}
+	participationData, err := rawdbv2.GetBlockParticipation(db, common.BeaconChainDataBaseID, 5, *block.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	participation := &BlockParticipation{}
+	if err := json.Unmarshal(participationData, participation); err != nil {
+		t.Fatal(err)
+	}
+	if participation.Height != 5 || participation.Epoch != 1 || participation.BlockHash != *block.Hash() || !reflect.DeepEqual(participation.Signers, []int{0, 2}) {
+		t.Errorf("indexed participation %+v, want the signers 0 and 2 of block 5", participation)
+	}
+	committeeData, err := rawdbv2.GetParticipationCommittee(db, participation.CommitteeHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	committeeStr := []string{}
+	if err := json.Unmarshal(committeeData, &committeeStr); err != nil {
+		t.Fatal(err)
+	}
+	if len(committeeStr) != len(committee) {
+		t.Errorf("indexed committee of %v members, want %v", len(committeeStr), len(committee))
+	}
+	// the blocks signed by the same committee share the stored committee
+	nextBlock := newValidatorParticipationTestBlock(6, 1, "1")
+	if err := bc.indexBlockParticipation(db, common.BeaconChainDataBaseID, nextBlock, committee); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.data) != 3 {
+		t.Errorf("%v records, want 2 blocks and a committee", len(db.data))
+	}
+	// a block without validation data is not indexed
+	if err := bc.indexBlockParticipation(db, common.BeaconChainDataBaseID, newValidatorParticipationTestBlock(7, 1, ""), committee); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.data) != 3 {
+		t.Errorf("%v records, want 3", len(db.data))
+	}
+}
+
+// the signatures and the missed ones of the mining keys of the node in the
+// committees are counted in their own metrics
+func TestBlockChain_indexBlockParticipationNodeMetrics(t *testing.T) {
+	db := newParticipationTestDB()
+	nodeKeys := newStateSyncTestCommittee(2, 5)
+	bc := &BlockChain{config: Config{ConsensusEngine: &participationTestEngine{
+		nodeKeys: []*incognitokey.CommitteePublicKey{&nodeKeys[0], &nodeKeys[1]},
+	}}}
+	committee := newStateSyncTestCommittee(1, 2, 3)
+	chainID := 3
+	for height, validationData := range []string{"0,2", "1", "0,1,2"} {
+		block := NewShardBlock()
+		block.Header.ShardID = byte(chainID)
+		block.Header.Height = uint64(height + 1)
+		block.ValidationData = validationData
+		if err := bc.indexBlockParticipation(db, chainID, block, committee); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prefix := getParticipationMetricPrefix(chainID) + "/node/"
+	member := prefix + nodeKeys[0].GetMiningKeyBase58(common.BlsConsensus)
+	if signed := metrics.GetOrRegisterCounter(member+"/signed", nil).Count(); signed != 2 {
+		t.Errorf("%v signed blocks counted, want 2", signed)
+	}
+	if missed := metrics.GetOrRegisterCounter(member+"/missed", nil).Count(); missed != 1 {
+		t.Errorf("%v missed blocks counted, want 1", missed)
+	}
+	notMember := prefix + nodeKeys[1].GetMiningKeyBase58(common.BlsConsensus)
+	if metrics.DefaultRegistry.Get(notMember+"/signed") != nil || metrics.DefaultRegistry.Get(notMember+"/missed") != nil {
+		t.Error("metrics of a key of the node out of the committee")
+	}
+}
+
+// newValidatorParticipationTestChain indexes beacon blocks 1 to 4, blocks 1
+// and 2 of epoch 1 are signed by the members 1, 2 and 3, blocks 3 and 4 of
+// epoch 2 by the members 1, 2 and 4. Block 2 is not indexed.
+func newValidatorParticipationTestChain(t *testing.T) *BlockChain {
+	db := newParticipationTestDB()
+	bc := &BlockChain{config: Config{
+		ConsensusEngine: &participationTestEngine{},
+		DataBase:        map[int]incdb.Database{common.BeaconChainDataBaseID: db},
+	}}
+	blocks := []*BeaconBlock{
+		newValidatorParticipationTestBlock(1, 1, "0,1"),
+		newValidatorParticipationTestBlock(2, 1, "0,1,2"),
+		newValidatorParticipationTestBlock(3, 2, "0,2"),
+		newValidatorParticipationTestBlock(4, 2, "1"),
+	}
+	committees := [][]byte{{1, 2, 3}, {1, 2, 3}, {1, 2, 4}, {1, 2, 4}}
+	for i, block := range blocks {
+		if i > 0 {
+			block.Header.PreviousBlockHash = *blocks[i-1].Hash()
+		}
+		if err := rawdbv2.StoreFinalizedBeaconBlockHashByIndex(db, block.GetHeight(), *block.Hash()); err != nil {
+			t.Fatal(err)
+		}
+		if block.GetHeight() == 2 {
+			continue
+		}
+		if err := bc.indexBlockParticipation(db, common.BeaconChainDataBaseID, block, newStateSyncTestCommittee(committees[i]...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	view := NewBeaconBestState()
+	view.BestBlock = *blocks[len(blocks)-1]
+	multiView := multiview.NewMultiView()
+	if !multiView.AddView(view) {
+		t.Fatal("add view failed")
+	}
+	bc.BeaconChain = NewBeaconChain(multiView, nil, bc, common.BeaconChainKey)
+	return bc
+}
+
+func TestBlockChain_GetValidatorParticipation(t *testing.T) {
+	bc := newValidatorParticipationTestChain(t)
+	members := newStateSyncTestCommittee(2, 3)
+	member2, err := members[0].ToBase58()
+	if err != nil {
+		t.Fatal(err)
+	}
+	member3, err := members[1].ToBase58()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		key        string
+		fromHeight uint64
+		toHeight   uint64
+		want       *ValidatorParticipation
+		wantErr    bool
+	}{
+		{
+			name: "member of both committees",
+			key:  member2,
+			want: &ValidatorParticipation{
+				ChainID: common.BeaconChainDataBaseID, CommitteePublicKey: member2, MiningPubKey: members[0].GetMiningKeyBase58(common.BlsConsensus),
+				FromHeight: 1, ToHeight: 4, Expected: 3, Signed: 2, Missed: 1, NotIndexed: 1,
+				Epochs: []*EpochParticipation{
+					{Epoch: 1, FromHeight: 1, ToHeight: 1, Expected: 1, Signed: 1, MissedHeights: []uint64{}},
+					{Epoch: 2, FromHeight: 3, ToHeight: 4, Expected: 2, Signed: 1, Missed: 1, MissedHeights: []uint64{3}},
+				},
+			},
+		},
+		{
+			name: "member by its mining key",
+			key:  members[1].GetMiningKeyBase58(common.BlsConsensus),
+			want: &ValidatorParticipation{
+				ChainID: common.BeaconChainDataBaseID, CommitteePublicKey: member3, MiningPubKey: members[1].GetMiningKeyBase58(common.BlsConsensus),
+				FromHeight: 1, ToHeight: 4, Expected: 1, Missed: 1, NotIndexed: 1,
+				Epochs: []*EpochParticipation{
+					{Epoch: 1, FromHeight: 1, ToHeight: 1, Expected: 1, Missed: 1, MissedHeights: []uint64{1}},
+				},
+			},
+		},
+		{
+			name:       "heights of an epoch",
+			key:        member3,
+			fromHeight: 3,
+			toHeight:   4,
+			want: &ValidatorParticipation{
+				ChainID: common.BeaconChainDataBaseID, FromHeight: 3, ToHeight: 4, Epochs: []*EpochParticipation{},
+			},
+		},
+		{name: "from height above to height", key: member2, fromHeight: 4, toHeight: 3, wantErr: true},
+		{name: "to height above best height", key: member2, fromHeight: 1, toHeight: 5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bc.GetValidatorParticipation(common.BeaconChainDataBaseID, tt.key, tt.fromHeight, tt.toHeight)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetValidatorParticipation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				gotData, _ := json.Marshal(got)
+				wantData, _ := json.Marshal(tt.want)
+				t.Errorf("GetValidatorParticipation() = %s, want %s", gotData, wantData)
+			}
+		})
+	}
+}
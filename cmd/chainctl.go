@@ -21,17 +21,17 @@ import (
 	"github.com/incognitochain/incognito-chain/pubsub"
 )
 
-func makeBlockChain(databaseDir string, testNet bool) (*blockchain.BlockChain, error) {
+func makeBlockChain(databaseDir string, dbType string, testNet bool) (*blockchain.BlockChain, error) {
 	blockchain.Logger.Init(common.NewBackend(nil).Logger("ChainCMD", true))
 	blockchain.BLogger.Init(common.NewBackend(nil).Logger("ChainCMD", true))
 	mempool.Logger.Init(common.NewBackend(nil).Logger("ChainCMD", true))
 	dataaccessobject.Logger.Init(common.NewBackend(nil).Logger("ChainCMD", true))
 	trie.Logger.Init(common.NewBackend(nil).Logger("ChainCMD", true))
-	db, err := incdb.Open("leveldb", filepath.Join(databaseDir))
+	db, err := incdb.OpenMultipleDB(dbType, filepath.Join(databaseDir))
 	if err != nil {
 		return nil, err
 	}
-	log.Printf("Open leveldb at %+v successfully", filepath.Join(databaseDir))
+	log.Printf("Open %+v at %+v successfully", dbType, filepath.Join(databaseDir))
 	bc := blockchain.NewBlockChain(&blockchain.Config{}, false)
 	var bcParams *blockchain.Params
 	if testNet {
@@ -39,6 +39,9 @@ type params struct {
 	ChainDataDir string `long:"chaindatadir" description:"Directory of Stored Blockchain Database"`
 	OutDataDir   string `long:"outdatadir" description:"Directory of Export Blockchain Data"`
 	FileName     string `long:"filename" description:"Filename of Backup Blockchin Data"`
+	DBType       string `long:"dbtype" description:"Database driver of the Stored Blockchain Database to backup or restore {leveldb, badgerdb}"`
+	FromDBType   string `long:"fromdbtype" description:"Database driver of the Stored Blockchain Database"`
+	ToDBType     string `long:"todbtype" description:"Database driver of the Migrated Blockchain Database"`
 	// wallet
 	WalletName        string `long:"wallet" description:"Wallet Database Name file, default is 'wallet'"`
 	WalletPassphrase  string `long:"walletpassphrase" description:"Wallet passphrase"`
@@ -58,8 +61,11 @@ func newConfigParser(cfg *params, options flags.Options) *flags.Parser {
 
 func loadParams() (*params, error) {
 	cfg := params{
-		DataDir: defaultDataDir,
-		TestNet: false,
+		DataDir:    defaultDataDir,
+		TestNet:    false,
+		DBType:     "leveldb",
+		FromDBType: "leveldb",
+		ToDBType:   "badgerdb",
 	}
 
 	preParser := newConfigParser(&cfg, flags.HelpFlag)
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/badgerdb"
+	"github.com/pkg/errors"
+)
+
+// migrationProgressFile is stored in the destination directory and lets an
+// interrupted migration resume where it stopped
+const migrationProgressFile = "migration-progress.json"
+
+// chainMigrationProgress records how far the database of one chain has been
+// copied. Count and Checksum cover every key up to and including LastKey.
+type chainMigrationProgress struct {
+	LastKey  []byte
+	Count    uint64
+	Checksum common.Hash
+	Copied   bool
+	Verified bool
+}
+
+type migrationProgress struct {
+	FromDBType string
+	ToDBType   string
+	Chains     map[string]*chainMigrationProgress
+}
+
+// errMigrationInterrupted is returned when the migration is stopped by a
+// signal, the progress is saved and running the command again resumes it
+var errMigrationInterrupted = errors.New("migration interrupted")
+
+// migrationChainDirs returns the database directory names created by
+// incdb.OpenMultipleDB, beacon first then every shard
+func migrationChainDirs(numberOfShards int) []string {
+	dirs := []string{common.BeaconChainDatabaseDirectory}
+	for i := 0; i < numberOfShards; i++ {
+		dirs = append(dirs, common.ShardChainDatabaseDirectory+strconv.Itoa(i))
+	}
+	return dirs
+}
+
+func loadMigrationProgress(outDataDir string, fromDBType string, toDBType string) (*migrationProgress, error) {
+	progress := &migrationProgress{
+		FromDBType: fromDBType,
+		ToDBType:   toDBType,
+		Chains:     make(map[string]*chainMigrationProgress),
+	}
+	data, err := ioutil.ReadFile(filepath.Join(outDataDir, migrationProgressFile))
+	if os.IsNotExist(err) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, errors.Wrap(err, "invalid migration progress file")
+	}
+	if progress.FromDBType != fromDBType || progress.ToDBType != toDBType {
+		return nil, fmt.Errorf("migration in %+v was started from %+v to %+v", outDataDir, progress.FromDBType, progress.ToDBType)
+	}
+	return progress, nil
+}
+
+func (progress *migrationProgress) save(outDataDir string) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	// write then rename so a crash never leaves a truncated progress file
+	file := filepath.Join(outDataDir, migrationProgressFile)
+	if err := ioutil.WriteFile(file+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(file+".tmp", file)
+}
+
+// updateMigrationChecksum chains the previous checksum with a length prefixed
+// key/value pair. Both drivers iterate in the same binary order, so source and
+// destination produce the same checksum when they hold the same data.
+func updateMigrationChecksum(checksum common.Hash, key []byte, value []byte) common.Hash {
+	data := make([]byte, 0, common.HashSize+16+len(key)+len(value))
+	data = append(data, checksum[:]...)
+	data = appendWithLength(data, key)
+	data = appendWithLength(data, value)
+	return common.HashH(data)
+}
+
+func appendWithLength(data []byte, b []byte) []byte {
+	length := make([]byte, 8)
+	binary.BigEndian.PutUint64(length, uint64(len(b)))
+	return append(append(data, length...), b...)
+}
+
+func migrateDatabase(chainDataDir string, outDataDir string, fromDBType string, toDBType string, chainDirs []string, stop <-chan struct{}) error {
+	if fromDBType == toDBType {
+		return fmt.Errorf("source and destination database type are both %+v", fromDBType)
+	}
+	if err := os.MkdirAll(outDataDir, 0700); err != nil {
+		return err
+	}
+	progress, err := loadMigrationProgress(outDataDir, fromDBType, toDBType)
+	if err != nil {
+		return err
+	}
+	for _, chainDir := range chainDirs {
+		srcPath := filepath.Join(chainDataDir, chainDir)
+		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
+			log.Printf("Skip %+v, no database at %+v", chainDir, srcPath)
+			continue
+		}
+		chainProgress, ok := progress.Chains[chainDir]
+		if !ok {
+			chainProgress = &chainMigrationProgress{}
+			progress.Chains[chainDir] = chainProgress
+		}
+		if chainProgress.Verified {
+			log.Printf("Skip %+v, already migrated %+v keys", chainDir, chainProgress.Count)
+			continue
+		}
+		if err := migrateChainDatabase(srcPath, filepath.Join(outDataDir, chainDir), progress, chainProgress, outDataDir, stop); err != nil {
+			return errors.Wrapf(err, "migrate %+v", chainDir)
+		}
+	}
+	return nil
+}
+
+func migrateChainDatabase(srcPath string, dstPath string, progress *migrationProgress, chainProgress *chainMigrationProgress, outDataDir string, stop <-chan struct{}) error {
+	src, err := incdb.Open(progress.FromDBType, srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := incdb.Open(progress.ToDBType, dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if !chainProgress.Copied {
+		if err := copyChainDatabase(src, dst, progress, chainProgress, outDataDir, stop); err != nil {
+			return err
+		}
+	}
+	count, checksum, err := databaseChecksum(dst)
+	if err != nil {
+		return err
+	}
+	if count != chainProgress.Count || checksum != chainProgress.Checksum {
+		return fmt.Errorf("verify %+v failed, copied %+v keys with checksum %+v but found %+v keys with checksum %+v",
+			dstPath, chainProgress.Count, chainProgress.Checksum.String(), count, checksum.String())
+	}
+	chainProgress.Verified = true
+	log.Printf("Migrated %+v to %+v, %+v keys, checksum %+v", srcPath, dstPath, count, checksum.String())
+	return progress.save(outDataDir)
+}
+
+func copyChainDatabase(src incdb.Database, dst incdb.Database, progress *migrationProgress, chainProgress *chainMigrationProgress, outDataDir string, stop <-chan struct{}) error {
+	resume := chainProgress.LastKey != nil
+	if !resume {
+		// a fresh copy must not be mixed with data already in the destination,
+		// otherwise the verification could never pass
+		iter := dst.NewIterator()
+		notEmpty := iter.Next()
+		iter.Release()
+		if notEmpty {
+			return errors.New("destination database is not empty")
+		}
+	}
+	var iter incdb.Iterator
+	if resume {
+		iter = src.NewIteratorWithStart(chainProgress.LastKey)
+	} else {
+		iter = src.NewIteratorWithPrefix([]byte{})
+	}
+	defer iter.Release()
+
+	batch := dst.NewBatch()
+	// pending holds the progress once batch is written, chainProgress is only
+	// updated after a successful write so a crash replays the unwritten batch
+	pending := *chainProgress
+	flush := func() error {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		*chainProgress = pending
+		return progress.save(outDataDir)
+	}
+	for iter.Next() {
+		key := iter.Key()
+		if resume && string(key) == string(chainProgress.LastKey) {
+			continue
+		}
+		value := iter.Value()
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+		pending.LastKey = append([]byte{}, key...)
+		pending.Count++
+		pending.Checksum = updateMigrationChecksum(pending.Checksum, key, value)
+		if batch.ValueSize() >= incdb.IdealBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+			select {
+			case <-stop:
+				return errMigrationInterrupted
+			default:
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	pending.Copied = true
+	return flush()
+}
+
+func databaseChecksum(db incdb.Database) (uint64, common.Hash, error) {
+	count := uint64(0)
+	checksum := common.Hash{}
+	iter := db.NewIteratorWithPrefix([]byte{})
+	defer iter.Release()
+	for iter.Next() {
+		count++
+		checksum = updateMigrationChecksum(checksum, iter.Key(), iter.Value())
+	}
+	return count, checksum, iter.Error()
+}
+
+func migrateDB(chainDataDir string, outDataDir string, fromDBType string, toDBType string, chainDirs []string) error {
+	// Watch for Ctrl-C while the migration is running.
+	// If a signal is received, the migration will stop after the current batch.
+	interrupt := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	// deferred calls run in reverse, stop the notifications before closing
+	// the channel they are sent to
+	defer close(interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Println("Interrupted during migration, stopping at next batch")
+		}
+		close(stop)
+	}()
+	return migrateDatabase(chainDataDir, outDataDir, fromDBType, toDBType, chainDirs, stop)
+}
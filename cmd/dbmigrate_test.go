@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareMigrationSource(t *testing.T, chainDataDir string, chainDir string, numberOfKeys int) {
+	db, err := incdb.Open("leveldb", filepath.Join(chainDataDir, chainDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for i := 0; i < numberOfKeys; i++ {
+		// values are big enough to spread the copy over several batches
+		value := make([]byte, 4096)
+		copy(value, fmt.Sprintf("%s-%d", chainDir, i))
+		if err := db.Put([]byte(fmt.Sprintf("key-%06d", i)), value); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMigrateDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "migrate_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	chainDataDir := filepath.Join(dir, "block")
+	outDataDir := filepath.Join(dir, "block-badger")
+	prepareMigrationSource(t, chainDataDir, "beacon", 100)
+	prepareMigrationSource(t, chainDataDir, "shard0", 80)
+	chainDirs := migrationChainDirs(2)
+	assert.Equal(t, []string{"beacon", "shard0", "shard1"}, chainDirs)
+
+	// interrupt right after the first batch
+	stop := make(chan struct{})
+	close(stop)
+	err = migrateDatabase(chainDataDir, outDataDir, "leveldb", "badgerdb", chainDirs, stop)
+	assert.Equal(t, errMigrationInterrupted, errors.Cause(err))
+	progress, err := loadMigrationProgress(outDataDir, "leveldb", "badgerdb")
+	assert.Equal(t, nil, err)
+	assert.True(t, progress.Chains["beacon"].Count > 0)
+	assert.True(t, progress.Chains["beacon"].Count < 100)
+	assert.False(t, progress.Chains["beacon"].Copied)
+
+	_, err = loadMigrationProgress(outDataDir, "leveldb", "leveldb")
+	assert.NotEqual(t, nil, err)
+
+	// resume
+	err = migrateDatabase(chainDataDir, outDataDir, "leveldb", "badgerdb", chainDirs, make(chan struct{}))
+	assert.Equal(t, nil, err)
+	progress, err = loadMigrationProgress(outDataDir, "leveldb", "badgerdb")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(100), progress.Chains["beacon"].Count)
+	assert.Equal(t, uint64(80), progress.Chains["shard0"].Count)
+	assert.True(t, progress.Chains["shard0"].Verified)
+	_, ok := progress.Chains["shard1"]
+	assert.False(t, ok)
+
+	for _, chainDir := range []string{"beacon", "shard0"} {
+		src, err := incdb.Open("leveldb", filepath.Join(chainDataDir, chainDir))
+		assert.Equal(t, nil, err)
+		dst, err := incdb.Open("badgerdb", filepath.Join(outDataDir, chainDir))
+		assert.Equal(t, nil, err)
+		srcCount, srcChecksum, err := databaseChecksum(src)
+		assert.Equal(t, nil, err)
+		dstCount, dstChecksum, err := databaseChecksum(dst)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, srcCount, dstCount)
+		assert.Equal(t, srcChecksum, dstChecksum)
+		src.Close()
+		dst.Close()
+	}
+
+	// a finished migration is a no-op
+	err = migrateDatabase(chainDataDir, outDataDir, "leveldb", "badgerdb", chainDirs, make(chan struct{}))
+	assert.Equal(t, nil, err)
+}
+
+func TestMigrateDatabaseNotEmptyDestination(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "migrate_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	chainDataDir := filepath.Join(dir, "block")
+	outDataDir := filepath.Join(dir, "block-badger")
+	prepareMigrationSource(t, chainDataDir, "beacon", 1)
+	dst, err := incdb.Open("badgerdb", filepath.Join(outDataDir, "beacon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, nil, dst.Put([]byte("a"), []byte("a")))
+	dst.Close()
+
+	err = migrateDatabase(chainDataDir, outDataDir, "leveldb", "badgerdb", []string{"beacon"}, make(chan struct{}))
+	assert.NotEqual(t, nil, err)
+}
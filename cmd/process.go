@@ -130,7 +130,7 @@ func processCmd() {
 				log.Println("No Expected Params")
 				return
 			}
-			bc, err := makeBlockChain(cfg.ChainDataDir, cfg.TestNet)
+			bc, err := makeBlockChain(cfg.ChainDataDir, cfg.DBType, cfg.TestNet)
 			if err != nil {
 				log.Println("Error create blockchain variable ", err)
 				return
@@ -193,7 +193,7 @@ func processCmd() {
 				log.Println("No Backup File to Process")
 				return
 			}
-			bc, err := makeBlockChain(cfg.ChainDataDir, cfg.TestNet)
+			bc, err := makeBlockChain(cfg.ChainDataDir, cfg.DBType, cfg.TestNet)
 			if err != nil {
 				log.Println("Error create blockchain variable ", err)
 				return
@@ -213,5 +213,22 @@ func processCmd() {
 				}
 			}
 		}
+	case migrateDatabaseCmd:
+		{
+			if cfg.ChainDataDir == "" || cfg.OutDataDir == "" {
+				log.Println("No Expected Params")
+				return
+			}
+			var numberOfShards int
+			if cfg.TestNet {
+				numberOfShards = blockchain.ChainTestParam.ActiveShards
+			} else {
+				numberOfShards = blockchain.ChainMainParam.ActiveShards
+			}
+			err := migrateDB(cfg.ChainDataDir, cfg.OutDataDir, cfg.FromDBType, cfg.ToDBType, migrationChainDirs(numberOfShards))
+			if err != nil {
+				log.Printf("Migrate Database failed, err %+v", err)
+			}
+		}
 	}
 }
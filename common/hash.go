@@ -44,7 +44,7 @@ func (hashObj *Hash) Format(f fmt.State, c rune) {
 		m := "%"
 		for i := 0; i < 128; i++ {
 			if f.Flag(i) {
-				m += string(i)
+				m += string(rune(i))
 			}
 		}
 		m += string(c)
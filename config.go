@@ -15,7 +15,9 @@ import (
 	"strings"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/pubsub"
 	"github.com/jessevdk/go-flags"
 )
 
@@ -24,7 +26,10 @@ const (
 	DefaultConfigFilename              = "config.conf"
 	DefaultDataDirname                 = "data"
 	DefaultDatabaseDirname             = "block"
+	DefaultDatabaseType                = "leveldb"
 	DefaultDatabaseMempoolDirname      = "mempool"
+	DefaultTxSelectionPolicy           = blockchain.FeePriorityTxSelectionPolicy
+	DefaultTxVerifyBatchSize           = 16
 	DefaultLogLevel                    = "info"
 	DefaultLogDirname                  = "logs"
 	DefaultLogFilename                 = "log.log"
@@ -38,6 +43,8 @@ const (
 	DefaultRPCLimitRequestPerDay       = 0 // 0: unlimited
 	DefaultRPCLimitErrorRequestPerHour = 0 // 0: unlimited
 	DefaultMaxRPCWsClients             = 200
+	DefaultRPCWsQueueSize              = pubsub.ChanWorkLoad
+	DefaultRPCWsSlowPolicy             = "dropoldest"
 	DefaultMetricUrl                   = ""
 	SampleConfigFilename               = "sample-config.conf"
 	DefaultDisableRpcTLS               = true
@@ -75,6 +82,8 @@ type config struct {
 	ConfigFile         string `short:"C" long:"configfile" description:"Path to configuration file"`
 	DataDir            string `short:"D" long:"datadir" description:"Directory to store data"`
 	DatabaseDir        string `short:"d" long:"datapre" description:"Database dir"`
+	DatabaseType       string `long:"dbtype" description:"Database driver used for block and state data {leveldb, badgerdb}"`
+	PruneState         uint64 `long:"prunestate" description:"Keep the state of the last N finalized views only, older states except epoch checkpoints are pruned (0 disables pruning)"`
 	DatabaseMempoolDir string `short:"m" long:"datamempool" description:"Mempool Database Dir"`
 	LogDir             string `short:"l" long:"logdir" description:"Directory to log output."`
 	LogLevel           string `long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
@@ -109,6 +118,8 @@ type config struct {
 	RPCLimitRequestErrorPerHour int      `long:"rpclimitrequesterrorperhour" description:"Max request error per hour by remote address"`
 	RPCMaxClients               int      `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
 	RPCMaxWSClients             int      `long:"rpcmaxwsclients" description:"Max number of RPC clients for standard connections"`
+	RPCWSQueueSize              int      `long:"rpcwsqueuesize" description:"Max number of events queued for a websocket subscription"`
+	RPCWSSlowPolicy             string   `long:"rpcwsslowpolicy" description:"Policy when the queue of a websocket subscription is full: dropoldest, dropnewest or block (unsubscribe if still full after a timeout)"`
 	RPCQuirks                   bool     `long:"rpcquirks" description:"Mirror some JSON-RPC quirks of coin Core -- NOTE: Discouraged unless interoperability issues need to be worked around"`
 	DisableRPC                  bool     `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
 	DisableTLS                  bool     `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
@@ -137,6 +148,15 @@ type config struct {
 	TxPoolMaxTx uint64 `long:"txpoolmaxtx" description:"Set Maximum number of transaction in pool"`
 	LimitFee    uint64 `long:"limitfee" description:"Limited fee for tx(per Kb data), default is 0.00 PRV"`
 
+	TxPoolMaxShardTx   uint64 `long:"txpoolmaxshardtx" description:"Maximum number of transactions of a shard in pool, the lowest fee per KB are evicted first (0 for no limit)"`
+	TxPoolMaxShardSize uint64 `long:"txpoolmaxshardsize" description:"Maximum size in KB of the transactions of a shard in pool, the lowest fee per KB are evicted first (0 for no limit)"`
+	TxPoolMaxSenderTx  uint64 `long:"txpoolmaxsendertx" description:"Maximum number of transactions of a sender in pool (0 for no limit)"`
+
+	TxSelectionPolicy string `long:"txselectionpolicy" description:"Order in which pending transactions are picked for a new block {feepriority, fifo}"`
+
+	TxVerifyWorkers   int `long:"txverifyworkers" description:"Number of workers verifying the proofs of new transactions in parallel (0 for the number of CPUs, -1 to verify them one by one under the lock of mempool)"`
+	TxVerifyBatchSize int `long:"txverifybatchsize" description:"Maximum number of transactions of which the proofs are verified in a batch"`
+
 	LoadMempool       bool   `long:"loadmempool" description:"Load transactions from Mempool database"`
 	PersistMempool    bool   `long:"persistmempool" description:"Persistence transaction in memepool database"`
 	MetricUrl         string `long:"metricurl" description:"Metric URL"`
@@ -148,6 +168,7 @@ type config struct {
 	EnableMining      bool   `long:"mining" description:"enable mining"`
 	MiningKeys        string `long:"miningkeys" description:"keys used for different consensus algorigthm"`
 	PrivateKey        string `long:"privatekey" description:"your wallet privatekey"`
+	RemoteSigner      string `long:"remotesigner" description:"Address of the signer process holding the mining key instead of miningkeys, unix:///path/to/socket (consensus v2 only)"`
 	Accelerator       bool   `long:"accelerator" description:"Relay Node Configuration For Consensus"`
 
 	// Highway
@@ -156,6 +177,10 @@ type config struct {
 	//backup
 	PreloadAddress string `long:"preloadaddress" description:"Endpoint of fullnode to download backup database"`
 	ForceBackup    bool   `long:"forcebackup" description:"Force node to backup"`
+	StateSync      bool   `long:"statesync" description:"Download the state of a recent finalized view from peers instead of processing every block of a new chain"`
+
+	// event log
+	EventLog bool `long:"eventlog" description:"Record the blocks and transactions joining and leaving the best chains in a durable event log readable from a cursor"`
 }
 
 func (cfg config) IsTestnet() bool {
@@ -317,11 +342,16 @@ func loadConfig() (*config, []string, error) {
 		MaxPeersBeacon:              DefaultMaxPeersBeacon,
 		RPCMaxClients:               DefaultMaxRPCClients,
 		RPCMaxWSClients:             DefaultMaxRPCWsClients,
+		RPCWSQueueSize:              DefaultRPCWsQueueSize,
+		RPCWSSlowPolicy:             DefaultRPCWsSlowPolicy,
 		RPCLimitRequestPerDay:       DefaultRPCLimitRequestPerDay,
 		RPCLimitRequestErrorPerHour: DefaultRPCLimitErrorRequestPerHour,
 		DataDir:                     defaultDataDir,
 		DatabaseDir:                 DefaultDatabaseDirname,
+		DatabaseType:                DefaultDatabaseType,
 		DatabaseMempoolDir:          DefaultDatabaseMempoolDirname,
+		TxSelectionPolicy:           DefaultTxSelectionPolicy,
+		TxVerifyBatchSize:           DefaultTxVerifyBatchSize,
 		LogDir:                      defaultLogDir,
 		RPCKey:                      defaultRPCKeyFile,
 		RPCCert:                     defaultRPCCertFile,
@@ -336,6 +366,7 @@ func loadConfig() (*config, []string, error) {
 		// NodeMode:                    DefaultNodeMode,
 		MiningKeys:     common.EmptyString,
 		PrivateKey:     common.EmptyString,
+		RemoteSigner:   common.EmptyString,
 		FastStartup:    DefaultFastStartup,
 		TxPoolTTL:      DefaultTxPoolTTL,
 		TxPoolMaxTx:    DefaultTxPoolMaxTx,
@@ -492,6 +523,72 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --prunestate must keep enough views and does not mix with --forcebackup.
+	if cfg.PruneState > 0 && cfg.PruneState < blockchain.MinPruneStateViews {
+		str := "%s: the --prunestate option must keep at least %d views"
+		err := fmt.Errorf(str, funcName, blockchain.MinPruneStateViews)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.PruneState > 0 && cfg.ForceBackup {
+		str := "%s: the --prunestate and --forcebackup options can not be mixed"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// --statesync and --preloadaddress both fill an empty database.
+	if cfg.StateSync && cfg.PreloadAddress != "" {
+		str := "%s: the --statesync and --preloadaddress options can not be mixed"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// --rpcwsqueuesize must be positive and --rpcwsslowpolicy known.
+	if cfg.RPCWSQueueSize <= 0 {
+		str := "%s: the --rpcwsqueuesize option must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if _, err := pubsub.ParseDeliveryPolicy(cfg.RPCWSSlowPolicy); err != nil {
+		str := "%s: the --rpcwsslowpolicy option must be dropoldest, dropnewest or block"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// --txselectionpolicy must be known.
+	if _, err := blockchain.NewTxSelectionPolicy(cfg.TxSelectionPolicy); err != nil {
+		str := "%s: the --txselectionpolicy option must be feepriority or fifo"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// --txverifyworkers must be at least -1 and --txverifybatchsize positive.
+	if cfg.TxVerifyWorkers < -1 {
+		str := "%s: the --txverifyworkers option must be -1, 0 or positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.TxVerifyBatchSize <= 0 {
+		str := "%s: the --txverifybatchsize option must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// --proxy or --connect without --listen disables listening.
 	if (cfg.Proxy != common.EmptyString || len(cfg.ConnectPeers) > 0) &&
 		len(cfg.Listener) == 0 {
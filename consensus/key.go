@@ -10,6 +10,7 @@ import (
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/consensus/blsbft"
 	"github.com/incognitochain/incognito-chain/consensus/blsbftv2"
+	blsbftv2v2 "github.com/incognitochain/incognito-chain/consensus_v2/blsbftv2"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 )
 
@@ -95,6 +96,15 @@ func (s *Engine) GetMiningPublicKeys() *incognitokey.CommitteePublicKey {
 	return s.userMiningPublicKeys[s.consensusName]
 }
 
+// GetNodeMiningPublicKeys returns the mining keys of the node, this engine
+// mines with one key
+func (s *Engine) GetNodeMiningPublicKeys() (userPks []*incognitokey.CommitteePublicKey) {
+	if pk := s.GetMiningPublicKeys(); pk != nil {
+		userPks = append(userPks, pk)
+	}
+	return userPks
+}
+
 func (engine *Engine) GetAllMiningPublicKeys() []string {
 	engine.lock.Lock()
 	defer engine.lock.Unlock()
@@ -191,6 +201,19 @@ func (engine *Engine) ValidateBlockCommitteSig(block common.BlockInterface, comm
 	return fmt.Errorf("Wrong block version: %v", block.GetVersion())
 }
 
+// ValidateEquivocationEvidence checks that evidence proves a member of the
+// committee of chainID signed two conflicting blocks and returns the member
+// and the height of the blocks
+func (engine *Engine) ValidateEquivocationEvidence(evidence string, chainID int, committee []incognitokey.CommitteePublicKey) (string, uint64, error) {
+	return blsbftv2v2.ValidateEquivocationEvidence(evidence, chainID, committee, blockchain.NewBlockFromHeader)
+}
+
+// GetEquivocationEvidenceKey returns the key of the equivocation evidence
+// proves, an equivocation is slashed once whatever evidence of it is carried
+func (engine *Engine) GetEquivocationEvidenceKey(evidence string) (common.Hash, error) {
+	return blsbftv2v2.GetEquivocationEvidenceKey(evidence, blockchain.NewBlockFromHeader)
+}
+
 func (engine *Engine) GenMiningKeyFromPrivateKey(privateKey string) (string, error) {
 	var keyList string
 	var key string
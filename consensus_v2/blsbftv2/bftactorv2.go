@@ -13,7 +13,6 @@ import (
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/incognitochain/incognito-chain/common"
-	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/wire"
@@ -37,10 +36,20 @@ type BLSBFT_V2 struct {
 	proposeHistory   *lru.Cache
 	ProposeMessageCh chan BFTPropose
 	VoteMessageCh    chan BFTVote
+	Clock            Clock // the system clock if nil
+	isStepped        bool
 
 	receiveBlockByHeight map[uint64][]*ProposeBlockInfo   //blockHeight -> blockInfo
 	receiveBlockByHash   map[string]*ProposeBlockInfo     //blockHash -> blockInfo
 	voteHistory          map[uint64]common.BlockInterface // bestview height (previsous height )-> block
+	observedProposals    map[string]*observedBlock        // proposer-height-timeslot -> first proposal
+	observedVotes        map[string]*observedBlock        // validator-height-produce timeslot-propose timeslot -> first vote
+}
+
+// Clock gives the time to the engine, a simulation replaces the system clock
+// with a virtual one
+type Clock interface {
+	Now() int64 // unix time in seconds
 }
 
 func (e BLSBFT_V2) GetChainKey() string {
@@ -93,212 +102,270 @@ func (e *BLSBFT_V2) Start() error {
 	e.StopCh = make(chan struct{})
 	e.ProposeMessageCh = make(chan BFTPropose)
 	e.VoteMessageCh = make(chan BFTVote)
-	e.receiveBlockByHash = make(map[string]*ProposeBlockInfo)
-	e.receiveBlockByHeight = make(map[uint64][]*ProposeBlockInfo)
-	e.voteHistory = make(map[uint64]common.BlockInterface)
-	var err error
-	e.proposeHistory, err = lru.New(1000)
-	if err != nil {
-		panic(err)
-	}
+	e.initState()
 
-	//init view maps
 	ticker := time.Tick(200 * time.Millisecond)
 	e.Logger.Info("start bls-bftv2 consensus for chain", e.ChainKey)
 	go func() {
 		for { //actor loop
-
-			//e.Logger.Debug("Current time ", currentTime, "time slot ", currentTimeSlot)
 			select {
 			case <-e.StopCh:
 				return
 			case proposeMsg := <-e.ProposeMessageCh:
-				//fmt.Println("debug receive propose message", string(proposeMsg.Block))
-				blockIntf, err := e.Chain.UnmarshalBlock(proposeMsg.Block)
-				if err != nil || blockIntf == nil {
-					e.Logger.Info(err)
-					continue
-				}
-				block := blockIntf.(common.BlockInterface)
-				blkHash := block.Hash().String()
-
-				if _, ok := e.receiveBlockByHash[blkHash]; !ok {
-					e.receiveBlockByHash[blkHash] = &ProposeBlockInfo{
-						block:      block,
-						votes:      make(map[string]*BFTVote),
-						hasNewVote: false,
-					}
-					e.Logger.Info(e.ChainKey, "Receive block ", block.Hash().String(), "height", block.GetHeight(), ",block timeslot ", common.CalculateTimeSlot(block.GetProposeTime()))
-					e.receiveBlockByHeight[block.GetHeight()] = append(e.receiveBlockByHeight[block.GetHeight()], e.receiveBlockByHash[blkHash])
-				} else {
-					e.receiveBlockByHash[blkHash].block = block
-				}
+				e.processProposeMsg(proposeMsg)
+			case voteMsg := <-e.VoteMessageCh:
+				e.processVoteMsg(voteMsg)
+			case <-ticker:
+				e.processTick()
+			}
+		}
+	}()
+	return nil
+}
 
-				if block.GetHeight() <= e.Chain.GetBestView().GetHeight() {
-					e.Logger.Infof("%v Receive block create from old view - height %v. Rejected! Expect: %v", e.ChainKey, block.GetHeight(), e.Chain.GetBestView().GetHeight())
-					continue
-				}
+// Step runs the engine synchronously at the time of its clock, without the
+// actor loop: it processes msgs in order, then does the work of a tick. The
+// calls to the node and the chain, which the actor loop does in goroutines,
+// are done before Step returns. A simulation drives engines which are not
+// started with Step to replay a scenario deterministically.
+func (e *BLSBFT_V2) Step(msgs []*wire.MessageBFT) {
+	if !e.isStepped {
+		e.isStepped = true
+		e.initState()
+	}
+	for _, msg := range msgs {
+		e.ProcessBFTMsg(msg)
+	}
+	e.processTick()
+}
 
-				proposeView := e.Chain.GetViewByHash(block.GetPrevHash())
-				if proposeView == nil {
-					e.Logger.Infof("%v Request sync block from node %s from %s to %s", e.ChainKey, proposeMsg.PeerID, block.GetPrevHash().String(), block.GetPrevHash().String())
-					e.Node.RequestMissingViewViaStream(proposeMsg.PeerID, [][]byte{block.GetPrevHash().Bytes()}, e.Chain.GetShardID(), e.Chain.GetChainName())
-				}
+func (e *BLSBFT_V2) initState() {
+	e.receiveBlockByHash = make(map[string]*ProposeBlockInfo)
+	e.receiveBlockByHeight = make(map[uint64][]*ProposeBlockInfo)
+	e.voteHistory = make(map[uint64]common.BlockInterface)
+	e.observedProposals = make(map[string]*observedBlock)
+	e.observedVotes = make(map[string]*observedBlock)
+	var err error
+	e.proposeHistory, err = lru.New(1000)
+	if err != nil {
+		panic(err)
+	}
+}
 
-			case voteMsg := <-e.VoteMessageCh:
-				voteMsg.IsValid = 0
-				if b, ok := e.receiveBlockByHash[voteMsg.BlockHash]; ok { //if receiveblock is already initiated
-					if _, ok := b.votes[voteMsg.Validator]; !ok { // and not receive validatorA vote
-						b.votes[voteMsg.Validator] = &voteMsg // store it
-						vid, v := GetValidatorIndex(e.Chain.GetBestView(), voteMsg.Validator)
-						if v != nil {
-							vbase58, _ := v.ToBase58()
-							e.Logger.Infof("%v Receive vote (%d) for block %s from validator %d %v", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, vid, vbase58)
-						} else {
-							e.Logger.Infof("%v Receive vote (%d) for block from unknown validator", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, voteMsg.Validator)
-						}
-
-						b.hasNewVote = true
-					}
-				} else {
-					e.receiveBlockByHash[voteMsg.BlockHash] = &ProposeBlockInfo{
-						votes:      make(map[string]*BFTVote),
-						hasNewVote: true,
-					}
-					e.receiveBlockByHash[voteMsg.BlockHash].votes[voteMsg.Validator] = &voteMsg
-					vid, v := GetValidatorIndex(e.Chain.GetBestView(), voteMsg.Validator)
-					if v != nil {
-						vbase58, _ := v.ToBase58()
-						e.Logger.Infof("%v Receive vote (%d) for block %s from validator %d %v", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, vid, vbase58)
-					} else {
-						e.Logger.Infof("%v Receive vote (%d) for block from unknown validator", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, voteMsg.Validator)
-					}
-				}
+func (e *BLSBFT_V2) now() int64 {
+	if e.Clock != nil {
+		return e.Clock.Now()
+	}
+	return time.Now().Unix()
+}
 
-			case <-ticker:
-				if !e.Chain.IsReady() {
-					continue
-				}
-				e.currentTime = time.Now().Unix()
+// async runs f in a goroutine, or before returning when the engine is stepped
+func (e *BLSBFT_V2) async(f func()) {
+	if e.isStepped {
+		f()
+		return
+	}
+	go f()
+}
 
-				newTimeSlot := false
-				if e.currentTimeSlot != common.CalculateTimeSlot(e.currentTime) {
-					newTimeSlot = true
-				}
+func (e *BLSBFT_V2) processProposeMsg(proposeMsg BFTPropose) {
+	//fmt.Println("debug receive propose message", string(proposeMsg.Block))
+	blockIntf, err := e.Chain.UnmarshalBlock(proposeMsg.Block)
+	if err != nil || blockIntf == nil {
+		e.Logger.Info(err)
+		return
+	}
+	block := blockIntf.(common.BlockInterface)
+	blkHash := block.Hash().String()
+
+	if b, ok := e.receiveBlockByHash[blkHash]; !ok || b.block == nil {
+		e.observeProposal(block)
+		if ok {
+			for _, vote := range b.votes {
+				e.observeVote(vote, block)
+			}
+		}
+	}
+	if _, ok := e.receiveBlockByHash[blkHash]; !ok {
+		e.receiveBlockByHash[blkHash] = &ProposeBlockInfo{
+			block:      block,
+			votes:      make(map[string]*BFTVote),
+			hasNewVote: false,
+		}
+		e.Logger.Info(e.ChainKey, "Receive block ", block.Hash().String(), "height", block.GetHeight(), ",block timeslot ", common.CalculateTimeSlot(block.GetProposeTime()))
+		e.receiveBlockByHeight[block.GetHeight()] = append(e.receiveBlockByHeight[block.GetHeight()], e.receiveBlockByHash[blkHash])
+	} else {
+		e.receiveBlockByHash[blkHash].block = block
+	}
 
-				e.currentTimeSlot = common.CalculateTimeSlot(e.currentTime)
-				bestView := e.Chain.GetBestView()
-
-				/*
-					Check for whether we should propose block
-				*/
-				proposerPk, _ := bestView.GetProposerByTimeSlot(e.currentTimeSlot, 2)
-				var userProposeKey signatureschemes2.MiningKey
-				shouldPropose := false
-				shouldListen := true
-				for _, userKey := range e.UserKeySet {
-					userPk := userKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus)
-					if proposerPk.GetMiningKeyBase58(common.BlsConsensus) == userPk {
-						shouldListen = false
-						if common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()) != e.currentTimeSlot { // current timeslot is not add to view, and this user is proposer of this timeslot
-							//using block hash as key of best view -> check if this best view we propose or not
-							if _, ok := e.proposeHistory.Get(fmt.Sprintf("%s%d", e.currentTimeSlot)); !ok {
-								shouldPropose = true
-								userProposeKey = userKey
-							}
-						}
-					}
-				}
+	if block.GetHeight() <= e.Chain.GetBestView().GetHeight() {
+		e.Logger.Infof("%v Receive block create from old view - height %v. Rejected! Expect: %v", e.ChainKey, block.GetHeight(), e.Chain.GetBestView().GetHeight())
+		return
+	}
 
-				if newTimeSlot { //for logging
-					e.Logger.Infof("%v", e.ChainKey)
-					e.Logger.Infof("%v ======================================================", e.ChainKey)
-					e.Logger.Infof("%v", e.ChainKey)
-					if shouldListen {
-						e.Logger.Infof("%v TS: %v, LISTEN BLOCK %v, Round %v", e.ChainKey, common.CalculateTimeSlot(e.currentTime), bestView.GetHeight()+1, e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()))
-					}
-					if shouldPropose {
-						e.Logger.Infof("%v TS: %v, PROPOSE BLOCK %v, Round %v", e.ChainKey, common.CalculateTimeSlot(e.currentTime), bestView.GetHeight()+1, e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()))
-					}
+	proposeView := e.Chain.GetViewByHash(block.GetPrevHash())
+	if proposeView == nil {
+		e.Logger.Infof("%v Request sync block from node %s from %s to %s", e.ChainKey, proposeMsg.PeerID, block.GetPrevHash().String(), block.GetPrevHash().String())
+		e.Node.RequestMissingViewViaStream(proposeMsg.PeerID, [][]byte{block.GetPrevHash().Bytes()}, e.Chain.GetShardID(), e.Chain.GetChainName())
+	}
+}
 
-				}
+func (e *BLSBFT_V2) processVoteMsg(voteMsg BFTVote) {
+	voteMsg.IsValid = 0
+	if b, ok := e.receiveBlockByHash[voteMsg.BlockHash]; ok { //if receiveblock is already initiated
+		if _, ok := b.votes[voteMsg.Validator]; !ok { // and not receive validatorA vote
+			b.votes[voteMsg.Validator] = &voteMsg // store it
+			if b.block != nil {
+				e.observeVote(&voteMsg, b.block)
+			}
+			vid, v := GetValidatorIndex(e.Chain.GetBestView(), voteMsg.Validator)
+			if v != nil {
+				vbase58, _ := v.ToBase58()
+				e.Logger.Infof("%v Receive vote (%d) for block %s from validator %d %v", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, vid, vbase58)
+			} else {
+				e.Logger.Infof("%v Receive vote (%d) for block from unknown validator", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, voteMsg.Validator)
+			}
 
-				if shouldPropose {
-					e.proposeHistory.Add(fmt.Sprintf("%s%d", e.currentTimeSlot), 1)
-					//Proposer Rule: check propose block connected to bestview(longest chain rule 1) and re-propose valid block with smallest timestamp (including already propose in the past) (rule 2)
-					sort.Slice(e.receiveBlockByHeight[bestView.GetHeight()+1], func(i, j int) bool {
-						return e.receiveBlockByHeight[bestView.GetHeight()+1][i].block.GetProduceTime() < e.receiveBlockByHeight[bestView.GetHeight()+1][j].block.GetProduceTime()
-					})
-
-					var proposeBlock common.BlockInterface = nil
-					for _, v := range e.receiveBlockByHeight[bestView.GetHeight()+1] {
-						if v.isValid {
-							proposeBlock = v.block
-							break
-						}
-					}
-
-					//proposerPk: which include mining pubkey + incokey
-					//userKey: only have minigkey
-					if createdBlk, err := e.proposeBlock(userProposeKey, proposerPk, proposeBlock); err != nil {
-						e.Logger.Critical(UnExpectedError, errors.New("can't propose block"))
-						e.Logger.Critical(err)
-
-					} else {
-						e.Logger.Infof("%v proposer block %v round %v time slot %v blockTimeSlot %v with hash %v", e.ChainKey, createdBlk.GetHeight(), e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()), e.currentTimeSlot, common.CalculateTimeSlot(createdBlk.GetProduceTime()), createdBlk.Hash().String())
-					}
-				}
+			b.hasNewVote = true
+		}
+	} else {
+		e.receiveBlockByHash[voteMsg.BlockHash] = &ProposeBlockInfo{
+			votes:      make(map[string]*BFTVote),
+			hasNewVote: true,
+		}
+		e.receiveBlockByHash[voteMsg.BlockHash].votes[voteMsg.Validator] = &voteMsg
+		vid, v := GetValidatorIndex(e.Chain.GetBestView(), voteMsg.Validator)
+		if v != nil {
+			vbase58, _ := v.ToBase58()
+			e.Logger.Infof("%v Receive vote (%d) for block %s from validator %d %v", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, vid, vbase58)
+		} else {
+			e.Logger.Infof("%v Receive vote (%d) for block from unknown validator", e.ChainKey, len(e.receiveBlockByHash[voteMsg.BlockHash].votes), voteMsg.BlockHash, voteMsg.Validator)
+		}
+	}
+}
 
-				/*
-					Check for valid block to vote
-				*/
-				validProposeBlock := []*ProposeBlockInfo{}
-				//get all block that has height = bestview height  + 1(rule 2 & rule 3) (
-				for h, proposeBlockInfo := range e.receiveBlockByHash {
-					if proposeBlockInfo.block == nil {
-						continue
-					}
-					bestViewHeight := bestView.GetHeight()
-					// e.Logger.Infof("[Monitor] bestview height %v, finalview height %v, block height %v %v", bestViewHeight, e.Chain.GetFinalView().GetHeight(), proposeBlockInfo.block.GetHeight(), proposeBlockInfo.block.GetProduceTime())
-					if proposeBlockInfo.block.GetHeight() == bestViewHeight+1 {
-
-						validProposeBlock = append(validProposeBlock, proposeBlockInfo)
-					}
-
-					if proposeBlockInfo.block.GetHeight() < e.Chain.GetFinalView().GetHeight() {
-						delete(e.receiveBlockByHash, h)
-					}
-				}
-				//rule 1: get history of vote for this height, vote if (round is lower than the vote before) or (round is equal but new proposer) or (there is no vote for this height yet)
-				sort.Slice(validProposeBlock, func(i, j int) bool {
-					return validProposeBlock[i].block.GetProduceTime() < validProposeBlock[j].block.GetProduceTime()
-				})
-				for _, v := range validProposeBlock {
-					blkCreateTimeSlot := common.CalculateTimeSlot(v.block.GetProduceTime())
-					bestViewHeight := bestView.GetHeight()
-
-					if lastVotedBlk, ok := e.voteHistory[bestViewHeight+1]; ok {
-						if blkCreateTimeSlot < common.CalculateTimeSlot(lastVotedBlk.GetProduceTime()) { //blkCreateTimeSlot is smaller than voted block => vote for this blk
-							e.validateAndVote(v)
-						} else if blkCreateTimeSlot == common.CalculateTimeSlot(lastVotedBlk.GetProduceTime()) && common.CalculateTimeSlot(v.block.GetProposeTime()) > common.CalculateTimeSlot(lastVotedBlk.GetProposeTime()) { //blk is old block (same round), but new proposer(larger timeslot) => vote again
-							e.validateAndVote(v)
-						} //blkCreateTimeSlot is larger or equal than voted block => do nothing
-					} else { //there is no vote for this height yet
-						e.validateAndVote(v)
-					}
-				}
+func (e *BLSBFT_V2) processTick() {
+	if !e.Chain.IsReady() {
+		return
+	}
+	e.currentTime = e.now()
+
+	newTimeSlot := false
+	if e.currentTimeSlot != common.CalculateTimeSlot(e.currentTime) {
+		newTimeSlot = true
+	}
 
-				/*
-					Check for 2/3 vote to commit
-				*/
-				for k, v := range e.receiveBlockByHash {
-					e.processIfBlockGetEnoughVote(k, v)
+	e.currentTimeSlot = common.CalculateTimeSlot(e.currentTime)
+	if newTimeSlot {
+		e.pruneObserved()
+	}
+	bestView := e.Chain.GetBestView()
+
+	/*
+		Check for whether we should propose block
+	*/
+	proposerPk, _ := bestView.GetProposerByTimeSlot(e.currentTimeSlot, 2)
+	var userProposeKey signatureschemes2.MiningKey
+	shouldPropose := false
+	shouldListen := true
+	for _, userKey := range e.UserKeySet {
+		userPk := userKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus)
+		if proposerPk.GetMiningKeyBase58(common.BlsConsensus) == userPk {
+			shouldListen = false
+			if common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()) != e.currentTimeSlot { // current timeslot is not add to view, and this user is proposer of this timeslot
+				//using block hash as key of best view -> check if this best view we propose or not
+				if _, ok := e.proposeHistory.Get(fmt.Sprintf("%d", e.currentTimeSlot)); !ok {
+					shouldPropose = true
+					userProposeKey = userKey
 				}
+			}
+		}
+	}
+
+	if newTimeSlot { //for logging
+		e.Logger.Infof("%v", e.ChainKey)
+		e.Logger.Infof("%v ======================================================", e.ChainKey)
+		e.Logger.Infof("%v", e.ChainKey)
+		if shouldListen {
+			e.Logger.Infof("%v TS: %v, LISTEN BLOCK %v, Round %v", e.ChainKey, common.CalculateTimeSlot(e.currentTime), bestView.GetHeight()+1, e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()))
+		}
+		if shouldPropose {
+			e.Logger.Infof("%v TS: %v, PROPOSE BLOCK %v, Round %v", e.ChainKey, common.CalculateTimeSlot(e.currentTime), bestView.GetHeight()+1, e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()))
+		}
+
+	}
 
+	if shouldPropose {
+		e.proposeHistory.Add(fmt.Sprintf("%d", e.currentTimeSlot), 1)
+		//Proposer Rule: check propose block connected to bestview(longest chain rule 1) and re-propose valid block with smallest timestamp (including already propose in the past) (rule 2)
+		sort.Slice(e.receiveBlockByHeight[bestView.GetHeight()+1], func(i, j int) bool {
+			return e.receiveBlockByHeight[bestView.GetHeight()+1][i].block.GetProduceTime() < e.receiveBlockByHeight[bestView.GetHeight()+1][j].block.GetProduceTime()
+		})
+
+		var proposeBlock common.BlockInterface = nil
+		for _, v := range e.receiveBlockByHeight[bestView.GetHeight()+1] {
+			if v.isValid {
+				proposeBlock = v.block
+				break
 			}
 		}
-	}()
-	return nil
+
+		//proposerPk: which include mining pubkey + incokey
+		//userKey: only have minigkey
+		if createdBlk, err := e.proposeBlock(userProposeKey, proposerPk, proposeBlock); err != nil {
+			e.Logger.Critical(UnExpectedError, errors.New("can't propose block"))
+			e.Logger.Critical(err)
+
+		} else {
+			e.Logger.Infof("%v proposer block %v round %v time slot %v blockTimeSlot %v with hash %v", e.ChainKey, createdBlk.GetHeight(), e.currentTimeSlot-common.CalculateTimeSlot(bestView.GetBlock().GetProposeTime()), e.currentTimeSlot, common.CalculateTimeSlot(createdBlk.GetProduceTime()), createdBlk.Hash().String())
+		}
+	}
+
+	/*
+		Check for valid block to vote
+	*/
+	validProposeBlock := []*ProposeBlockInfo{}
+	//get all block that has height = bestview height  + 1(rule 2 & rule 3) (
+	for h, proposeBlockInfo := range e.receiveBlockByHash {
+		if proposeBlockInfo.block == nil {
+			continue
+		}
+		bestViewHeight := bestView.GetHeight()
+		// e.Logger.Infof("[Monitor] bestview height %v, finalview height %v, block height %v %v", bestViewHeight, e.Chain.GetFinalView().GetHeight(), proposeBlockInfo.block.GetHeight(), proposeBlockInfo.block.GetProduceTime())
+		if proposeBlockInfo.block.GetHeight() == bestViewHeight+1 {
+
+			validProposeBlock = append(validProposeBlock, proposeBlockInfo)
+		}
+
+		if proposeBlockInfo.block.GetHeight() < e.Chain.GetFinalView().GetHeight() {
+			delete(e.receiveBlockByHash, h)
+		}
+	}
+	//rule 1: get history of vote for this height, vote if (round is lower than the vote before) or (round is equal but new proposer) or (there is no vote for this height yet)
+	sort.Slice(validProposeBlock, func(i, j int) bool {
+		return validProposeBlock[i].block.GetProduceTime() < validProposeBlock[j].block.GetProduceTime()
+	})
+	for _, v := range validProposeBlock {
+		blkCreateTimeSlot := common.CalculateTimeSlot(v.block.GetProduceTime())
+		bestViewHeight := bestView.GetHeight()
+
+		if lastVotedBlk, ok := e.voteHistory[bestViewHeight+1]; ok {
+			if blkCreateTimeSlot < common.CalculateTimeSlot(lastVotedBlk.GetProduceTime()) { //blkCreateTimeSlot is smaller than voted block => vote for this blk
+				e.validateAndVote(v)
+			} else if blkCreateTimeSlot == common.CalculateTimeSlot(lastVotedBlk.GetProduceTime()) && common.CalculateTimeSlot(v.block.GetProposeTime()) > common.CalculateTimeSlot(lastVotedBlk.GetProposeTime()) { //blk is old block (same round), but new proposer(larger timeslot) => vote again
+				e.validateAndVote(v)
+			} //blkCreateTimeSlot is larger or equal than voted block => do nothing
+		} else { //there is no vote for this height yet
+			e.validateAndVote(v)
+		}
+	}
+
+	/*
+		Check for 2/3 vote to commit
+	*/
+	for k, v := range e.receiveBlockByHash {
+		e.processIfBlockGetEnoughVote(k, v)
+	}
 }
 
 func NewInstance(chain ChainInterface, chainKey string, chainID int, node NodeInterface, logger common.Logger) *BLSBFT_V2 {
@@ -408,7 +475,8 @@ func (e *BLSBFT_V2) processIfBlockGetEnoughVote(blockHash string, v *ProposeBloc
 			return
 		}
 
-		go e.Chain.InsertAndBroadcastBlock(v.block)
+		block := v.block
+		e.async(func() { e.Chain.InsertAndBroadcastBlock(block) })
 
 		delete(e.receiveBlockByHash, blockHash)
 	}
@@ -437,7 +505,7 @@ func (e *BLSBFT_V2) validateAndVote(v *ProposeBlockInfo) error {
 	for _, userKey := range e.UserKeySet {
 		pubKey := userKey.GetPublicKey()
 		if common.IndexOfStr(pubKey.GetMiningKeyBase58(e.GetConsensusName()), committeeBLSString) != -1 {
-			Vote, err := CreateVote(&userKey, v.block, e.Chain.GetBestView().GetCommittee())
+			Vote, err := CreateVote(&userKey, e.ChainID, v.block, e.Chain.GetBestView().GetCommittee())
 			if err != nil {
 				e.Logger.Error(err)
 				return NewConsensusError(UnExpectedError, err)
@@ -452,17 +520,17 @@ func (e *BLSBFT_V2) validateAndVote(v *ProposeBlockInfo) error {
 			v.isValid = true
 			e.voteHistory[v.block.GetHeight()] = v.block
 			e.Logger.Info(e.ChainKey, "sending vote...")
-			go e.ProcessBFTMsg(msg.(*wire.MessageBFT))
-			go e.Node.PushMessageToChain(msg, e.Chain)
+			e.async(func() { e.ProcessBFTMsg(msg.(*wire.MessageBFT)) })
+			e.async(func() { e.Node.PushMessageToChain(msg, e.Chain) })
 		}
 	}
 
 	return nil
 }
 
-func CreateVote(userKey *signatureschemes2.MiningKey, block common.BlockInterface, committees []incognitokey.CommitteePublicKey) (*BFTVote, error) {
+func CreateVote(userKey *signatureschemes2.MiningKey, chainID int, block common.BlockInterface, committees []incognitokey.CommitteePublicKey) (*BFTVote, error) {
 	var Vote = new(BFTVote)
-	bytelist := []blsmultisig.PublicKey{}
+	bytelist := [][]byte{}
 	selfIdx := 0
 	userBLSPk := userKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus)
 	for i, v := range committees {
@@ -472,29 +540,28 @@ func CreateVote(userKey *signatureschemes2.MiningKey, block common.BlockInterfac
 		bytelist = append(bytelist, v.MiningPubKey[common.BlsConsensus])
 	}
 
-	blsSig, err := userKey.BLSSignData(block.Hash().GetBytes(), selfIdx, bytelist)
+	signature, err := userKey.Sign(&signatureschemes2.SignRequest{
+		Type:            signatureschemes2.SignVoteRequest,
+		ChainID:         chainID,
+		Height:          block.GetHeight(),
+		ProduceTimeSlot: common.CalculateTimeSlot(block.GetProduceTime()),
+		ProposeTimeSlot: common.CalculateTimeSlot(block.GetProposeTime()),
+		BlockHash:       *block.Hash(),
+		Committee:       bytelist,
+		SelfIdx:         selfIdx,
+		SignBridge:      metadata.HasBridgeInstructions(block.GetInstructions()) || metadata.HasPortalInstructions(block.GetInstructions()),
+	})
 	if err != nil {
-
 		return nil, NewConsensusError(UnExpectedError, err)
 	}
-	bridgeSig := []byte{}
-	if metadata.HasBridgeInstructions(block.GetInstructions()) || metadata.HasPortalInstructions(block.GetInstructions()) {
-		bridgeSig, err = userKey.BriSignData(block.Hash().GetBytes())
-		if err != nil {
-			return nil, NewConsensusError(UnExpectedError, err)
-		}
-	}
-	Vote.BLS = blsSig
-	Vote.BRI = bridgeSig
+	Vote.BLS = signature.BLSSig
+	Vote.BRI = signature.BriSig
+	Vote.Confirmation = signature.Confirmation
 	Vote.BlockHash = block.Hash().String()
 
 	userPk := userKey.GetPublicKey()
 	Vote.Validator = userPk.GetMiningKeyBase58(common.BlsConsensus)
 	Vote.PrevBlockHash = block.GetPrevHash().String()
-	err = Vote.signVote(userKey)
-	if err != nil {
-		return nil, NewConsensusError(UnExpectedError, err)
-	}
 	return Vote, nil
 }
 
@@ -526,8 +593,19 @@ func (e *BLSBFT_V2) proposeBlock(userMiningKey signatureschemes2.MiningKey, prop
 		return nil, NewConsensusError(BlockCreationError, errors.New("block is nil"))
 	}
 
+	signature, err := userMiningKey.Sign(&signatureschemes2.SignRequest{
+		Type:            signatureschemes2.SignProposalRequest,
+		ChainID:         e.ChainID,
+		Height:          block.GetHeight(),
+		ProduceTimeSlot: common.CalculateTimeSlot(block.GetProduceTime()),
+		ProposeTimeSlot: common.CalculateTimeSlot(block.GetProposeTime()),
+		BlockHash:       *block.Hash(),
+	})
+	if err != nil {
+		return nil, NewConsensusError(SignDataError, err)
+	}
 	var validationData ValidationData
-	validationData.ProducerBLSSig, _ = userMiningKey.BriSignData(block.Hash().GetBytes())
+	validationData.ProducerBLSSig = signature.BriSig
 	validationDataString, _ := EncodeValidationData(validationData)
 	block.(blockValidation).AddValidationField(validationDataString)
 	blockData, _ := json.Marshal(block)
@@ -535,8 +613,8 @@ func (e *BLSBFT_V2) proposeBlock(userMiningKey signatureschemes2.MiningKey, prop
 	proposeCtn.Block = blockData
 	proposeCtn.PeerID = e.Node.GetSelfPeerID().String()
 	msg, _ := MakeBFTProposeMsg(proposeCtn, e.ChainKey, e.currentTimeSlot, block.GetHeight())
-	go e.ProcessBFTMsg(msg.(*wire.MessageBFT))
-	go e.Node.PushMessageToChain(msg, e.Chain)
+	e.async(func() { e.ProcessBFTMsg(msg.(*wire.MessageBFT)) })
+	e.async(func() { e.Node.PushMessageToChain(msg, e.Chain) })
 
 	return block, nil
 }
@@ -551,6 +629,10 @@ func (e *BLSBFT_V2) ProcessBFTMsg(msgBFT *wire.MessageBFT) {
 			return
 		}
 		msgPropose.PeerID = msgBFT.PeerID
+		if e.isStepped {
+			e.processProposeMsg(msgPropose)
+			return
+		}
 		e.ProposeMessageCh <- msgPropose
 	case MSG_VOTE:
 		var msgVote BFTVote
@@ -559,6 +641,10 @@ func (e *BLSBFT_V2) ProcessBFTMsg(msgBFT *wire.MessageBFT) {
 			e.Logger.Error(err)
 			return
 		}
+		if e.isStepped {
+			e.processVoteMsg(msgVote)
+			return
+		}
 		e.VoteMessageCh <- msgVote
 	default:
 		e.Logger.Critical("Unknown BFT message type")
@@ -576,17 +662,6 @@ func (e *BLSBFT_V2) preValidateVote(blockHash []byte, Vote *BFTVote, candidate [
 	return err
 }
 
-func (s *BFTVote) signVote(key *signatureschemes2.MiningKey) error {
-	data := []byte{}
-	data = append(data, s.BlockHash...)
-	data = append(data, s.BLS...)
-	data = append(data, s.BRI...)
-	data = common.HashB(data)
-	var err error
-	s.Confirmation, err = key.BriSignData(data)
-	return err
-}
-
 func (s *BFTVote) validateVoteOwner(ownerPk []byte) error {
 	data := []byte{}
 	data = append(data, s.BlockHash...)
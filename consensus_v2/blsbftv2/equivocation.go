@@ -0,0 +1,300 @@
+package blsbftv2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+// kinds of equivocation
+const (
+	EquivocationProposal = "proposal" // a proposer signs two blocks of the same height in its timeslot
+	EquivocationVote     = "vote"     // a validator votes for two blocks of the same height, produce and propose timeslots
+)
+
+// equivocationWindow is the number of timeslots the engine keeps the proposals
+// and the votes it observed
+const equivocationWindow = 5
+
+// EquivocationEvidence proves that a committee member signed two conflicting
+// blocks: the headers of the blocks, whose hashes are what the member signed,
+// and its signatures on them. Only the blocks of version 2 sign their propose
+// time, so only them can be evidences.
+type EquivocationEvidence struct {
+	Type         string             // EquivocationProposal or EquivocationVote
+	ChainID      int                // -1 for the beacon
+	Validator    string             // committee public key in base58 of the member, its bridge key checks the signatures
+	Headers      [2]json.RawMessage // headers of the two blocks
+	ProducerSigs [2][]byte          // bridge signatures of the proposer on the block hashes, for a proposal
+	Votes        [2]*BFTVote        // votes of the validator on the block hashes, for a vote
+}
+
+// EquivocationReporter is implemented by the chains which carry the
+// evidences found by the engine to the beacon
+type EquivocationReporter interface {
+	AddEquivocationEvidence(evidence string)
+}
+
+// observedBlock is a signed message of a member the engine observed
+type observedBlock struct {
+	block       common.BlockInterface
+	producerSig []byte
+	vote        *BFTVote
+	timeSlot    int64 // propose timeslot of the block
+	isReported  bool
+}
+
+func EncodeEquivocationEvidence(evidence *EquivocationEvidence) (string, error) {
+	result, err := json.Marshal(evidence)
+	if err != nil {
+		return "", NewConsensusError(EquivocationEvidenceError, err)
+	}
+	return string(result), nil
+}
+
+func DecodeEquivocationEvidence(data string) (*EquivocationEvidence, error) {
+	var evidence EquivocationEvidence
+	if err := json.Unmarshal([]byte(data), &evidence); err != nil {
+		return nil, NewConsensusError(EquivocationEvidenceError, err)
+	}
+	return &evidence, nil
+}
+
+// ValidateEquivocationEvidence checks that data is an evidence against a
+// member of the committee of chainID and returns the member and the height of
+// the blocks. decodeHeader returns a block with a header of the evidence.
+func ValidateEquivocationEvidence(
+	data string,
+	chainID int,
+	committee []incognitokey.CommitteePublicKey,
+	decodeHeader func(chainID int, header []byte) (common.BlockInterface, error),
+) (string, uint64, error) {
+	evidence, err := DecodeEquivocationEvidence(data)
+	if err != nil {
+		return "", 0, err
+	}
+	if evidence.ChainID != chainID {
+		return "", 0, NewConsensusError(EquivocationEvidenceError, fmt.Errorf("evidence of chain %v, expect %v", evidence.ChainID, chainID))
+	}
+	var blocks [2]common.BlockInterface
+	for i, header := range evidence.Headers {
+		if blocks[i], err = decodeHeader(chainID, header); err != nil {
+			return "", 0, NewConsensusError(EquivocationEvidenceError, err)
+		}
+	}
+	if err := VerifyEquivocationEvidence(evidence, blocks, committee); err != nil {
+		return "", 0, err
+	}
+	return evidence.Validator, blocks[0].GetHeight(), nil
+}
+
+// GetEquivocationEvidenceKey returns the key of the equivocation data proves,
+// the same for every evidence of it however it is encoded: the chain, the
+// member, the height, the propose and produce timeslots and the hashes of the
+// two blocks, ordered by hash. It does not check the evidence.
+func GetEquivocationEvidenceKey(
+	data string,
+	decodeHeader func(chainID int, header []byte) (common.BlockInterface, error),
+) (common.Hash, error) {
+	evidence, err := DecodeEquivocationEvidence(data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	blocks := make([]common.BlockInterface, len(evidence.Headers))
+	for i, header := range evidence.Headers {
+		if blocks[i], err = decodeHeader(evidence.ChainID, header); err != nil {
+			return common.Hash{}, NewConsensusError(EquivocationEvidenceError, err)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Hash().String() < blocks[j].Hash().String()
+	})
+	key := fmt.Sprintf("%v-%v-%v-%v-%v-%v-%v-%v",
+		evidence.ChainID,
+		evidence.Validator,
+		blocks[0].GetHeight(),
+		common.CalculateTimeSlot(blocks[0].GetProposeTime()),
+		common.CalculateTimeSlot(blocks[0].GetProduceTime()),
+		common.CalculateTimeSlot(blocks[1].GetProduceTime()),
+		blocks[0].Hash().String(),
+		blocks[1].Hash().String(),
+	)
+	return common.HashH([]byte(key)), nil
+}
+
+// VerifyEquivocationEvidence checks that blocks, the blocks of the headers of
+// evidence, conflict and that the member of committee of the evidence signed
+// both
+func VerifyEquivocationEvidence(evidence *EquivocationEvidence, blocks [2]common.BlockInterface, committee []incognitokey.CommitteePublicKey) error {
+	var member *incognitokey.CommitteePublicKey
+	for i := range committee {
+		if b58Str, _ := committee[i].ToBase58(); b58Str == evidence.Validator {
+			member = &committee[i]
+			break
+		}
+	}
+	if member == nil {
+		return NewConsensusError(EquivocationEvidenceError, errors.New("validator is not in committee"))
+	}
+	for _, block := range blocks {
+		if block == nil || block.GetVersion() < 2 {
+			return NewConsensusError(EquivocationEvidenceError, errors.New("evidence needs blocks of version 2"))
+		}
+	}
+	if blocks[0].Hash().IsEqual(blocks[1].Hash()) {
+		return NewConsensusError(EquivocationEvidenceError, errors.New("blocks are the same"))
+	}
+	if blocks[0].GetHeight() != blocks[1].GetHeight() {
+		return NewConsensusError(EquivocationEvidenceError, errors.New("blocks have different heights"))
+	}
+	if common.CalculateTimeSlot(blocks[0].GetProposeTime()) != common.CalculateTimeSlot(blocks[1].GetProposeTime()) {
+		return NewConsensusError(EquivocationEvidenceError, errors.New("blocks have different propose timeslots"))
+	}
+	dsaKey := member.MiningPubKey[common.BridgeConsensus]
+	switch evidence.Type {
+	case EquivocationProposal:
+		for i, block := range blocks {
+			if block.GetProposer() != evidence.Validator {
+				return NewConsensusError(EquivocationEvidenceError, errors.New("validator is not the proposer"))
+			}
+			if err := validateSingleBriSig(block.Hash(), evidence.ProducerSigs[i], dsaKey); err != nil {
+				return NewConsensusError(EquivocationEvidenceError, err)
+			}
+		}
+	case EquivocationVote:
+		if common.CalculateTimeSlot(blocks[0].GetProduceTime()) != common.CalculateTimeSlot(blocks[1].GetProduceTime()) {
+			return NewConsensusError(EquivocationEvidenceError, errors.New("blocks have different produce timeslots"))
+		}
+		for i, block := range blocks {
+			vote := evidence.Votes[i]
+			if vote == nil || vote.BlockHash != block.Hash().String() || vote.Validator != member.GetMiningKeyBase58(common.BlsConsensus) {
+				return NewConsensusError(EquivocationEvidenceError, errors.New("vote is not of the validator for the block"))
+			}
+			if err := vote.validateVoteOwner(dsaKey); err != nil {
+				return NewConsensusError(EquivocationEvidenceError, err)
+			}
+		}
+	default:
+		return NewConsensusError(EquivocationEvidenceError, fmt.Errorf("unknown equivocation %v", evidence.Type))
+	}
+	return nil
+}
+
+// observeProposal keeps the proposal of block, a signed block of version 2,
+// and reports an evidence if its proposer signed another block of the same
+// height in the same timeslot
+func (e *BLSBFT_V2) observeProposal(block common.BlockInterface) {
+	if block.GetVersion() < 2 || ValidateProducerSig(block) != nil {
+		return
+	}
+	valData, err := DecodeValidationData(block.GetValidationField())
+	if err != nil {
+		return
+	}
+	timeSlot := common.CalculateTimeSlot(block.GetProposeTime())
+	key := fmt.Sprintf("%v-%v-%v", block.GetProposer(), block.GetHeight(), timeSlot)
+	observed := &observedBlock{block: block, producerSig: valData.ProducerBLSSig, timeSlot: timeSlot}
+	first, ok := e.observedProposals[key]
+	if !ok {
+		e.observedProposals[key] = observed
+		return
+	}
+	if first.isReported || first.block.Hash().IsEqual(block.Hash()) {
+		return
+	}
+	evidence := &EquivocationEvidence{
+		Type:         EquivocationProposal,
+		ChainID:      e.ChainID,
+		Validator:    block.GetProposer(),
+		ProducerSigs: [2][]byte{first.producerSig, observed.producerSig},
+	}
+	if e.reportEquivocation(evidence, [2]common.BlockInterface{first.block, block}) {
+		first.isReported = true
+	}
+}
+
+// observeVote keeps vote, the vote on block, and reports an evidence if its
+// validator voted for another block of the same height, produce and propose
+// timeslots
+func (e *BLSBFT_V2) observeVote(vote *BFTVote, block common.BlockInterface) {
+	if block.GetVersion() < 2 {
+		return
+	}
+	_, committeePk := GetValidatorIndex(e.Chain.GetBestView(), vote.Validator)
+	if committeePk == nil || vote.validateVoteOwner(committeePk.MiningPubKey[common.BridgeConsensus]) != nil {
+		return
+	}
+	timeSlot := common.CalculateTimeSlot(block.GetProposeTime())
+	key := fmt.Sprintf("%v-%v-%v-%v", vote.Validator, block.GetHeight(), common.CalculateTimeSlot(block.GetProduceTime()), timeSlot)
+	first, ok := e.observedVotes[key]
+	if !ok {
+		e.observedVotes[key] = &observedBlock{block: block, vote: vote, timeSlot: timeSlot}
+		return
+	}
+	if first.isReported || first.block.Hash().IsEqual(block.Hash()) {
+		return
+	}
+	validator, _ := committeePk.ToBase58()
+	evidence := &EquivocationEvidence{
+		Type:      EquivocationVote,
+		ChainID:   e.ChainID,
+		Validator: validator,
+		Votes:     [2]*BFTVote{first.vote, vote},
+	}
+	if e.reportEquivocation(evidence, [2]common.BlockInterface{first.block, block}) {
+		first.isReported = true
+	}
+}
+
+// reportEquivocation adds the headers of blocks to evidence, checks it and
+// gives it to the chain. It returns whether the evidence is reported.
+func (e *BLSBFT_V2) reportEquivocation(evidence *EquivocationEvidence, blocks [2]common.BlockInterface) bool {
+	for i, block := range blocks {
+		blockData, err := json.Marshal(block)
+		if err != nil {
+			e.Logger.Error(err)
+			return false
+		}
+		var blockHeader struct {
+			Header json.RawMessage
+		}
+		if err := json.Unmarshal(blockData, &blockHeader); err != nil {
+			e.Logger.Error(err)
+			return false
+		}
+		evidence.Headers[i] = blockHeader.Header
+	}
+	if err := VerifyEquivocationEvidence(evidence, blocks, e.Chain.GetBestView().GetCommittee()); err != nil {
+		e.Logger.Error(err)
+		return false
+	}
+	evidenceStr, err := EncodeEquivocationEvidence(evidence)
+	if err != nil {
+		e.Logger.Error(err)
+		return false
+	}
+	e.Logger.Criticalf("%v Equivocation %v of %v at height %v, blocks %v and %v", e.ChainKey, evidence.Type, evidence.Validator, blocks[0].GetHeight(), blocks[0].Hash().String(), blocks[1].Hash().String())
+	if reporter, ok := e.Chain.(EquivocationReporter); ok {
+		reporter.AddEquivocationEvidence(evidenceStr)
+	}
+	return true
+}
+
+// pruneObserved forgets the proposals and the votes of the blocks proposed
+// before the window
+func (e *BLSBFT_V2) pruneObserved() {
+	for key, observed := range e.observedProposals {
+		if observed.timeSlot < e.currentTimeSlot-equivocationWindow {
+			delete(e.observedProposals, key)
+		}
+	}
+	for key, observed := range e.observedVotes {
+		if observed.timeSlot < e.currentTimeSlot-equivocationWindow {
+			delete(e.observedVotes, key)
+		}
+	}
+}
@@ -19,6 +19,7 @@ const (
 	DecodeValidationDataError
 	EncodeValidationDataError
 	BlockCreationError
+	EquivocationEvidenceError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -37,6 +38,7 @@ var ErrCodeMessage = map[int]struct {
 	DecodeValidationDataError:    {-1009, "Decode Validation Data error"},
 	EncodeValidationDataError:    {-1010, "Encode Validation Data Error"},
 	BlockCreationError:           {-1011, "Block Creation Error"},
+	EquivocationEvidenceError:    {-1012, "Equivocation evidence error"},
 }
 
 type ConsensusError struct {
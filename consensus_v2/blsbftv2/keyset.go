@@ -25,11 +25,11 @@ func (e *BLSBFT_V2) GetUserPublicKey() *incognitokey.CommitteePublicKey {
 
 func (e BLSBFT_V2) SignData(data []byte) (string, error) {
 	if e.UserKeySet != nil && len(e.UserKeySet) > 0 {
-		result, err := e.UserKeySet[0].BriSignData(data)
+		result, err := e.UserKeySet[0].Sign(&signatureschemes2.SignRequest{Type: signatureschemes2.SignDataRequest, Data: data})
 		if err != nil {
 			return "", NewConsensusError(SignDataError, err)
 		}
-		return base58.Base58Check{}.Encode(result, common.Base58Version), nil
+		return base58.Base58Check{}.Encode(result.BriSig, common.Base58Version), nil
 	}
 	return "", NewConsensusError(SignDataError, fmt.Errorf("No validator key"))
 
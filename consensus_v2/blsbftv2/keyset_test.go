@@ -2,7 +2,6 @@ package blsbftv2
 
 import (
 	"fmt"
-	consensus "github.com/incognitochain/incognito-chain/consensus_v2"
 	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
 	"strconv"
 	"strings"
@@ -76,7 +75,7 @@ func newMiningKey(privateSeed string) (*signatureschemes.MiningKey, error) {
 	var miningKey signatureschemes.MiningKey
 	privateSeedBytes, _, err := base58.Base58Check{}.Decode(privateSeed)
 	if err != nil {
-		return nil, consensus.NewConsensusError(consensus.LoadKeyError, err)
+		return nil, NewConsensusError(LoadKeyError, err)
 	}
 
 	blsPriKey, blsPubKey := blsmultisig.KeyGen(privateSeedBytes)
@@ -16,6 +16,13 @@ import (
 	"github.com/incognitochain/incognito-chain/wire"
 )
 
+// the node waits remoteSignerRetries*remoteSignerRetryDelay for its remote
+// signer to start
+const (
+	remoteSignerRetries    = 10
+	remoteSignerRetryDelay = 3 * time.Second
+)
+
 type Engine struct {
 	BFTProcess map[int]ConsensusInterface //chainID -> consensus
 	validators []*consensus.Validator     //list of validator
@@ -239,11 +246,37 @@ func (engine *Engine) Start() error {
 			engine.validators = append(engine.validators, &consensus.Validator{PrivateSeed: key, MiningKey: *miningKey})
 		}
 		engine.validators = engine.validators[:1] //allow only 1 key
+	} else if engine.config.Node.GetRemoteSigner() != "" {
+		miningKey, err := loadRemoteMiningKey(engine.config.Node.GetRemoteSigner())
+		if err != nil {
+			return err
+		}
+		engine.validators = []*consensus.Validator{&consensus.Validator{MiningKey: *miningKey}}
 	}
 	engine.IsEnabled = 1
 	return nil
 }
 
+// loadRemoteMiningKey returns the mining key of the signer at address, it
+// waits for a signer started after the node
+func loadRemoteMiningKey(address string) (*signatureschemes2.MiningKey, error) {
+	signer, err := signatureschemes2.NewRemoteSigner(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer: %v", err)
+	}
+	for i := 1; ; i++ {
+		miningKey, err := signatureschemes2.NewRemoteMiningKey(signer)
+		if err == nil {
+			return miningKey, nil
+		}
+		if i == remoteSignerRetries {
+			return nil, fmt.Errorf("can not load the mining key of remote signer %v after %v tries: %v", address, i, err)
+		}
+		Logger.Log.Errorf("CONSENSUS: remote signer %v is not ready, retry in %v: %v", address, remoteSignerRetryDelay, err)
+		time.Sleep(remoteSignerRetryDelay)
+	}
+}
+
 func (engine *Engine) Stop() error {
 	Logger.Log.Infof("CONSENSUS: Stop")
 	for _, BFTProcess := range engine.BFTProcess {
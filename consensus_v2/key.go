@@ -173,6 +173,19 @@ func (engine *Engine) ValidateBlockCommitteSig(block common.BlockInterface, comm
 	return fmt.Errorf("Wrong block version: %v", block.GetVersion())
 }
 
+// ValidateEquivocationEvidence checks that evidence proves a member of the
+// committee of chainID signed two conflicting blocks and returns the member
+// and the height of the blocks
+func (engine *Engine) ValidateEquivocationEvidence(evidence string, chainID int, committee []incognitokey.CommitteePublicKey) (string, uint64, error) {
+	return blsbftv2.ValidateEquivocationEvidence(evidence, chainID, committee, blockchain.NewBlockFromHeader)
+}
+
+// GetEquivocationEvidenceKey returns the key of the equivocation evidence
+// proves, an equivocation is slashed once whatever evidence of it is carried
+func (engine *Engine) GetEquivocationEvidenceKey(evidence string) (common.Hash, error) {
+	return blsbftv2.GetEquivocationEvidenceKey(evidence, blockchain.NewBlockFromHeader)
+}
+
 func (engine *Engine) GenMiningKeyFromPrivateKey(privateKey string) (string, error) {
 	privateSeed, err := LoadUserKeyFromIncPrivateKey(privateKey)
 	if err != nil {
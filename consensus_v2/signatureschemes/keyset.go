@@ -12,6 +12,7 @@ import (
 type MiningKey struct {
 	PriKey map[string][]byte
 	PubKey map[string][]byte
+	Signer Signer `json:"-"` // signs instead of PriKey if not nil, see Sign
 }
 
 func (miningKey *MiningKey) GetPublicKey() *incognitokey.CommitteePublicKey {
@@ -0,0 +1,192 @@
+package signatureschemes
+
+import (
+	"errors"
+	"fmt"
+	"net/rpc"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
+)
+
+// kinds of signing requests
+const (
+	SignProposalRequest = "proposal" // bridge signature of the producer on a block hash
+	SignVoteRequest     = "vote"     // bls and bridge signatures of a validator on a block hash, and the confirmation of its vote
+	SignDataRequest     = "data"     // bridge signature on data which is not a block hash
+)
+
+// remoteSignerTimeout is the time a node waits for the signatures of a
+// request out of a timeslot, the signatures of a proposal or a vote are
+// waited until the end of its propose timeslot, they are of no use after
+const remoteSignerTimeout = 5 * time.Second
+
+// SignRequest asks for the signatures of a mining key. The block of a proposal
+// or a vote is given by its hash, chain, height and timeslots, so a signer
+// holding the key can refuse to sign conflicting blocks.
+type SignRequest struct {
+	Type            string
+	ChainID         int // -1 for the beacon
+	Height          uint64
+	ProduceTimeSlot int64
+	ProposeTimeSlot int64
+	BlockHash       common.Hash
+	Committee       [][]byte // bls public keys of the committee, for a vote
+	SelfIdx         int      // index of the key in Committee, for a vote
+	SignBridge      bool     // whether a vote has a bridge signature, for the blocks with bridge or portal instructions
+	Data            []byte   // data to sign, for SignDataRequest
+}
+
+// SignResponse is the bridge signature of a proposal or of data, or the
+// signatures and the confirmation of a vote
+type SignResponse struct {
+	BLSSig       []byte
+	BriSig       []byte
+	Confirmation []byte
+}
+
+// Signer signs with a mining key the node does not hold
+type Signer interface {
+	GetPublicKey() (map[string][]byte, error)
+	Sign(request *SignRequest) (*SignResponse, error)
+}
+
+// NewRemoteMiningKey returns the mining key signer holds, with no private key
+func NewRemoteMiningKey(signer Signer) (*MiningKey, error) {
+	pubKey, err := signer.GetPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(pubKey[common.BlsConsensus]) == 0 || len(pubKey[common.BridgeConsensus]) == 0 {
+		return nil, errors.New("signer has no mining key")
+	}
+	return &MiningKey{
+		PriKey: map[string][]byte{},
+		PubKey: pubKey,
+		Signer: signer,
+	}, nil
+}
+
+// Sign returns the signatures of request, from the signer of miningKey if it
+// has one
+func (miningKey *MiningKey) Sign(request *SignRequest) (*SignResponse, error) {
+	if miningKey.Signer != nil {
+		return miningKey.Signer.Sign(request)
+	}
+	return miningKey.signRequest(request)
+}
+
+func (miningKey *MiningKey) signRequest(request *SignRequest) (*SignResponse, error) {
+	response := &SignResponse{}
+	var err error
+	switch request.Type {
+	case SignProposalRequest:
+		response.BriSig, err = miningKey.BriSignData(request.BlockHash.GetBytes())
+	case SignVoteRequest:
+		committee := []blsmultisig.PublicKey{}
+		for _, pk := range request.Committee {
+			committee = append(committee, pk)
+		}
+		response.BLSSig, err = miningKey.BLSSignData(request.BlockHash.GetBytes(), request.SelfIdx, committee)
+		if err != nil {
+			return nil, err
+		}
+		response.BriSig = []byte{}
+		if request.SignBridge {
+			response.BriSig, err = miningKey.BriSignData(request.BlockHash.GetBytes())
+			if err != nil {
+				return nil, err
+			}
+		}
+		// the confirmation is checked against the block hash in string of
+		// the vote and its signatures
+		data := []byte{}
+		data = append(data, request.BlockHash.String()...)
+		data = append(data, response.BLSSig...)
+		data = append(data, response.BriSig...)
+		response.Confirmation, err = miningKey.BriSignData(common.HashB(data))
+	case SignDataRequest:
+		response.BriSig, err = miningKey.BriSignData(request.Data)
+	default:
+		return nil, fmt.Errorf("unknown sign request %v", request.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// RemoteSigner asks a signer process, serving a SignerService over a unix
+// socket, for the signatures of a mining key. The socket is not
+// authenticated, its file permission restricts it to the user of the node.
+type RemoteSigner struct {
+	address string
+	client  *rpc.Client
+	lock    sync.Mutex
+}
+
+// NewRemoteSigner returns the signer at address, unix:///path/to/socket or a
+// path alone. It connects on the first request.
+func NewRemoteSigner(address string) (*RemoteSigner, error) {
+	signer := &RemoteSigner{address: strings.TrimPrefix(address, "unix://")}
+	if strings.Contains(signer.address, "://") {
+		return nil, fmt.Errorf("unsupported signer address %v, only unix sockets are supported", address)
+	}
+	if signer.address == "" {
+		return nil, errors.New("empty signer address")
+	}
+	return signer, nil
+}
+
+func (signer *RemoteSigner) GetPublicKey() (map[string][]byte, error) {
+	pubKey := map[string][]byte{}
+	if err := signer.call("SignerService.GetPublicKey", "", &pubKey, remoteSignerTimeout); err != nil {
+		return nil, err
+	}
+	return pubKey, nil
+}
+
+func (signer *RemoteSigner) Sign(request *SignRequest) (*SignResponse, error) {
+	response := &SignResponse{}
+	timeout := remoteSignerTimeout
+	if request.ProposeTimeSlot != 0 {
+		timeout = time.Until(time.Unix((request.ProposeTimeSlot+1)*int64(common.TIMESLOT), 0))
+		if timeout <= 0 {
+			return nil, fmt.Errorf("propose timeslot %v of %v is over", request.ProposeTimeSlot, request.Type)
+		}
+	}
+	if err := signer.call("SignerService.Sign", request, response, timeout); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// call calls method of the signer and waits timeout for its reply, it
+// connects again after a connection error
+func (signer *RemoteSigner) call(method string, args interface{}, reply interface{}, timeout time.Duration) error {
+	signer.lock.Lock()
+	defer signer.lock.Unlock()
+	if signer.client == nil {
+		client, err := rpc.Dial("unix", signer.address)
+		if err != nil {
+			return fmt.Errorf("can not connect to signer %v: %v", signer.address, err)
+		}
+		signer.client = client
+	}
+	call := signer.client.Go(method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		if _, ok := call.Error.(rpc.ServerError); call.Error != nil && !ok {
+			signer.client.Close()
+			signer.client = nil
+		}
+		return call.Error
+	case <-time.After(timeout):
+		signer.client.Close()
+		signer.client = nil
+		return fmt.Errorf("signer %v does not answer %v", signer.address, method)
+	}
+}
@@ -0,0 +1,157 @@
+package signatureschemes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// signedHeightWindow is the number of heights under the highest one of a
+// chain the service keeps the blocks it signed
+const signedHeightWindow = 100
+
+// signedBlock is a block the service signed
+type signedBlock struct {
+	ChainID   int
+	Height    uint64
+	BlockHash common.Hash
+}
+
+// SignerService signs the requests of a node with a mining key it holds, it is
+// served with net/rpc by a signer process. It never signs two blocks of the
+// same height which a chain treats as an equivocation:
+//
+//   - two proposals in the same propose timeslot
+//   - two votes in the same produce and propose timeslots
+//
+// The blocks it signed are written to statePath, if any, so a restarted
+// signer keeps refusing them.
+//
+// A block more than maxHeightAhead above the highest height signed of its
+// chain is refused, it would move the window over the heights of the chain
+// and stop the signer signing them.
+type SignerService struct {
+	key            *MiningKey
+	statePath      string
+	maxHeightAhead uint64
+	signed         map[string]*signedBlock // type-chain-height-timeslots -> signed block
+	heights        map[int]uint64          // chainID -> highest height signed
+	lock           sync.Mutex
+}
+
+// NewSignerService returns the service of key, it loads the blocks signed
+// before from statePath if it is not empty
+func NewSignerService(key *MiningKey, statePath string, maxHeightAhead uint64) (*SignerService, error) {
+	service := &SignerService{
+		key:            key,
+		statePath:      statePath,
+		maxHeightAhead: maxHeightAhead,
+		signed:         make(map[string]*signedBlock),
+		heights:        make(map[int]uint64),
+	}
+	if statePath == "" {
+		return service, nil
+	}
+	data, err := ioutil.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return service, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &service.signed); err != nil {
+		return nil, fmt.Errorf("invalid signer state %v: %v", statePath, err)
+	}
+	for _, block := range service.signed {
+		if block.Height > service.heights[block.ChainID] {
+			service.heights[block.ChainID] = block.Height
+		}
+	}
+	return service, nil
+}
+
+func (service *SignerService) GetPublicKey(_ string, pubKey *map[string][]byte) error {
+	*pubKey = map[string][]byte{
+		common.BlsConsensus:    service.key.PubKey[common.BlsConsensus],
+		common.BridgeConsensus: service.key.PubKey[common.BridgeConsensus],
+	}
+	return nil
+}
+
+func (service *SignerService) Sign(request *SignRequest, response *SignResponse) error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	var key string
+	switch request.Type {
+	case SignProposalRequest:
+		key = fmt.Sprintf("%v-%v-%v-%v", request.Type, request.ChainID, request.Height, request.ProposeTimeSlot)
+	case SignVoteRequest:
+		key = fmt.Sprintf("%v-%v-%v-%v-%v", request.Type, request.ChainID, request.Height, request.ProduceTimeSlot, request.ProposeTimeSlot)
+	case SignDataRequest:
+		// the bridge signature on a hash could be the one of a block
+		if len(request.Data) == common.HashSize {
+			return fmt.Errorf("refuse to sign data of %v bytes", common.HashSize)
+		}
+	}
+	isNew := false
+	if key != "" {
+		if block, ok := service.signed[key]; ok {
+			if !block.BlockHash.IsEqual(&request.BlockHash) {
+				return fmt.Errorf("refuse to sign %v of chain %v block %v %v, block %v is signed", request.Type, request.ChainID, request.Height, request.BlockHash.String(), block.BlockHash.String())
+			}
+		} else if request.Height+signedHeightWindow <= service.heights[request.ChainID] {
+			return fmt.Errorf("refuse to sign %v of chain %v block %v, height %v is signed", request.Type, request.ChainID, request.Height, service.heights[request.ChainID])
+		} else if service.heights[request.ChainID] != 0 && request.Height > service.heights[request.ChainID]+service.maxHeightAhead {
+			return fmt.Errorf("refuse to sign %v of chain %v block %v, more than %v heights above the signed height %v", request.Type, request.ChainID, request.Height, service.maxHeightAhead, service.heights[request.ChainID])
+		} else {
+			isNew = true
+		}
+	}
+	result, err := service.key.signRequest(request)
+	if err != nil {
+		return err
+	}
+	if isNew {
+		service.signed[key] = &signedBlock{ChainID: request.ChainID, Height: request.Height, BlockHash: request.BlockHash}
+		if request.Height > service.heights[request.ChainID] {
+			service.heights[request.ChainID] = request.Height
+		}
+		service.prune(request.ChainID)
+		if err := service.save(); err != nil {
+			delete(service.signed, key)
+			return err
+		}
+	}
+	*response = *result
+	return nil
+}
+
+// prune forgets the blocks of chainID under the window of its highest height
+func (service *SignerService) prune(chainID int) {
+	for key, block := range service.signed {
+		if block.ChainID == chainID && block.Height+signedHeightWindow <= service.heights[chainID] {
+			delete(service.signed, key)
+		}
+	}
+}
+
+// save writes the signed blocks to statePath before the signatures are
+// returned
+func (service *SignerService) save() error {
+	if service.statePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(service.signed)
+	if err != nil {
+		return err
+	}
+	tmpPath := service.statePath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, service.statePath)
+}
@@ -0,0 +1,191 @@
+package signatureschemes
+
+import (
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/blsmultisig"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes/bridgesig"
+)
+
+func newTestMiningKey(seed []byte) *MiningKey {
+	blsPriKey, blsPubKey := blsmultisig.KeyGen(seed)
+	bridgePriKey, bridgePubKey := bridgesig.KeyGen(seed)
+	return &MiningKey{
+		PriKey: map[string][]byte{
+			common.BlsConsensus:    blsmultisig.SKBytes(blsPriKey),
+			common.BridgeConsensus: bridgesig.SKBytes(&bridgePriKey),
+		},
+		PubKey: map[string][]byte{
+			common.BlsConsensus:    blsmultisig.PKBytes(blsPubKey),
+			common.BridgeConsensus: bridgesig.PKBytes(&bridgePubKey),
+		},
+	}
+}
+
+const testMaxHeightAhead = 1000
+
+// serveTestSigner serves the service of key on a unix socket in dir
+func serveTestSigner(t *testing.T, key *MiningKey, dir string) (*RemoteSigner, net.Listener) {
+	service, err := NewSignerService(key, filepath.Join(dir, "state.json"), testMaxHeightAhead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	if err := server.Register(service); err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(dir, "signer.sock")
+	os.Remove(socket)
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Accept(listener)
+	signer, err := NewRemoteSigner("unix://" + socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, listener
+}
+
+func TestRemoteSigner(t *testing.T) {
+	timeSlot := common.TIMESLOT
+	common.TIMESLOT = 10
+	defer func() { common.TIMESLOT = timeSlot }()
+	now := common.CalculateTimeSlot(time.Now().Unix())
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	localKey := newTestMiningKey([]byte{0, 1, 2, 3, 4})
+	signer, listener := serveTestSigner(t, localKey, dir)
+	remoteKey, err := NewRemoteMiningKey(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(remoteKey.PubKey, localKey.PubKey) {
+		t.Fatalf("remote public key %v, want %v", remoteKey.PubKey, localKey.PubKey)
+	}
+
+	vote := &SignRequest{
+		Type:            SignVoteRequest,
+		Height:          10,
+		ProduceTimeSlot: now,
+		ProposeTimeSlot: now,
+		BlockHash:       common.HashH([]byte("block")),
+		Committee:       [][]byte{localKey.PubKey[common.BlsConsensus]},
+		SignBridge:      true,
+	}
+	want, err := localKey.Sign(vote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := remoteKey.Sign(vote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("remote vote %+v, want %+v", got, want)
+	}
+	// the same block is signed again
+	if _, err := remoteKey.Sign(vote); err != nil {
+		t.Fatal(err)
+	}
+
+	conflict := *vote
+	conflict.BlockHash = common.HashH([]byte("conflict"))
+	if _, err := remoteKey.Sign(&conflict); err == nil {
+		t.Fatal("conflicting vote is signed")
+	}
+	// another propose timeslot is a new vote
+	conflict.ProposeTimeSlot++
+	if _, err := remoteKey.Sign(&conflict); err != nil {
+		t.Fatal(err)
+	}
+	// the same height of another chain
+	conflict = *vote
+	conflict.ChainID = -1
+	conflict.BlockHash = common.HashH([]byte("beacon"))
+	if _, err := remoteKey.Sign(&conflict); err != nil {
+		t.Fatal(err)
+	}
+
+	proposal := &SignRequest{Type: SignProposalRequest, Height: 10, ProduceTimeSlot: now, ProposeTimeSlot: now, BlockHash: vote.BlockHash}
+	if _, err := remoteKey.Sign(proposal); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remoteKey.Sign(&SignRequest{Type: SignDataRequest, Data: vote.BlockHash.GetBytes()}); err == nil {
+		t.Fatal("data of a block hash is signed")
+	}
+	if _, err := remoteKey.Sign(&SignRequest{Type: SignDataRequest, Data: []byte("peer id")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a restarted signer keeps refusing the conflicting blocks
+	listener.Close()
+	signer, listener = serveTestSigner(t, localKey, dir)
+	defer listener.Close()
+	remoteKey.Signer = signer
+	proposal.BlockHash = common.HashH([]byte("conflict"))
+	if _, err := remoteKey.Sign(proposal); err == nil {
+		t.Fatal("conflicting proposal is signed after restart")
+	}
+	vote.Height += signedHeightWindow
+	vote.BlockHash = common.HashH([]byte("next"))
+	if _, err := remoteKey.Sign(vote); err != nil {
+		t.Fatal(err)
+	}
+	// the heights under the window are refused
+	conflict = *vote
+	conflict.Height = 10
+	if _, err := remoteKey.Sign(&conflict); err == nil {
+		t.Fatal("vote under the window is signed")
+	}
+	// the heights too far above the highest one signed are refused
+	conflict = *vote
+	conflict.Height += testMaxHeightAhead + 1
+	if _, err := remoteKey.Sign(&conflict); err == nil {
+		t.Fatal("vote too far ahead is signed")
+	}
+	conflict.Height--
+	if _, err := remoteKey.Sign(&conflict); err != nil {
+		t.Fatal(err)
+	}
+	// a vote after its propose timeslot is of no use
+	conflict.Height++
+	conflict.ProposeTimeSlot = now - 1
+	if _, err := remoteKey.Sign(&conflict); err == nil {
+		t.Fatal("vote of a past timeslot is signed")
+	}
+}
+
+func TestNewRemoteSigner(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+		wantErr bool
+	}{
+		{address: "unix:///var/run/signer.sock", want: "/var/run/signer.sock"},
+		{address: "signer.sock", want: "signer.sock"},
+		{address: "tcp://127.0.0.1:9000", wantErr: true},
+		{address: "unix://", wantErr: true},
+	}
+	for _, tt := range tests {
+		signer, err := NewRemoteSigner(tt.address)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("NewRemoteSigner(%v) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+		}
+		if err == nil && signer.address != tt.want {
+			t.Errorf("NewRemoteSigner(%v) address = %v, want %v", tt.address, signer.address, tt.want)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"encoding/json"
-	"time"
 
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
@@ -14,15 +13,16 @@ type Chain struct {
 	multiview *multiview.MultiView
 	chainID   int
 	chainName string
+	evidences []string // equivocation evidences reported by the engine
 }
 
-func NewChain(chainID int, chainName string, committee []incognitokey.CommitteePublicKey) *Chain {
+func NewChain(chainID int, chainName string, committee []incognitokey.CommitteePublicKey, genesisTime int64) *Chain {
 	c := new(Chain)
 	c.chainID = chainID
 	c.chainName = chainName
 	c.multiview = multiview.NewMultiView()
 	state := &State{
-		NewBlock(1, 1, "Genesis", common.Hash{}),
+		NewBlock(1, genesisTime, "Genesis", common.Hash{}),
 		committee,
 	}
 	c.multiview.AddView(state)
@@ -47,21 +47,24 @@ func (s *Chain) IsReady() bool {
 
 func (s *Chain) UnmarshalBlock(blockString []byte) (common.BlockInterface, error) {
 	blk := &blockchain.ShardBlock{}
-	json.Unmarshal(blockString, blk)
+	if err := json.Unmarshal(blockString, blk); err != nil {
+		return nil, err
+	}
 	return blk, nil
 }
 
 func (c *Chain) CreateNewBlock(version int, proposer string, round int, startTime int64) (common.BlockInterface, error) {
-	newBlock := NewBlock(c.GetBestView().GetHeight()+1, time.Now().Unix(), proposer, *c.GetBestView().GetHash())
+	newBlock := NewBlock(c.GetBestView().GetHeight()+1, startTime, proposer, *c.GetBestView().GetHash())
 	return newBlock, nil
 }
 
+// CreateNewBlockFromOldBlock re-proposes a copy of oldBlock, which keeps its
+// produce time
 func (c *Chain) CreateNewBlockFromOldBlock(oldBlock common.BlockInterface, proposer string, startTime int64) (common.BlockInterface, error) {
-	//TODO: must using the old block data, and timestamp
-	oldBlock.(*blockchain.ShardBlock).Header.Proposer = proposer
-	oldBlock.(*blockchain.ShardBlock).Header.ProposeTime = time.Now().Unix()
-	// newBlock := NewBlock(c.GetBestView().GetHeight()+1, oldBlock.GetProduceTime(), proposer, *c.GetBestView().GetHash())
-	return oldBlock, nil
+	newBlock := *oldBlock.(*blockchain.ShardBlock)
+	newBlock.Header.Proposer = proposer
+	newBlock.Header.ProposeTime = startTime
+	return &newBlock, nil
 }
 
 func (s *Chain) InsertAndBroadcastBlock(block common.BlockInterface) error {
@@ -84,3 +87,7 @@ func (s *Chain) GetShardID() int {
 func (c Chain) GetViewByHash(hash common.Hash) multiview.View {
 	return c.multiview.GetViewByHash(hash)
 }
+
+func (s *Chain) AddEquivocationEvidence(evidence string) {
+	s.evidences = append(s.evidences, evidence)
+}
@@ -1,18 +1,17 @@
 package main
 
 import (
-	"fmt"
+	"encoding/json"
 	"strconv"
 	"testing"
-	"time"
 
+	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/consensus_v2"
-	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/consensus_v2/blsbftv2"
+	"github.com/incognitochain/incognito-chain/wire"
 )
 
-var startTimeSlot uint64
-
 type TimeSlotScenerio struct {
 	ProposingScenerio []int                     // offset from position of current timeslot proposer
 	VotingScenerios   map[string][]int          // offset from position of current timeslot proposer
@@ -425,189 +424,192 @@ func Test_Main4Committee_Case1(t *testing.T) {
 	RunSimulation(&testScn4, t)
 
 }
-func RunSimulation(testScn *testScenerio, t *testing.T) {
-	simulation = nil
-	committeePkStruct := []incognitokey.CommitteePublicKey{}
-	committeePkBytes := [][]byte{}
-	for _, v := range testScn.Committee {
-		p, _ := consensus_v2.LoadUserKeyFromIncPrivateKey(v)
-		m, _ := consensus_v2.GetMiningKeyFromPrivateSeed(p)
-		pk := m.GetPublicKey()
-		committeePkStruct = append(committeePkStruct, *pk)
-		committeePkBytes = append(committeePkBytes, pk.MiningPubKey["bls"])
+
+// a partition without 2/3 of the committee on a side stops the chain until it
+// heals, the late votes of timeslot 5 commit the block in timeslot 6
+func Test_Main4Committee_Partition(t *testing.T) {
+	committee := []string{
+		"112t8rnXB47RhSdyVRU41TEf78nxbtWGtmjutwSp9YqsNaCpFxQGXcnwcXTtBkCGDk1KLBRBeWMvb2aXG5SeDUJRHtFV8jTB3weHEkbMJ1AL",
+		"112t8rnXVdfBqBMigSs5fm9NSS8rgsVVURUxArpv6DxYmPZujKqomqUa2H9wh1zkkmDGtDn2woK4NuRDYnYRtVkUhK34TMfbUF4MShSkrCw5",
+		"112t8rnXi8eKJ5RYJjyQYcFMThfbXHgaL6pq5AF5bWsDXwfsw8pqQUreDv6qgWyiABoDdphvqE7NFr9K92aomX7Gi5Nm1e4tEoV3qRLVdfSR",
+		"112t8rnY42xRqJghQX3zvhgEa2ZJBwSzJ46SXyVQEam1yNpN4bfAqJwh1SsobjHAz8wwRvwnqJBfxrbwUuTxqgEbuEE8yMu6F14QmwtwyM43",
+	}
+	scenario := NewScenario()
+	scenario.setPartition(2, len(committee), []int{0, 1}, []int{2, 3})
+	scenario.setPartition(3, len(committee), []int{0, 1}, []int{2, 3})
+	for sender := 1; sender < len(committee); sender++ {
+		scenario.setVoteComm(5, sender, []int{9, 9, 9, 9})
+	}
+	expected := map[uint64]map[string]Expected{
+		1: {"all": {BestHeight: 2, BestTimeSlot: 1, FinalHeight: 1, FinalTimeSlot: 0, ViewCount: 2}},
+		3: {"all": {BestHeight: 2, BestTimeSlot: 1, FinalHeight: 1, FinalTimeSlot: 0, ViewCount: 2}},
+		5: {"all": {BestHeight: 2, BestTimeSlot: 1, FinalHeight: 1, FinalTimeSlot: 0, ViewCount: 2}},
+		6: {"all": {BestHeight: 3, BestTimeSlot: 2, FinalHeight: 2, FinalTimeSlot: 1, ViewCount: 2}},
+		7: {"all": {BestHeight: 4, BestTimeSlot: 7, FinalHeight: 3, FinalTimeSlot: 2, ViewCount: 2}},
+	}
+	s, err := InitSimulation("partition", committee, 7, scenario, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range s.Run() {
+		t.Error(err)
 	}
-	nodeList := []*Node{}
+}
 
-	for i, v := range testScn.Committee {
-		p, _ := consensus_v2.LoadUserKeyFromIncPrivateKey(v)
-		m, _ := consensus_v2.GetMiningKeyFromPrivateSeed(p)
-		ni := NewNode(committeePkStruct, m, i, testScn.Name)
-		nodeList = append(nodeList, ni)
+// a node given two blocks the proposer of timeslot 1 signed for height 2, and
+// the votes of a validator for both, reports evidences of both equivocations
+// which the beacon accepts
+func Test_Main4Committee_Equivocation(t *testing.T) {
+	committee := []string{
+		"112t8rnXB47RhSdyVRU41TEf78nxbtWGtmjutwSp9YqsNaCpFxQGXcnwcXTtBkCGDk1KLBRBeWMvb2aXG5SeDUJRHtFV8jTB3weHEkbMJ1AL",
+		"112t8rnXVdfBqBMigSs5fm9NSS8rgsVVURUxArpv6DxYmPZujKqomqUa2H9wh1zkkmDGtDn2woK4NuRDYnYRtVkUhK34TMfbUF4MShSkrCw5",
+		"112t8rnXi8eKJ5RYJjyQYcFMThfbXHgaL6pq5AF5bWsDXwfsw8pqQUreDv6qgWyiABoDdphvqE7NFr9K92aomX7Gi5Nm1e4tEoV3qRLVdfSR",
+		"112t8rnY42xRqJghQX3zvhgEa2ZJBwSzJ46SXyVQEam1yNpN4bfAqJwh1SsobjHAz8wwRvwnqJBfxrbwUuTxqgEbuEE8yMu6F14QmwtwyM43",
 	}
-	var startNode = func() {
-		for i, v := range nodeList {
-			v.nodeList = nodeList
-			fmt.Println("node start", i)
-			go v.Start()
+	s, err := InitSimulation("equivocation", committee, 1, NewScenario(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposer, validator, node := s.nodeList[0], s.nodeList[2], s.nodeList[1]
+	committeePkStruct := node.chain.GetBestView().GetCommittee()
+	proposerPk, _ := committeePkStruct[0].ToBase58()
+	validatorPk, _ := committeePkStruct[2].ToBase58()
+	proposeTime := int64(s.startTimeSlot * TIMESLOT)
+	s.clock.now = proposeTime
+
+	proposals := []*wire.MessageBFT{}
+	votes := []*wire.MessageBFT{}
+	for _, txRoot := range []string{"a", "b"} {
+		block := NewBlock(2, proposeTime, proposerPk, *node.chain.GetBestView().GetHash()).(*blockchain.ShardBlock)
+		block.Header.Version = 2
+		block.Header.TxRoot = common.HashH([]byte(txRoot))
+		var validationData blsbftv2.ValidationData
+		validationData.ProducerBLSSig, _ = proposer.consensusEngine.UserKeySet[0].BriSignData(block.Hash().GetBytes())
+		validationDataString, _ := blsbftv2.EncodeValidationData(validationData)
+		block.AddValidationField(validationDataString)
+		blockData, _ := json.Marshal(block)
+		msg, err := blsbftv2.MakeBFTProposeMsg(&blsbftv2.BFTPropose{Block: blockData, PeerID: proposer.GetSelfPeerID().String()}, "shard", int64(s.startTimeSlot), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proposals = append(proposals, msg.(*wire.MessageBFT))
+
+		vote, err := blsbftv2.CreateVote(&validator.consensusEngine.UserKeySet[0], validator.consensusEngine.ChainID, block, committeePkStruct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err = blsbftv2.MakeBFTVoteMsg(vote, "shard", int64(s.startTimeSlot), 2)
+		if err != nil {
+			t.Fatal(err)
 		}
+		votes = append(votes, msg.(*wire.MessageBFT))
 	}
+	// the vote for the second block comes before the block
+	node.consensusEngine.Step([]*wire.MessageBFT{proposals[0], votes[1], votes[0], proposals[1]})
 
-	GetSimulation().nodeList = nodeList
-	startTimeSlot = uint64(common.CalculateTimeSlot(time.Now().Unix()))
-	GetSimulation().setStartTimeSlot(uint64(startTimeSlot))
-	var setTimeSlot = func(s int) uint64 {
-		return startTimeSlot + uint64(s) - 1
+	expected := map[string]bool{proposerPk: false, validatorPk: false}
+	for _, evidence := range node.chain.evidences {
+		member, _, err := new(consensus_v2.Engine).ValidateEquivocationEvidence(evidence, 0, committeePkStruct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reported, ok := expected[member]; !ok || reported {
+			t.Fatalf("unexpected evidence against %v", member)
+		}
+		expected[member] = true
 	}
-	var setProposeCommunication = func(timeslot uint64, scenario []int) {
-		if GetSimulation().scenario.proposeComm[timeslot] == nil {
-			GetSimulation().scenario.proposeComm[timeslot] = []int{}
+	for member, reported := range expected {
+		if !reported {
+			t.Errorf("no evidence against %v", member)
 		}
-		GetSimulation().scenario.proposeComm[timeslot] = scenario
 	}
-	var setVoteCommunication = func(timeslot uint64, nodeID int, scenario []int) {
-		if GetSimulation().scenario.voteComm[timeslot] == nil {
-			GetSimulation().scenario.voteComm[timeslot] = make(map[string][]int)
+	if _, _, err := new(consensus_v2.Engine).ValidateEquivocationEvidence(node.chain.evidences[0], 1, committeePkStruct); err == nil {
+		t.Error("evidence of shard 0 is valid for shard 1")
+	}
+
+	// an evidence with its blocks swapped proves the same equivocation
+	keys := map[common.Hash]bool{}
+	for _, data := range node.chain.evidences {
+		key, err := new(consensus_v2.Engine).GetEquivocationEvidenceKey(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[key] = true
+		evidence, err := blsbftv2.DecodeEquivocationEvidence(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		evidence.Headers[0], evidence.Headers[1] = evidence.Headers[1], evidence.Headers[0]
+		evidence.ProducerSigs[0], evidence.ProducerSigs[1] = evidence.ProducerSigs[1], evidence.ProducerSigs[0]
+		evidence.Votes[0], evidence.Votes[1] = evidence.Votes[1], evidence.Votes[0]
+		swapped, err := blsbftv2.EncodeEquivocationEvidence(evidence)
+		if err != nil {
+			t.Fatal(err)
 		}
-		GetSimulation().scenario.voteComm[timeslot][fmt.Sprintf("%d", nodeID)] = scenario
+		if swappedKey, err := new(consensus_v2.Engine).GetEquivocationEvidenceKey(swapped); err != nil || swappedKey != key {
+			t.Errorf("swapped evidence has key %v, want %v: %v", swappedKey, key, err)
+		}
+	}
+	if len(keys) != len(node.chain.evidences) {
+		t.Errorf("%v keys for %v equivocations", len(keys), len(node.chain.evidences))
 	}
+}
 
-	var timeslot uint64
-	for i := 1; i <= testScn.TimeSlots; i++ {
-		timeslot = setTimeSlot(i)
-		slotProducerIdx := GetIndexOfBytes(nodeList[0].chain.GetBestView().GetProposerByTimeSlot(int64(timeslot), 2).MiningPubKey["bls"], committeePkBytes)
-		if scenerio, ok := testScn.TimeSlotScenerios[i]; ok {
-			pComm := make([]int, len(testScn.Committee))
-			for a := range pComm {
-				pComm[a] = 1
-			}
+func RunSimulation(testScn *testScenerio, t *testing.T) {
+	n := len(testScn.Committee)
+	// offsets are from the position of the proposer of the timeslot
+	var getLinks = func(timeslot uint64, dropped []int) []int {
+		links := make([]int, n)
+		for a := range links {
+			links[a] = 1
+		}
+		for _, p := range dropped {
+			links[(getProposerIndex(timeslot, n)+p)%n] = 0
+		}
+		return links
+	}
 
-			for _, p := range scenerio.ProposingScenerio {
-				pComm[(slotProducerIdx+p)%len(testScn.Committee)] = 0
+	scenario := NewScenario()
+	expected := make(map[uint64]map[string]Expected)
+	for i, scenerio := range testScn.TimeSlotScenerios {
+		timeslot := uint64(i)
+		scenario.setProposeComm(timeslot, getLinks(timeslot, scenerio.ProposingScenerio))
+		if vaComm, ok := scenerio.VotingScenerios["all"]; ok {
+			for sender := 0; sender < n; sender++ {
+				scenario.setVoteComm(timeslot, sender, getLinks(timeslot, vaComm))
 			}
-			setProposeCommunication(timeslot, pComm)
-			if len(scenerio.VotingScenerios) > 0 {
-				if vaComm, ok := scenerio.VotingScenerios["all"]; ok {
-					vComm := make([]int, len(testScn.Committee))
-					for a := range vComm {
-						vComm[a] = 1
-					}
-					for _, p := range vaComm {
-						vComm[(slotProducerIdx+p)%len(testScn.Committee)] = 0
-					}
-					for n := 0; n < len(testScn.Committee); n++ {
-						setVoteCommunication(timeslot, n, vComm)
-					}
-				} else {
-					for n := 0; n < len(testScn.Committee); n++ {
-						if nComm, ok := scenerio.VotingScenerios[strconv.Itoa(n)]; ok {
-							vComm := make([]int, len(testScn.Committee))
-							for a := range vComm {
-								vComm[a] = 1
-							}
-							for _, p := range nComm {
-								vComm[(slotProducerIdx+p)%len(testScn.Committee)] = 0
-							}
-							setVoteCommunication(timeslot, n, vComm)
-						}
-					}
+		} else {
+			for sender := 0; sender < n; sender++ {
+				if nComm, ok := scenerio.VotingScenerios[strconv.Itoa(sender)]; ok {
+					scenario.setVoteComm(timeslot, sender, getLinks(timeslot, nComm))
 				}
 			}
 		}
-	}
-
-	GetSimulation().setMaxTimeSlot(timeslot)
-	startNode()
-	lastTimeSlot := uint64(0)
-	for {
-		curTimeSlotTime := uint64(common.CalculateTimeSlot(time.Now().Unix()))
-		curTimeSlot := (curTimeSlotTime - startTimeSlot) + 1
-		if lastTimeSlot != curTimeSlot {
-			time.AfterFunc(time.Millisecond*1500, func() {
-				slotProducerIdx := GetIndexOfBytes(nodeList[0].chain.GetBestView().GetProposerByTimeSlot(int64(curTimeSlotTime), 2).MiningPubKey["bls"], committeePkBytes)
-				fmt.Println("==========================")
-				fmt.Printf("Timeslot is: %v\n", curTimeSlot)
-				fmt.Printf("Proposer is: %d\n", slotProducerIdx)
-				expectedOutput := true
-				if _, ok := testScn.TimeSlotScenerios[int(curTimeSlot)]; ok {
-					if len(testScn.TimeSlotScenerios[int(curTimeSlot)].ExpectedOutput) > 0 {
-						if output, ok := testScn.TimeSlotScenerios[int(curTimeSlot)].ExpectedOutput["all"]; ok {
-							for i := 0; i < len(testScn.Committee); i++ {
-								if nodeList[i].chain.GetBestView().GetHeight() != output.BestHeight {
-									expectedOutput = false
-									fmt.Println("BestHeight not match!")
-									break
-								}
-								if uint64(common.CalculateTimeSlot(nodeList[i].chain.GetBestView().GetBlock().GetProduceTime()))-startTimeSlot+1 != output.BestTimeslot {
-									expectedOutput = false
-									fmt.Println("BestTimeslot not match!")
-									break
-								}
-								if nodeList[i].chain.GetFinalView().GetHeight() != output.FinalHeight {
-									expectedOutput = false
-									fmt.Println("FinalHeight not match!")
-									break
-								}
-								if uint64(common.CalculateTimeSlot(nodeList[i].chain.GetFinalView().GetBlock().GetProduceTime()))-startTimeSlot+1 != output.FinalTimeslot {
-									expectedOutput = false
-									fmt.Println("FinalHeight not match!")
-									break
-								}
-								if len(nodeList[i].chain.multiview.GetAllViewsWithBFS()) != output.ViewCount {
-									expectedOutput = false
-									fmt.Println("Viewcount not match!")
-									break
-								}
-							}
-						} else {
-							for nodeID, output := range testScn.TimeSlotScenerios[int(curTimeSlot)].ExpectedOutput {
-								nodeIDOffset, _ := strconv.Atoi(nodeID)
-								if nodeList[(slotProducerIdx+nodeIDOffset)%len(testScn.Committee)].chain.GetBestView().GetHeight() != output.BestHeight {
-									expectedOutput = false
-									fmt.Println("BestHeight not match!")
-									break
-								}
-								if uint64(common.CalculateTimeSlot(nodeList[(slotProducerIdx+nodeIDOffset)%len(testScn.Committee)].chain.GetBestView().GetBlock().GetProduceTime()))-startTimeSlot+1 != output.BestTimeslot {
-									expectedOutput = false
-									fmt.Println("BestTimeslot not match!")
-									break
-								}
-								if nodeList[(slotProducerIdx+nodeIDOffset)%len(testScn.Committee)].chain.GetFinalView().GetHeight() != output.FinalHeight {
-									expectedOutput = false
-									fmt.Println("FinalHeight not match!")
-									break
-								}
-								if uint64(common.CalculateTimeSlot(nodeList[(slotProducerIdx+nodeIDOffset)%len(testScn.Committee)].chain.GetFinalView().GetBlock().GetProduceTime()))-startTimeSlot+1 != output.FinalTimeslot {
-									expectedOutput = false
-									fmt.Println("FinalHeight not match!")
-									break
-								}
-								if len(nodeList[(slotProducerIdx+nodeIDOffset)%len(testScn.Committee)].chain.multiview.GetAllViewsWithBFS()) != output.ViewCount {
-									expectedOutput = false
-									fmt.Println("Viewcount not match!")
-									break
-								}
-							}
-						}
-					}
-				}
-				fmt.Printf("ExpectedOutput met: %v\n", expectedOutput)
-
-				for i := 0; i < len(testScn.Committee); i++ {
-					fmt.Printf("Node %v: \n -Best view height: %d:%d\n -Final view height: %d:%d\n -View count: %v\n", i, nodeList[i].chain.GetBestView().GetHeight(), uint64(common.CalculateTimeSlot(nodeList[i].chain.GetBestView().GetBlock().GetProduceTime()))-startTimeSlot+1, nodeList[i].chain.GetFinalView().GetHeight(), uint64(common.CalculateTimeSlot(nodeList[i].chain.GetFinalView().GetBlock().GetProduceTime()))-startTimeSlot+1, len(nodeList[i].chain.multiview.GetAllViewsWithBFS()))
-				}
-				fmt.Println("==========================")
-			})
+		if len(scenerio.ExpectedOutput) > 0 {
+			expected[timeslot] = make(map[string]Expected)
 		}
-		lastTimeSlot = curTimeSlot
-		time.Sleep(1 * time.Millisecond)
-		if curTimeSlot == uint64(testScn.TimeSlots) {
-			for i, v := range nodeList {
-				fmt.Println("node stop", i)
-				go v.Stop()
+		for nodeID, output := range scenerio.ExpectedOutput {
+			if nodeID != "all" {
+				nodeIDOffset, _ := strconv.Atoi(nodeID)
+				nodeID = strconv.Itoa((getProposerIndex(timeslot, n) + nodeIDOffset) % n)
+			}
+			expected[timeslot][nodeID] = Expected{
+				BestHeight:    output.BestHeight,
+				BestTimeSlot:  output.BestTimeslot,
+				FinalHeight:   output.FinalHeight,
+				FinalTimeSlot: output.FinalTimeslot,
+				ViewCount:     output.ViewCount,
 			}
-			return
 		}
 	}
+
+	s, err := InitSimulation(testScn.Name, testScn.Committee, uint64(testScn.TimeSlots), scenario, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range s.Run() {
+		t.Error(err)
+	}
 }
 
 //func Test_Main4BeaconCommittee_ScenarioA(t *testing.T) {
@@ -3,27 +3,23 @@ package main
 import (
 	"fmt"
 	"os"
-	"strings"
-	"sync"
-	"time"
-
-	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
 
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/consensus_v2/blsbftv2"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/multiview"
 	"github.com/incognitochain/incognito-chain/wire"
 	libp2p "github.com/libp2p/go-libp2p-peer"
 )
 
-var mapNodeID = make(map[string]string)
-var mapLock = sync.Mutex{}
-
+// Node is a member of the committee of a simulation, it is the NodeInterface
+// of its engine
 type Node struct {
 	id              string
 	consensusEngine *blsbftv2.BLSBFT_V2
 	chain           *Chain
-	nodeList        []*Node
+	simulation      *Simulation
 }
 
 type logWriter struct {
@@ -36,8 +32,8 @@ func (s logWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func NewNode(committeePkStruct []incognitokey.CommitteePublicKey, miningKey *signatureschemes.MiningKey, index int, testname string) *Node {
-	name := fmt.Sprintf("%s_log%d", testname, index)
+func NewNode(simulation *Simulation, committeePkStruct []incognitokey.CommitteePublicKey, miningKey *signatureschemes.MiningKey, index int, genesisTime int64) *Node {
+	name := fmt.Sprintf("%s_log%d", simulation.name, index)
 	fd, err := os.OpenFile(fmt.Sprintf("%s.log", name), os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
 		panic(err)
@@ -49,116 +45,53 @@ func NewNode(committeePkStruct []incognitokey.CommitteePublicKey, miningKey *sig
 	})
 	consensusLogger := backendLog.Logger("Consensus", false)
 	consensusLogger.SetLevel(1)
-	chainViewLogger := backendLog.Logger("ShardView", false)
-	chainViewLogger.SetLevel(1)
 
-	node := Node{id: fmt.Sprintf("%d", index)}
-	node.chain = NewChain(0, "shard0", committeePkStruct)
+	node := &Node{id: fmt.Sprintf("%d", index), simulation: simulation}
+	node.chain = NewChain(0, "shard0", committeePkStruct, genesisTime)
 
 	node.consensusEngine = &blsbftv2.BLSBFT_V2{
 		Chain:    node.chain,
-		Node:     &node,
+		Node:     node,
 		ChainKey: "shard",
 		PeerID:   name,
 		Logger:   consensusLogger,
+		Clock:    simulation.clock,
 	}
 	node.consensusEngine.LoadUserKeys([]signatureschemes.MiningKey{*miningKey})
-	return &node
+	return node
 }
 
 func (s *Node) PushMessageToChain(msg wire.Message, chain common.ChainInterface) error {
-	time.AfterFunc(time.Millisecond*100, func() {
-		if msg.(*wire.MessageBFT).Type == "propose" {
-			timeSlot := uint64(msg.(*wire.MessageBFT).TimeSlot)
-			if timeSlot > GetSimulation().maxTimeSlot {
-				os.Exit(0)
-			}
-			pComm := GetSimulation().scenario.proposeComm
-			if senderComm, ok := pComm[timeSlot]; ok {
-				for i, c := range s.nodeList {
-					if s.id == c.id {
-						continue
-					}
-					if senderComm[i] == 0 {
-						fmt.Printf("Don'trrrrrr send propose from %v to %v at timeslot %v\n", s.id, c.id, timeSlot-GetSimulation().startTimeSlot+1)
-						// c.consensusEngine.ProcessBFTMsg(msg.(*wire.MessageBFT))
-					} else {
-						fmt.Printf("Send propose from %v to %v at timeslot %v\n", s.id, c.id, timeSlot-GetSimulation().startTimeSlot+1)
-						c.consensusEngine.ProcessBFTMsg(msg.(*wire.MessageBFT))
-					}
-				}
-			} else {
-				for _, c := range s.nodeList {
-					if s.id == c.id {
-						continue
-					}
-					fmt.Printf("Send* propose from %v to %v at timeslot %v\n", s.id, c.id, timeSlot-GetSimulation().startTimeSlot+1)
-					c.consensusEngine.ProcessBFTMsg(msg.(*wire.MessageBFT))
-				}
-			}
-			return
-		}
-
-		if msg.(*wire.MessageBFT).Type == "vote" {
-			vComm := GetSimulation().scenario.voteComm
-			timeSlot := uint64(msg.(*wire.MessageBFT).TimeSlot)
-			if timeSlot > GetSimulation().maxTimeSlot {
-				os.Exit(0)
-			}
-			if comm, ok := vComm[timeSlot]; ok {
-				for i, c := range s.nodeList {
-					if s.id == c.id {
-						continue
-					}
-					if senderComm, ok := comm[s.id]; ok {
-						if senderComm[i] == 0 {
-							fmt.Printf("Don't send vote from %v to %v at timeslot %v\n", s.id, c.id, timeSlot-GetSimulation().startTimeSlot+1)
-							// c.consensusEngine.ProcessBFTMsg(msg.(*wire.MessageBFT))
-						} else {
-							fmt.Printf("Send vote from %v to %v at timeslot %v\n", s.id, c.id, timeSlot-GetSimulation().startTimeSlot+1)
-							c.consensusEngine.ProcessBFTMsg(msg.(*wire.MessageBFT))
-						}
-					} else {
-						fmt.Printf("Send vote from %v to %v at timeslot %v\n", s.id, c.id, timeSlot-GetSimulation().startTimeSlot+1)
-						c.consensusEngine.ProcessBFTMsg(msg.(*wire.MessageBFT))
-					}
-
-				}
-			} else {
-				for _, c := range s.nodeList {
-					if s.id == c.id {
-						continue
-					}
-					fmt.Printf("Send* vote from %v to %v at timeslot %v\n", s.id, c.id, timeSlot-GetSimulation().startTimeSlot+1)
-					c.consensusEngine.ProcessBFTMsg(msg.(*wire.MessageBFT))
-				}
-			}
-			return
-		}
-	})
-
+	s.simulation.send(s, msg.(*wire.MessageBFT))
 	return nil
 }
 
+// RequestMissingViewViaStream adds the views of hashes and their missing
+// ancestors from the chain of the node of peerID, if the scenario lets the
+// node sync from it
 func (s *Node) RequestMissingViewViaStream(peerID string, hashes [][]byte, fromCID int, chainName string) (err error) {
-	str := []string{}
-	for _, h := range hashes {
-		pH, _ := common.Hash{}.NewHash(h)
-		str = append(str, pH.String())
+	peer := s.simulation.getNodeByPeerID(peerID)
+	if peer == nil {
+		return fmt.Errorf("unknown peer %v", peerID)
+	}
+	if !s.simulation.canSync(s.id, peer.id) {
+		s.logf("can not sync from %v", peer.id)
+		return nil
 	}
-	for _, c := range s.nodeList {
-		if s.id == c.id {
-			continue
+	for _, h := range hashes {
+		hash, err := common.Hash{}.NewHash(h)
+		if err != nil {
+			return err
+		}
+		missingViews := []multiview.View{}
+		for view := peer.chain.GetViewByHash(*hash); view != nil && s.chain.GetViewByHash(*view.GetHash()) == nil; view = peer.chain.GetViewByHash(*view.GetPreviousHash()) {
+			missingViews = append(missingViews, view)
 		}
-		if c.id == mapNodeID[peerID] {
-			for _, h := range hashes {
-				pH, _ := common.Hash{}.NewHash(h)
-				v := c.chain.multiview.GetViewByHash(*pH)
-				s.chain.multiview.AddView(v)
-			}
+		for i := len(missingViews) - 1; i >= 0; i-- {
+			s.chain.multiview.AddView(missingViews[i])
 		}
+		s.logf("sync %v views up to %v from %v", len(missingViews), hash.String(), peer.id)
 	}
-	fmt.Printf("Node %v RequestMissingViewViaStream from %v hash %v \n", s.id, mapNodeID[peerID], strings.Join(str, ","))
 	return nil
 }
 
@@ -166,17 +99,6 @@ func (s *Node) GetSelfPeerID() libp2p.ID {
 	return libp2p.ID(s.id)
 }
 
-func (s *Node) Start() {
-	fmt.Printf("Node %s log is %s, peerID: %v \n", s.id, fmt.Sprintf("log%s.log", s.id), libp2p.ID(s.id).String())
-	mapLock.Lock()
-	defer mapLock.Unlock()
-	mapNodeID[libp2p.ID(s.id).String()] = s.id
-	s.consensusEngine.Start()
-}
-
-func (s *Node) Stop() {
-	mapLock.Lock()
-	defer mapLock.Unlock()
-	delete(mapNodeID, libp2p.ID(s.id).String())
-	s.consensusEngine.Stop()
+func (s *Node) logf(format string, args ...interface{}) {
+	s.consensusEngine.Logger.Infof("SIMULATION NODE %v "+format, append([]interface{}{s.id}, args...)...)
 }
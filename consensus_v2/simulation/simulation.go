@@ -1,56 +1,309 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/wire"
 )
 
+// TIMESLOT is the duration in seconds of a timeslot of the simulation, the
+// engines are stepped every second of the virtual clock
+const TIMESLOT = 10
+
+// Simulation runs the blsbftv2 engines of a committee in process, over a fake
+// network and a virtual clock. The engines are stepped one after the other
+// every virtual second, the messages they send are delivered at the following
+// steps as the scenario says, so a run is replayed the same way every time.
+// The timeslots of the scenario and of the expected views count from 1, the
+// genesis block is produced in timeslot 0.
 type Simulation struct {
+	name          string
 	nodeList      []*Node
-	fd            os.File
+	clock         *VirtualClock
 	maxTimeSlot   uint64
 	startTimeSlot uint64
 	scenario      Scenario
-	expected      Expected
+	expected      map[uint64]map[string]Expected // timeslot -> node id -> views at the end of the timeslot, "all" for every node
+
+	step     int64
+	sequence uint64
+	inbox    map[string][]*delivery // node id -> messages in flight
 }
 
+// Scenario scripts the network of a simulation per timeslot. A link is the
+// delay in seconds after which the receiver gets the messages of the
+// timeslot, 0 drops them. The links which are not set deliver in 1 second.
 type Scenario struct {
-	proposeComm map[uint64][]int // timeSlot -> sender -> receiver
-	voteComm    map[uint64]map[string][]int
-	sync        map[string][]string
+	proposeComm map[uint64][]int            // timeSlot -> receiver -> link of the proposal of the timeslot
+	voteComm    map[uint64]map[string][]int // timeSlot -> sender -> receiver -> link of the votes of the timeslot
+	sync        map[string][]string         // node -> nodes it can sync missing views from, every node if not set
 }
 
+// Expected are the views of a node at the end of a timeslot, the timeslots are
+// those of the produce time of their block
 type Expected struct {
+	BestHeight    uint64
+	BestTimeSlot  uint64
+	FinalHeight   uint64
+	FinalTimeSlot uint64
+	ViewCount     int // views from the final view
+}
+
+type delivery struct {
+	step     int64
+	sequence uint64
+	msg      *wire.MessageBFT
+}
+
+// VirtualClock is the clock of the engines of a simulation, it only moves
+// when the simulation steps
+type VirtualClock struct {
+	now int64
+}
+
+func (c *VirtualClock) Now() int64 {
+	return c.now
+}
+
+func NewScenario() Scenario {
+	return Scenario{
+		proposeComm: make(map[uint64][]int),
+		voteComm:    make(map[uint64]map[string][]int),
+		sync:        make(map[string][]string),
+	}
 }
 
-var simulation *Simulation
+func (sc Scenario) setProposeComm(timeSlot uint64, links []int) {
+	sc.proposeComm[timeSlot] = links
+}
 
-func InitSimulation(scenario []map[string]Scenario, expected []map[string]Expected) *Simulation {
-	return simulation
+func (sc Scenario) setVoteComm(timeSlot uint64, sender int, links []int) {
+	if sc.voteComm[timeSlot] == nil {
+		sc.voteComm[timeSlot] = make(map[string][]int)
+	}
+	sc.voteComm[timeSlot][strconv.Itoa(sender)] = links
 }
 
-func GetSimulation() *Simulation {
-	if simulation == nil {
-		simulation = new(Simulation)
-		simulation.scenario = Scenario{
-			proposeComm: make(map[uint64][]int),
-			voteComm:    make(map[uint64]map[string][]int),
-			sync:        make(map[string][]string),
+// setPartition drops the proposal and the votes of timeSlot between the nodes
+// of different groups among the n nodes of the committee, a node which is in
+// no group is cut from all the others
+func (sc Scenario) setPartition(timeSlot uint64, n int, groups ...[]int) {
+	groupOf := make([]int, n)
+	for i := range groupOf {
+		groupOf[i] = -1 - i
+	}
+	for g, group := range groups {
+		for _, i := range group {
+			groupOf[i] = g
 		}
 	}
-	return simulation
+	links := func(from int) []int {
+		l := make([]int, n)
+		for i := range l {
+			if groupOf[i] == groupOf[from] {
+				l[i] = 1
+			}
+		}
+		return l
+	}
+	sc.setProposeComm(timeSlot, links(getProposerIndex(timeSlot, n)))
+	for i := 0; i < n; i++ {
+		sc.setVoteComm(timeSlot, i, links(i))
+	}
+}
+
+// getProposerIndex returns the index in the committee of size n of the
+// proposer of timeSlot, the first member proposes in the first timeslot
+func getProposerIndex(timeSlot uint64, n int) int {
+	return int((timeSlot - 1) % uint64(n))
+}
+
+// InitSimulation creates the nodes of committee, the private keys of its
+// members, with the scenario to run for maxTimeSlot timeslots and the views
+// expected at the end of the timeslots. The nodes write their logs to
+// <name>_log<index>.log.
+func InitSimulation(name string, committee []string, maxTimeSlot uint64, scenario Scenario, expected map[uint64]map[string]Expected) (*Simulation, error) {
+	if len(committee) == 0 {
+		return nil, fmt.Errorf("simulation %v has no committee", name)
+	}
+	common.TIMESLOT = TIMESLOT
+	s := &Simulation{
+		name:     name,
+		clock:    new(VirtualClock),
+		scenario: scenario,
+		expected: expected,
+		inbox:    make(map[string][]*delivery),
+	}
+	// see getProposerIndex
+	s.startTimeSlot = uint64(len(committee)) * 1000
+	s.setMaxTimeSlot(maxTimeSlot)
+	s.clock.now = int64(s.startTimeSlot * TIMESLOT)
+
+	committeePkStruct := []incognitokey.CommitteePublicKey{}
+	miningKeys := []*signatureschemes.MiningKey{}
+	for _, v := range committee {
+		p, err := consensus_v2.LoadUserKeyFromIncPrivateKey(v)
+		if err != nil {
+			return nil, err
+		}
+		m, err := consensus_v2.GetMiningKeyFromPrivateSeed(p)
+		if err != nil {
+			return nil, err
+		}
+		committeePkStruct = append(committeePkStruct, *m.GetPublicKey())
+		miningKeys = append(miningKeys, m)
+	}
+	genesisTime := int64((s.startTimeSlot - 1) * TIMESLOT)
+	for i, m := range miningKeys {
+		s.nodeList = append(s.nodeList, NewNode(s, committeePkStruct, m, i, genesisTime))
+	}
+	return s, nil
 }
 
 func (s *Simulation) setMaxTimeSlot(max uint64) {
-	fmt.Println("Set Max Time Slot", max)
 	s.maxTimeSlot = max
 }
-func (s *Simulation) setStartTimeSlot(start uint64) {
-	fmt.Println("Set Start Time Slot", start)
-	s.startTimeSlot = start
+
+// Run steps the engines until the end of the last timeslot and returns the
+// differences between the views of the nodes and the expected ones
+func (s *Simulation) Run() []error {
+	errs := []error{}
+	for timeSlot := uint64(1); timeSlot <= s.maxTimeSlot; timeSlot++ {
+		for i := 0; i < TIMESLOT; i++ {
+			s.clock.now = int64((s.startTimeSlot+timeSlot-1)*TIMESLOT) + int64(i)
+			s.step++
+			for _, node := range s.nodeList {
+				node.consensusEngine.Step(s.receive(node.id))
+			}
+		}
+		errs = append(errs, s.checkExpected(timeSlot)...)
+	}
+	return errs
+}
+
+// send puts msg of sender in flight to the other nodes
+func (s *Simulation) send(sender *Node, msg *wire.MessageBFT) {
+	timeSlot := uint64(msg.TimeSlot) - s.startTimeSlot + 1
+	for i, receiver := range s.nodeList {
+		if receiver.id == sender.id {
+			continue
+		}
+		link := s.link(msg.Type, timeSlot, sender.id, i)
+		if link <= 0 {
+			receiver.logf("drop %v from %v at timeslot %v", msg.Type, sender.id, timeSlot)
+			continue
+		}
+		s.sequence++
+		s.inbox[receiver.id] = append(s.inbox[receiver.id], &delivery{
+			step:     s.step + int64(link),
+			sequence: s.sequence,
+			msg:      msg,
+		})
+	}
+}
+
+func (s *Simulation) link(msgType string, timeSlot uint64, sender string, receiver int) int {
+	var links []int
+	switch msgType {
+	case "propose":
+		links = s.scenario.proposeComm[timeSlot]
+	case "vote":
+		links = s.scenario.voteComm[timeSlot][sender]
+	}
+	if receiver >= len(links) {
+		return 1
+	}
+	return links[receiver]
+}
+
+// receive takes the messages to deliver to node id at the current step, in
+// the order they were sent
+func (s *Simulation) receive(id string) []*wire.MessageBFT {
+	inFlight := s.inbox[id]
+	sort.SliceStable(inFlight, func(i, j int) bool {
+		if inFlight[i].step != inFlight[j].step {
+			return inFlight[i].step < inFlight[j].step
+		}
+		return inFlight[i].sequence < inFlight[j].sequence
+	})
+	msgs := []*wire.MessageBFT{}
+	n := 0
+	for n < len(inFlight) && inFlight[n].step <= s.step {
+		msgs = append(msgs, inFlight[n].msg)
+		n++
+	}
+	s.inbox[id] = inFlight[n:]
+	return msgs
 }
 
-func (s *Simulation) setLogfile(fd os.File) {
-	s.fd = fd
+// canSync tells whether node id can sync missing views from peer
+func (s *Simulation) canSync(id string, peer string) bool {
+	peers, ok := s.scenario.sync[id]
+	return !ok || common.IndexOfStr(peer, peers) != -1
+}
 
+func (s *Simulation) getNodeByPeerID(peerID string) *Node {
+	for _, node := range s.nodeList {
+		if node.GetSelfPeerID().String() == peerID {
+			return node
+		}
+	}
+	return nil
+}
+
+func (s *Simulation) checkExpected(timeSlot uint64) []error {
+	errs := []error{}
+	expected := s.expected[timeSlot]
+	for _, node := range s.nodeList {
+		output, ok := expected[node.id]
+		if !ok {
+			if output, ok = expected["all"]; !ok {
+				continue
+			}
+		}
+		if actual := s.getViews(node); actual != output {
+			errs = append(errs, fmt.Errorf("%v timeslot %v node %v: expected views %+v, got %+v", s.name, timeSlot, node.id, output, actual))
+		}
+	}
+	return errs
+}
+
+// getViews returns the views of node like an Expected
+func (s *Simulation) getViews(node *Node) Expected {
+	bestView, finalView := node.chain.GetBestView(), node.chain.GetFinalView()
+	return Expected{
+		BestHeight:    bestView.GetHeight(),
+		BestTimeSlot:  s.getTimeSlot(bestView.GetBlock().GetProduceTime()),
+		FinalHeight:   finalView.GetHeight(),
+		FinalTimeSlot: s.getTimeSlot(finalView.GetBlock().GetProduceTime()),
+		ViewCount:     len(node.chain.multiview.GetAllViewsWithBFS()),
+	}
+}
+
+func (s *Simulation) getTimeSlot(t int64) uint64 {
+	return uint64(common.CalculateTimeSlot(t)) - s.startTimeSlot + 1
+}
+
+// main runs the committee of the private keys of the arguments on a fully
+// connected network and prints the views of the nodes
+func main() {
+	maxTimeSlot := flag.Uint64("timeslots", 10, "number of timeslots to run")
+	flag.Parse()
+	s, err := InitSimulation("simulation", flag.Args(), *maxTimeSlot, NewScenario(), nil)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	s.Run()
+	for _, node := range s.nodeList {
+		fmt.Printf("Node %v: %+v\n", node.id, s.getViews(node))
+	}
 }
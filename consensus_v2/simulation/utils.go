@@ -17,7 +17,14 @@ func NewEmptyBlock() common.BlockInterface {
 	return &blockchain.ShardBlock{}
 }
 
+// NewBlock returns an empty block which passes the sanity checks of the
+// unmarshaling of shard blocks. The blocks are of version 1: the final view is
+// the previous view of the best view.
 func NewBlock(height uint64, time int64, producer string, prev common.Hash) common.BlockInterface {
+	committeeRoot := common.Hash{}
+	if height > 1 {
+		committeeRoot = common.HashH([]byte("committee"))
+	}
 	return &blockchain.ShardBlock{
 		Header: blockchain.ShardHeader{
 			Version:           1,
@@ -29,8 +36,14 @@ func NewBlock(height uint64, time int64, producer string, prev common.Hash) comm
 			Producer:          producer,
 			ProposeTime:       time,
 			Proposer:          producer,
+			CommitteeRoot:     committeeRoot,
+			BeaconHeight:      1,
+			TotalTxsFee:       make(map[common.Hash]uint64),
+		},
+		Body: blockchain.ShardBody{
+			Instructions:      [][]string{},
+			CrossTransactions: make(map[byte][]blockchain.CrossTransaction),
 		},
-		Body: blockchain.ShardBody{},
 	}
 }
 
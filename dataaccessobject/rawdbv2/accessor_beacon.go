@@ -1,8 +1,12 @@
 package rawdbv2
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/incdb"
 )
@@ -96,3 +100,84 @@ func GetBeaconViews(db incdb.KeyValueReader) ([]byte, error) {
 	}
 	return block, nil
 }
+
+// StoreBeaconStateSyncView stores the beacon view downloaded from a peer when
+// the node synced the beacon state instead of processing the blocks before it
+func StoreBeaconStateSyncView(db incdb.KeyValueWriter, val []byte) error {
+	key := GetBeaconStateSyncViewKey()
+	if err := db.Put(key, val); err != nil {
+		return NewRawdbError(StoreBeaconBestStateError, err)
+	}
+	return nil
+}
+
+func GetBeaconStateSyncView(db incdb.KeyValueReader) ([]byte, error) {
+	key := GetBeaconStateSyncViewKey()
+	val, err := db.Get(key)
+	if err != nil {
+		return nil, NewRawdbError(GetBeaconBestStateError, err)
+	}
+	return val, nil
+}
+
+// StoreBeaconCommitteeSwap stores the encoded swap of the beacon committee by
+// the finalized beacon block at height
+func StoreBeaconCommitteeSwap(db incdb.KeyValueWriter, height uint64, data []byte) error {
+	if err := db.Put(GetBeaconCommitteeSwapKey(height), data); err != nil {
+		return NewRawdbError(StoreBeaconCommitteeSwapError, err, height)
+	}
+	return nil
+}
+
+// GetBeaconCommitteeSwaps returns, in height order, at most limit encoded
+// swaps of the beacon committee from height from to height to
+func GetBeaconCommitteeSwaps(db incdb.Database, from uint64, to uint64, limit int) ([][]byte, error) {
+	end := GetBeaconCommitteeSwapKey(to)
+	iterator := db.NewIteratorWithStart(GetBeaconCommitteeSwapKey(from))
+	defer iterator.Release()
+	res := [][]byte{}
+	for len(res) < limit && iterator.Next() {
+		if !bytes.HasPrefix(iterator.Key(), beaconCommitteeSwapPrefix) || bytes.Compare(iterator.Key(), end) > 0 {
+			break
+		}
+		value := make([]byte, len(iterator.Value()))
+		copy(value, iterator.Value())
+		res = append(res, value)
+	}
+	if err := iterator.Error(); err != nil {
+		return nil, NewRawdbError(GetBeaconCommitteeSwapsError, err, from, to)
+	}
+	return res, nil
+}
+
+// StoreBeaconCommitteeSwapIndexHeight stores the height up to which every
+// swap of the beacon committee is stored
+func StoreBeaconCommitteeSwapIndexHeight(db incdb.KeyValueWriter, height uint64) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, height)
+	if err := db.Put(GetBeaconCommitteeSwapIndexHeightKey(), buf); err != nil {
+		return NewRawdbError(StoreBeaconCommitteeSwapIndexHeightError, err, height)
+	}
+	return nil
+}
+
+// GetBeaconCommitteeSwapIndexHeight returns the height up to which every swap
+// of the beacon committee is stored, zero if nothing has been indexed
+func GetBeaconCommitteeSwapIndexHeight(db incdb.Database) (uint64, error) {
+	key := GetBeaconCommitteeSwapIndexHeightKey()
+	has, err := db.Has(key)
+	if err != nil {
+		return 0, NewRawdbError(GetBeaconCommitteeSwapIndexHeightError, err)
+	}
+	if !has {
+		return 0, nil
+	}
+	res, err := db.Get(key)
+	if err != nil {
+		return 0, NewRawdbError(GetBeaconCommitteeSwapIndexHeightError, err)
+	}
+	if len(res) != 8 {
+		return 0, NewRawdbError(GetBeaconCommitteeSwapIndexHeightError, errors.New("invalid height length"))
+	}
+	return binary.BigEndian.Uint64(res), nil
+}
@@ -0,0 +1,94 @@
+package rawdbv2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/incognitochain/incognito-chain/incdb"
+)
+
+// StoreChainEvent stores the encoded event of a chain at its sequence number,
+// the first event stored at a height is indexed by this height
+func StoreChainEvent(db incdb.KeyValueWriter, chainID int, sequence uint64, height uint64, firstAtHeight bool, data []byte) error {
+	if err := db.Put(GetChainEventKey(chainID, sequence), data); err != nil {
+		return NewRawdbError(StoreChainEventError, err, chainID, sequence)
+	}
+	if firstAtHeight {
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, sequence)
+		if err := db.Put(GetChainEventHeightKey(chainID, height), buf); err != nil {
+			return NewRawdbError(StoreChainEventError, err, chainID, sequence)
+		}
+	}
+	return nil
+}
+
+// HasChainEventAtHeight tells whether an event of a chain is indexed at height
+func HasChainEventAtHeight(db incdb.KeyValueReader, chainID int, height uint64) (bool, error) {
+	has, err := db.Has(GetChainEventHeightKey(chainID, height))
+	if err != nil {
+		return false, NewRawdbError(GetChainEventSequenceByHeightError, err, chainID, height)
+	}
+	return has, nil
+}
+
+// GetChainEventSequenceByHeight returns the sequence number of the first event
+// of a chain stored at height
+func GetChainEventSequenceByHeight(db incdb.KeyValueReader, chainID int, height uint64) (uint64, error) {
+	res, err := db.Get(GetChainEventHeightKey(chainID, height))
+	if err != nil {
+		return 0, NewRawdbError(GetChainEventSequenceByHeightError, err, chainID, height)
+	}
+	if len(res) != 8 {
+		return 0, NewRawdbError(GetChainEventSequenceByHeightError, errors.New("invalid sequence length"), chainID, height)
+	}
+	return binary.BigEndian.Uint64(res), nil
+}
+
+// GetChainEvents returns, in sequence order, at most limit encoded events of a
+// chain starting at sequence from
+func GetChainEvents(db incdb.Database, chainID int, from uint64, limit int) ([][]byte, error) {
+	prefix := GetChainEventPrefix(chainID)
+	iterator := db.NewIteratorWithStart(GetChainEventKey(chainID, from))
+	defer iterator.Release()
+	res := [][]byte{}
+	for len(res) < limit && iterator.Next() {
+		if !bytes.HasPrefix(iterator.Key(), prefix) {
+			break
+		}
+		value := make([]byte, len(iterator.Value()))
+		copy(value, iterator.Value())
+		res = append(res, value)
+	}
+	if err := iterator.Error(); err != nil {
+		return nil, NewRawdbError(GetChainEventsError, err, chainID, from)
+	}
+	return res, nil
+}
+
+// StoreChainEventTip stores the encoded tip of the event log of a chain
+func StoreChainEventTip(db incdb.KeyValueWriter, chainID int, data []byte) error {
+	if err := db.Put(GetChainEventTipKey(chainID), data); err != nil {
+		return NewRawdbError(StoreChainEventTipError, err, chainID)
+	}
+	return nil
+}
+
+// GetChainEventTip returns the encoded tip of the event log of a chain, nil if
+// nothing has been logged
+func GetChainEventTip(db incdb.Database, chainID int) ([]byte, error) {
+	key := GetChainEventTipKey(chainID)
+	has, err := db.Has(key)
+	if err != nil {
+		return nil, NewRawdbError(GetChainEventTipError, err, chainID)
+	}
+	if !has {
+		return nil, nil
+	}
+	res, err := db.Get(key)
+	if err != nil {
+		return nil, NewRawdbError(GetChainEventTipError, err, chainID)
+	}
+	return res, nil
+}
@@ -0,0 +1,52 @@
+package rawdbv2
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incdb"
+)
+
+// StoreBlockParticipation stores the encoded signing participation of the
+// block of a chain at height with hash
+func StoreBlockParticipation(db incdb.KeyValueWriter, chainID int, height uint64, hash common.Hash, data []byte) error {
+	if err := db.Put(GetBlockParticipationKey(chainID, height, hash), data); err != nil {
+		return NewRawdbError(StoreBlockParticipationError, err, chainID, height, hash)
+	}
+	return nil
+}
+
+// GetBlockParticipation returns the encoded signing participation of the block
+// of a chain at height with hash
+func GetBlockParticipation(db incdb.KeyValueReader, chainID int, height uint64, hash common.Hash) ([]byte, error) {
+	res, err := db.Get(GetBlockParticipationKey(chainID, height, hash))
+	if err != nil {
+		return nil, NewRawdbError(GetBlockParticipationError, err, chainID, height, hash)
+	}
+	return res, nil
+}
+
+// HasParticipationCommittee tells whether the committee of hash is stored
+func HasParticipationCommittee(db incdb.KeyValueReader, hash common.Hash) (bool, error) {
+	has, err := db.Has(GetParticipationCommitteeKey(hash))
+	if err != nil {
+		return false, NewRawdbError(GetParticipationCommitteeError, err, hash)
+	}
+	return has, nil
+}
+
+// StoreParticipationCommittee stores the encoded committee of hash which the
+// block participations refer to
+func StoreParticipationCommittee(db incdb.KeyValueWriter, hash common.Hash, data []byte) error {
+	if err := db.Put(GetParticipationCommitteeKey(hash), data); err != nil {
+		return NewRawdbError(StoreParticipationCommitteeError, err, hash)
+	}
+	return nil
+}
+
+// GetParticipationCommittee returns the encoded committee of hash
+func GetParticipationCommittee(db incdb.KeyValueReader, hash common.Hash) ([]byte, error) {
+	res, err := db.Get(GetParticipationCommitteeKey(hash))
+	if err != nil {
+		return nil, NewRawdbError(GetParticipationCommitteeError, err, hash)
+	}
+	return res, nil
+}
@@ -84,6 +84,25 @@ const (
 	StoreTxByPublicKeyError
 	GetTxByPublicKeyError
 
+	// event log
+	StoreChainEventError
+	GetChainEventsError
+	StoreChainEventTipError
+	GetChainEventTipError
+	GetChainEventSequenceByHeightError
+
+	// validator participation
+	StoreBlockParticipationError
+	GetBlockParticipationError
+	StoreParticipationCommitteeError
+	GetParticipationCommitteeError
+
+	// beacon committee swaps
+	StoreBeaconCommitteeSwapError
+	GetBeaconCommitteeSwapsError
+	StoreBeaconCommitteeSwapIndexHeightError
+	GetBeaconCommitteeSwapIndexHeightError
+
 	// relaying - portal
 	StoreRelayingBNBHeaderError
 	GetRelayingBNBHeaderError
@@ -169,6 +188,22 @@ var ErrCodeMessage = map[int]struct {
 	GetBeaconPreCommitteeInfoError:          {-4032, "Get Beacon Pre Committee Info Error"},
 	GetShardPendingValidatorsError:          {-4033, "Get Shard Pending Validators Error"},
 
+	StoreChainEventError:               {-3100, "Store Chain Event Error"},
+	GetChainEventsError:                {-3101, "Get Chain Events Error"},
+	StoreChainEventTipError:            {-3102, "Store Chain Event Tip Error"},
+	GetChainEventTipError:              {-3103, "Get Chain Event Tip Error"},
+	GetChainEventSequenceByHeightError: {-3104, "Get Chain Event Sequence By Height Error"},
+
+	StoreBlockParticipationError:     {-3200, "Store Block Participation Error"},
+	GetBlockParticipationError:       {-3201, "Get Block Participation Error"},
+	StoreParticipationCommitteeError: {-3202, "Store Participation Committee Error"},
+	GetParticipationCommitteeError:   {-3203, "Get Participation Committee Error"},
+
+	StoreBeaconCommitteeSwapError:            {-3300, "Store Beacon Committee Swap Error"},
+	GetBeaconCommitteeSwapsError:             {-3301, "Get Beacon Committee Swaps Error"},
+	StoreBeaconCommitteeSwapIndexHeightError: {-3302, "Store Beacon Committee Swap Index Height Error"},
+	GetBeaconCommitteeSwapIndexHeightError:   {-3303, "Get Beacon Committee Swap Index Height Error"},
+
 	// relaying
 	StoreRelayingBNBHeaderError: {-5001, "Store relaying header bnb error"},
 	GetRelayingBNBHeaderError:   {-5002, "Get relaying header bnb error"},
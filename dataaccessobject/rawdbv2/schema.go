@@ -1,6 +1,8 @@
 package rawdbv2
 
 import (
+	"encoding/binary"
+
 	"github.com/incognitochain/incognito-chain/common"
 )
 
@@ -9,6 +11,7 @@ var (
 	lastShardBlockKey                  = []byte("LastShardBlock" + string(splitter))
 	lastBeaconBlockKey                 = []byte("LastBeaconBlock")
 	beaconViewsPrefix                  = []byte("BeaconViews")
+	beaconStateSyncViewKey             = []byte("BeaconStateSyncView")
 	shardBestStatePrefix               = []byte("ShardViews" + string(splitter))
 	shardHashToBlockPrefix             = []byte("s-b-h" + string(splitter))
 	viewPrefix                         = []byte("V" + string(splitter))
@@ -35,6 +38,13 @@ var (
 	shardSlashRootHashPrefix           = []byte("s-sl" + string(splitter))
 	shardFeatureRootHashPrefix         = []byte("s-fe" + string(splitter))
 	previousBestStatePrefix            = []byte("previous-best-state" + string(splitter))
+	chainEventPrefix                   = []byte("c-ev" + string(splitter))
+	chainEventHeightPrefix             = []byte("c-ev-h" + string(splitter))
+	chainEventTipPrefix                = []byte("c-ev-t" + string(splitter))
+	blockParticipationPrefix           = []byte("v-pa" + string(splitter))
+	participationCommitteePrefix       = []byte("v-pa-c" + string(splitter))
+	beaconCommitteeSwapPrefix          = []byte("b-cs" + string(splitter))
+	beaconCommitteeSwapIndexHeightKey  = []byte("b-cs-i" + string(splitter))
 	splitter                           = []byte("-[-]-")
 )
 
@@ -139,6 +149,27 @@ func GetBeaconViewsKey() []byte {
 	return temp
 }
 
+func GetBeaconStateSyncViewKey() []byte {
+	temp := make([]byte, 0, len(beaconStateSyncViewKey))
+	temp = append(temp, beaconStateSyncViewKey...)
+	return temp
+}
+
+// heights are big endian so that the swaps are iterated in their order
+func GetBeaconCommitteeSwapKey(height uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, height)
+	temp := make([]byte, 0, len(beaconCommitteeSwapPrefix)+len(buf))
+	temp = append(temp, beaconCommitteeSwapPrefix...)
+	return append(temp, buf...)
+}
+
+func GetBeaconCommitteeSwapIndexHeightKey() []byte {
+	temp := make([]byte, 0, len(beaconCommitteeSwapIndexHeightKey))
+	temp = append(temp, beaconCommitteeSwapIndexHeightKey...)
+	return temp
+}
+
 // ============================= Transaction =======================================
 func GetTransactionHashKey(hash common.Hash) []byte {
 	temp := make([]byte, 0, len(txHashPrefix))
@@ -308,6 +339,57 @@ func GetPreviousBestStateKey(shardID int) []byte {
 	return append(temp, byte(shardID))
 }
 
+// ============================= Event Log =======================================
+// sequences and heights are big endian so that keys are iterated in their order
+func GetChainEventPrefix(chainID int) []byte {
+	temp := make([]byte, 0, len(chainEventPrefix)+1)
+	temp = append(temp, chainEventPrefix...)
+	return append(temp, byte(chainID))
+}
+
+func GetChainEventKey(chainID int, sequence uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, sequence)
+	return append(GetChainEventPrefix(chainID), buf...)
+}
+
+func GetChainEventHeightKey(chainID int, height uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, height)
+	temp := make([]byte, 0, len(chainEventHeightPrefix)+1+len(buf))
+	temp = append(temp, chainEventHeightPrefix...)
+	temp = append(temp, byte(chainID))
+	return append(temp, buf...)
+}
+
+func GetChainEventTipKey(chainID int) []byte {
+	temp := make([]byte, 0, len(chainEventTipPrefix)+1)
+	temp = append(temp, chainEventTipPrefix...)
+	return append(temp, byte(chainID))
+}
+
+// ============================= Validator Participation =======================================
+// heights are big endian so that keys are iterated in their order, a height
+// may have blocks of several forks
+func GetBlockParticipationPrefix(chainID int, height uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, height)
+	temp := make([]byte, 0, len(blockParticipationPrefix)+1+len(buf))
+	temp = append(temp, blockParticipationPrefix...)
+	temp = append(temp, byte(chainID))
+	return append(temp, buf...)
+}
+
+func GetBlockParticipationKey(chainID int, height uint64, hash common.Hash) []byte {
+	return append(GetBlockParticipationPrefix(chainID, height), hash[:]...)
+}
+
+func GetParticipationCommitteeKey(hash common.Hash) []byte {
+	temp := make([]byte, 0, len(participationCommitteePrefix)+common.HashSize)
+	temp = append(temp, participationCommitteePrefix...)
+	return append(temp, hash[:]...)
+}
+
 func GetLastBeaconHeightConfirmCrossShardKey() []byte {
 	temp := make([]byte, 0, len(lastBeaconHeightConfirmCrossShard))
 	temp = append(temp, lastBeaconHeightConfirmCrossShard...)
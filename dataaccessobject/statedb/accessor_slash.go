@@ -1,13 +1,27 @@
 package statedb
 
+import "github.com/incognitochain/incognito-chain/common"
+
 func GetProducersBlackList(stateDB *StateDB, beaconHeight uint64) map[string]uint8 {
 	return stateDB.getAllProducerBlackList()
 }
 
-func StoreProducersBlackList(stateDB *StateDB, beaconHeight uint64, producersBlackList map[string]uint8) error {
+// GetProducersBlackListDetail returns the states of the blacklisted producers
+func GetProducersBlackListDetail(stateDB *StateDB) []*BlackListProducerState {
+	return stateDB.getAllBlackListProducerState()
+}
+
+// StoreProducersBlackList stores the blacklisted producers, reasons are why
+// the producers newly blacklisted are, the others keep their stored reason
+func StoreProducersBlackList(stateDB *StateDB, beaconHeight uint64, producersBlackList map[string]uint8, reasons map[string]string) error {
 	for producerKey, punishedEpoches := range producersBlackList {
 		key := GenerateBlackListProducerObjectKey(producerKey)
 		value := NewBlackListProducerStateWithValue(producerKey, punishedEpoches, beaconHeight)
+		if reason, ok := reasons[producerKey]; ok {
+			value.SetReason(reason)
+		} else if blackListProducerState, has, err := stateDB.getBlackListProducerState(key); err == nil && has {
+			value.SetReason(blackListProducerState.Reason())
+		}
 		err := stateDB.SetStateObject(BlackListProducerObjectType, key, value)
 		if err != nil {
 			return NewStatedbError(StoreBlackListProducersError, err)
@@ -31,3 +45,28 @@ func RemoveAllEmptyProducerBlackList(stateDB *StateDB, blackListProducerKey stri
 		}
 	}
 }
+
+// StoreEquivocationEvidences records the equivocations a beacon block at
+// beaconHeight slashed for, by the keys of their evidences
+func StoreEquivocationEvidences(stateDB *StateDB, beaconHeight uint64, evidenceHashes []common.Hash) error {
+	for _, evidenceHash := range evidenceHashes {
+		key := GenerateEquivocationEvidenceObjectKey(evidenceHash)
+		value := NewEquivocationEvidenceStateWithValue(evidenceHash, beaconHeight)
+		err := stateDB.SetStateObject(EquivocationEvidenceObjectType, key, value)
+		if err != nil {
+			return NewStatedbError(StoreEquivocationEvidenceError, err)
+		}
+	}
+	return nil
+}
+
+// IsEquivocationEvidenceUsed returns whether a beacon block already slashed
+// for the equivocation of the evidence key evidenceHash
+func IsEquivocationEvidenceUsed(stateDB *StateDB, evidenceHash common.Hash) (bool, error) {
+	key := GenerateEquivocationEvidenceObjectKey(evidenceHash)
+	_, has, err := stateDB.getEquivocationEvidenceState(key)
+	if err != nil {
+		return false, NewStatedbError(IsEquivocationEvidenceUsedError, err)
+	}
+	return has, nil
+}
@@ -0,0 +1,110 @@
+package statedb
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/trie"
+)
+
+// StateObjectEntry is a state object decoded from the state trie
+type StateObjectEntry struct {
+	Key   common.Hash
+	Value interface{}
+}
+
+// GetStateObjectTypePrefix returns the key prefix of every object of a type,
+// false for the types whose prefix depends on a token, an epoch or a height
+func GetStateObjectTypePrefix(objectType int) ([]byte, bool) {
+	switch objectType {
+	case StakerObjectType:
+		return GetStakerInfoPrefix(), true
+	case CommitteeRewardObjectType:
+		return GetCommitteeRewardPrefix(), true
+	case BlackListProducerObjectType:
+		return GetBlackListProducerPrefix(), true
+	case EquivocationEvidenceObjectType:
+		return GetEquivocationEvidencePrefix(), true
+	case CommitmentLengthObjectType:
+		return GetCommitmentLengthPrefix(), true
+	case TokenObjectType:
+		return GetTokenPrefix(), true
+	case WaitingPDEContributionObjectType:
+		return GetWaitingPDEContributionPrefix(), true
+	case PDEPoolPairObjectType:
+		return GetPDEPoolPairPrefix(), true
+	case PDEShareObjectType:
+		return GetPDESharePrefix(), true
+	case PDETradingFeeObjectType:
+		return GetPDETradingFeePrefix(), true
+	case PDEStatusObjectType:
+		return GetPDEStatusPrefix(), true
+	case BridgeEthTxObjectType:
+		return GetBridgeEthTxPrefix(), true
+	case BridgeStatusObjectType:
+		return GetBridgeStatusPrefix(), true
+	case BurningConfirmObjectType:
+		return GetBurningConfirmPrefix(), true
+	case PortalFinalExchangeRatesStateObjectType:
+		return GetFinalExchangeRatesStatePrefix(), true
+	case PortalUnlockOverRateCollaterals:
+		return GetPortalUnlockOverRateCollateralsPrefix(), true
+	case PortalWaitingPortingRequestObjectType:
+		return GetPortalWaitingPortingRequestPrefix(), true
+	case PortalLiquidationPoolObjectType:
+		return GetPortalLiquidationPoolPrefix(), true
+	case CustodianStateObjectType:
+		return GetPortalCustodianStatePrefix(), true
+	case PortalStatusObjectType:
+		return GetPortalStatusPrefix(), true
+	case LockedCollateralStateObjectType:
+		return GetLockedCollateralStatePrefix(), true
+	case PortalExternalTxObjectType:
+		return GetPortalExternalTxPrefix(), true
+	default:
+		return nil, false
+	}
+}
+
+// GetStateObjectValue returns the decoded value of the object of a type stored
+// at key, false if there is none
+func GetStateObjectValue(stateDB *StateDB, objectType int, key common.Hash) (interface{}, bool, error) {
+	if GetStateObjectTypeName(objectType) == "" {
+		return nil, false, fmt.Errorf("state object type %v not exist", objectType)
+	}
+	obj, err := stateDB.getStateObject(objectType, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if obj == nil {
+		return nil, false, nil
+	}
+	return obj.GetValue(), true, nil
+}
+
+// GetStateObjectsByPrefix returns, in key order, the decoded values of at most
+// limit objects of a type whose key starts with prefix, and whether more objects
+// are left. Objects are read from the committed trie, pending changes are not
+// returned.
+func GetStateObjectsByPrefix(stateDB *StateDB, objectType int, prefix []byte, limit int) ([]StateObjectEntry, bool, error) {
+	if GetStateObjectTypeName(objectType) == "" {
+		return nil, false, fmt.Errorf("state object type %v not exist", objectType)
+	}
+	res := []StateObjectEntry{}
+	it := trie.NewIterator(stateDB.trie.NodeIterator(prefix))
+	for it.Next() {
+		if len(res) >= limit {
+			return res, true, nil
+		}
+		key := common.BytesToHash(it.Key)
+		value, err := DecodeStateObjectValue(objectType, key, common.CopyBytes(it.Value))
+		if err != nil {
+			return nil, false, fmt.Errorf("decode object %v: %v", key.String(), err)
+		}
+		res = append(res, StateObjectEntry{Key: key, Value: value})
+	}
+	if it.Err != nil {
+		return nil, false, it.Err
+	}
+	return res, false, nil
+}
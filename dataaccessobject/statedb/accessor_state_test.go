@@ -0,0 +1,45 @@
+package statedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetStateObjectsByPrefix(t *testing.T) {
+	rootHash, wantM, wantMByToken := storeSerialNumber(emptyRoot, warperDBStatedbTest, 10, 1)
+	sDB, err := NewWithPrefixTrie(rootHash, warperDBStatedbTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range wantM {
+		got, has, err := GetStateObjectValue(sDB, SerialNumberObjectType, key)
+		if err != nil || !has {
+			t.Fatalf("key %v not found, %v", key.String(), err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("want %+v, got %+v", want, got)
+		}
+	}
+	for tokenID, serialNumbers := range wantMByToken {
+		prefix := GetSerialNumberPrefix(tokenID, 1)
+		objects, more, err := GetStateObjectsByPrefix(sDB, SerialNumberObjectType, prefix, len(serialNumbers))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if more || len(objects) != len(serialNumbers) {
+			t.Fatalf("token %v want %v objects, got %v, more %v", tokenID.String(), len(serialNumbers), len(objects), more)
+		}
+		for _, object := range objects {
+			if !reflect.DeepEqual(object.Value, wantM[object.Key]) {
+				t.Fatalf("want %+v, got %+v", wantM[object.Key], object.Value)
+			}
+		}
+		objects, more, err = GetStateObjectsByPrefix(sDB, SerialNumberObjectType, prefix, len(serialNumbers)-1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !more || len(objects) != len(serialNumbers)-1 {
+			t.Fatalf("token %v want %v objects and more, got %v, more %v", tokenID.String(), len(serialNumbers)-1, len(objects), more)
+		}
+	}
+}
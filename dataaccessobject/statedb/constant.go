@@ -52,6 +52,9 @@ const (
 	PortalExternalTxObjectType
 	PortalConfirmProofObjectType
 	PortalUnlockOverRateCollaterals
+
+	// slash
+	EquivocationEvidenceObjectType
 )
 
 // Prefix length
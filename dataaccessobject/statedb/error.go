@@ -45,6 +45,7 @@ const (
 	ErrInvalidBlockHashType                      = "invalid block hash type"
 	ErrInvalidPortalExternalTxStateType          = "invalid portal external tx state type"
 	ErrInvalidPortalConfirmProofStateType        = "invalid portal confirm proof state type"
+	ErrInvalidEquivocationEvidenceStateType      = "invalid equivocation evidence state type"
 )
 const (
 	InvalidByteArrayTypeError = iota
@@ -192,6 +193,10 @@ const (
 	GetWithdrawCollateralConfirmError
 	StorePortalUnlockOverRateCollateralsError
 	GetPortalUnlockOverRateCollateralsStatusError
+
+	// slash
+	StoreEquivocationEvidenceError
+	IsEquivocationEvidenceUsedError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -248,6 +253,8 @@ var ErrCodeMessage = map[int]struct {
 	StoreBlackListProducersError:           {-3013, "Store Black List Producers Error"},
 	StoreOneShardSubstitutesValidatorError: {-3014, "Store One Shard Substitutes Validator Error"},
 	StoreBeaconSubstitutesValidatorError:   {-3014, "Store Beacon Substitutes Validator Error"},
+	StoreEquivocationEvidenceError:         {-3015, "Store Equivocation Evidence Error"},
+	IsEquivocationEvidenceUsedError:        {-3016, "Is Equivocation Evidence Used Error"},
 	// -4xxx: pdex error
 	StoreWaitingPDEContributionError: {-4000, "Store Waiting PDEX Contribution Error"},
 	StorePDEPoolPairError:            {-4001, "Store PDEX Pool Pair Error"},
@@ -0,0 +1,161 @@
+package statedb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/trie"
+)
+
+// stateObjectTypeNames maps the name of every object type that can be proven
+// to its type, the names are those of the type constants
+var stateObjectTypeNames = map[string]int{
+	"CommitteeObjectType":                     CommitteeObjectType,
+	"CommitteeRewardObjectType":               CommitteeRewardObjectType,
+	"RewardRequestObjectType":                 RewardRequestObjectType,
+	"BlackListProducerObjectType":             BlackListProducerObjectType,
+	"SerialNumberObjectType":                  SerialNumberObjectType,
+	"CommitmentObjectType":                    CommitmentObjectType,
+	"CommitmentIndexObjectType":               CommitmentIndexObjectType,
+	"CommitmentLengthObjectType":              CommitmentLengthObjectType,
+	"SNDerivatorObjectType":                   SNDerivatorObjectType,
+	"OutputCoinObjectType":                    OutputCoinObjectType,
+	"TokenObjectType":                         TokenObjectType,
+	"WaitingPDEContributionObjectType":        WaitingPDEContributionObjectType,
+	"PDEPoolPairObjectType":                   PDEPoolPairObjectType,
+	"PDEShareObjectType":                      PDEShareObjectType,
+	"PDEStatusObjectType":                     PDEStatusObjectType,
+	"BridgeEthTxObjectType":                   BridgeEthTxObjectType,
+	"BridgeTokenInfoObjectType":               BridgeTokenInfoObjectType,
+	"BridgeStatusObjectType":                  BridgeStatusObjectType,
+	"BurningConfirmObjectType":                BurningConfirmObjectType,
+	"TokenTransactionObjectType":              TokenTransactionObjectType,
+	"PortalFinalExchangeRatesStateObjectType": PortalFinalExchangeRatesStateObjectType,
+	"PortalWaitingPortingRequestObjectType":   PortalWaitingPortingRequestObjectType,
+	"PortalLiquidationPoolObjectType":         PortalLiquidationPoolObjectType,
+	"PortalStatusObjectType":                  PortalStatusObjectType,
+	"CustodianStateObjectType":                CustodianStateObjectType,
+	"WaitingRedeemRequestObjectType":          WaitingRedeemRequestObjectType,
+	"PortalRewardInfoObjectType":              PortalRewardInfoObjectType,
+	"LockedCollateralStateObjectType":         LockedCollateralStateObjectType,
+	"RewardFeatureStateObjectType":            RewardFeatureStateObjectType,
+	"PDETradingFeeObjectType":                 PDETradingFeeObjectType,
+	"StakerObjectType":                        StakerObjectType,
+	"PortalExternalTxObjectType":              PortalExternalTxObjectType,
+	"PortalConfirmProofObjectType":            PortalConfirmProofObjectType,
+	"PortalUnlockOverRateCollaterals":         PortalUnlockOverRateCollaterals,
+	"EquivocationEvidenceObjectType":          EquivocationEvidenceObjectType,
+}
+
+// GetStateObjectType returns the object type of its name, e.g.
+// "SerialNumberObjectType"
+func GetStateObjectType(name string) (int, bool) {
+	objectType, ok := stateObjectTypeNames[name]
+	return objectType, ok
+}
+
+// StateProof is a merkle proof of the value stored at Key in a state trie, or
+// of the absence of Key if Value is empty. Nodes are the rlp encoded trie nodes
+// on the path from the root to the key.
+type StateProof struct {
+	Key   common.Hash
+	Value []byte
+	Nodes [][]byte
+}
+
+// proofNodeSet is an in-memory key value store of proof nodes, keyed by the
+// hash of the node
+type proofNodeSet map[string][]byte
+
+func (s proofNodeSet) Put(key []byte, value []byte) error {
+	s[string(key)] = common.CopyBytes(value)
+	return nil
+}
+
+func (s proofNodeSet) Delete(key []byte) error {
+	delete(s, string(key))
+	return nil
+}
+
+func (s proofNodeSet) Has(key []byte) (bool, error) {
+	_, ok := s[string(key)]
+	return ok, nil
+}
+
+func (s proofNodeSet) Get(key []byte) ([]byte, error) {
+	if value, ok := s[string(key)]; ok {
+		return value, nil
+	}
+	return nil, errors.New("proof node not found")
+}
+
+// GetStateProof returns the value stored at key and the merkle proof of this
+// value to the root of the state, the proof of the absence of key if nothing is
+// stored. Pending changes are not committed, the proof is against the root
+// returned by IntermediateRoot.
+func (stateDB *StateDB) GetStateProof(key common.Hash) (*StateProof, error) {
+	value, err := stateDB.trie.TryGet(key[:])
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(proofNodeSet)
+	if err := stateDB.trie.Prove(key[:], 0, nodes); err != nil {
+		return nil, err
+	}
+	proof := &StateProof{Key: key, Value: common.CopyBytes(value)}
+	for _, node := range nodes {
+		proof.Nodes = append(proof.Nodes, node)
+	}
+	return proof, nil
+}
+
+// VerifyStateProof checks that proof proves its value, or the absence of its
+// key, in the state trie of the given root. The proof only links the value to
+// root, see blockchain.VerifyStateProof for the link of root to a signed block.
+func VerifyStateProof(root common.Hash, proof *StateProof) error {
+	if proof == nil {
+		return errors.New("nil state proof")
+	}
+	nodes := make(proofNodeSet)
+	for _, node := range proof.Nodes {
+		hash := common.Keccak256Hash(node)
+		nodes[string(hash[:])] = node
+	}
+	value, _, err := trie.VerifyProof(root, proof.Key[:], nodes)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(value, proof.Value) {
+		return fmt.Errorf("proof of key %v to state %v does not prove its value", proof.Key.String(), root.String())
+	}
+	return nil
+}
+
+// DecodeStateObjectValue decodes the value of a state object of the given type,
+// as stored in the state trie
+func DecodeStateObjectValue(objectType int, key common.Hash, value []byte) (interface{}, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	if GetStateObjectTypeName(objectType) == "" {
+		return nil, fmt.Errorf("state object type %v not exist", objectType)
+	}
+	obj, err := newStateObjectWithValue(nil, objectType, key, value)
+	if err != nil {
+		return nil, err
+	}
+	return obj.GetValue(), nil
+}
+
+// GetStateObjectTypeName returns the name of an object type, empty if it can
+// not be proven
+func GetStateObjectTypeName(objectType int) string {
+	for name, t := range stateObjectTypeNames {
+		if t == objectType {
+			return name
+		}
+	}
+	return ""
+}
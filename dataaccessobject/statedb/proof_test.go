@@ -0,0 +1,64 @@
+package statedb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+func TestStateDB_GetStateProof(t *testing.T) {
+	rootHash, wantM, _ := storeSerialNumber(emptyRoot, warperDBStatedbTest, 20, 0)
+	sDB, err := NewWithPrefixTrie(rootHash, warperDBStatedbTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range wantM {
+		proof, err := sDB.GetStateProof(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyStateProof(rootHash, proof); err != nil {
+			t.Fatalf("key %v: %v", key.String(), err)
+		}
+		got, err := DecodeStateObjectValue(SerialNumberObjectType, key, proof.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("want %+v, got %+v", want, got)
+		}
+
+		// another value or another root must not verify
+		forged := *proof
+		forged.Value = append(common.CopyBytes(proof.Value), 0)
+		if err := VerifyStateProof(rootHash, &forged); err == nil {
+			t.Fatalf("forged value of key %v verified", key.String())
+		}
+		if err := VerifyStateProof(common.HashH(rootHash[:]), proof); err == nil {
+			t.Fatalf("proof of key %v verified against another root", key.String())
+		}
+	}
+
+	absentKey := GenerateSerialNumberObjectKey(common.PRVCoinID, 0, []byte("absent"))
+	proof, err := sDB.GetStateProof(absentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof.Value) != 0 {
+		t.Fatalf("absent key has value %v", proof.Value)
+	}
+	if err := VerifyStateProof(rootHash, proof); err != nil {
+		t.Fatal(err)
+	}
+	// an absent key can not be proven present
+	for key := range wantM {
+		forged := *proof
+		forged.Value = []byte("{}")
+		forged.Key = key
+		if err := VerifyStateProof(rootHash, &forged); err == nil {
+			t.Fatalf("forged proof of key %v verified", key.String())
+		}
+		break
+	}
+}
@@ -20,6 +20,7 @@ var (
 	committeeRewardPrefix              = []byte("committee-reward-")
 	rewardRequestPrefix                = []byte("reward-request-")
 	blackListProducerPrefix            = []byte("black-list-")
+	equivocationEvidencePrefix         = []byte("equivocation-evidence-")
 	serialNumberPrefix                 = []byte("serial-number-")
 	commitmentPrefix                   = []byte("com-value-")
 	commitmentIndexPrefix              = []byte("com-index-")
@@ -158,6 +159,11 @@ func GetBlackListProducerPrefix() []byte {
 	return h[:][:prefixHashKeyLength]
 }
 
+func GetEquivocationEvidencePrefix() []byte {
+	h := common.HashH(equivocationEvidencePrefix)
+	return h[:][:prefixHashKeyLength]
+}
+
 func GetSerialNumberPrefix(tokenID common.Hash, shardID byte) []byte {
 	h := common.HashH(append(serialNumberPrefix, append(tokenID[:], shardID)...))
 	return h[:][:prefixHashKeyLength]
@@ -516,6 +522,13 @@ var _ = func() (_ struct{}) {
 		panic("black-list-" + " same prefix " + v)
 	}
 	m[string(tempBlackListProducer)] = "black-list-"
+	// equivocation evidence
+	tempEquivocationEvidence := GetEquivocationEvidencePrefix()
+	prefixs = append(prefixs, tempEquivocationEvidence)
+	if v, ok := m[string(tempEquivocationEvidence)]; ok {
+		panic("equivocation-evidence-" + " same prefix " + v)
+	}
+	m[string(tempEquivocationEvidence)] = "equivocation-evidence-"
 	for i, v1 := range prefixs {
 		for j, v2 := range prefixs {
 			if i == j {
@@ -927,6 +927,17 @@ func (stateDB *StateDB) getBlackListProducerState(key common.Hash) (*BlackListPr
 	return NewBlackListProducerState(), false, nil
 }
 
+func (stateDB *StateDB) getEquivocationEvidenceState(key common.Hash) (*EquivocationEvidenceState, bool, error) {
+	equivocationEvidenceState, err := stateDB.getStateObject(EquivocationEvidenceObjectType, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if equivocationEvidenceState != nil {
+		return equivocationEvidenceState.GetValue().(*EquivocationEvidenceState), true, nil
+	}
+	return NewEquivocationEvidenceState(), false, nil
+}
+
 func (stateDB *StateDB) getBlackListProducerPunishedEpoch(key common.Hash) (uint8, bool, error) {
 	duration := uint8(0)
 	blackListProducerObject, err := stateDB.getStateObject(BlackListProducerObjectType, key)
@@ -91,6 +91,8 @@ func newStateObjectWithValue(db *StateDB, objectType int, hash common.Hash, valu
 		return newPortalConfirmProofStateObjectWithValue(db, hash, value)
 	case StakerObjectType:
 		return newStakerObjectWithValue(db, hash, value)
+	case EquivocationEvidenceObjectType:
+		return newEquivocationEvidenceObjectWithValue(db, hash, value)
 	default:
 		panic("state object type not exist")
 	}
@@ -162,6 +164,8 @@ func newStateObject(db *StateDB, objectType int, hash common.Hash) StateObject {
 		return newPortalConfirmProofStateObject(db, hash)
 	case StakerObjectType:
 		return newStakerObject(db, hash)
+	case EquivocationEvidenceObjectType:
+		return newEquivocationEvidenceObject(db, hash)
 	default:
 		panic("state object type not exist")
 	}
@@ -13,6 +13,8 @@ type BlackListProducerState struct {
 	producerCommitteePublicKey string
 	punishedEpoches            uint8
 	beaconHeight               uint64
+	// why the producer is blacklisted, empty for the objects stored before it
+	reason string
 }
 
 func NewBlackListProducerStateWithValue(producerCommitteePublicKey string, punishedEpoches uint8, beaconHeight uint64) *BlackListProducerState {
@@ -35,6 +37,14 @@ func (bl *BlackListProducerState) SetPunishedEpoches(punishedEpoches uint8) {
 	bl.punishedEpoches = punishedEpoches
 }
 
+func (bl BlackListProducerState) Reason() string {
+	return bl.reason
+}
+
+func (bl *BlackListProducerState) SetReason(reason string) {
+	bl.reason = reason
+}
+
 func (bl BlackListProducerState) ProducerCommitteePublicKey() string {
 	return bl.producerCommitteePublicKey
 }
@@ -52,10 +62,12 @@ func (bl BlackListProducerState) MarshalJSON() ([]byte, error) {
 		ProducerCommitteePublicKey string
 		PunishedEpoches            uint8
 		BeaconHeight               uint64
+		Reason                     string `json:",omitempty"`
 	}{
 		ProducerCommitteePublicKey: bl.producerCommitteePublicKey,
 		PunishedEpoches:            bl.punishedEpoches,
 		BeaconHeight:               bl.beaconHeight,
+		Reason:                     bl.reason,
 	})
 	if err != nil {
 		return []byte{}, err
@@ -68,6 +80,7 @@ func (bl *BlackListProducerState) UnmarshalJSON(data []byte) error {
 		ProducerCommitteePublicKey string
 		PunishedEpoches            uint8
 		BeaconHeight               uint64
+		Reason                     string
 	}{}
 	err := json.Unmarshal(data, &temp)
 	if err != nil {
@@ -76,6 +89,7 @@ func (bl *BlackListProducerState) UnmarshalJSON(data []byte) error {
 	bl.producerCommitteePublicKey = temp.ProducerCommitteePublicKey
 	bl.punishedEpoches = temp.PunishedEpoches
 	bl.beaconHeight = temp.BeaconHeight
+	bl.reason = temp.Reason
 	return nil
 }
 
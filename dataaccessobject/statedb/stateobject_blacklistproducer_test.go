@@ -149,3 +149,41 @@ func TestStateDB_GetAllBlackListProducerStateMultipleRootHash(t *testing.T) {
 		}
 	}
 }
+
+func TestStateDB_StoreProducersBlackListKeepReason(t *testing.T) {
+	sDB, err := NewWithPrefixTrie(emptyRoot, wrarperDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	producers := committeePublicKeys[:2]
+	err = StoreProducersBlackList(sDB, 1, map[string]uint8{producers[0]: 3, producers[1]: 2}, map[string]string{producers[0]: "equivocation"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the next block only decreases the punishment of the first producer
+	err = StoreProducersBlackList(sDB, 2, map[string]uint8{producers[0]: 2, producers[1]: 2}, map[string]string{producers[1]: "downtime"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootHash, err := sDB.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempStateDB, err := NewWithPrefixTrie(rootHash, wrarperDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantReasons := map[string]string{producers[0]: "equivocation", producers[1]: "downtime"}
+	states := GetProducersBlackListDetail(tempStateDB)
+	if len(states) != len(wantReasons) {
+		t.Fatalf("want %v producers but got %v", len(wantReasons), len(states))
+	}
+	for _, state := range states {
+		if state.Reason() != wantReasons[state.ProducerCommitteePublicKey()] {
+			t.Fatalf("want reason %v but got %v", wantReasons[state.ProducerCommitteePublicKey()], state.Reason())
+		}
+		if state.PunishedEpoches() != 2 || state.BeaconHeight() != 2 {
+			t.Fatalf("want 2 epoches at beacon height 2 but got %+v", state)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package statedb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// EquivocationEvidenceState records an equivocation evidence a beacon block
+// slashed for, so that the same evidence can not slash twice
+type EquivocationEvidenceState struct {
+	evidenceHash common.Hash
+	beaconHeight uint64
+}
+
+func NewEquivocationEvidenceState() *EquivocationEvidenceState {
+	return &EquivocationEvidenceState{}
+}
+
+func NewEquivocationEvidenceStateWithValue(evidenceHash common.Hash, beaconHeight uint64) *EquivocationEvidenceState {
+	return &EquivocationEvidenceState{evidenceHash: evidenceHash, beaconHeight: beaconHeight}
+}
+
+func (e EquivocationEvidenceState) EvidenceHash() common.Hash {
+	return e.evidenceHash
+}
+
+func (e *EquivocationEvidenceState) SetEvidenceHash(evidenceHash common.Hash) {
+	e.evidenceHash = evidenceHash
+}
+
+func (e EquivocationEvidenceState) BeaconHeight() uint64 {
+	return e.beaconHeight
+}
+
+func (e *EquivocationEvidenceState) SetBeaconHeight(beaconHeight uint64) {
+	e.beaconHeight = beaconHeight
+}
+
+func (e EquivocationEvidenceState) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(struct {
+		EvidenceHash common.Hash
+		BeaconHeight uint64
+	}{
+		EvidenceHash: e.evidenceHash,
+		BeaconHeight: e.beaconHeight,
+	})
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+func (e *EquivocationEvidenceState) UnmarshalJSON(data []byte) error {
+	temp := struct {
+		EvidenceHash common.Hash
+		BeaconHeight uint64
+	}{}
+	err := json.Unmarshal(data, &temp)
+	if err != nil {
+		return err
+	}
+	e.evidenceHash = temp.EvidenceHash
+	e.beaconHeight = temp.BeaconHeight
+	return nil
+}
+
+type EquivocationEvidenceObject struct {
+	db *StateDB
+	// Write caches.
+	trie Trie // storage trie, which becomes non-nil on first access
+
+	version                   int
+	equivocationEvidenceHash  common.Hash
+	equivocationEvidenceState *EquivocationEvidenceState
+	objectType                int
+	deleted                   bool
+
+	// DB error.
+	// State objects are used by the consensus core and VM which are
+	// unable to deal with database-level errors. Any error that occurs
+	// during a database read is memoized here and will eventually be returned
+	// by StateDB.Commit.
+	dbErr error
+}
+
+func newEquivocationEvidenceObject(db *StateDB, hash common.Hash) *EquivocationEvidenceObject {
+	return &EquivocationEvidenceObject{
+		version:                   defaultVersion,
+		db:                        db,
+		equivocationEvidenceHash:  hash,
+		equivocationEvidenceState: NewEquivocationEvidenceState(),
+		objectType:                EquivocationEvidenceObjectType,
+		deleted:                   false,
+	}
+}
+
+func newEquivocationEvidenceObjectWithValue(db *StateDB, key common.Hash, data interface{}) (*EquivocationEvidenceObject, error) {
+	var newEquivocationEvidenceState = NewEquivocationEvidenceState()
+	var ok bool
+	var dataBytes []byte
+	if dataBytes, ok = data.([]byte); ok {
+		err := json.Unmarshal(dataBytes, newEquivocationEvidenceState)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		newEquivocationEvidenceState, ok = data.(*EquivocationEvidenceState)
+		if !ok {
+			return nil, fmt.Errorf("%+v, got type %+v", ErrInvalidEquivocationEvidenceStateType, reflect.TypeOf(data))
+		}
+	}
+	return &EquivocationEvidenceObject{
+		version:                   defaultVersion,
+		equivocationEvidenceHash:  key,
+		equivocationEvidenceState: newEquivocationEvidenceState,
+		db:                        db,
+		objectType:                EquivocationEvidenceObjectType,
+		deleted:                   false,
+	}, nil
+}
+
+func GenerateEquivocationEvidenceObjectKey(evidenceHash common.Hash) common.Hash {
+	prefixHash := GetEquivocationEvidencePrefix()
+	valueHash := common.HashH(evidenceHash[:])
+	return common.BytesToHash(append(prefixHash, valueHash[:][:prefixKeyLength]...))
+}
+
+func (e EquivocationEvidenceObject) GetVersion() int {
+	return e.version
+}
+
+// setError remembers the first non-nil error it is called with.
+func (e *EquivocationEvidenceObject) SetError(err error) {
+	if e.dbErr == nil {
+		e.dbErr = err
+	}
+}
+
+func (e EquivocationEvidenceObject) GetTrie(db DatabaseAccessWarper) Trie {
+	return e.trie
+}
+
+func (e *EquivocationEvidenceObject) SetValue(data interface{}) error {
+	var newEquivocationEvidenceState = NewEquivocationEvidenceState()
+	var ok bool
+	var dataBytes []byte
+	if dataBytes, ok = data.([]byte); ok {
+		err := json.Unmarshal(dataBytes, newEquivocationEvidenceState)
+		if err != nil {
+			return err
+		}
+	} else {
+		newEquivocationEvidenceState, ok = data.(*EquivocationEvidenceState)
+		if !ok {
+			return fmt.Errorf("%+v, got type %+v", ErrInvalidEquivocationEvidenceStateType, reflect.TypeOf(data))
+		}
+	}
+	e.equivocationEvidenceState = newEquivocationEvidenceState
+	return nil
+}
+
+func (e EquivocationEvidenceObject) GetValue() interface{} {
+	return e.equivocationEvidenceState
+}
+
+func (e EquivocationEvidenceObject) GetValueBytes() []byte {
+	data := e.GetValue()
+	value, err := json.Marshal(data)
+	if err != nil {
+		panic("failed to marshal equivocation evidence state")
+	}
+	return []byte(value)
+}
+
+func (e EquivocationEvidenceObject) GetHash() common.Hash {
+	return e.equivocationEvidenceHash
+}
+
+func (e EquivocationEvidenceObject) GetType() int {
+	return e.objectType
+}
+
+// MarkDelete will delete an object in trie
+func (e *EquivocationEvidenceObject) MarkDelete() {
+	e.deleted = true
+}
+
+func (e *EquivocationEvidenceObject) Reset() bool {
+	e.equivocationEvidenceState = NewEquivocationEvidenceState()
+	return true
+}
+
+func (e EquivocationEvidenceObject) IsDeleted() bool {
+	return e.deleted
+}
+
+// value is either default or nil
+func (e EquivocationEvidenceObject) IsEmpty() bool {
+	temp := NewEquivocationEvidenceState()
+	return reflect.DeepEqual(temp, e.equivocationEvidenceState) || e.equivocationEvidenceState == nil
+}
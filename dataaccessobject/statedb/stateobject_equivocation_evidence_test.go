@@ -0,0 +1,52 @@
+package statedb
+
+import (
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+func TestStateDB_StoreEquivocationEvidences(t *testing.T) {
+	sDB, err := NewWithPrefixTrie(emptyRoot, wrarperDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	used := []common.Hash{common.HashH([]byte("evidence 1")), common.HashH([]byte("evidence 2"))}
+	if err := StoreEquivocationEvidences(sDB, 1, used); err != nil {
+		t.Fatal(err)
+	}
+	rootHash, err := sDB.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sDB.Database().TrieDB().Commit(rootHash, false); err != nil {
+		t.Fatal(err)
+	}
+	tempStateDB, err := NewWithPrefixTrie(rootHash, wrarperDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, evidenceHash := range used {
+		isUsed, err := IsEquivocationEvidenceUsed(tempStateDB, evidenceHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isUsed {
+			t.Fatalf("evidence %v is not used", evidenceHash.String())
+		}
+		state, has, err := tempStateDB.getEquivocationEvidenceState(GenerateEquivocationEvidenceObjectKey(evidenceHash))
+		if err != nil || !has {
+			t.Fatalf("want the state of evidence %v but got %v", evidenceHash.String(), err)
+		}
+		if state.EvidenceHash() != evidenceHash || state.BeaconHeight() != 1 {
+			t.Fatalf("want evidence %v at beacon height 1 but got %+v", evidenceHash.String(), state)
+		}
+	}
+	isUsed, err := IsEquivocationEvidenceUsed(tempStateDB, common.HashH([]byte("evidence 3")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isUsed {
+		t.Fatal("evidence 3 is used")
+	}
+}
@@ -11,6 +11,7 @@ type DatabaseInterface interface {
 	HasValue(key []byte) (bool, error)
 
 	AddTransaction(txHash *common.Hash, txType string, valueTx []byte, valueDesc []byte) error
+	PutTransaction(txHash *common.Hash, value []byte) error
 	RemoveTransaction(key *common.Hash) error
 	GetTransaction(key *common.Hash) ([]byte, error)
 	HasTransaction(key *common.Hash) (bool, error)
@@ -1,13 +1,23 @@
 package lvdb
 
 import (
+	"path/filepath"
+	"sync"
+
 	"github.com/incognitochain/incognito-chain/databasemp"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 )
 
 type db struct {
 	lvdb *leveldb.DB
+
+	// the tx writes are journaled, pending holds the writes of the journal not
+	// applied to leveldb yet, a nil value is a deleted key
+	txMtx   sync.RWMutex
+	journal *journal
+	pending map[string][]byte
 }
 
 func open(dbPath string) (databasemp.DatabaseInterface, error) {
@@ -15,13 +25,105 @@ func open(dbPath string) (databasemp.DatabaseInterface, error) {
 	if err != nil {
 		return nil, databasemp.NewDatabaseMempoolError(databasemp.OpenDbErr, errors.Wrapf(err, "levelvdb.OpenFile %s", dbPath))
 	}
-	return &db{lvdb: lvdb}, nil
+	journal, err := openJournal(filepath.Join(dbPath, journalFileName))
+	if err != nil {
+		lvdb.Close()
+		return nil, err
+	}
+	db := &db{lvdb: lvdb, journal: journal, pending: make(map[string][]byte)}
+	// recover the writes journaled before a crash, and drop the torn record of
+	// the crash before anything is appended after it
+	offset, err := journal.replay(func(op byte, key []byte, value []byte) {
+		if op == journalDelete {
+			db.pending[string(key)] = nil
+		} else {
+			db.pending[string(key)] = value
+		}
+	})
+	if err == nil {
+		err = journal.truncate(offset)
+	}
+	if err == nil {
+		err = db.checkpoint()
+	}
+	if err != nil {
+		journal.close()
+		lvdb.Close()
+		return nil, databasemp.NewDatabaseMempoolError(databasemp.OpenDbErr, err)
+	}
+	return db, nil
 }
 
 func (db *db) Close() error {
+	db.txMtx.Lock()
+	defer db.txMtx.Unlock()
+	if err := db.checkpoint(); err != nil {
+		return err
+	}
+	if err := db.journal.close(); err != nil {
+		return err
+	}
 	return errors.Wrap(db.lvdb.Close(), "db.lvdb.Close")
 }
 
+// writeTx journals a tx write, value nil deletes key
+// This function MUST be called with txMtx locked for writes
+func (db *db) writeTx(key []byte, value []byte) error {
+	op := journalPut
+	if value == nil {
+		op = journalDelete
+	}
+	if err := db.journal.append(op, key, value); err != nil {
+		return databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, err)
+	}
+	db.pending[string(key)] = value
+	if db.journal.records >= maxJournalRecords {
+		return db.checkpoint()
+	}
+	return nil
+}
+
+// readTx returns the value of key, with the journaled writes
+// This function MUST be called with txMtx locked
+func (db *db) readTx(key []byte) ([]byte, bool, error) {
+	if value, ok := db.pending[string(key)]; ok {
+		return value, value != nil, nil
+	}
+	value, err := db.lvdb.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, errors.Wrap(err, "db.lvdb.Get"))
+	}
+	return value, true, nil
+}
+
+// checkpoint applies the journaled writes to leveldb in a synced batch, then
+// empties the journal
+// This function MUST be called with txMtx locked for writes
+func (db *db) checkpoint() error {
+	if len(db.pending) == 0 && db.journal.records == 0 {
+		return nil
+	}
+	batch := new(leveldb.Batch)
+	for key, value := range db.pending {
+		if value == nil {
+			batch.Delete([]byte(key))
+		} else {
+			batch.Put([]byte(key), value)
+		}
+	}
+	if err := db.lvdb.Write(batch, &opt.WriteOptions{Sync: true}); err != nil {
+		return databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, errors.Wrap(err, "db.lvdb.Write"))
+	}
+	db.pending = make(map[string][]byte)
+	if err := db.journal.reset(); err != nil {
+		return databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, err)
+	}
+	return nil
+}
+
 func (db *db) HasValue(key []byte) (bool, error) {
 	ret, err := db.lvdb.Has(key, nil)
 	if err != nil {
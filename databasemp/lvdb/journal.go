@@ -0,0 +1,124 @@
+package lvdb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/incognitochain/incognito-chain/databasemp"
+	"github.com/pkg/errors"
+)
+
+const (
+	journalFileName = "mempool.journal"
+	// the journal is applied to leveldb once it holds this number of records
+	maxJournalRecords = 1024
+)
+
+// journal operations
+const (
+	journalPut    = byte(1)
+	journalDelete = byte(2)
+)
+
+// journal is the write-ahead log of the tx writes of the mempool database. A
+// write is appended and synced to the journal before it returns, then the
+// journal is applied to leveldb in a single batch every maxJournalRecords
+// records, so that a crash does not lose the last txs without syncing leveldb
+// on every tx.
+// Record: length (4 bytes) | crc32 of body (4 bytes) | body
+// Body: operation (1 byte) | key length (uvarint) | key | value
+type journal struct {
+	file    *os.File
+	records int
+}
+
+func openJournal(path string) (*journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, databasemp.NewDatabaseMempoolError(databasemp.OpenDbErr, errors.Wrapf(err, "os.OpenFile %s", path))
+	}
+	return &journal{file: file}, nil
+}
+
+// append writes the record of an operation on key and syncs it to disk
+func (j *journal) append(op byte, key []byte, value []byte) error {
+	body := make([]byte, 1+binary.MaxVarintLen64, 1+binary.MaxVarintLen64+len(key)+len(value))
+	body[0] = op
+	n := binary.PutUvarint(body[1:], uint64(len(key)))
+	body = append(body[:1+n], key...)
+	body = append(body, value...)
+	record := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(body)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(body))
+	record = append(record, body...)
+	if _, err := j.file.Write(record); err != nil {
+		return errors.Wrap(err, "journal.Write")
+	}
+	if err := j.file.Sync(); err != nil {
+		return errors.Wrap(err, "journal.Sync")
+	}
+	j.records++
+	return nil
+}
+
+// replay calls apply on the records of the journal in order and returns the
+// offset of the end of the last good record. A torn or corrupted record, left
+// by a crash in the middle of a write, ends the journal: the write it records
+// never returned.
+func (j *journal) replay(apply func(op byte, key []byte, value []byte)) (int64, error) {
+	if _, err := j.file.Seek(0, io.SeekStart); err != nil {
+		return 0, errors.Wrap(err, "journal.Seek")
+	}
+	reader := bufio.NewReader(j.file)
+	header := make([]byte, 8)
+	offset := int64(0)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return offset, nil
+		}
+		body := make([]byte, binary.BigEndian.Uint32(header[0:4]))
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return offset, nil
+		}
+		if len(body) == 0 || crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:8]) {
+			return offset, nil
+		}
+		keyLength, n := binary.Uvarint(body[1:])
+		if n <= 0 || uint64(len(body)-1-n) < keyLength {
+			return offset, nil
+		}
+		key := body[1+n : 1+n+int(keyLength)]
+		value := body[1+n+int(keyLength):]
+		apply(body[0], key, value)
+		j.records++
+		offset += int64(len(header) + len(body))
+	}
+}
+
+// truncate drops the journal after offset, the torn record of a crash, so that
+// the next records are appended right after the last good one
+func (j *journal) truncate(offset int64) error {
+	if err := j.file.Truncate(offset); err != nil {
+		return errors.Wrap(err, "journal.Truncate")
+	}
+	if err := j.file.Sync(); err != nil {
+		return errors.Wrap(err, "journal.Sync")
+	}
+	return nil
+}
+
+// reset empties the journal once its records are applied to leveldb
+func (j *journal) reset() error {
+	if err := j.truncate(0); err != nil {
+		return err
+	}
+	j.records = 0
+	return nil
+}
+
+func (j *journal) close() error {
+	return errors.Wrap(j.file.Close(), "journal.Close")
+}
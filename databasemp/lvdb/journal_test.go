@@ -0,0 +1,152 @@
+package lvdb_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/databasemp"
+)
+
+func TestDb_JournalReplay(t *testing.T) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_journal_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dbPath)
+	db, err := databasemp.Open("leveldbmempool", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash1 := common.HashH([]byte("tx1"))
+	hash2 := common.HashH([]byte("tx2"))
+	hash3 := common.HashH([]byte("tx3"))
+	for _, hash := range []common.Hash{hash1, hash2, hash3} {
+		if err := db.PutTransaction(&hash, hash[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.RemoveTransaction(&hash2); err != nil {
+		t.Fatal(err)
+	}
+	// the process crashes: leveldb is not closed and the last record is torn
+	journalPath := filepath.Join(dbPath, "mempool.journal")
+	journal, err := ioutil.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PutTransaction(&hash3, []byte("replaced")); err != nil {
+		t.Fatal(err)
+	}
+	torn, err := ioutil.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crashPath, err := ioutil.TempDir(os.TempDir(), "test_journal_crash_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(crashPath)
+	torn = append(journal, torn[len(journal):len(torn)-3]...)
+	if err := ioutil.WriteFile(filepath.Join(crashPath, "mempool.journal"), torn, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, err := databasemp.Open("leveldbmempool", crashPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recovered.Close()
+	for hash, want := range map[common.Hash]bool{hash1: true, hash2: false, hash3: true} {
+		has, err := recovered.HasTransaction(&hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if has != want {
+			t.Fatalf("tx %v: want in db %v, got %v", hash.String(), want, has)
+		}
+	}
+	value, err := recovered.GetTransaction(&hash3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, hash3[:]) {
+		t.Fatalf("want the value written before the torn record, got %v", value)
+	}
+	txHashes, _, err := recovered.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txHashes) != 2 {
+		t.Fatalf("want 2 txs, got %v", len(txHashes))
+	}
+	// the journal is applied to leveldb on open
+	if info, err := os.Stat(filepath.Join(crashPath, "mempool.journal")); err != nil || info.Size() != 0 {
+		t.Fatalf("want an empty journal, got %+v, %+v", info, err)
+	}
+	db.Close()
+}
+
+func TestDb_JournalTornFirstRecord(t *testing.T) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_journal_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dbPath)
+	db, err := databasemp.Open("leveldbmempool", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash1 := common.HashH([]byte("tx1"))
+	hash2 := common.HashH([]byte("tx2"))
+	if err := db.PutTransaction(&hash1, hash1[:]); err != nil {
+		t.Fatal(err)
+	}
+	// the journal is empty after the checkpoint of Close, then the process
+	// crashes during the first write after it
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	journalPath := filepath.Join(dbPath, "mempool.journal")
+	if err := ioutil.WriteFile(journalPath, []byte{0, 0, 0, 100, 1, 2, 3, 4, 1}, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = databasemp.Open("leveldbmempool", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.PutTransaction(&hash2, hash2[:]); err != nil {
+		t.Fatal(err)
+	}
+	// the process crashes again: only the journal is left
+	journal, err := ioutil.ReadFile(journalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crashPath, err := ioutil.TempDir(os.TempDir(), "test_journal_crash_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(crashPath)
+	if err := ioutil.WriteFile(filepath.Join(crashPath, "mempool.journal"), journal, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, err := databasemp.Open("leveldbmempool", crashPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recovered.Close()
+	has, err := recovered.HasTransaction(&hash2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !has {
+		t.Fatal("tx written after the torn record is lost")
+	}
+}
@@ -1,52 +1,71 @@
 package lvdb
 
 import (
+	"bytes"
+
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/databasemp"
 	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 // Key: tx-{txHash}
 // Value: {type}-transaction(byte value)-Splitter-otherDescValue(byte Value)
 func (db *db) AddTransaction(txHash *common.Hash, txType string, valueTx []byte, valueDesc []byte) error {
-	key := getKey(txHash)
 	value := append([]byte(txType), Splitter...)
 	value = append(value, valueTx...)
 	value = append(value, Splitter...)
 	value = append(value, valueDesc...)
-	if err := db.Put(key, value); err != nil {
-		return databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, errors.Wrap(err, "db.lvdb.Put"))
-	}
-	return nil
+	return db.PutTransaction(txHash, value)
 }
+
+// Key: tx-{txHash}
+// Value: encoded tx, the encoding is up to the mempool
+func (db *db) PutTransaction(txHash *common.Hash, value []byte) error {
+	db.txMtx.Lock()
+	defer db.txMtx.Unlock()
+	newValue := make([]byte, len(value))
+	copy(newValue, value)
+	return db.writeTx(getKey(txHash), newValue)
+}
+
 func (db *db) RemoveTransaction(txHash *common.Hash) error {
-	key := getKey(txHash)
-	if err := db.Delete(key); err != nil {
-		return databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, errors.Wrap(err, "db.lvdb.Delete"))
-	}
-	return nil
+	db.txMtx.Lock()
+	defer db.txMtx.Unlock()
+	return db.writeTx(getKey(txHash), nil)
 }
 
 func (db *db) GetTransaction(txHash *common.Hash) ([]byte, error) {
-	key := getKey(txHash)
-	value, err := db.Get(key)
+	db.txMtx.RLock()
+	defer db.txMtx.RUnlock()
+	value, ok, err := db.readTx(getKey(txHash))
 	if err != nil {
-		return []byte{}, databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, errors.Wrap(err, "db.lvdb.Get"))
+		return []byte{}, err
+	}
+	if !ok {
+		return []byte{}, databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, errors.Wrap(leveldb.ErrNotFound, "db.lvdb.Get"))
 	}
 	return value, nil
 }
 
 func (db *db) HasTransaction(txHash *common.Hash) (bool, error) {
-	key := getKey(txHash)
-	ret, err := db.HasValue(key)
+	db.txMtx.RLock()
+	defer db.txMtx.RUnlock()
+	_, ok, err := db.readTx(getKey(txHash))
 	if err != nil {
 		return false, databasemp.NewDatabaseMempoolError(databasemp.NotExistValue, err)
 	}
-	return ret, nil
+	return ok, nil
 }
 
 func (db *db) Reset() error {
+	db.txMtx.Lock()
+	defer db.txMtx.Unlock()
+	db.pending = make(map[string][]byte)
+	if err := db.journal.reset(); err != nil {
+		return databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, err)
+	}
 	iter := db.lvdb.NewIterator(util.BytesPrefix(txKeyPrefix), nil)
 	for iter.Next() {
 		err := db.Delete(iter.Key())
@@ -62,11 +81,16 @@ func (db *db) Reset() error {
 }
 
 func (db *db) Load() ([][]byte, [][]byte, error) {
+	db.txMtx.RLock()
+	defer db.txMtx.RUnlock()
 	txHashes := [][]byte{}
 	txs := [][]byte{}
 	iter := db.lvdb.NewIterator(util.BytesPrefix(txKeyPrefix), nil)
 	for iter.Next() {
 		key := iter.Key()
+		if _, ok := db.pending[string(key)]; ok {
+			continue
+		}
 		newKey := make([]byte, len(key))
 		copy(newKey, key)
 		txHashes = append(txHashes, newKey)
@@ -79,5 +103,12 @@ func (db *db) Load() ([][]byte, [][]byte, error) {
 	if err := iter.Error(); err != nil {
 		return txHashes, txs, databasemp.NewDatabaseMempoolError(databasemp.UnexpectedError, errors.Wrap(err, "iter.Error"))
 	}
+	for key, value := range db.pending {
+		if value == nil || !bytes.HasPrefix([]byte(key), txKeyPrefix) {
+			continue
+		}
+		txHashes = append(txHashes, []byte(key))
+		txs = append(txs, value)
+	}
 	return txHashes, txs, nil
 }
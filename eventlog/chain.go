@@ -0,0 +1,105 @@
+package eventlog
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incdb"
+)
+
+// Chain is a chain whose best chain is recorded by the event log
+type Chain interface {
+	GetChainID() int
+	GetDatabase() incdb.Database
+	GetBestBlock() (*BlockInfo, error)
+	GetBlockByHash(hash common.Hash) (*BlockInfo, error)
+}
+
+// NewChains returns the beacon chain and the shard chains of a blockchain
+func NewChains(bc *blockchain.BlockChain) []Chain {
+	chains := []Chain{&beaconChain{bc: bc}}
+	for shardID := range bc.ShardChain {
+		chains = append(chains, &shardChain{bc: bc, shardID: byte(shardID)})
+	}
+	return chains
+}
+
+type beaconChain struct {
+	bc *blockchain.BlockChain
+}
+
+func (chain *beaconChain) GetChainID() int {
+	return common.BeaconChainDataBaseID
+}
+
+func (chain *beaconChain) GetDatabase() incdb.Database {
+	return chain.bc.GetBeaconChainDatabase()
+}
+
+func (chain *beaconChain) GetBestBlock() (*BlockInfo, error) {
+	block, ok := chain.bc.BeaconChain.GetBestView().GetBlock().(*blockchain.BeaconBlock)
+	if !ok {
+		return nil, fmt.Errorf("best view of beacon chain has no beacon block")
+	}
+	return newBeaconBlockInfo(block), nil
+}
+
+func (chain *beaconChain) GetBlockByHash(hash common.Hash) (*BlockInfo, error) {
+	block, _, err := chain.bc.GetBeaconBlockByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconBlockInfo(block), nil
+}
+
+func newBeaconBlockInfo(block *blockchain.BeaconBlock) *BlockInfo {
+	return &BlockInfo{
+		Hash:         block.Header.Hash(),
+		PreviousHash: block.Header.PreviousBlockHash,
+		Height:       block.Header.Height,
+		Time:         block.Header.Timestamp,
+	}
+}
+
+type shardChain struct {
+	bc      *blockchain.BlockChain
+	shardID byte
+}
+
+func (chain *shardChain) GetChainID() int {
+	return int(chain.shardID)
+}
+
+func (chain *shardChain) GetDatabase() incdb.Database {
+	return chain.bc.GetShardChainDatabase(chain.shardID)
+}
+
+func (chain *shardChain) GetBestBlock() (*BlockInfo, error) {
+	block, ok := chain.bc.ShardChain[chain.shardID].GetBestView().GetBlock().(*blockchain.ShardBlock)
+	if !ok {
+		return nil, fmt.Errorf("best view of shard %v has no shard block", chain.shardID)
+	}
+	return newShardBlockInfo(block), nil
+}
+
+func (chain *shardChain) GetBlockByHash(hash common.Hash) (*BlockInfo, error) {
+	block, _, err := chain.bc.GetShardBlockByHashWithShardID(hash, chain.shardID)
+	if err != nil {
+		return nil, err
+	}
+	return newShardBlockInfo(block), nil
+}
+
+func newShardBlockInfo(block *blockchain.ShardBlock) *BlockInfo {
+	info := &BlockInfo{
+		Hash:         block.Header.Hash(),
+		PreviousHash: block.Header.PreviousBlockHash,
+		Height:       block.Header.Height,
+		Time:         block.Header.Timestamp,
+	}
+	for _, tx := range block.Body.Transactions {
+		info.TxHashes = append(info.TxHashes, *tx.Hash())
+	}
+	return info
+}
@@ -0,0 +1,49 @@
+package eventlog
+
+import (
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// Types of event
+const (
+	// NewBlockEvent is logged when a block joins the best chain
+	NewBlockEvent = "newblock"
+	// TransactionEvent is logged for every transaction of a shard block joining
+	// the best chain, right after the NewBlockEvent of this block
+	TransactionEvent = "transaction"
+	// RollbackEvent is logged when a block leaves the best chain, from the tip
+	// down to the common ancestor of the old and the new best chain
+	RollbackEvent = "rollback"
+)
+
+// Event is an entry of the event log of a chain. Sequence numbers of a chain
+// start at zero and increase by one with every event, a client resumes the log
+// at the sequence following the last event it received.
+type Event struct {
+	ChainID           int
+	Sequence          uint64
+	Height            uint64
+	Type              string
+	BlockHash         common.Hash
+	PreviousBlockHash common.Hash
+	BlockTime         int64
+	TxHash            *common.Hash `json:",omitempty"`
+	TxIndex           int          `json:",omitempty"`
+}
+
+// Tip is the last block of the best chain recorded by the event log of a chain
+// and the sequence number of the next event
+type Tip struct {
+	BlockHash    common.Hash
+	Height       uint64
+	NextSequence uint64
+}
+
+// BlockInfo is what the event log needs to know about a block
+type BlockInfo struct {
+	Hash         common.Hash
+	PreviousHash common.Hash
+	Height       uint64
+	Time         int64
+	TxHashes     []common.Hash
+}
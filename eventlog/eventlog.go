@@ -0,0 +1,379 @@
+package eventlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/pubsub"
+)
+
+const (
+	// SyncInterval is how often the event log catches up with the best chains
+	// without being told of a new block
+	SyncInterval = 10 * time.Second
+	// maxBatchEvents is the number of events above which a batch is written
+	// while catching up
+	maxBatchEvents = 1000
+)
+
+// EventLog records, in the database of every chain, the blocks joining and
+// leaving its best chain and the transactions of these blocks. It follows the
+// best view of the multiview of the chain, a block is logged once it is on the
+// best chain and rolled back if the best view moves to another branch. The log
+// of a chain starts at its best block when the log is first enabled, history is
+// not backfilled.
+//
+// Every logged event is published to the ChainEventTopic once written.
+type EventLog struct {
+	chains        map[int]Chain
+	pubSubManager *pubsub.PubSubManager
+	lock          sync.Mutex // serializes syncs
+	cQuit         chan struct{}
+	started       bool
+}
+
+type Config struct {
+	Chains        []Chain
+	PubSubManager *pubsub.PubSubManager
+}
+
+func NewEventLog(config Config) *EventLog {
+	eventLog := &EventLog{
+		chains:        make(map[int]Chain),
+		pubSubManager: config.PubSubManager,
+		cQuit:         make(chan struct{}),
+	}
+	for _, chain := range config.Chains {
+		eventLog.chains[chain.GetChainID()] = chain
+	}
+	return eventLog
+}
+
+// Start catches up with the best chains and follows them until Stop
+func (eventLog *EventLog) Start() error {
+	if eventLog.started {
+		return nil
+	}
+	newShardBlockSubID, newShardBlockCh, err := eventLog.pubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewShardblockTopic, pubsub.InternalSubscribeOptions)
+	if err != nil {
+		return err
+	}
+	newBeaconBlockSubID, newBeaconBlockCh, err := eventLog.pubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, pubsub.InternalSubscribeOptions)
+	if err != nil {
+		eventLog.pubSubManager.Unsubscribe(pubsub.NewShardblockTopic, newShardBlockSubID)
+		return err
+	}
+	eventLog.started = true
+	go func() {
+		defer func() {
+			eventLog.pubSubManager.Unsubscribe(pubsub.NewShardblockTopic, newShardBlockSubID)
+			eventLog.pubSubManager.Unsubscribe(pubsub.NewBeaconBlockTopic, newBeaconBlockSubID)
+		}()
+		eventLog.syncAll()
+		ticker := time.NewTicker(SyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-eventLog.cQuit:
+				return
+			case msg := <-newShardBlockCh:
+				if block, ok := msg.Value.(common.ChainInterface); ok {
+					eventLog.syncChainByID(block.GetShardID())
+				}
+			case <-newBeaconBlockCh:
+				eventLog.syncChainByID(common.BeaconChainDataBaseID)
+			case <-ticker.C:
+				eventLog.syncAll()
+			}
+		}
+	}()
+	return nil
+}
+
+func (eventLog *EventLog) Stop() {
+	if !eventLog.started {
+		return
+	}
+	eventLog.started = false
+	close(eventLog.cQuit)
+}
+
+func (eventLog *EventLog) syncAll() {
+	for chainID := range eventLog.chains {
+		eventLog.syncChainByID(chainID)
+	}
+}
+
+func (eventLog *EventLog) syncChainByID(chainID int) {
+	chain, ok := eventLog.chains[chainID]
+	if !ok {
+		return
+	}
+	if err := eventLog.Sync(chain); err != nil {
+		Logger.log.Errorf("Sync event log of chain %v: %v", chainID, err)
+	}
+}
+
+// Sync logs the changes of the best chain of chain since its last sync
+func (eventLog *EventLog) Sync(chain Chain) error {
+	eventLog.lock.Lock()
+	defer eventLog.lock.Unlock()
+	chainID := chain.GetChainID()
+	db := chain.GetDatabase()
+	tip, err := getTip(db, chainID)
+	if err != nil {
+		return err
+	}
+	best, err := chain.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	if tip == nil {
+		writer := eventLog.newEventWriter(db, chainID, &Tip{})
+		if err := writer.appendBlock(best); err != nil {
+			return err
+		}
+		return writer.write()
+	}
+	if tip.BlockHash == best.Hash {
+		return nil
+	}
+
+	// walk back from the best block and from the tip to the common ancestor
+	added := []common.Hash{}
+	ancestor := best
+	for ancestor.Height > tip.Height {
+		added = append(added, ancestor.Hash)
+		if ancestor, err = chain.GetBlockByHash(ancestor.PreviousHash); err != nil {
+			return err
+		}
+	}
+	removed := []*BlockInfo{}
+	tipBlock, err := chain.GetBlockByHash(tip.BlockHash)
+	if err != nil {
+		return err
+	}
+	for tipBlock.Height > ancestor.Height {
+		removed = append(removed, tipBlock)
+		if tipBlock, err = chain.GetBlockByHash(tipBlock.PreviousHash); err != nil {
+			return err
+		}
+	}
+	for tipBlock.Hash != ancestor.Hash {
+		removed = append(removed, tipBlock)
+		added = append(added, ancestor.Hash)
+		if tipBlock, err = chain.GetBlockByHash(tipBlock.PreviousHash); err != nil {
+			return err
+		}
+		if ancestor, err = chain.GetBlockByHash(ancestor.PreviousHash); err != nil {
+			return err
+		}
+	}
+
+	writer := eventLog.newEventWriter(db, chainID, tip)
+	for _, block := range removed {
+		if err := writer.rollbackBlock(block); err != nil {
+			return err
+		}
+	}
+	for i := len(added) - 1; i >= 0; i-- {
+		block := best
+		if added[i] != best.Hash {
+			if block, err = chain.GetBlockByHash(added[i]); err != nil {
+				return err
+			}
+		}
+		if err := writer.appendBlock(block); err != nil {
+			return err
+		}
+		if len(writer.events) >= maxBatchEvents {
+			if err := writer.write(); err != nil {
+				return err
+			}
+		}
+	}
+	return writer.write()
+}
+
+// eventWriter batches the events of a chain and the new tip of its log
+type eventWriter struct {
+	eventLog *EventLog
+	db       incdb.Database
+	batch    incdb.Batch
+	chainID  int
+	tip      Tip
+	events   []*Event
+	indexed  map[uint64]struct{} // heights indexed in the batch
+}
+
+func (eventLog *EventLog) newEventWriter(db incdb.Database, chainID int, tip *Tip) *eventWriter {
+	return &eventWriter{
+		eventLog: eventLog,
+		db:       db,
+		batch:    db.NewBatch(),
+		chainID:  chainID,
+		tip:      *tip,
+		indexed:  make(map[uint64]struct{}),
+	}
+}
+
+func (writer *eventWriter) appendBlock(block *BlockInfo) error {
+	event := &Event{
+		Type:              NewBlockEvent,
+		Height:            block.Height,
+		BlockHash:         block.Hash,
+		PreviousBlockHash: block.PreviousHash,
+		BlockTime:         block.Time,
+	}
+	if err := writer.append(event); err != nil {
+		return err
+	}
+	for i := range block.TxHashes {
+		event := &Event{
+			Type:              TransactionEvent,
+			Height:            block.Height,
+			BlockHash:         block.Hash,
+			PreviousBlockHash: block.PreviousHash,
+			BlockTime:         block.Time,
+			TxHash:            &block.TxHashes[i],
+			TxIndex:           i,
+		}
+		if err := writer.append(event); err != nil {
+			return err
+		}
+	}
+	writer.tip.BlockHash, writer.tip.Height = block.Hash, block.Height
+	return nil
+}
+
+func (writer *eventWriter) rollbackBlock(block *BlockInfo) error {
+	event := &Event{
+		Type:              RollbackEvent,
+		Height:            block.Height,
+		BlockHash:         block.Hash,
+		PreviousBlockHash: block.PreviousHash,
+		BlockTime:         block.Time,
+	}
+	if err := writer.append(event); err != nil {
+		return err
+	}
+	writer.tip.BlockHash, writer.tip.Height = block.PreviousHash, block.Height-1
+	return nil
+}
+
+func (writer *eventWriter) append(event *Event) error {
+	event.ChainID = writer.chainID
+	event.Sequence = writer.tip.NextSequence
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	firstAtHeight := false
+	if _, ok := writer.indexed[event.Height]; !ok {
+		has, err := rawdbv2.HasChainEventAtHeight(writer.db, writer.chainID, event.Height)
+		if err != nil {
+			return err
+		}
+		firstAtHeight = !has
+		writer.indexed[event.Height] = struct{}{}
+	}
+	if err := rawdbv2.StoreChainEvent(writer.batch, writer.chainID, event.Sequence, event.Height, firstAtHeight, data); err != nil {
+		return err
+	}
+	writer.events = append(writer.events, event)
+	writer.tip.NextSequence++
+	return nil
+}
+
+// write writes the batched events and the tip, then publishes the events
+func (writer *eventWriter) write() error {
+	if len(writer.events) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(writer.tip)
+	if err != nil {
+		return err
+	}
+	if err := rawdbv2.StoreChainEventTip(writer.batch, writer.chainID, data); err != nil {
+		return err
+	}
+	if err := writer.batch.Write(); err != nil {
+		return err
+	}
+	if writer.eventLog.pubSubManager != nil {
+		for _, event := range writer.events {
+			writer.eventLog.pubSubManager.PublishMessage(pubsub.NewMessage(pubsub.ChainEventTopic, event))
+		}
+	}
+	writer.batch.Reset()
+	writer.events = writer.events[:0]
+	writer.indexed = make(map[uint64]struct{})
+	return nil
+}
+
+func getTip(db incdb.Database, chainID int) (*Tip, error) {
+	data, err := rawdbv2.GetChainEventTip(db, chainID)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	tip := &Tip{}
+	if err := json.Unmarshal(data, tip); err != nil {
+		return nil, err
+	}
+	return tip, nil
+}
+
+func (eventLog *EventLog) getChain(chainID int) (Chain, error) {
+	chain, ok := eventLog.chains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("chain %v not exist", chainID)
+	}
+	return chain, nil
+}
+
+// GetTip returns the tip of the event log of a chain, nil if nothing is logged
+func (eventLog *EventLog) GetTip(chainID int) (*Tip, error) {
+	chain, err := eventLog.getChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return getTip(chain.GetDatabase(), chainID)
+}
+
+// GetEvents returns at most limit events of a chain, in sequence order,
+// starting at sequence from
+func (eventLog *EventLog) GetEvents(chainID int, from uint64, limit int) ([]*Event, error) {
+	chain, err := eventLog.getChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rawdbv2.GetChainEvents(chain.GetDatabase(), chainID, from, limit)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*Event, 0, len(data))
+	for _, value := range data {
+		event := &Event{}
+		if err := json.Unmarshal(value, event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// GetSequenceByHeight returns the sequence number of the first event logged at
+// a height of a chain. After a rollback, later events may be logged at lower
+// heights, a client replaying from this sequence receives them too.
+func (eventLog *EventLog) GetSequenceByHeight(chainID int, height uint64) (uint64, error) {
+	chain, err := eventLog.getChain(chainID)
+	if err != nil {
+		return 0, err
+	}
+	return rawdbv2.GetChainEventSequenceByHeight(chain.GetDatabase(), chainID, height)
+}
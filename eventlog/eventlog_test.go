@@ -0,0 +1,163 @@
+package eventlog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
+)
+
+var _ = func() (_ struct{}) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	return
+}()
+
+type testChain struct {
+	db     incdb.Database
+	blocks map[common.Hash]*BlockInfo
+	best   *BlockInfo
+}
+
+func newTestChain(t *testing.T) *testChain {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_eventlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := incdb.Open("leveldb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := &BlockInfo{Hash: common.HashH([]byte("genesis")), Height: 1}
+	return &testChain{
+		db:     db,
+		blocks: map[common.Hash]*BlockInfo{genesis.Hash: genesis},
+		best:   genesis,
+	}
+}
+
+// addBlock adds a block with txs transactions on top of parent and makes it the
+// best block
+func (chain *testChain) addBlock(parent *BlockInfo, branch string, txs int) *BlockInfo {
+	block := &BlockInfo{
+		Hash:         common.HashH([]byte(fmt.Sprintf("%v-%v-%v", branch, parent.Height+1, parent.Hash))),
+		PreviousHash: parent.Hash,
+		Height:       parent.Height + 1,
+	}
+	for i := 0; i < txs; i++ {
+		block.TxHashes = append(block.TxHashes, common.HashH(append(block.Hash[:], byte(i))))
+	}
+	chain.blocks[block.Hash] = block
+	chain.best = block
+	return block
+}
+
+func (chain *testChain) GetChainID() int {
+	return 0
+}
+
+func (chain *testChain) GetDatabase() incdb.Database {
+	return chain.db
+}
+
+func (chain *testChain) GetBestBlock() (*BlockInfo, error) {
+	return chain.best, nil
+}
+
+func (chain *testChain) GetBlockByHash(hash common.Hash) (*BlockInfo, error) {
+	block, ok := chain.blocks[hash]
+	if !ok {
+		return nil, fmt.Errorf("block %v not found", hash)
+	}
+	return block, nil
+}
+
+func TestEventLog_Sync(t *testing.T) {
+	chain := newTestChain(t)
+	eventLog := NewEventLog(Config{Chains: []Chain{chain}})
+	genesis := chain.best
+
+	if err := eventLog.Sync(chain); err != nil {
+		t.Fatal(err)
+	}
+	b2 := chain.addBlock(genesis, "a", 2)
+	a3 := chain.addBlock(b2, "a", 0)
+	a4 := chain.addBlock(a3, "a", 1)
+	if err := eventLog.Sync(chain); err != nil {
+		t.Fatal(err)
+	}
+	// the best view moves to another branch forked at b2
+	c3 := chain.addBlock(b2, "c", 1)
+	c4 := chain.addBlock(c3, "c", 0)
+	c5 := chain.addBlock(c4, "c", 0)
+	if err := eventLog.Sync(chain); err != nil {
+		t.Fatal(err)
+	}
+
+	type wantEvent struct {
+		eventType string
+		block     *BlockInfo
+	}
+	want := []wantEvent{
+		{NewBlockEvent, genesis},
+		{NewBlockEvent, b2}, {TransactionEvent, b2}, {TransactionEvent, b2},
+		{NewBlockEvent, a3},
+		{NewBlockEvent, a4}, {TransactionEvent, a4},
+		{RollbackEvent, a4}, {RollbackEvent, a3},
+		{NewBlockEvent, c3}, {TransactionEvent, c3},
+		{NewBlockEvent, c4},
+		{NewBlockEvent, c5},
+	}
+	events, err := eventLog.GetEvents(0, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != len(want) {
+		t.Fatalf("want %v events, got %v", len(want), len(events))
+	}
+	for i, event := range events {
+		if event.Sequence != uint64(i) || event.Type != want[i].eventType || event.BlockHash != want[i].block.Hash || event.Height != want[i].block.Height {
+			t.Fatalf("event %v: want %v of block %v, got %+v", i, want[i].eventType, want[i].block.Height, event)
+		}
+	}
+	if *events[3].TxHash != b2.TxHashes[1] || events[3].TxIndex != 1 {
+		t.Fatalf("want transaction 1 of block %v, got %+v", b2.Height, events[3])
+	}
+
+	tip, err := eventLog.GetTip(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.BlockHash != c5.Hash || tip.NextSequence != uint64(len(want)) {
+		t.Fatalf("want tip at block %v, sequence %v, got %+v", c5.Height, len(want), tip)
+	}
+
+	// resume from a cursor
+	events, err = eventLog.GetEvents(0, 7, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Sequence != 7 || events[1].Sequence != 8 {
+		t.Fatalf("want events 7 and 8, got %+v", events)
+	}
+	// the first event of a height is indexed, later events at this height are not
+	for height, sequence := range map[uint64]uint64{2: 1, 3: 4, 4: 5} {
+		got, err := eventLog.GetSequenceByHeight(0, height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != sequence {
+			t.Fatalf("height %v: want sequence %v, got %v", height, sequence, got)
+		}
+	}
+	// nothing new to log
+	if err := eventLog.Sync(chain); err != nil {
+		t.Fatal(err)
+	}
+	if tip, _ := eventLog.GetTip(0); tip.NextSequence != uint64(len(want)) {
+		t.Fatalf("want %v events, got %v", len(want), tip.NextSequence)
+	}
+}
@@ -0,0 +1,14 @@
+package eventlog
+
+import "github.com/incognitochain/incognito-chain/common"
+
+type EventLogLogger struct {
+	log common.Logger
+}
+
+func (eventLogLogger *EventLogLogger) Init(inst common.Logger) {
+	eventLogLogger.log = inst
+}
+
+// Global instant to use
+var Logger = EventLogLogger{}
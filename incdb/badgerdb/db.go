@@ -0,0 +1,364 @@
+package badgerdb
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/dgraph-io/badger"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DbType is the name this driver is registered with in incdb
+	DbType = "badgerdb"
+
+	// statProperty is the only property understood by Stat
+	statProperty = "badgerdb.stats"
+
+	// gcDiscardRatio is the ratio of stale data a value log file must contain
+	// before it is rewritten by Compact
+	gcDiscardRatio = 0.5
+
+	// loadMaxPendingWrites is the number of pending writes allowed while
+	// restoring a backup stream
+	loadMaxPendingWrites = 256
+)
+
+// db guards bdb with lock: the handle is replaced by ReOpen and closed by
+// Close, every other use of it holds the read lock. Batches and iterators keep
+// the handle they were created with.
+type db struct {
+	fn     string // filename for reporting
+	dbPath string
+	bdb    *badger.DB
+	closed bool
+	lock   sync.RWMutex
+}
+
+func init() {
+	driver := incdb.Driver{
+		DbType: DbType,
+		Open:   openDriver,
+	}
+	if err := incdb.RegisterDriver(driver); err != nil {
+		panic("failed to register db driver")
+	}
+}
+
+func openDriver(args ...interface{}) (incdb.Database, error) {
+	if len(args) != 1 {
+		return nil, errors.New("invalid arguments")
+	}
+	dbPath, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("expected db path")
+	}
+	return open(dbPath)
+}
+
+func open(dbPath string) (incdb.Database, error) {
+	bdb, err := openBadger(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	return &db{fn: dbPath, bdb: bdb, dbPath: dbPath}, nil
+}
+
+func openBadger(dbPath string) (*badger.DB, error) {
+	if err := os.MkdirAll(dbPath, 0700); err != nil {
+		return nil, errors.Wrapf(err, "os.MkdirAll %s", dbPath)
+	}
+	opts := badger.DefaultOptions(dbPath).
+		WithTruncate(true). // recover from a torn value log write instead of refusing to start
+		WithLogger(nil)
+	bdb, err := badger.Open(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "badger.Open %s", dbPath)
+	}
+	return bdb, nil
+}
+
+func (db *db) GetPath() string {
+	return db.fn
+}
+
+func (db *db) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	if db.closed {
+		return nil
+	}
+	db.closed = true
+	return errors.Wrap(db.bdb.Close(), "db.bdb.Close")
+}
+
+// ReOpen closes the current handle, if it is open, and opens the database
+// again
+func (db *db) ReOpen() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	if !db.closed {
+		if err := db.bdb.Close(); err != nil {
+			return errors.Wrap(err, "db.bdb.Close")
+		}
+		db.closed = true
+	}
+	bdb, err := openBadger(db.dbPath)
+	if err != nil {
+		return err
+	}
+	db.bdb = bdb
+	db.closed = false
+	return nil
+}
+
+func (db *db) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	err := db.bdb.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(key)
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (db *db) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	var value []byte
+	err := db.bdb.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (db *db) Put(key, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return db.bdb.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, value)
+	})
+}
+
+func (db *db) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return db.bdb.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (db *db) NewBatch() incdb.Batch {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return &batch{
+		db: db.bdb,
+	}
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// contained within the badger database.
+func (db *db) NewIterator() incdb.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return newIterator(db.bdb, nil, nil)
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// database content starting at a particular initial key (or after, if it does
+// not exist).
+func (db *db) NewIteratorWithStart(start []byte) incdb.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return newIterator(db.bdb, nil, start)
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix.
+func (db *db) NewIteratorWithPrefix(prefix []byte) incdb.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return newIterator(db.bdb, prefix, prefix)
+}
+
+// Stat returns a particular internal stat of the database. Only
+// "badgerdb.stats" is supported, it reports the on-disk size of the LSM tree
+// and of the value log.
+func (db *db) Stat(property string) (string, error) {
+	if property != statProperty {
+		return "", errors.Errorf("unknown property %s", property)
+	}
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	lsm, vlog := db.bdb.Size()
+	return fmt.Sprintf("lsm: %d bytes, vlog: %d bytes, tables: %d", lsm, vlog, len(db.bdb.Tables(false))), nil
+}
+
+// Compact flattens the LSM tree into a single level and then garbage collects
+// the value log until no more file can be rewritten. Badger cannot compact a
+// key range, so start and limit are ignored and the whole store is compacted.
+func (db *db) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	if err := db.bdb.Flatten(runtime.NumCPU()); err != nil {
+		return errors.Wrap(err, "db.bdb.Flatten")
+	}
+	for {
+		err := db.bdb.RunValueLogGC(gcDiscardRatio)
+		if err == badger.ErrNoRewrite {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "db.bdb.RunValueLogGC")
+		}
+	}
+}
+
+// Path returns the path to the database directory.
+func (db *db) Path() string {
+	return db.fn
+}
+
+// PreloadBackup replaces the content of the database with a backup stream
+// previously written by Backup. The database may be closed, as it is while a
+// node preloads its chains, it is then opened for the load only.
+func (db *db) PreloadBackup(backupFile string) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	f, err := os.Open(backupFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	bdb := db.bdb
+	if db.closed {
+		bdb, err = openBadger(db.dbPath)
+		if err != nil {
+			return err
+		}
+		defer bdb.Close()
+	}
+	if err := bdb.DropAll(); err != nil {
+		return errors.Wrap(err, "db.bdb.DropAll")
+	}
+	return bdb.Load(bufio.NewReader(f), loadMaxPendingWrites)
+}
+
+func (db *db) LatestBackup(path string) (int, string) {
+	backupFolder := filepath.Join(db.dbPath, path)
+	files, err := ioutil.ReadDir(backupFolder)
+	if err != nil {
+		return 0, ""
+	}
+	if len(files) == 0 {
+		return 0, ""
+	}
+	latestBackupEpoch := 0
+	//Get max epoch
+	for _, file := range files {
+		epoch, err := strconv.Atoi(file.Name())
+		if err != nil {
+			return 0, ""
+		}
+		if epoch > latestBackupEpoch {
+			latestBackupEpoch = epoch
+		}
+	}
+
+	return latestBackupEpoch, fmt.Sprintf("%v/%v", backupFolder, latestBackupEpoch)
+}
+
+func (db *db) RemoveBackup(backupFile string) {
+	backupFile = filepath.Join(db.dbPath, backupFile)
+	os.Remove(backupFile)
+}
+
+// Backup writes a full badger backup stream of the database into backupFile.
+// Unlike leveldb, badger can stream a consistent snapshot while it is open, so
+// the database does not need to be closed during the backup.
+func (db *db) Backup(backupFile string) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	backupFile = filepath.Join(db.dbPath, backupFile)
+	if err := os.MkdirAll(filepath.Dir(backupFile), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.Create(backupFile)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if _, err := db.bdb.Backup(w, 0); err != nil {
+		f.Close()
+		return errors.Wrap(err, "db.bdb.Backup")
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return removeUnusedBackupDatabase(backupFile)
+}
+
+func (db *db) Clear() error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	return db.bdb.DropAll()
+}
+
+// removeUnusedBackupDatabase removes old backups in the backup folder, only
+// the latest epoch and the one before it are kept
+func removeUnusedBackupDatabase(filePath string) error {
+	latestEpoch, err := strconv.Atoi(filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filePath)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		epoch, err := strconv.Atoi(file.Name())
+		if err != nil {
+			return err
+		}
+		if epoch != latestEpoch && epoch != latestEpoch-1 {
+			if err := os.Remove(filepath.Join(dir, file.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
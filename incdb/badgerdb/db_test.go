@@ -0,0 +1,205 @@
+package badgerdb_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incdb/badgerdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func openTestDB(t *testing.T) (incdb.Database, string) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	db, err := incdb.Open(badgerdb.DbType, dbPath)
+	if err != nil {
+		t.Fatalf("could not open db path: %s, %+v", dbPath, err)
+	}
+	return db, dbPath
+}
+
+func TestDb_Setup(t *testing.T) {
+	db, dbPath := openTestDB(t)
+	defer os.RemoveAll(dbPath)
+	if err := db.Close(); err != nil {
+		t.Fatalf("db.close %+v", err)
+	}
+	if err := db.ReOpen(); err != nil {
+		t.Fatalf("db.ReOpen %+v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("db.close %+v", err)
+	}
+}
+
+func TestDb_ReOpen(t *testing.T) {
+	db, dbPath := openTestDB(t)
+	defer os.RemoveAll(dbPath)
+	defer db.Close()
+
+	assert.Equal(t, nil, db.Put([]byte("a"), []byte{1}))
+	// the open handle is closed before the database is opened again
+	assert.Equal(t, nil, db.ReOpen())
+	result, err := db.Get([]byte("a"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte{1}, result)
+	assert.Equal(t, nil, db.Close())
+	assert.Equal(t, nil, db.Close())
+	assert.Equal(t, nil, db.ReOpen())
+	has, err := db.Has([]byte("a"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, has)
+}
+
+func TestDb_Base(t *testing.T) {
+	db, dbPath := openTestDB(t)
+	defer os.RemoveAll(dbPath)
+	defer db.Close()
+
+	err := db.Put([]byte("a"), []byte{1})
+	assert.Equal(t, nil, err)
+	result, err := db.Get([]byte("a"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte{1}, result)
+	has, err := db.Has([]byte("a"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, has)
+
+	err = db.Delete([]byte("a"))
+	assert.Equal(t, nil, err)
+	err = db.Delete([]byte("b"))
+	assert.Equal(t, nil, err)
+	has, err = db.Has([]byte("a"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, has)
+	_, err = db.Get([]byte("a"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDb_Batch(t *testing.T) {
+	db, dbPath := openTestDB(t)
+	defer os.RemoveAll(dbPath)
+	defer db.Close()
+
+	assert.Equal(t, nil, db.Put([]byte("abc0"), []byte("abc0")))
+	batch := db.NewBatch()
+	assert.Equal(t, nil, batch.Put([]byte("abc1"), []byte("abc1")))
+	assert.Equal(t, nil, batch.Put([]byte("abc2"), []byte("abc2")))
+	assert.Equal(t, nil, batch.Delete([]byte("abc0")))
+	assert.Equal(t, 9, batch.ValueSize())
+	assert.Equal(t, nil, batch.Write())
+
+	v, err := db.Get([]byte("abc2"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "abc2", string(v))
+	has, err := db.Has([]byte("abc0"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, has)
+
+	other, otherPath := openTestDB(t)
+	defer os.RemoveAll(otherPath)
+	defer other.Close()
+	assert.Equal(t, nil, batch.Replay(other))
+	v, err = other.Get([]byte("abc1"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "abc1", string(v))
+
+	batch.Reset()
+	assert.Equal(t, 0, batch.ValueSize())
+}
+
+func TestDb_Iterator(t *testing.T) {
+	db, dbPath := openTestDB(t)
+	defer os.RemoveAll(dbPath)
+	defer db.Close()
+
+	for _, k := range []string{"a1", "b1", "b2", "b3", "c1"} {
+		assert.Equal(t, nil, db.Put([]byte(k), []byte("v"+k)))
+	}
+
+	keys := []string{}
+	iter := db.NewIteratorWithPrefix([]byte("b"))
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+		assert.Equal(t, "v"+string(iter.Key()), string(iter.Value()))
+	}
+	assert.Equal(t, nil, iter.Error())
+	iter.Release()
+	assert.Equal(t, []string{"b1", "b2", "b3"}, keys)
+
+	iter = db.NewIteratorWithPrefix([]byte("b"))
+	assert.Equal(t, true, iter.Last())
+	assert.Equal(t, "b3", string(iter.Key()))
+	assert.Equal(t, false, iter.Next())
+	iter.Release()
+
+	keys = keys[:0]
+	iter = db.NewIteratorWithStart([]byte("b2"))
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	assert.Equal(t, []string{"b2", "b3", "c1"}, keys)
+
+	iter = db.NewIterator()
+	assert.Equal(t, true, iter.Last())
+	assert.Equal(t, "c1", string(iter.Key()))
+	iter.Release()
+
+	iter = db.NewIteratorWithPrefix([]byte("d"))
+	assert.Equal(t, false, iter.Next())
+	assert.Equal(t, false, iter.Last())
+	iter.Release()
+}
+
+func TestDb_BackupAndPreload(t *testing.T) {
+	db, dbPath := openTestDB(t)
+	defer os.RemoveAll(dbPath)
+	defer db.Close()
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, nil, db.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	assert.Equal(t, nil, db.Compact(nil, nil))
+	_, err := db.Stat("badgerdb.stats")
+	assert.Equal(t, nil, err)
+	_, err = db.Stat("leveldb.stats")
+	assert.NotEqual(t, nil, err)
+
+	assert.Equal(t, nil, db.Backup("../backup/1"))
+	assert.Equal(t, nil, db.Backup("../backup/2"))
+	assert.Equal(t, nil, db.Backup("../backup/3"))
+	epoch, backupFile := db.LatestBackup("../backup")
+	assert.Equal(t, 3, epoch)
+	files, err := ioutil.ReadDir(filepath.Join(dbPath, "../backup"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(files))
+
+	restored, restoredPath := openTestDB(t)
+	defer os.RemoveAll(restoredPath)
+	defer restored.Close()
+	assert.Equal(t, nil, restored.Put([]byte("stale"), []byte("stale")))
+	assert.Equal(t, nil, restored.PreloadBackup(backupFile))
+	v, err := restored.Get([]byte("key42"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "value42", string(v))
+	has, err := restored.Has([]byte("stale"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, has)
+
+	// a closed database is preloaded as the chains of a node
+	assert.Equal(t, nil, restored.Close())
+	assert.Equal(t, nil, restored.PreloadBackup(backupFile))
+	assert.Equal(t, nil, restored.ReOpen())
+	v, err = restored.Get([]byte("key7"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "value7", string(v))
+
+	os.RemoveAll(filepath.Join(dbPath, "../backup"))
+}
@@ -0,0 +1,153 @@
+package badgerdb
+
+import (
+	"bytes"
+
+	"github.com/dgraph-io/badger"
+)
+
+// iterator adapts a badger read-only transaction iterator to incdb.Iterator.
+// Keys and values are copied on every move so they stay valid after the
+// underlying badger item is recycled.
+type iterator struct {
+	txn    *badger.Txn
+	it     *badger.Iterator
+	prefix []byte
+	start  []byte
+
+	started bool
+	key     []byte
+	value   []byte
+	err     error
+}
+
+func newIterator(bdb *badger.DB, prefix []byte, start []byte) *iterator {
+	txn := bdb.NewTransaction(false)
+	return &iterator{
+		txn:    txn,
+		it:     txn.NewIterator(iteratorOptions(prefix, false)),
+		prefix: prefix,
+		start:  start,
+	}
+}
+
+func iteratorOptions(prefix []byte, reverse bool) badger.IteratorOptions {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	opts.Reverse = reverse
+	return opts
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if it.err != nil || it.it == nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		if it.start != nil {
+			it.it.Seek(it.start)
+		} else {
+			it.it.Rewind()
+		}
+	} else {
+		it.it.Next()
+	}
+	return it.load()
+}
+
+// Last moves the iterator to the last key/value pair. It returns whether such
+// pair exist.
+func (it *iterator) Last() bool {
+	if it.err != nil || it.it == nil {
+		return false
+	}
+	// badger iterators are one-directional, seek from the end with a reverse
+	// iterator and keep it for the following Next calls, which will then
+	// yield nothing like leveldb does after its last pair.
+	it.it.Close()
+	if next := nextPrefix(it.prefix); next == nil {
+		// a rewind seeks to the prefix option, leave it out to start from
+		// the greatest key
+		it.it = it.txn.NewIterator(iteratorOptions(nil, true))
+		it.it.Rewind()
+	} else {
+		it.it = it.txn.NewIterator(iteratorOptions(it.prefix, true))
+		// a reverse seek stops at the greatest key not after next, which is
+		// next itself if it exists, Valid is false there as it is not under
+		// the prefix
+		it.it.Seek(next)
+		if item := it.it.Item(); item != nil && bytes.Equal(item.Key(), next) {
+			it.it.Next()
+		}
+	}
+	it.started = true
+	if !it.load() {
+		return false
+	}
+	if it.start != nil && bytes.Compare(it.key, it.start) < 0 {
+		it.key, it.value = nil, nil
+		return false
+	}
+	it.it.Close()
+	it.it = nil
+	return true
+}
+
+// load copies the current item, or clears it when the iterator is exhausted.
+func (it *iterator) load() bool {
+	if !it.it.ValidForPrefix(it.prefix) {
+		it.key, it.value = nil, nil
+		return false
+	}
+	item := it.it.Item()
+	it.key = item.KeyCopy(nil)
+	it.value, it.err = item.ValueCopy(nil)
+	if it.err != nil {
+		it.key, it.value = nil, nil
+		return false
+	}
+	return true
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *iterator) Key() []byte {
+	return it.key
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	if it.it != nil {
+		it.it.Close()
+		it.it = nil
+	}
+	it.txn.Discard()
+	it.key, it.value = nil, nil
+}
+
+// nextPrefix returns the smallest key sorting after every key starting with
+// prefix, or nil if there is none as prefix is empty or only 0xff bytes. It is
+// used as the seek target of reverse iterations.
+func nextPrefix(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			next := append([]byte{}, prefix[:i+1]...)
+			next[i]++
+			return next
+		}
+	}
+	return nil
+}
@@ -1,27 +1,127 @@
-package incdb
+package incdb_test
 
 import (
-	"github.com/stretchr/testify/assert"
-	"path/filepath"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
+
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/incdb/badgerdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
+	"github.com/stretchr/testify/assert"
 )
 
+// dbTypes are the drivers the database tests run against
+var dbTypes = []string{"leveldb", badgerdb.DbType}
+
+func openTestDB(t *testing.T, dbType string) (incdb.Database, func()) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "test_")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	db, err := incdb.Open(dbType, dbPath)
+	if err != nil {
+		os.RemoveAll(dbPath)
+		t.Fatalf("could not open %v db path: %s, %+v", dbType, dbPath, err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dbPath)
+	}
+}
+
 func Test_RegisterDriver(t *testing.T) {
-	driver := Driver{
-		DbType: "leveldb",
-		Open: func(args ...interface{}) (databaseInterface Database, e error) {
+	driver := incdb.Driver{
+		DbType: "testdb",
+		Open: func(args ...interface{}) (databaseInterface incdb.Database, e error) {
 			return nil, nil
 		},
 	}
-	err := RegisterDriver(driver)
+	err := incdb.RegisterDriver(driver)
 	assert.Equal(t, nil, err)
-	err = RegisterDriver(driver)
+	err = incdb.RegisterDriver(driver)
 	assert.NotEqual(t, nil, err)
 }
 
 func Test_Open(t *testing.T) {
-	_, err := Open("leveldb", filepath.Join("./", "test"))
-	assert.Equal(t, nil, err)
-	_, err = Open("leveldb1", filepath.Join("./", "test"))
+	for _, dbType := range dbTypes {
+		_, closeDB := openTestDB(t, dbType)
+		closeDB()
+	}
+	_, err := incdb.Open("leveldb1", os.TempDir())
 	assert.NotEqual(t, nil, err)
 }
+
+func Test_Database(t *testing.T) {
+	for _, dbType := range dbTypes {
+		t.Run(dbType, func(t *testing.T) {
+			db, closeDB := openTestDB(t, dbType)
+			defer closeDB()
+
+			assert.Equal(t, nil, db.Put([]byte("a"), []byte{1}))
+			result, err := db.Get([]byte("a"))
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []byte{1}, result)
+			has, err := db.Has([]byte("a"))
+			assert.Equal(t, nil, err)
+			assert.Equal(t, true, has)
+			assert.Equal(t, nil, db.Delete([]byte("a")))
+			has, err = db.Has([]byte("a"))
+			assert.Equal(t, nil, err)
+			assert.Equal(t, false, has)
+			_, err = db.Get([]byte("a"))
+			assert.NotEqual(t, nil, err)
+
+			batch := db.NewBatch()
+			assert.Equal(t, nil, batch.Put([]byte("b"), []byte("b")))
+			assert.Equal(t, nil, batch.Write())
+			result, err = db.Get([]byte("b"))
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []byte("b"), result)
+		})
+	}
+}
+
+func Test_DatabaseIterator(t *testing.T) {
+	// longer than the prefix padded with 32 0xff bytes
+	long := "b3" + strings.Repeat("\xff", 40)
+	tests := []struct {
+		name   string
+		prefix []byte
+		keys   []string // keys under prefix in order
+		last   string
+	}{
+		// "c", the key right after the prefix, is not under it
+		{name: "prefix", prefix: []byte("b"), keys: []string{"b1", "b2", "b3", long}, last: long},
+		{name: "long key last", prefix: []byte("b3"), keys: []string{"b3", long}, last: long},
+		{name: "all", prefix: nil, keys: []string{"a1", "b1", "b2", "b3", long, "c", "c1", "\xff\xff1"}, last: "\xff\xff1"},
+		{name: "0xff prefix", prefix: []byte("\xff\xff"), keys: []string{"\xff\xff1"}, last: "\xff\xff1"},
+		{name: "empty", prefix: []byte("d"), keys: []string{}},
+	}
+	for _, dbType := range dbTypes {
+		db, closeDB := openTestDB(t, dbType)
+		for _, k := range []string{"a1", "b1", "b2", "b3", long, "c", "c1", "\xff\xff1"} {
+			assert.Equal(t, nil, db.Put([]byte(k), []byte("v"+k)))
+		}
+		for _, tt := range tests {
+			t.Run(dbType+"/"+tt.name, func(t *testing.T) {
+				iter := db.NewIteratorWithPrefix(tt.prefix)
+				keys := []string{}
+				for iter.Next() {
+					keys = append(keys, string(iter.Key()))
+					assert.Equal(t, "v"+string(iter.Key()), string(iter.Value()))
+				}
+				assert.Equal(t, nil, iter.Error())
+				iter.Release()
+				assert.Equal(t, tt.keys, keys)
+
+				iter = db.NewIteratorWithPrefix(tt.prefix)
+				assert.Equal(t, tt.last != "", iter.Last())
+				assert.Equal(t, tt.last, string(iter.Key()))
+				iter.Release()
+			})
+		}
+		closeDB()
+	}
+}
@@ -66,16 +66,12 @@ func TestDb_Base(t *testing.T) {
 		assert.Equal(t, err, nil)
 		assert.Equal(t, has, false)
 
-		batchData := []incdb.BatchData{}
-		batchData = append(batchData, incdb.BatchData{
-			Key:   []byte("abc1"),
-			Value: []byte("abc1"),
-		})
-		batchData = append(batchData, incdb.BatchData{
-			Key:   []byte("abc2"),
-			Value: []byte("abc2"),
-		})
-		err = db.PutBatch(batchData)
+		batch := db.NewBatch()
+		err = batch.Put([]byte("abc1"), []byte("abc1"))
+		assert.Equal(t, err, nil)
+		err = batch.Put([]byte("abc2"), []byte("abc2"))
+		assert.Equal(t, err, nil)
+		err = batch.Write()
 		assert.Equal(t, err, nil)
 		v, err := db.Get([]byte("abc2"))
 		assert.Equal(t, err, nil)
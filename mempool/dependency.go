@@ -0,0 +1,336 @@
+package mempool
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	zkp "github.com/incognitochain/incognito-chain/privacy/zeroknowledge"
+	"github.com/incognitochain/incognito-chain/transaction"
+)
+
+// limits of the txs spending outputs of unconfirmed txs
+const (
+	maxDependentTxs    = 1000 // held txs
+	maxDependencyDepth = 25   // unconfirmed ancestors of a held tx, in a row
+)
+
+// dependentTx is a tx spending outputs of unconfirmed txs, its parents, in
+// pool or held too. It is held out of pool until all its parents are in a
+// block, since its proofs can only be verified against the commitments of its
+// inputs once they are in the blockchain.
+type dependentTx struct {
+	txDesc  *TxDesc
+	parents map[common.Hash]struct{} // the parents which are not in a block yet
+	depth   int
+}
+
+// TxChainEntry is a tx of a chain of unconfirmed spends, Held if it waits out
+// of pool for its parents to be in a block
+type TxChainEntry struct {
+	TxHash   common.Hash
+	Held     bool
+	Parents  []common.Hash
+	Children []common.Hash
+}
+
+// listSNDInputsHashH returns the hashes of the SNDs of the input coins of tx
+// which are not hidden by a privacy proof, the input coins of a privacy proof
+// can not spend unconfirmed outputs anyway
+func listSNDInputsHashH(tx metadata.Transaction) []common.Hash {
+	proofs := []*zkp.PaymentProof{}
+	switch tx := tx.(type) {
+	case *transaction.Tx:
+		proofs = append(proofs, tx.Proof)
+	case *transaction.TxCustomTokenPrivacy:
+		proofs = append(proofs, tx.Proof, tx.TxPrivacyTokenData.TxNormal.Proof)
+	}
+	result := []common.Hash{}
+	for _, proof := range proofs {
+		if proof == nil {
+			continue
+		}
+		for _, inputCoin := range proof.GetInputCoins() {
+			if inputCoin.CoinDetails == nil || inputCoin.CoinDetails.GetSNDerivator() == nil {
+				continue
+			}
+			result = append(result, common.HashH(inputCoin.CoinDetails.GetSNDerivator().ToBytesS()))
+		}
+	}
+	return result
+}
+
+// indexSNDOutputs indexes the outputs of a tx of pool or held, so that the txs
+// spending them find their parent
+func (tp *TxPool) indexSNDOutputs(tx metadata.Transaction) {
+	for _, sndHash := range tx.ListSNDOutputsHashH() {
+		tp.poolSNDOutputs[sndHash] = *tx.Hash()
+	}
+}
+
+// unindexSNDOutputs reverts indexSNDOutputs
+func (tp *TxPool) unindexSNDOutputs(tx metadata.Transaction) {
+	for _, sndHash := range tx.ListSNDOutputsHashH() {
+		if txHash, ok := tp.poolSNDOutputs[sndHash]; ok && txHash == *tx.Hash() {
+			delete(tp.poolSNDOutputs, sndHash)
+		}
+	}
+}
+
+// getUnconfirmedParents returns the txs of pool or held of which tx spends
+// outputs
+// This function MUST be called with the pool locked
+func (tp *TxPool) getUnconfirmedParents(tx metadata.Transaction) map[common.Hash]struct{} {
+	parents := make(map[common.Hash]struct{})
+	for _, sndHash := range listSNDInputsHashH(tx) {
+		if parent, ok := tp.poolSNDOutputs[sndHash]; ok {
+			parents[parent] = struct{}{}
+		}
+	}
+	return parents
+}
+
+// hasUnconfirmedParents returns true if tx spends outputs of txs of pool or held
+// This function is safe for concurrent access.
+func (tp *TxPool) hasUnconfirmedParents(tx metadata.Transaction) bool {
+	tp.mtx.RLock()
+	defer tp.mtx.RUnlock()
+	return len(tp.getUnconfirmedParents(tx)) > 0
+}
+
+// holdTransaction holds tx, which spends outputs of its unconfirmed parents,
+// until they are all in a block. It goes through the checks of a new tx now and
+// its proofs are verified unless isVerified, except the commitments of its
+// inputs, which are checked when it is released to pool. A held tx counts in
+// the txs of its sender, it is not persisted: it is dropped on restart.
+// This function MUST be called with the pool locked
+func (tp *TxPool) holdTransaction(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, beaconHeight int64, parents map[common.Hash]struct{}, isVerified bool) (*common.Hash, *TxDesc, error) {
+	txHash := *tx.Hash()
+	if _, ok := tp.dependents[txHash]; ok {
+		return nil, nil, NewMempoolTxError(RejectDuplicateTx, fmt.Errorf("already hold transaction %+v", txHash.String()))
+	}
+	if len(tp.dependents) >= maxDependentTxs {
+		return nil, nil, NewMempoolTxError(RejectDependentTxError, fmt.Errorf("already hold %+v txs spending unconfirmed outputs", len(tp.dependents)))
+	}
+	if err := tp.checkSenderLimit(tx); err != nil {
+		tp.countRejection(RejectSender)
+		return nil, nil, err
+	}
+	depth := 1
+	for parent := range parents {
+		if dependent, ok := tp.dependents[parent]; ok && dependent.depth+1 > depth {
+			depth = dependent.depth + 1
+		}
+	}
+	if depth > maxDependencyDepth {
+		return nil, nil, NewMempoolTxError(RejectDependentTxError, fmt.Errorf("tx %+v has more than %+v unconfirmed ancestors in a row", txHash.String(), maxDependencyDepth))
+	}
+	// the held txs must not spend the same coins
+	serialNumbers := tx.ListSerialNumbersHashH()
+	for _, serialNumber := range serialNumbers {
+		if other, ok := tp.dependentSerialNumbers[serialNumber]; ok {
+			return nil, nil, NewMempoolTxError(RejectDoubleSpendWithMempoolTx, fmt.Errorf("tx %+v spends an input of held tx %+v", txHash.String(), other.String()))
+		}
+	}
+	for _, check := range tp.getValidationChecks(shardView, beaconView, tx, beaconHeight, true, true, true) {
+		if err := check.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !isVerified {
+		if err := tp.checkTxByItself(shardView, beaconView, tx, beaconHeight, true, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	txD := createTxDescMempool(tx, shardView.BestBlock.Header.Height, tx.GetTxFee(), tx.GetTxFeeToken())
+	txD.FeePerKB = blockchain.GetTxFeePerKBInPRV(tx, beaconHeight, beaconView.GetBeaconFeatureStateDB())
+	tp.addDependent(txD, parents, depth)
+	Logger.log.Infof("Hold tx %+v spending outputs of %+v unconfirmed txs", txHash.String(), len(parents))
+	return &txHash, txD, nil
+}
+
+// addDependent holds a checked tx until its parents are all in a block
+// This function MUST be called with the pool locked
+func (tp *TxPool) addDependent(txD *TxDesc, parents map[common.Hash]struct{}, depth int) {
+	tx := txD.Desc.Tx
+	txHash := *tx.Hash()
+	tp.dependents[txHash] = &dependentTx{
+		txDesc:  txD,
+		parents: parents,
+		depth:   depth,
+	}
+	for _, serialNumber := range tx.ListSerialNumbersHashH() {
+		tp.dependentSerialNumbers[serialNumber] = txHash
+	}
+	for parent := range parents {
+		if _, ok := tp.children[parent]; !ok {
+			tp.children[parent] = make(map[common.Hash]struct{})
+		}
+		tp.children[parent][txHash] = struct{}{}
+	}
+	tp.indexSNDOutputs(tx)
+	tp.countSenderTx(tx)
+}
+
+// removeDependent removes a held tx, its children stay held
+// This function MUST be called with the pool locked
+func (tp *TxPool) removeDependent(txHash common.Hash) *dependentTx {
+	dependent, ok := tp.dependents[txHash]
+	if !ok {
+		return nil
+	}
+	tx := dependent.txDesc.Desc.Tx
+	delete(tp.dependents, txHash)
+	for _, serialNumber := range tx.ListSerialNumbersHashH() {
+		if other, ok := tp.dependentSerialNumbers[serialNumber]; ok && other == txHash {
+			delete(tp.dependentSerialNumbers, serialNumber)
+		}
+	}
+	for parent := range dependent.parents {
+		delete(tp.children[parent], txHash)
+		if len(tp.children[parent]) == 0 {
+			delete(tp.children, parent)
+		}
+	}
+	tp.unindexSNDOutputs(tx)
+	tp.uncountSenderTx(tx)
+	return dependent
+}
+
+// dropDependents removes the held txs spending outputs of parentHash, which
+// leaves the pool without being in a block, then the held txs spending their
+// outputs in turn
+// This function MUST be called with the pool locked
+func (tp *TxPool) dropDependents(parentHash common.Hash) {
+	for txHash := range tp.children[parentHash] {
+		dependent := tp.removeDependent(txHash)
+		if dependent == nil {
+			continue
+		}
+		Logger.log.Infof("Drop held tx %+v, its parent %+v left the pool", txHash.String(), parentHash.String())
+		tp.recordEviction(Eviction{
+			TxHash:   txHash,
+			ShardID:  common.GetShardIDFromLastByte(dependent.txDesc.Desc.Tx.GetSenderAddrLastByte()),
+			FeePerKB: dependent.txDesc.FeePerKB,
+			Reason:   EvictParentRemoved,
+			Time:     time.Now().Unix(),
+		})
+		tp.dropDependents(txHash)
+	}
+	delete(tp.children, parentHash)
+}
+
+// confirmDependencies detaches the held txs from their parents among txs,
+// which are in a block, and returns the held txs of which all the parents are
+// now in a block
+// This function MUST be called with the pool locked
+func (tp *TxPool) confirmDependencies(txs []metadata.Transaction) []common.Hash {
+	ready := []common.Hash{}
+	for _, tx := range txs {
+		txHash := *tx.Hash()
+		// a held tx may be in a block of another node, its children stay held
+		tp.removeDependent(txHash)
+		for child := range tp.children[txHash] {
+			dependent, ok := tp.dependents[child]
+			if !ok {
+				continue
+			}
+			delete(dependent.parents, txHash)
+			if len(dependent.parents) == 0 {
+				ready = append(ready, child)
+			}
+		}
+		delete(tp.children, txHash)
+	}
+	return ready
+}
+
+// releaseDependents accepts to pool the held txs of which all the parents are
+// in a block, after verifying their proofs. A released tx is the parent in
+// pool of its children, which stay held. The held txs depending on a tx which
+// is rejected are dropped.
+// This function MUST be called with the pool locked
+func (tp *TxPool) releaseDependents(txHashes []common.Hash) {
+	if len(txHashes) == 0 {
+		return
+	}
+	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+	for _, txHash := range txHashes {
+		dependent := tp.removeDependent(txHash)
+		if dependent == nil {
+			continue
+		}
+		tx := dependent.txDesc.Desc.Tx
+		senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+		shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
+		_, _, err := tp.acceptTransaction(shardView, beaconView, tx, tp.config.PersistMempool, true, false, int64(beaconView.BeaconHeight))
+		if err != nil {
+			Logger.log.Errorf("Release held tx %+v error %+v", txHash.String(), err)
+			tp.dropDependents(txHash)
+			continue
+		}
+		Logger.log.Infof("Release held tx %+v to pool", txHash.String())
+		tp.notifyAcceptedTx(tx)
+	}
+}
+
+// GetTxChain returns the chain of unconfirmed spends of a tx of pool or held:
+// the tx, then its unconfirmed ancestors, then its descendants, all held
+// This function is safe for concurrent access.
+func (tp *TxPool) GetTxChain(txHash common.Hash) ([]TxChainEntry, error) {
+	tp.mtx.RLock()
+	defer tp.mtx.RUnlock()
+	if _, ok := tp.pool[txHash]; !ok {
+		if _, ok := tp.dependents[txHash]; !ok {
+			return nil, NewMempoolTxError(TransactionNotFoundError, fmt.Errorf("transaction %+v is neither in pool nor held", txHash.String()))
+		}
+	}
+	chain := []TxChainEntry{tp.getTxChainEntry(txHash)}
+	visited := map[common.Hash]struct{}{txHash: {}}
+	// ancestors, then descendants, nearest first
+	for _, next := range []func(entry TxChainEntry) []common.Hash{
+		func(entry TxChainEntry) []common.Hash { return entry.Parents },
+		func(entry TxChainEntry) []common.Hash { return entry.Children },
+	} {
+		queue := next(chain[0])
+		for len(queue) > 0 {
+			hash := queue[0]
+			queue = queue[1:]
+			if _, ok := visited[hash]; ok {
+				continue
+			}
+			visited[hash] = struct{}{}
+			entry := tp.getTxChainEntry(hash)
+			chain = append(chain, entry)
+			queue = append(queue, next(entry)...)
+		}
+	}
+	return chain, nil
+}
+
+func (tp *TxPool) getTxChainEntry(txHash common.Hash) TxChainEntry {
+	entry := TxChainEntry{
+		TxHash:   txHash,
+		Parents:  []common.Hash{},
+		Children: []common.Hash{},
+	}
+	if dependent, ok := tp.dependents[txHash]; ok {
+		entry.Held = true
+		for parent := range dependent.parents {
+			entry.Parents = append(entry.Parents, parent)
+		}
+	}
+	for child := range tp.children[txHash] {
+		entry.Children = append(entry.Children, child)
+	}
+	sort.Slice(entry.Parents, func(i, j int) bool {
+		return entry.Parents[i].String() < entry.Parents[j].String()
+	})
+	sort.Slice(entry.Children, func(i, j int) bool {
+		return entry.Children[i].String() < entry.Children[j].String()
+	})
+	return entry
+}
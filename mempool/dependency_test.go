@@ -0,0 +1,150 @@
+package mempool
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/metadata/mocks"
+)
+
+type testHeldTx struct {
+	name    string
+	sender  string
+	parents []string
+	depth   int
+}
+
+func newTestHeldTxDesc(tx testHeldTx) *TxDesc {
+	hash := common.HashH([]byte(tx.name))
+	mockTx := &mocks.Transaction{}
+	mockTx.On("Hash").Return(&hash)
+	mockTx.On("GetSenderAddrLastByte").Return(byte(1))
+	mockTx.On("GetSigPubKey").Return([]byte(tx.sender))
+	mockTx.On("ListSerialNumbersHashH").Return([]common.Hash{common.HashH([]byte("serial number of " + tx.name))})
+	mockTx.On("ListSNDOutputsHashH").Return([]common.Hash{common.HashH([]byte("output of " + tx.name))})
+	return &TxDesc{Desc: metadata.TxDesc{Tx: mockTx}, StartTime: time.Now()}
+}
+
+// newTestDependencyPool holds txs, in order, spending outputs of the tx p of
+// pool and of each other
+func newTestDependencyPool(config Config, txs []testHeldTx) *TxPool {
+	tp := newTestEvictionPool(config)
+	parentTxD := newTestHeldTxDesc(testHeldTx{name: "p", sender: "s0"})
+	tp.pool[*parentTxD.Desc.Tx.Hash()] = parentTxD
+	tp.indexSNDOutputs(parentTxD.Desc.Tx)
+	for _, tx := range txs {
+		parents := map[common.Hash]struct{}{}
+		for _, parent := range tx.parents {
+			parents[common.HashH([]byte(parent))] = struct{}{}
+		}
+		tp.addDependent(newTestHeldTxDesc(tx), parents, tx.depth)
+	}
+	return tp
+}
+
+func heldTxNames(tp *TxPool, names ...string) []string {
+	held := []string{}
+	for _, name := range names {
+		if _, ok := tp.dependents[common.HashH([]byte(name))]; ok {
+			held = append(held, name)
+		}
+	}
+	return held
+}
+
+func TestTxPool_holdTransactionSenderLimit(t *testing.T) {
+	tp := newTestDependencyPool(Config{MaxTx: 10, MaxSenderTx: 1}, []testHeldTx{
+		{name: "a", sender: "s1", parents: []string{"p"}, depth: 1},
+	})
+	if tp.senderTxCount["s1"] != 1 {
+		t.Fatalf("held tx counts %v txs of its sender, want 1", tp.senderTxCount["s1"])
+	}
+	txD := newTestHeldTxDesc(testHeldTx{name: "b", sender: "s1"})
+	parents := map[common.Hash]struct{}{common.HashH([]byte("a")): {}}
+	_, _, err := tp.holdTransaction(nil, nil, txD.Desc.Tx, 0, parents, false)
+	if mempoolErr, ok := err.(*MempoolTxError); !ok || mempoolErr.Code != ErrCodeMessage[RejectSenderLimitError].Code {
+		t.Fatalf("holdTransaction() error = %v, want code %v", err, ErrCodeMessage[RejectSenderLimitError].Code)
+	}
+	if tp.rejected[RejectSender] != 1 {
+		t.Errorf("%v sender rejections, want 1", tp.rejected[RejectSender])
+	}
+	// the held txs count in the txs of their sender in pool too
+	_, reason, err := tp.pickEvictions(newTestTxDesc(testPoolTx{name: "c", shardID: 1, sender: "s1", size: 1, feePerKB: 10}, time.Now()))
+	if err == nil || reason != RejectSender {
+		t.Errorf("pickEvictions() = %v, %v, want the sender rejection", reason, err)
+	}
+	if held := heldTxNames(tp, "a", "b"); !reflect.DeepEqual(held, []string{"a"}) {
+		t.Errorf("held txs %v, want [a]", held)
+	}
+}
+
+func TestTxPool_confirmDependencies(t *testing.T) {
+	tp := newTestDependencyPool(Config{MaxTx: 10}, []testHeldTx{
+		{name: "a", sender: "s1", parents: []string{"p"}, depth: 1},
+		{name: "b", sender: "s1", parents: []string{"a"}, depth: 2},
+		{name: "c", sender: "s2", parents: []string{"p", "q"}, depth: 1},
+	})
+	ready := tp.confirmDependencies([]metadata.Transaction{tp.pool[common.HashH([]byte("p"))].Desc.Tx})
+	if !reflect.DeepEqual(ready, []common.Hash{common.HashH([]byte("a"))}) {
+		t.Fatalf("confirmDependencies() = %v, want [a]", ready)
+	}
+	// a is released to pool by removing it from the held txs, its children
+	// stay held
+	if dependent := tp.removeDependent(common.HashH([]byte("a"))); dependent == nil {
+		t.Fatal("tx a is not held")
+	}
+	if held := heldTxNames(tp, "a", "b", "c"); !reflect.DeepEqual(held, []string{"b", "c"}) {
+		t.Errorf("held txs %v, want [b c]", held)
+	}
+	if parents := tp.dependents[common.HashH([]byte("c"))].parents; !reflect.DeepEqual(parents, map[common.Hash]struct{}{common.HashH([]byte("q")): {}}) {
+		t.Errorf("tx c waits for %v, want q", parents)
+	}
+	if _, ok := tp.children[common.HashH([]byte("a"))]; !ok {
+		t.Error("tx b is not a child of tx a")
+	}
+	if tp.senderTxCount["s1"] != 1 || tp.senderTxCount["s2"] != 1 {
+		t.Errorf("sender counts %v, want s1 and s2 once", tp.senderTxCount)
+	}
+}
+
+func TestTxPool_dropDependents(t *testing.T) {
+	tp := newTestDependencyPool(Config{MaxTx: 10}, []testHeldTx{
+		{name: "a", sender: "s1", parents: []string{"p"}, depth: 1},
+		{name: "b", sender: "s1", parents: []string{"a"}, depth: 2},
+		{name: "c", sender: "s2", parents: []string{"p"}, depth: 1},
+		{name: "d", sender: "s3", parents: []string{"q"}, depth: 1},
+	})
+	tp.dropDependents(common.HashH([]byte("p")))
+	if held := heldTxNames(tp, "a", "b", "c", "d"); !reflect.DeepEqual(held, []string{"d"}) {
+		t.Errorf("held txs %v, want [d]", held)
+	}
+	if tp.evicted[EvictParentRemoved] != 3 {
+		t.Errorf("%v txs dropped, want 3", tp.evicted[EvictParentRemoved])
+	}
+	dropped := []string{}
+	names := map[common.Hash]string{}
+	for _, name := range []string{"a", "b", "c"} {
+		names[common.HashH([]byte(name))] = name
+	}
+	for _, eviction := range tp.recentEvictions {
+		dropped = append(dropped, names[eviction.TxHash])
+	}
+	sort.Strings(dropped)
+	if !reflect.DeepEqual(dropped, []string{"a", "b", "c"}) {
+		t.Errorf("dropped txs %v, want [a b c]", dropped)
+	}
+	if !reflect.DeepEqual(tp.senderTxCount, map[string]uint64{"s3": 1}) {
+		t.Errorf("sender counts %v, want s3 once", tp.senderTxCount)
+	}
+	if len(tp.dependentSerialNumbers) != 1 || len(tp.children) != 1 {
+		t.Errorf("%v held serial numbers and %v parents left, want the ones of d", len(tp.dependentSerialNumbers), len(tp.children))
+	}
+	// the outputs of p and d are still spendable by new held txs
+	if len(tp.poolSNDOutputs) != 2 {
+		t.Errorf("%v indexed outputs, want the ones of p and d", len(tp.poolSNDOutputs))
+	}
+}
@@ -43,6 +43,10 @@ const (
 	ValidateAggSignatureForCrossShardBlockError
 	DuplicateSerialNumbersHashError
 	CouldNotGetExchangeRateError
+	RejectSenderLimitError
+	TxVerifierStoppedError
+	RejectDependentTxError
+	RejectNotRelayedShardTxError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -84,6 +88,10 @@ var ErrCodeMessage = map[int]struct {
 	CouldNotGetExchangeRateError:                {-1032, "Could not get the exchange rate error"},
 	RejectSanityTxLocktime:                      {-1033, "Wrong tx locktime"},
 	RejectMetadataWithBlockchainTx:              {-1034, "Reject invalid metadata with blockchain"},
+	RejectSenderLimitError:                      {-1035, "Reject tx over the limit of txs of its sender in pool"},
+	TxVerifierStoppedError:                      {-1036, "Tx verification pipeline is stopped"},
+	RejectDependentTxError:                      {-1037, "Reject tx over the limits of txs spending unconfirmed outputs"},
+	RejectNotRelayedShardTxError:                {-1038, "Reject tx of a shard the node neither relays nor validates"},
 }
 
 type MempoolTxError struct {
@@ -0,0 +1,200 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+// percentiles of the fee rates of the txs confirmed in the last blocks which
+// give the bands
+const (
+	feeBandSlowPercentile   = 25
+	feeBandNormalPercentile = 50
+	feeBandFastPercentile   = 90
+)
+
+// FeeBands are the fee rates per kilobyte for a tx to be confirmed within
+// numBlocks blocks (Slow), half of them (Normal) and the next block (Fast)
+type FeeBands struct {
+	Slow   CoinPerKilobyte
+	Normal CoinPerKilobyte
+	Fast   CoinPerKilobyte
+}
+
+// EstimateFeeBands estimates the fee per kilobyte of a tx of shardID to be
+// confirmed within numBlocks from the fees of the txs confirmed in the last
+// blocks, whatever token they paid in, and from the txs of the shard waiting
+// in pool. The fees in pTokens are converted to PRV with the prices of the
+// pool pairs of the pDEX. The bands are in tokenID if it is not nil.
+// This function is safe for concurrent access.
+func (tp *TxPool) EstimateFeeBands(shardID byte, numBlocks uint64, tokenID *common.Hash) (FeeBands, error) {
+	if numBlocks == 0 {
+		return FeeBands{}, errors.New("cannot confirm transaction in zero blocks")
+	}
+	if numBlocks > estimateFeeDepth {
+		return FeeBands{}, fmt.Errorf("can only estimate fees for up to %d blocks from now", estimateFeeDepth)
+	}
+	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+	prices, err := tp.getPDETokenPrices(beaconView)
+	if err != nil {
+		return FeeBands{}, err
+	}
+	var tokenPrice float64
+	if tokenID != nil {
+		var ok bool
+		if tokenPrice, ok = prices[*tokenID]; !ok {
+			return FeeBands{}, fmt.Errorf("there is no exchange rate between PRV and token %+v", tokenID.String())
+		}
+	}
+
+	var feeRates []CoinPerKilobyte
+	limitFee := uint64(0)
+	if feeEstimator, ok := tp.config.FeeEstimator[shardID]; ok {
+		feeRates = feeEstimator.feeRatesInPRV(numBlocks, prices)
+		limitFee = feeEstimator.GetLimitFeeForNativeToken()
+	}
+	tp.mtx.RLock()
+	backlog := tp.sortByEvictionOrder(true, shardID)
+	tp.mtx.RUnlock()
+
+	normalBlocks := numBlocks / 2
+	if normalBlocks == 0 {
+		normalBlocks = 1
+	}
+	bands := FeeBands{
+		Slow:   maxFeeRate(percentileFeeRate(feeRates, feeBandSlowPercentile), backlogFeeRate(backlog, numBlocks), CoinPerKilobyte(limitFee)),
+		Normal: maxFeeRate(percentileFeeRate(feeRates, feeBandNormalPercentile), backlogFeeRate(backlog, normalBlocks), CoinPerKilobyte(limitFee)),
+		Fast:   maxFeeRate(percentileFeeRate(feeRates, feeBandFastPercentile), backlogFeeRate(backlog, 1), CoinPerKilobyte(limitFee)),
+	}
+	bands.Normal = maxFeeRate(bands.Normal, bands.Slow)
+	bands.Fast = maxFeeRate(bands.Fast, bands.Normal)
+	if tokenID == nil {
+		return bands, nil
+	}
+	// add extra fee to cover the slippage of the conversion of the fee of the
+	// tx to PRV, extra fee = 10%
+	toToken := func(feeRate CoinPerKilobyte) CoinPerKilobyte {
+		return CoinPerKilobyte(math.Ceil(float64(feeRate) / tokenPrice * 1.1))
+	}
+	return FeeBands{Slow: toToken(bands.Slow), Normal: toToken(bands.Normal), Fast: toToken(bands.Fast)}, nil
+}
+
+// getPDETokenPrices returns the prices of the pDEX tokens at beaconView, the
+// pDEX state is only read from the database once per beacon block
+// This function is safe for concurrent access.
+func (tp *TxPool) getPDETokenPrices(beaconView *blockchain.BeaconBestState) (map[common.Hash]float64, error) {
+	tp.pdePricesMtx.Lock()
+	defer tp.pdePricesMtx.Unlock()
+	if tp.pdePrices != nil && tp.pdePricesHash == beaconView.BestBlockHash {
+		return tp.pdePrices, nil
+	}
+	pdeState, err := blockchain.InitCurrentPDEStateFromDB(beaconView.GetBeaconFeatureStateDB(), beaconView.BeaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	tp.pdePrices = newPDETokenPrices(pdeState, beaconView.BeaconHeight)
+	tp.pdePricesHash = beaconView.BestBlockHash
+	return tp.pdePrices, nil
+}
+
+// newPDETokenPrices returns the price in PRV of the tokens of the pool pairs
+// with PRV of the pDEX, the pairs under the minimum PRV liquidity are ignored
+// like in metadata.ConvertPrivacyTokenToNativeToken
+func newPDETokenPrices(pdeState *blockchain.CurrentPDEState, beaconHeight uint64) map[common.Hash]float64 {
+	prvIDStr := common.PRVCoinID.String()
+	isMinLiquidity := beaconHeight >= common.BeaconBlockHeighMilestoneForMinTxFeesOnTokenRequirement
+	prices := make(map[common.Hash]float64)
+	for _, poolPair := range pdeState.PDEPoolPairs {
+		tokenIDStr, tokenPool, prvPool := poolPair.Token2IDStr, poolPair.Token2PoolValue, poolPair.Token1PoolValue
+		if poolPair.Token2IDStr == prvIDStr {
+			tokenIDStr, tokenPool, prvPool = poolPair.Token1IDStr, poolPair.Token1PoolValue, poolPair.Token2PoolValue
+		} else if poolPair.Token1IDStr != prvIDStr {
+			continue
+		}
+		if tokenPool == 0 || prvPool == 0 || (isMinLiquidity && prvPool < uint64(common.MinTxFeesOnTokenRequirement)) {
+			continue
+		}
+		tokenID, err := common.Hash{}.NewHashFromStr(tokenIDStr)
+		if err != nil {
+			continue
+		}
+		prices[*tokenID] = float64(prvPool) / float64(tokenPool)
+	}
+	return prices
+}
+
+// feeRatesInPRV returns the fee rates in PRV of the observed txs confirmed
+// within numBlocks, sorted in ascending order. The fee a tx paid in a token is
+// added with the price of the token, the txs paying in a token without price
+// are ignored. It returns nil until enough blocks are registered.
+func (ef *FeeEstimator) feeRatesInPRV(numBlocks uint64, prices map[common.Hash]float64) []CoinPerKilobyte {
+	ef.mtx.RLock()
+	defer ef.mtx.RUnlock()
+
+	if ef.numBlocksRegistered < ef.minRegisteredBlocks {
+		return nil
+	}
+	feeRates := []CoinPerKilobyte{}
+	for i := uint64(0); i < numBlocks && i < estimateFeeDepth; i++ {
+	observed:
+		for _, o := range ef.bin[i] {
+			feeRate := float64(o.feeRate)
+			for tokenID, tokenFeeRate := range o.feeRateForToken {
+				price, ok := prices[tokenID]
+				if !ok {
+					continue observed
+				}
+				feeRate += float64(tokenFeeRate) * price
+			}
+			feeRates = append(feeRates, CoinPerKilobyte(feeRate))
+		}
+	}
+	sort.Slice(feeRates, func(i, j int) bool {
+		return feeRates[i] < feeRates[j]
+	})
+	return feeRates
+}
+
+// percentileFeeRate returns the nearest-rank percentile of the fee rates sorted
+// in ascending order, 0 if there is none
+func percentileFeeRate(feeRates []CoinPerKilobyte, percentile int) CoinPerKilobyte {
+	if len(feeRates) == 0 {
+		return 0
+	}
+	rank := (len(feeRates)*percentile + 99) / 100
+	if rank == 0 {
+		rank = 1
+	}
+	return feeRates[rank-1]
+}
+
+// backlogFeeRate returns the fee rate in PRV for a tx to be included in the
+// next numBlocks blocks ahead of the backlog, the txs waiting in pool sorted
+// by ascending fee rate: just above the rate of the first tx which does not
+// fit in the blocks by descending fee rate, 0 if they all fit
+func backlogFeeRate(backlog []*TxDesc, numBlocks uint64) CoinPerKilobyte {
+	capacity := common.MaxBlockSize * numBlocks
+	size := uint64(0)
+	for i := len(backlog) - 1; i >= 0; i-- {
+		size += backlog[i].Desc.Tx.GetTxActualSize()
+		if size > capacity {
+			return CoinPerKilobyte(backlog[i].FeePerKB + 1)
+		}
+	}
+	return 0
+}
+
+func maxFeeRate(feeRates ...CoinPerKilobyte) CoinPerKilobyte {
+	max := CoinPerKilobyte(0)
+	for _, feeRate := range feeRates {
+		if feeRate > max {
+			max = feeRate
+		}
+	}
+	return max
+}
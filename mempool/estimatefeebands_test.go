@@ -0,0 +1,132 @@
+package mempool
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/rawdbv2"
+)
+
+func Test_percentileFeeRate(t *testing.T) {
+	feeRates := []CoinPerKilobyte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		name       string
+		feeRates   []CoinPerKilobyte
+		percentile int
+		want       CoinPerKilobyte
+	}{
+		{name: "no fee rate", feeRates: nil, percentile: 50, want: 0},
+		{name: "single fee rate, lowest percentile", feeRates: []CoinPerKilobyte{7}, percentile: 0, want: 7},
+		{name: "single fee rate", feeRates: []CoinPerKilobyte{7}, percentile: 90, want: 7},
+		{name: "zero percentile is the lowest", feeRates: feeRates, percentile: 0, want: 1},
+		{name: "slow", feeRates: feeRates, percentile: feeBandSlowPercentile, want: 3},
+		{name: "normal", feeRates: feeRates, percentile: feeBandNormalPercentile, want: 5},
+		{name: "fast", feeRates: feeRates, percentile: feeBandFastPercentile, want: 9},
+		{name: "hundredth percentile is the highest", feeRates: feeRates, percentile: 100, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentileFeeRate(tt.feeRates, tt.percentile); got != tt.want {
+				t.Errorf("percentileFeeRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_backlogFeeRate(t *testing.T) {
+	// backlogs sorted by ascending fee rate, the sizes are in kilobytes
+	newBacklog := func(txs ...testPoolTx) []*TxDesc {
+		backlog := []*TxDesc{}
+		for _, tx := range txs {
+			backlog = append(backlog, newTestTxDesc(tx, time.Now()))
+		}
+		return backlog
+	}
+	half := common.MaxBlockSize / 2
+	tests := []struct {
+		name      string
+		backlog   []*TxDesc
+		numBlocks uint64
+		want      CoinPerKilobyte
+	}{
+		{name: "empty backlog", backlog: nil, numBlocks: 1, want: 0},
+		{
+			name:      "single tx fitting in a block",
+			backlog:   newBacklog(testPoolTx{name: "a", size: common.MaxBlockSize, feePerKB: 10}),
+			numBlocks: 1,
+			want:      0,
+		},
+		{
+			name:      "single tx larger than a block",
+			backlog:   newBacklog(testPoolTx{name: "a", size: common.MaxBlockSize + 1, feePerKB: 10}),
+			numBlocks: 1,
+			want:      11,
+		},
+		{
+			name: "above the highest fee rate not fitting",
+			backlog: newBacklog(
+				testPoolTx{name: "a", size: half, feePerKB: 5},
+				testPoolTx{name: "b", size: half, feePerKB: 10},
+				testPoolTx{name: "c", size: half, feePerKB: 20},
+			),
+			numBlocks: 1,
+			want:      6,
+		},
+		{
+			name: "all fit in the blocks",
+			backlog: newBacklog(
+				testPoolTx{name: "a", size: half, feePerKB: 5},
+				testPoolTx{name: "b", size: half, feePerKB: 10},
+				testPoolTx{name: "c", size: half, feePerKB: 20},
+			),
+			numBlocks: 2,
+			want:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backlogFeeRate(tt.backlog, tt.numBlocks); got != tt.want {
+				t.Errorf("backlogFeeRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newPDETokenPrices(t *testing.T) {
+	prvIDStr := common.PRVCoinID.String()
+	token1, token2, token3 := common.HashH([]byte("token1")), common.HashH([]byte("token2")), common.HashH([]byte("token3"))
+	pdeState := &blockchain.CurrentPDEState{
+		PDEPoolPairs: map[string]*rawdbv2.PDEPoolForPair{
+			"prv-token1":    {Token1IDStr: prvIDStr, Token1PoolValue: 2 * common.MinTxFeesOnTokenRequirement, Token2IDStr: token1.String(), Token2PoolValue: 4 * common.MinTxFeesOnTokenRequirement},
+			"token2-prv":    {Token1IDStr: token2.String(), Token1PoolValue: common.MinTxFeesOnTokenRequirement, Token2IDStr: prvIDStr, Token2PoolValue: 3 * common.MinTxFeesOnTokenRequirement},
+			"token1-token2": {Token1IDStr: token1.String(), Token1PoolValue: 1, Token2IDStr: token2.String(), Token2PoolValue: 1},
+			"prv-token3":    {Token1IDStr: prvIDStr, Token1PoolValue: common.MinTxFeesOnTokenRequirement - 1, Token2IDStr: token3.String(), Token2PoolValue: 1},
+		},
+	}
+	tests := []struct {
+		name         string
+		beaconHeight uint64
+		want         map[common.Hash]float64
+	}{
+		{
+			name:         "before the minimum liquidity",
+			beaconHeight: common.BeaconBlockHeighMilestoneForMinTxFeesOnTokenRequirement - 1,
+			want:         map[common.Hash]float64{token1: 0.5, token2: 3, token3: float64(common.MinTxFeesOnTokenRequirement - 1)},
+		},
+		{
+			name:         "pairs under the minimum liquidity are ignored",
+			beaconHeight: common.BeaconBlockHeighMilestoneForMinTxFeesOnTokenRequirement,
+			want:         map[common.Hash]float64{token1: 0.5, token2: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newPDETokenPrices(pdeState, tt.beaconHeight); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newPDETokenPrices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package mempool
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// reasons a transaction leaves the pool before being included in a block, or
+// is not let in
+const (
+	EvictPoolFull   = "poolfull"
+	EvictShardCount = "shardcount"
+	EvictShardSize  = "shardsize"
+	EvictExpired    = "expired"
+	RejectSender    = "senderlimit"
+	// a held tx spending outputs of a tx which leaves the pool without being in a block
+	EvictParentRemoved = "parentremoved"
+)
+
+const maxRecentEvictions = 100
+
+// Eviction is a transaction removed from the pool to make room for a
+// transaction paying a higher fee per KB, because it expired, or because it
+// spent outputs of a transaction removed from the pool
+type Eviction struct {
+	TxHash    common.Hash
+	ShardID   byte
+	FeePerKB  uint64
+	Reason    string
+	EvictedBy *common.Hash // transaction which took its place, nil if expired or its parent was removed
+	Time      int64
+}
+
+// EvictionMetrics reports the evictions of the pool, by reason, the new
+// transactions rejected by the caps of the pool, by reason, and the last
+// evictions, the most recent first
+type EvictionMetrics struct {
+	Evicted  map[string]uint64
+	Rejected map[string]uint64
+	Recent   []Eviction
+}
+
+// poolUsage is the number and the size in KB of the transactions of a shard
+type poolUsage struct {
+	count uint64
+	size  uint64
+}
+
+func getSenderKey(tx metadata.Transaction) string {
+	return string(tx.GetSigPubKey())
+}
+
+// countSenderTx counts tx, of pool or held, in the txs of its sender
+func (tp *TxPool) countSenderTx(tx metadata.Transaction) {
+	tp.senderTxCount[getSenderKey(tx)]++
+}
+
+// uncountSenderTx reverts countSenderTx
+func (tp *TxPool) uncountSenderTx(tx metadata.Transaction) {
+	senderKey := getSenderKey(tx)
+	if tp.senderTxCount[senderKey] <= 1 {
+		delete(tp.senderTxCount, senderKey)
+	} else {
+		tp.senderTxCount[senderKey]--
+	}
+}
+
+// checkSenderLimit rejects tx if its sender already has MaxSenderTx txs in pool
+// or held
+func (tp *TxPool) checkSenderLimit(tx metadata.Transaction) error {
+	if tp.config.MaxSenderTx > 0 && tp.senderTxCount[getSenderKey(tx)] >= tp.config.MaxSenderTx {
+		return NewMempoolTxError(RejectSenderLimitError, fmt.Errorf("sender of tx %+v already has %+v txs in pool", tx.Hash().String(), tp.config.MaxSenderTx))
+	}
+	return nil
+}
+
+// trackTx counts txD in the usage of its shard and of its sender, and indexes
+// it in the eviction queues and in the order of the tx selection policy
+func (tp *TxPool) trackTx(txD *TxDesc) {
+	tx := txD.Desc.Tx
+	shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	usage, ok := tp.shardUsage[shardID]
+	if !ok {
+		usage = &poolUsage{}
+		tp.shardUsage[shardID] = usage
+	}
+	usage.count++
+	usage.size += tx.GetTxActualSize()
+	tp.countSenderTx(tx)
+	tp.evictionQueue.add(txD)
+	shardQueue, ok := tp.shardEvictionQueues[shardID]
+	if !ok {
+		shardQueue = newEvictionQueue()
+		tp.shardEvictionQueues[shardID] = shardQueue
+	}
+	shardQueue.add(txD)
+	tp.pendingTxs.add(&blockchain.PendingTx{
+		Tx:          tx,
+		ShardID:     shardID,
+		FeePerKB:    txD.FeePerKB,
+		ArrivalTime: txD.StartTime,
+	})
+}
+
+// untrackTx reverts trackTx
+func (tp *TxPool) untrackTx(txD *TxDesc) {
+	tx := txD.Desc.Tx
+	shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	if usage, ok := tp.shardUsage[shardID]; ok {
+		usage.count--
+		usage.size -= tx.GetTxActualSize()
+		if usage.count == 0 {
+			delete(tp.shardUsage, shardID)
+		}
+	}
+	tp.uncountSenderTx(tx)
+	tp.evictionQueue.remove(*tx.Hash())
+	if shardQueue, ok := tp.shardEvictionQueues[shardID]; ok {
+		shardQueue.remove(*tx.Hash())
+		if shardQueue.Len() == 0 {
+			delete(tp.shardEvictionQueues, shardID)
+		}
+	}
+	tp.pendingTxs.remove(*tx.Hash())
+}
+
+type evictionCandidate struct {
+	txDesc *TxDesc
+	reason string
+}
+
+// evictionEntry is a transaction of an eviction queue, index is its position in
+// the heap
+type evictionEntry struct {
+	txDesc *TxDesc
+	index  int
+}
+
+// evictionQueue is a heap of transactions of the pool, the next to evict first:
+// the lowest fee per KB, the newest at equal fee
+type evictionQueue struct {
+	entries []*evictionEntry
+	byHash  map[common.Hash]*evictionEntry
+}
+
+func newEvictionQueue() *evictionQueue {
+	return &evictionQueue{byHash: make(map[common.Hash]*evictionEntry)}
+}
+
+func (queue *evictionQueue) Len() int {
+	return len(queue.entries)
+}
+
+func (queue *evictionQueue) Less(i, j int) bool {
+	a, b := queue.entries[i].txDesc, queue.entries[j].txDesc
+	if a.FeePerKB != b.FeePerKB {
+		return a.FeePerKB < b.FeePerKB
+	}
+	return a.StartTime.After(b.StartTime)
+}
+
+func (queue *evictionQueue) Swap(i, j int) {
+	queue.entries[i], queue.entries[j] = queue.entries[j], queue.entries[i]
+	queue.entries[i].index = i
+	queue.entries[j].index = j
+}
+
+func (queue *evictionQueue) Push(x interface{}) {
+	entry := x.(*evictionEntry)
+	entry.index = len(queue.entries)
+	queue.entries = append(queue.entries, entry)
+}
+
+func (queue *evictionQueue) Pop() interface{} {
+	n := len(queue.entries)
+	entry := queue.entries[n-1]
+	queue.entries[n-1] = nil
+	queue.entries = queue.entries[:n-1]
+	return entry
+}
+
+func (queue *evictionQueue) add(txDesc *TxDesc) {
+	hash := *txDesc.Desc.Tx.Hash()
+	if _, ok := queue.byHash[hash]; ok {
+		return
+	}
+	entry := &evictionEntry{txDesc: txDesc}
+	heap.Push(queue, entry)
+	queue.byHash[hash] = entry
+}
+
+func (queue *evictionQueue) remove(hash common.Hash) {
+	entry, ok := queue.byHash[hash]
+	if !ok {
+		return
+	}
+	heap.Remove(queue, entry.index)
+	delete(queue.byHash, hash)
+}
+
+// pop removes and returns the next transaction to evict, nil if there is none
+func (queue *evictionQueue) pop() *TxDesc {
+	if queue == nil || len(queue.entries) == 0 {
+		return nil
+	}
+	entry := heap.Pop(queue).(*evictionEntry)
+	delete(queue.byHash, *entry.txDesc.Desc.Tx.Hash())
+	return entry.txDesc
+}
+
+// makeRoomForTx checks txD against the caps of the pool: the number of
+// transactions of the pool, the number and the size of the transactions of its
+// shard, the number of transactions of its sender. It returns the transactions
+// to evict for txD to fit, paying the lowest fee per KB and less than txD, and
+// leaves the pool untouched: they are evicted by evictTxs once txD is added.
+// This function MUST be called with the pool locked
+func (tp *TxPool) makeRoomForTx(txD *TxDesc) ([]evictionCandidate, error) {
+	candidates, reason, err := tp.pickEvictions(txD)
+	if err != nil {
+		tp.countRejection(reason)
+	}
+	return candidates, err
+}
+
+// pickEvictions is makeRoomForTx without counting the rejections, it returns
+// the reason of the rejection of txD if it does not fit
+// This function MUST be called with the pool locked
+func (tp *TxPool) pickEvictions(txD *TxDesc) ([]evictionCandidate, string, error) {
+	tx := txD.Desc.Tx
+	shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	if err := tp.checkSenderLimit(tx); err != nil {
+		return nil, RejectSender, err
+	}
+	size := tx.GetTxActualSize()
+	shardCount, shardSize := uint64(0), uint64(0)
+	if usage, ok := tp.shardUsage[shardID]; ok {
+		shardCount, shardSize = usage.count, usage.size
+	}
+	poolCount := uint64(len(tp.pool))
+
+	// the candidates are popped from the eviction queues, which are restored
+	// once they are picked
+	type poppedTx struct {
+		queue  *evictionQueue
+		txDesc *TxDesc
+	}
+	popped := []poppedTx{}
+	defer func() {
+		for _, p := range popped {
+			p.queue.add(p.txDesc)
+		}
+	}()
+	picked := make(map[common.Hash]struct{})
+	nextLowest := func(queue *evictionQueue) *TxDesc {
+		for {
+			txDesc := queue.pop()
+			if txDesc == nil {
+				return nil
+			}
+			popped = append(popped, poppedTx{queue: queue, txDesc: txDesc})
+			if _, ok := picked[*txDesc.Desc.Tx.Hash()]; !ok {
+				return txDesc
+			}
+		}
+	}
+	candidates := []evictionCandidate{}
+	for {
+		reason := ""
+		switch {
+		case tp.config.MaxShardTx > 0 && shardCount+1 > tp.config.MaxShardTx:
+			reason = EvictShardCount
+		case tp.config.MaxShardSize > 0 && shardSize+size > tp.config.MaxShardSize:
+			reason = EvictShardSize
+		case poolCount+1 > tp.config.MaxTx:
+			reason = EvictPoolFull
+		}
+		if reason == "" {
+			break
+		}
+		var lowest *TxDesc
+		if reason == EvictPoolFull {
+			lowest = nextLowest(tp.evictionQueue)
+		} else {
+			lowest = nextLowest(tp.shardEvictionQueues[shardID])
+		}
+		if lowest == nil || lowest.FeePerKB >= txD.FeePerKB {
+			return nil, reason, NewMempoolTxError(MaxPoolSizeError, fmt.Errorf("pool is full (%+v) and tx %+v does not pay more than the txs in pool", reason, tx.Hash().String()))
+		}
+		picked[*lowest.Desc.Tx.Hash()] = struct{}{}
+		candidates = append(candidates, evictionCandidate{txDesc: lowest, reason: reason})
+		poolCount--
+		if common.GetShardIDFromLastByte(lowest.Desc.Tx.GetSenderAddrLastByte()) == shardID {
+			shardCount--
+			shardSize -= lowest.Desc.Tx.GetTxActualSize()
+		}
+	}
+	return candidates, "", nil
+}
+
+// evictTxs evicts the candidates returned by makeRoomForTx for the tx of
+// evictedBy, once it is in the pool
+// This function MUST be called with the pool locked
+func (tp *TxPool) evictTxs(candidates []evictionCandidate, evictedBy *common.Hash) {
+	for _, candidate := range candidates {
+		tp.evictTx(candidate.txDesc, candidate.reason, evictedBy)
+	}
+}
+
+// sortByEvictionOrder returns the transactions of the pool, of a shard only if
+// byShard, the lowest fee per KB first, the newest first at equal fee. It sorts
+// the pool, the eviction itself pops the eviction queues
+func (tp *TxPool) sortByEvictionOrder(byShard bool, shardID byte) []*TxDesc {
+	txDescs := []*TxDesc{}
+	for _, txDesc := range tp.pool {
+		if byShard && common.GetShardIDFromLastByte(txDesc.Desc.Tx.GetSenderAddrLastByte()) != shardID {
+			continue
+		}
+		txDescs = append(txDescs, txDesc)
+	}
+	sort.Slice(txDescs, func(i, j int) bool {
+		if txDescs[i].FeePerKB != txDescs[j].FeePerKB {
+			return txDescs[i].FeePerKB < txDescs[j].FeePerKB
+		}
+		return txDescs[i].StartTime.After(txDescs[j].StartTime)
+	})
+	return txDescs
+}
+
+// evictTx removes txDesc from the pool and records the eviction
+// This function MUST be called with the pool locked
+func (tp *TxPool) evictTx(txDesc *TxDesc, reason string, evictedBy *common.Hash) {
+	tx := txDesc.Desc.Tx
+	txHash := *tx.Hash()
+	Logger.log.Infof("Evict tx %+v from pool, reason %+v", txHash.String(), reason)
+	tp.removeTx(tx)
+	tp.TriggerCRemoveTxs(tx)
+	tp.removeCandidateByTxHash(txHash)
+	if tp.config.PersistMempool {
+		if err := tp.removeTransactionFromDatabaseMP(&txHash); err != nil {
+			Logger.log.Errorf("Remove evicted tx %+v from mempool database error %+v", txHash.String(), err)
+		}
+	}
+	tp.recordEviction(Eviction{
+		TxHash:    txHash,
+		ShardID:   common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()),
+		FeePerKB:  txDesc.FeePerKB,
+		Reason:    reason,
+		EvictedBy: evictedBy,
+		Time:      time.Now().Unix(),
+	})
+}
+
+func (tp *TxPool) recordEviction(eviction Eviction) {
+	tp.evictionMtx.Lock()
+	defer tp.evictionMtx.Unlock()
+	tp.evicted[eviction.Reason]++
+	if len(tp.recentEvictions) >= maxRecentEvictions {
+		copy(tp.recentEvictions, tp.recentEvictions[1:])
+		tp.recentEvictions = tp.recentEvictions[:maxRecentEvictions-1]
+	}
+	tp.recentEvictions = append(tp.recentEvictions, eviction)
+}
+
+func (tp *TxPool) countRejection(reason string) {
+	tp.evictionMtx.Lock()
+	defer tp.evictionMtx.Unlock()
+	tp.rejected[reason]++
+}
+
+// GetEvictionMetrics returns the evictions and the rejections of the pool
+func (tp *TxPool) GetEvictionMetrics() EvictionMetrics {
+	tp.evictionMtx.RLock()
+	defer tp.evictionMtx.RUnlock()
+	metrics := EvictionMetrics{
+		Evicted:  make(map[string]uint64),
+		Rejected: make(map[string]uint64),
+		Recent:   make([]Eviction, 0, len(tp.recentEvictions)),
+	}
+	for reason, count := range tp.evicted {
+		metrics.Evicted[reason] = count
+	}
+	for reason, count := range tp.rejected {
+		metrics.Rejected[reason] = count
+	}
+	for i := len(tp.recentEvictions) - 1; i >= 0; i-- {
+		metrics.Recent = append(metrics.Recent, tp.recentEvictions[i])
+	}
+	return metrics
+}
@@ -0,0 +1,185 @@
+package mempool
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/metadata/mocks"
+)
+
+type testPoolTx struct {
+	name     string
+	shardID  byte
+	sender   string
+	size     uint64
+	feePerKB uint64
+	arrival  int // seconds after the first tx
+}
+
+func newTestTxDesc(tx testPoolTx, start time.Time) *TxDesc {
+	hash := common.HashH([]byte(tx.name))
+	mockTx := &mocks.Transaction{}
+	mockTx.On("Hash").Return(&hash)
+	mockTx.On("GetSenderAddrLastByte").Return(tx.shardID)
+	mockTx.On("GetTxActualSize").Return(tx.size)
+	mockTx.On("GetSigPubKey").Return([]byte(tx.sender))
+	return &TxDesc{
+		Desc:      metadata.TxDesc{Tx: mockTx},
+		StartTime: start.Add(time.Duration(tx.arrival) * time.Second),
+		FeePerKB:  tx.feePerKB,
+	}
+}
+
+func newTestEvictionPool(config Config) *TxPool {
+	return &TxPool{
+		config:              config,
+		pool:                make(map[common.Hash]*TxDesc),
+		shardUsage:          make(map[byte]*poolUsage),
+		evictionQueue:       newEvictionQueue(),
+		shardEvictionQueues: make(map[byte]*evictionQueue),
+		senderTxCount:       make(map[string]uint64),
+		evicted:             make(map[string]uint64),
+		rejected:            make(map[string]uint64),
+		pendingTxs:          newPendingTxIndex(nil),
+
+		poolSNDOutputs:         make(map[common.Hash]common.Hash),
+		dependents:             make(map[common.Hash]*dependentTx),
+		dependentSerialNumbers: make(map[common.Hash]common.Hash),
+		children:               make(map[common.Hash]map[common.Hash]struct{}),
+	}
+}
+
+func TestTxPool_makeRoomForTx(t *testing.T) {
+	// shards are the last bytes of the senders, 1 and 2 are distinct shards
+	txs := []testPoolTx{
+		{name: "a", shardID: 1, sender: "s1", size: 1, feePerKB: 10, arrival: 1},
+		{name: "b", shardID: 1, sender: "s2", size: 1, feePerKB: 10, arrival: 2},
+		{name: "c", shardID: 1, sender: "s3", size: 1, feePerKB: 30, arrival: 3},
+		{name: "d", shardID: 2, sender: "s4", size: 1, feePerKB: 5, arrival: 4},
+	}
+	tests := []struct {
+		name       string
+		config     Config
+		tx         testPoolTx
+		wantEvicts []string
+		wantReason string
+		wantErr    int
+	}{
+		{
+			name:   "room left",
+			config: Config{MaxTx: 10},
+			tx:     testPoolTx{name: "new", shardID: 1, sender: "s5", size: 1, feePerKB: 1},
+		},
+		{
+			name:       "pool full evicts the lowest fee of the pool",
+			config:     Config{MaxTx: 4},
+			tx:         testPoolTx{name: "new", shardID: 1, sender: "s5", size: 1, feePerKB: 20},
+			wantEvicts: []string{"d"},
+			wantReason: EvictPoolFull,
+		},
+		{
+			name:       "shard full evicts the newest of the lowest fee of the shard",
+			config:     Config{MaxTx: 10, MaxShardTx: 3},
+			tx:         testPoolTx{name: "new", shardID: 1, sender: "s5", size: 1, feePerKB: 20},
+			wantEvicts: []string{"b"},
+			wantReason: EvictShardCount,
+		},
+		{
+			name:       "shard size evicts until the tx fits",
+			config:     Config{MaxTx: 10, MaxShardSize: 4},
+			tx:         testPoolTx{name: "new", shardID: 1, sender: "s5", size: 3, feePerKB: 20},
+			wantEvicts: []string{"b", "a"},
+			wantReason: EvictShardSize,
+		},
+		{
+			name:    "pool full of higher fees",
+			config:  Config{MaxTx: 4},
+			tx:      testPoolTx{name: "new", shardID: 2, sender: "s5", size: 1, feePerKB: 5},
+			wantErr: MaxPoolSizeError,
+		},
+		{
+			name:    "shard full of higher fees",
+			config:  Config{MaxTx: 10, MaxShardTx: 3},
+			tx:      testPoolTx{name: "new", shardID: 1, sender: "s5", size: 1, feePerKB: 10},
+			wantErr: MaxPoolSizeError,
+		},
+		{
+			name:    "too many txs of the sender",
+			config:  Config{MaxTx: 10, MaxSenderTx: 1},
+			tx:      testPoolTx{name: "new", shardID: 1, sender: "s1", size: 1, feePerKB: 100},
+			wantErr: RejectSenderLimitError,
+		},
+	}
+	start := time.Now()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := newTestEvictionPool(tt.config)
+			names := map[common.Hash]string{}
+			for _, tx := range txs {
+				txD := newTestTxDesc(tx, start)
+				names[*txD.Desc.Tx.Hash()] = tx.name
+				tp.pool[*txD.Desc.Tx.Hash()] = txD
+				tp.trackTx(txD)
+			}
+			newTxD := newTestTxDesc(tt.tx, start.Add(time.Minute))
+			candidates, err := tp.makeRoomForTx(newTxD)
+			if tt.wantErr != 0 {
+				mempoolErr, ok := err.(*MempoolTxError)
+				if !ok || mempoolErr.Code != ErrCodeMessage[tt.wantErr].Code {
+					t.Fatalf("makeRoomForTx() error = %v, want code %v", err, ErrCodeMessage[tt.wantErr].Code)
+				}
+			} else if err != nil {
+				t.Fatalf("makeRoomForTx() error = %v", err)
+			}
+			evicts := []string{}
+			for _, candidate := range candidates {
+				evicts = append(evicts, names[*candidate.txDesc.Desc.Tx.Hash()])
+				if candidate.reason != tt.wantReason {
+					t.Errorf("tx %v evicted for %v, want %v", names[*candidate.txDesc.Desc.Tx.Hash()], candidate.reason, tt.wantReason)
+				}
+			}
+			if len(tt.wantEvicts) == 0 {
+				tt.wantEvicts = []string{}
+			}
+			if !reflect.DeepEqual(evicts, tt.wantEvicts) {
+				t.Errorf("makeRoomForTx() evicts %v, want %v", evicts, tt.wantEvicts)
+			}
+			// nothing is evicted until the tx is added
+			if len(tp.pool) != len(txs) || tp.evictionQueue.Len() != len(txs) || tp.shardEvictionQueues[1].Len()+tp.shardEvictionQueues[2].Len() != len(txs) {
+				t.Errorf("makeRoomForTx() changes the pool: %v txs, %v queued", len(tp.pool), tp.evictionQueue.Len())
+			}
+		})
+	}
+}
+
+func TestEvictionQueue(t *testing.T) {
+	txs := []testPoolTx{
+		{name: "a", feePerKB: 10, arrival: 1},
+		{name: "b", feePerKB: 5, arrival: 2},
+		{name: "c", feePerKB: 10, arrival: 3},
+		{name: "d", feePerKB: 20, arrival: 4},
+		{name: "e", feePerKB: 1, arrival: 5},
+	}
+	start := time.Now()
+	queue := newEvictionQueue()
+	names := map[common.Hash]string{}
+	for _, tx := range txs {
+		txD := newTestTxDesc(tx, start)
+		names[*txD.Desc.Tx.Hash()] = tx.name
+		queue.add(txD)
+		queue.add(txD)
+	}
+	queue.remove(common.HashH([]byte("e")))
+	queue.remove(common.HashH([]byte("unknown")))
+	got := []string{}
+	for txD := queue.pop(); txD != nil; txD = queue.pop() {
+		got = append(got, names[*txD.Desc.Tx.Hash()])
+	}
+	want := []string{"b", "c", "a", "d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("eviction order %v, want %v", got, want)
+	}
+}
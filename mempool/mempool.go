@@ -43,9 +43,16 @@ type Config struct {
 	FeeEstimator      map[byte]*FeeEstimator // FeeEstimatator provides a feeEstimator. If it is not nil, the mempool records all new transactions it observes into the feeEstimator.
 	TxLifeTime        uint                   // Transaction life time in pool
 	MaxTx             uint64                 //Max transaction pool may have
+	MaxShardTx        uint64                 // Max transactions of a shard pool may have, 0 for no limit
+	MaxShardSize      uint64                 // Max size in KB of the transactions of a shard pool may have, 0 for no limit
+	MaxSenderTx       uint64                 // Max transactions of a sender pool may have, 0 for no limit
 	IsLoadFromMempool bool                   //Reset mempool database when run node
 	PersistMempool    bool
 	RelayShards       []byte
+	TxVerifyWorkers   int // Workers verifying the proofs of new transactions, 0 for the number of CPUs
+	TxVerifyBatchSize int // Max transactions of which the proofs are verified in a batch, 1 if not positive
+	// Order of the pending txs picked for a block, fee priority if nil
+	TxSelectionPolicy blockchain.TxSelectionPolicy
 	// UserKeyset            *incognitokey.KeySet
 	PubSubManager interface {
 		PublishMessage(message *pubsub.Message)
@@ -58,6 +65,8 @@ type TxDesc struct {
 	Desc            metadata.TxDesc // transaction details
 	StartTime       time.Time       //Unix Time that transaction enter mempool
 	IsFowardMessage bool
+	IsPrivate       bool   // relayed to the nodes of the committee of its shard only, never published to the network
+	FeePerKB        uint64 // fee per KB in PRV, the lowest are evicted first when pool is full
 }
 
 type TxPool struct {
@@ -81,6 +90,34 @@ type TxPool struct {
 	IsUnlockMempool   bool
 	ReplaceFeeRatio   float64
 
+	// eviction
+	shardUsage          map[byte]*poolUsage
+	evictionQueue       *evictionQueue
+	shardEvictionQueues map[byte]*evictionQueue
+	senderTxCount       map[string]uint64
+	evicted             map[string]uint64 // [reason] -> number of evicted txs
+	rejected            map[string]uint64 // [reason] -> number of rejected txs
+	recentEvictions     []Eviction
+	evictionMtx         sync.RWMutex
+
+	// dependency graph of the txs spending outputs of unconfirmed txs
+	poolSNDOutputs         map[common.Hash]common.Hash              // [hash of snd of output] -> tx of pool or held
+	dependents             map[common.Hash]*dependentTx             // txs held until their parents are in a block
+	dependentSerialNumbers map[common.Hash]common.Hash              // [hash of serial number] -> held tx
+	children               map[common.Hash]map[common.Hash]struct{} // [parent] -> held txs spending its outputs
+
+	// verification pipeline of new transactions
+	verifier    *txVerifier
+	verifierMtx sync.RWMutex
+
+	// txs of pool in the order of the tx selection policy
+	pendingTxs *pendingTxIndex
+
+	// prices of the pDEX tokens at the final beacon block of pdePricesHash
+	pdePrices     map[common.Hash]float64
+	pdePricesHash common.Hash
+	pdePricesMtx  sync.Mutex
+
 	//for testing
 	IsTest       bool
 	duplicateTxs map[common.Hash]uint64 //For testing
@@ -97,6 +134,17 @@ func (tp *TxPool) Init(cfg *Config) {
 	tp.poolCandidate = make(map[common.Hash]string)
 	tp.poolRequestStopStaking = make(map[common.Hash]string)
 	tp.duplicateTxs = make(map[common.Hash]uint64)
+	tp.shardUsage = make(map[byte]*poolUsage)
+	tp.evictionQueue = newEvictionQueue()
+	tp.shardEvictionQueues = make(map[byte]*evictionQueue)
+	tp.senderTxCount = make(map[string]uint64)
+	tp.evicted = make(map[string]uint64)
+	tp.rejected = make(map[string]uint64)
+	tp.poolSNDOutputs = make(map[common.Hash]common.Hash)
+	tp.dependents = make(map[common.Hash]*dependentTx)
+	tp.dependentSerialNumbers = make(map[common.Hash]common.Hash)
+	tp.children = make(map[common.Hash]map[common.Hash]struct{})
+	tp.pendingTxs = newPendingTxIndex(cfg.TxSelectionPolicy)
 	// _, subChanRole, _ := tp.config.PubSubManager.RegisterNewSubscriber(pubsub.ShardRoleTopic)
 	// tp.config.RoleInCommitteesEvent = subChanRole
 	tp.ScanTime = defaultScanTime
@@ -190,11 +238,20 @@ func (tp *TxPool) MonitorPool() {
 			tp.TriggerCRemoveTxs(txDesc.Desc.Tx)
 			tp.removeCandidateByTxHash(txHash)
 			//tp.removeRequestStopStakingByTxHash(txHash)
-			err := tp.config.DataBaseMempool.RemoveTransaction(txDesc.Desc.Tx.Hash())
-			if err != nil {
-				Logger.log.Errorf("MonitorPool: RemoveTransaction tx hash=%+v with error %+v", txDesc.Desc.Tx.Hash().String(), err)
-				Logger.log.Error(err)
+			if tp.config.PersistMempool {
+				err := tp.removeTransactionFromDatabaseMP(txDesc.Desc.Tx.Hash())
+				if err != nil {
+					Logger.log.Errorf("MonitorPool: RemoveTransaction tx hash=%+v with error %+v", txDesc.Desc.Tx.Hash().String(), err)
+					Logger.log.Error(err)
+				}
 			}
+			tp.recordEviction(Eviction{
+				TxHash:   txHash,
+				ShardID:  common.GetShardIDFromLastByte(txDesc.Desc.Tx.GetSenderAddrLastByte()),
+				FeePerKB: txDesc.FeePerKB,
+				Reason:   EvictExpired,
+				Time:     time.Now().Unix(),
+			})
 		}
 		tp.mtx.Unlock()
 	}
@@ -214,57 +271,177 @@ func (tp *TxPool) MonitorPool() {
 // #1: tx
 // #2: default nil, contain input coins hash, which are used for creating this tx
 func (tp *TxPool) MaybeAcceptTransaction(tx metadata.Transaction, beaconHeight int64) (*common.Hash, *TxDesc, error) {
-	//beaconView.BeaconHeight
-	tp.mtx.Lock()
-	defer tp.mtx.Unlock()
 	if tp.IsTest {
 		return &common.Hash{}, &TxDesc{}, nil
 	}
 	go func(txHash common.Hash) {
 		tp.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.TransactionHashEnterNodeTopic, txHash))
 	}(*tx.Hash())
+	// the proofs are verified in parallel, out of the lock of pool, once the
+	// verification pipeline is started
+	if verifier := tp.getTxVerifier(); verifier != nil {
+		return verifier.verifyAndAccept(tx, beaconHeight)
+	}
+	//beaconView.BeaconHeight
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+	return tp.maybeAcceptNewTransaction(tx, beaconHeight, false, false)
+}
+
+// MaybeAcceptBatchTransaction accepts new txs submitted together and returns
+// the error of each tx, nil if it is accepted. The txs go through the steps of
+// the verification pipeline: the proofs of the txs of a shard are verified
+// together, out of the lock of pool, then the txs are accepted in order, so
+// that a tx can spend outputs of a tx before it. A rejected tx does not reject
+// the others.
+// This function is safe for concurrent access.
+func (tp *TxPool) MaybeAcceptBatchTransaction(txs []metadata.Transaction, beaconHeight int64) []error {
+	if tp.IsTest {
+		return make([]error, len(txs))
+	}
+	errs := acceptNewTxBatch(txs,
+		func(tx metadata.Transaction) error {
+			return tp.precheckNewTx(tx, beaconHeight)
+		},
+		func(txs []metadata.Transaction) newTxsVerification {
+			beaconHeights := make([]int64, len(txs))
+			for i := range beaconHeights {
+				beaconHeights[i] = beaconHeight
+			}
+			return tp.verifyNewTxs(txs, beaconHeights)
+		},
+		func(tx metadata.Transaction, isVerified bool, isHoldVerified bool) error {
+			tp.mtx.Lock()
+			defer tp.mtx.Unlock()
+			_, _, err := tp.maybeAcceptNewTransaction(tx, beaconHeight, isVerified, isHoldVerified)
+			return err
+		},
+	)
+	go func() {
+		for _, tx := range txs {
+			tp.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.TransactionHashEnterNodeTopic, *tx.Hash()))
+		}
+	}()
+	return errs
+}
+
+// acceptNewTxBatch runs precheck on each tx, verify on the prechecked txs of
+// each shard in parallel, then accept on the verified txs in order, and returns
+// the error of each tx
+func acceptNewTxBatch(
+	txs []metadata.Transaction,
+	precheck func(tx metadata.Transaction) error,
+	verify func(txs []metadata.Transaction) newTxsVerification,
+	accept func(tx metadata.Transaction, isVerified bool, isHoldVerified bool) error,
+) []error {
+	errs := make([]error, len(txs))
+	groups := make(map[byte][]int)
+	for i, tx := range txs {
+		// the proofs are only verified for the txs passing the cheap checks
+		if errs[i] = precheck(tx); errs[i] != nil {
+			continue
+		}
+		shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+		groups[shardID] = append(groups[shardID], i)
+	}
+	isVerified := make([]bool, len(txs))
+	isHoldVerified := make([]bool, len(txs))
+	var wg sync.WaitGroup
+	for _, indexes := range groups {
+		wg.Add(1)
+		go func(indexes []int) {
+			defer wg.Done()
+			groupTxs := make([]metadata.Transaction, len(indexes))
+			for j, i := range indexes {
+				groupTxs[j] = txs[i]
+			}
+			verification := verify(groupTxs)
+			for j, i := range indexes {
+				errs[i] = verification.errs[j]
+				isVerified[i] = verification.isVerified[j]
+				isHoldVerified[i] = verification.isHoldVerified[j]
+			}
+		}(indexes)
+	}
+	wg.Wait()
+
+	// the pool is locked tx by tx, not for the whole batch
+	for i, tx := range txs {
+		if errs[i] != nil {
+			Logger.log.Error(errs[i])
+			continue
+		}
+		errs[i] = accept(tx, isVerified[i], isHoldVerified[i])
+	}
+	return errs
+}
+
+// maybeAcceptNewTransaction accepts a tx received from network or RPC, isVerified
+// if its proofs are already verified, isHoldVerified if they are but the
+// commitments of its unconfirmed inputs
+// This function MUST be called with the pool locked
+func (tp *TxPool) maybeAcceptNewTransaction(tx metadata.Transaction, beaconHeight int64, isVerified bool, isHoldVerified bool) (*common.Hash, *TxDesc, error) {
 	senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
 	if !tp.checkRelayShard(tx) && !tp.checkPublicKeyRole(tx) {
-		err := NewMempoolTxError(UnexpectedTransactionError, errors.New("Unexpected Transaction From Shard "+fmt.Sprintf("%d", senderShardID)))
+		err := NewMempoolTxError(RejectNotRelayedShardTxError, errors.New("Unexpected Transaction From Shard "+fmt.Sprintf("%d", senderShardID)))
 		Logger.log.Error(err)
 		return &common.Hash{}, &TxDesc{}, err
 	}
 	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
 	shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
 	//==========
-	if uint64(len(tp.pool)) >= tp.config.MaxTx {
-		return nil, nil, NewMempoolTxError(MaxPoolSizeError, errors.New("Pool reach max number of transaction"))
-	}
-	if tx.GetType() == common.TxReturnStakingType{
-		return &common.Hash{}, &TxDesc{}, NewMempoolTxError(RejectInvalidTx, fmt.Errorf("%+v is a return staking tx", tx.Hash().String()))
+	if err := checkStandaloneTxType(tx); err != nil {
+		return &common.Hash{}, &TxDesc{}, err
 	}
-	if tx.GetType() == common.TxCustomTokenPrivacyType{
-		tempTx, ok := tx.(*transaction.TxCustomTokenPrivacy)
-		if !ok {
-			return &common.Hash{}, &TxDesc{}, NewMempoolTxError(RejectInvalidTx, fmt.Errorf("cannot detect transaction type for tx %+v", tx.Hash().String()))
-		}
-		if tempTx.TxPrivacyTokenData.Mintable{
-			return &common.Hash{}, &TxDesc{}, NewMempoolTxError(RejectInvalidTx, fmt.Errorf("%+v is a minteable tx", tx.Hash().String()))
+	// a tx spending outputs of unconfirmed txs waits for them to be in a block
+	if parents := tp.getUnconfirmedParents(tx); len(parents) > 0 {
+		hash, txDesc, err := tp.holdTransaction(shardView, beaconView, tx, beaconHeight, parents, isVerified || isHoldVerified)
+		if err != nil {
+			Logger.log.Error(err)
 		}
+		return hash, txDesc, err
 	}
-	hash, txDesc, err := tp.maybeAcceptTransaction(shardView, beaconView, tx, tp.config.PersistMempool, true, beaconHeight)
+	hash, txDesc, err := tp.acceptTransaction(shardView, beaconView, tx, tp.config.PersistMempool, true, isVerified, beaconHeight)
 	//==========
 	if err != nil {
 		Logger.log.Error(err)
 	} else {
-		if tp.IsBlockGenStarted {
-			if tp.IsUnlockMempool {
-				go func(tx metadata.Transaction) {
-					tp.CPendingTxs <- tx
-				}(tx)
-			}
-		}
-		// Publish Message
-		go tp.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.MempoolInfoTopic, tp.listTxs()))
+		tp.notifyAcceptedTx(tx)
 	}
 	return hash, txDesc, err
 }
 
+// notifyAcceptedTx delivers a new tx of pool to block gen and publishes the pool
+// This function MUST be called with the pool locked
+func (tp *TxPool) notifyAcceptedTx(tx metadata.Transaction) {
+	if tp.IsBlockGenStarted {
+		if tp.IsUnlockMempool {
+			go func(tx metadata.Transaction) {
+				tp.CPendingTxs <- tx
+			}(tx)
+		}
+	}
+	// Publish Message
+	tp.config.PubSubManager.PublishMessage(pubsub.NewMessage(pubsub.MempoolInfoTopic, tp.listTxs()))
+}
+
+// checkStandaloneTxType rejects the txs which are only created by block producers
+func checkStandaloneTxType(tx metadata.Transaction) error {
+	if tx.GetType() == common.TxReturnStakingType {
+		return NewMempoolTxError(RejectInvalidTx, fmt.Errorf("%+v is a return staking tx", tx.Hash().String()))
+	}
+	if tx.GetType() == common.TxCustomTokenPrivacyType {
+		tempTx, ok := tx.(*transaction.TxCustomTokenPrivacy)
+		if !ok {
+			return NewMempoolTxError(RejectInvalidTx, fmt.Errorf("cannot detect transaction type for tx %+v", tx.Hash().String()))
+		}
+		if tempTx.TxPrivacyTokenData.Mintable {
+			return NewMempoolTxError(RejectInvalidTx, fmt.Errorf("%+v is a minteable tx", tx.Hash().String()))
+		}
+	}
+	return nil
+}
+
 // This function is safe for concurrent access.
 func (tp *TxPool) MaybeAcceptTransactionForBlockProducing(tx metadata.Transaction, beaconHeight int64, shardView *blockchain.ShardBestState) (*metadata.TxDesc, error) {
 	tp.mtx.Lock()
@@ -344,8 +521,14 @@ func (tp *TxPool) maybeAcceptBatchTransaction(shardView *blockchain.ShardBestSta
 // #3: default nil, contain input coins hash, which are used for creating this tx
 */
 func (tp *TxPool) maybeAcceptTransaction(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, isStore bool, isNewTransaction bool, beaconHeight int64) (*common.Hash, *TxDesc, error) {
+	return tp.acceptTransaction(shardView, beaconView, tx, isStore, isNewTransaction, false, beaconHeight)
+}
+
+// acceptTransaction is maybeAcceptTransaction, without verifying the proofs of
+// tx by itself if isVerified
+func (tp *TxPool) acceptTransaction(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, isStore bool, isNewTransaction bool, isVerified bool, beaconHeight int64) (*common.Hash, *TxDesc, error) {
 	// validate tx
-	err := tp.validateTransaction(shardView, beaconView, tx, beaconHeight, false, isNewTransaction)
+	err := tp.validateTransaction(shardView, beaconView, tx, beaconHeight, isVerified, isNewTransaction)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -354,10 +537,20 @@ func (tp *TxPool) maybeAcceptTransaction(shardView *blockchain.ShardBestState, b
 	txFee := tx.GetTxFee()
 	txFeeToken := tx.GetTxFeeToken()
 	txD := createTxDescMempool(tx, bestHeight, txFee, txFeeToken)
+	txD.FeePerKB = blockchain.GetTxFeePerKBInPRV(tx, beaconHeight, beaconView.GetBeaconFeatureStateDB())
+	// the caps of the pool only apply to new transactions, not to the ones
+	// verified for a new block
+	var evictions []evictionCandidate
+	if isNewTransaction {
+		if evictions, err = tp.makeRoomForTx(txD); err != nil {
+			return nil, nil, err
+		}
+	}
 	err = tp.addTx(txD, isStore)
 	if err != nil {
 		return nil, nil, err
 	}
+	tp.evictTxs(evictions, tx.Hash())
 	if isNewTransaction {
 		Logger.log.Infof("Add New Txs Into Pool %+v FROM SHARD %+v\n", *tx.Hash(), shardID)
 	}
@@ -482,11 +675,161 @@ In Param#2: isStore: store transaction to persistence storage only work for tran
 10. RequestStopAutoStaking
 */
 func (tp *TxPool) validateTransaction(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, beaconHeight int64, isBatch bool, isNewTransaction bool) error {
-	var err error
+	for _, check := range tp.getValidationChecks(shardView, beaconView, tx, beaconHeight, isBatch, isNewTransaction, false) {
+		if err := check.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validationCheck is one of the conditions of validateTransaction
+type validationCheck struct {
+	name     string
+	validate func() error
+}
+
+// names of the validation checks
+const (
+	CheckSanity                   = "sanity"
+	CheckDuplicate                = "duplicate"
+	CheckSalary                   = "salary"
+	CheckFee                      = "fee"
+	CheckDoubleSpendWithMempool   = "doublespendwithmempool"
+	CheckTxByItself               = "txbyitself"
+	CheckTxWithBlockchain         = "txwithblockchain"
+	CheckDuplicateStakePubkey     = "duplicatestakepubkey"
+	CheckDuplicateStopAutoStaking = "duplicatestopautostaking"
+)
+
+// getValidationChecks returns the conditions of validateTransaction in order.
+// With isDryRun, a replacement tx does not remove the tx it replaces from pool.
+func (tp *TxPool) getValidationChecks(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, beaconHeight int64, isBatch bool, isNewTransaction bool, isDryRun bool) []validationCheck {
 	txHash := tx.Hash()
 	shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	checks := []validationCheck{}
 	// Condition 1: sanity data
+	checks = append(checks, validationCheck{CheckSanity, func() error {
+		return tp.checkSanity(shardView, beaconView, tx, beaconHeight, isNewTransaction)
+	}})
+	// Condition 2: Don't accept the transaction if it already exists in the pool.
+	checks = append(checks, validationCheck{CheckDuplicate, func() error {
+		isTxInPool := tp.isTxInPool(txHash)
+		if isTxInPool {
+			return NewMempoolTxError(RejectDuplicateTx, fmt.Errorf("already had transaction %+v in mempool", txHash.String()))
+		}
+		return nil
+	}})
+	// Condition 3: A standalone transaction must not be a salary transaction.
+	checks = append(checks, validationCheck{CheckSalary, func() error {
+		isSalaryTx := tx.IsSalaryTx()
+		if isSalaryTx {
+			return NewMempoolTxError(RejectSalaryTx, fmt.Errorf("%+v is salary tx", txHash.String()))
+		}
+		return nil
+	}})
+	// Condition 4: check fee PRV of tx
+	checks = append(checks, validationCheck{CheckFee, func() error {
+		validFee := tp.checkFees(beaconView, tx, shardID, beaconHeight)
+		if !validFee {
+			return NewMempoolTxError(RejectInvalidFee,
+				fmt.Errorf("Transaction %+v has invalid fees.",
+					tx.Hash().String()))
+		}
+		return nil
+	}})
+	// Condition 5: check tx with all txs in current mempool
+	checks = append(checks, validationCheck{CheckDoubleSpendWithMempool, func() error {
+		err := tx.ValidateTxWithCurrentMempool(tp)
+		if err != nil {
+			replaceErr, isReplacedTx := tp.validateTransactionReplacement(tx, isDryRun)
+			// if replace tx success (no replace error found) then continue with next validate condition
+			if isReplacedTx {
+				if replaceErr != nil {
+					return replaceErr
+				}
+			} else {
+				// replace fail
+				return NewMempoolTxError(RejectDoubleSpendWithMempoolTx, err)
+			}
+		}
+		return nil
+	}})
+	// Condition 6: ValidateTransaction tx by it self
+	if !isBatch {
+		checks = append(checks, validationCheck{CheckTxByItself, func() error {
+			return tp.checkTxByItself(shardView, beaconView, tx, beaconHeight, isNewTransaction, false)
+		}})
+	}
+	// Condition 7: validate tx with data of blockchain
+	checks = append(checks, validationCheck{CheckTxWithBlockchain, func() error {
+		err := tx.ValidateTxWithBlockChain(tp.config.BlockChain, shardView, beaconView, shardID, shardView.GetCopiedTransactionStateDB())
+		if err != nil {
+			// parse error
+			e1, ok := err.(*transaction.TransactionError)
+			if ok {
+				switch e1.Code {
+				case transaction.RejectTxMedataWithBlockChain:
+					{
+						return NewMempoolTxError(RejectMetadataWithBlockchainTx, err)
+					}
+				}
+			}
+			return NewMempoolTxError(RejectDoubleSpendWithBlockchainTx, err)
+		}
+		return nil
+	}})
+	// Condition 9: check duplicate stake public key ONLY with staking transaction
+	checks = append(checks, validationCheck{CheckDuplicateStakePubkey, func() error {
+		pubkey := ""
+		foundPubkey := -1
+		if tx.GetMetadata() != nil {
+			if tx.GetMetadata().GetType() == metadata.ShardStakingMeta || tx.GetMetadata().GetType() == metadata.BeaconStakingMeta {
+				stakingMetadata, ok := tx.GetMetadata().(*metadata.StakingMetadata)
+				if !ok {
+					return NewMempoolTxError(GetStakingMetadataError, fmt.Errorf("Expect metadata type to be *metadata.StakingMetadata but get %+v", reflect.TypeOf(tx.GetMetadata())))
+				}
+				pubkey = stakingMetadata.CommitteePublicKey
+				tp.candidateMtx.RLock()
+				foundPubkey = common.IndexOfStrInHashMap(stakingMetadata.CommitteePublicKey, tp.poolCandidate)
+				tp.candidateMtx.RUnlock()
+			}
+		}
+		if foundPubkey > 0 {
+			return NewMempoolTxError(RejectDuplicateStakePubkey, fmt.Errorf("This public key already stake and still in pool %+v", pubkey))
+		}
+		return nil
+	}})
+	// Condition 10: check duplicate request stop auto staking
+	checks = append(checks, validationCheck{CheckDuplicateStopAutoStaking, func() error {
+		requestedPublicKey := ""
+		foundRequestStopAutoStaking := -1
+		if tx.GetMetadata() != nil {
+			if tx.GetMetadata().GetType() == metadata.StopAutoStakingMeta {
+				stopAutoStakingMetadata, ok := tx.GetMetadata().(*metadata.StopAutoStakingMetadata)
+				if !ok {
+					return NewMempoolTxError(GetStakingMetadataError, fmt.Errorf("Expect metadata type to be *metadata.StopAutoStakingMetadata but get %+v", reflect.TypeOf(tx.GetMetadata())))
+				}
+				requestedPublicKey = stopAutoStakingMetadata.CommitteePublicKey
+				tp.requestStopStakingMtx.RLock()
+				foundRequestStopAutoStaking = common.IndexOfStrInHashMap(stopAutoStakingMetadata.CommitteePublicKey, tp.poolRequestStopStaking)
+				tp.requestStopStakingMtx.RUnlock()
+			}
+		}
+		if foundRequestStopAutoStaking > 0 {
+			return NewMempoolTxError(RejectDuplicateRequestStopAutoStaking, fmt.Errorf("This public key already request to stop auto staking and still in pool %+v", requestedPublicKey))
+		}
+		return nil
+	}})
+	return checks
+}
+
+// checkSanity is the sanity condition of validateTransaction, it does not read
+// the pool
+func (tp *TxPool) checkSanity(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, beaconHeight int64, isNewTransaction bool) error {
+	txHash := tx.Hash()
 	validated := false
+	var err error
 	if !isNewTransaction {
 		// need to use beacon height from
 		validated, err = tx.ValidateSanityData(tp.config.BlockChain, shardView, beaconView, uint64(beaconHeight))
@@ -514,102 +857,26 @@ func (tp *TxPool) validateTransaction(shardView *blockchain.ShardBestState, beac
 		}
 		return NewMempoolTxError(RejectSanityTx, fmt.Errorf("transaction's sansity %v is error %v", txHash.String(), err))
 	}
+	return nil
+}
 
-	// Condition 2: Don't accept the transaction if it already exists in the pool.
-	isTxInPool := tp.isTxInPool(txHash)
-	if isTxInPool {
-		return NewMempoolTxError(RejectDuplicateTx, fmt.Errorf("already had transaction %+v in mempool", txHash.String()))
-	}
-	// Condition 3: A standalone transaction must not be a salary transaction.
-	isSalaryTx := tx.IsSalaryTx()
-	if isSalaryTx {
-		return NewMempoolTxError(RejectSalaryTx, fmt.Errorf("%+v is salary tx", txHash.String()))
-	}
-	// Condition 4: check fee PRV of tx
-	validFee := tp.checkFees(beaconView, tx, shardID, beaconHeight)
-	if !validFee {
-		return NewMempoolTxError(RejectInvalidFee,
-			fmt.Errorf("Transaction %+v has invalid fees.",
-				tx.Hash().String()))
-	}
-	// Condition 5: check tx with all txs in current mempool
-	err = tx.ValidateTxWithCurrentMempool(tp)
-	if err != nil {
-		replaceErr, isReplacedTx := tp.validateTransactionReplacement(tx)
-		// if replace tx success (no replace error found) then continue with next validate condition
-		if isReplacedTx {
-			if replaceErr != nil {
-				return replaceErr
-			}
-		} else {
-			// replace fail
-			return NewMempoolTxError(RejectDoubleSpendWithMempoolTx, err)
-		}
-	}
-	// Condition 6: ValidateTransaction tx by it self
-	if !isBatch {
-		isNewZKP := tp.config.BlockChain.IsAfterNewZKPCheckPoint(uint64(beaconHeight))
+// checkTxByItself is the condition of validateTransaction verifying the proofs
+// and the signature of tx, it does not read the pool. The commitments of the
+// inputs of tx are not checked if hasUnconfirmedInputs, they are outputs of txs
+// which are not in a block yet.
+func (tp *TxPool) checkTxByItself(shardView *blockchain.ShardBestState, beaconView *blockchain.BeaconBestState, tx metadata.Transaction, beaconHeight int64, isNewTransaction bool, hasUnconfirmedInputs bool) error {
+	shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	isNewZKP := tp.config.BlockChain.IsAfterNewZKPCheckPoint(uint64(beaconHeight))
 
-		boolParams := make(map[string]bool)
-		boolParams["hasPrivacy"] = tx.IsPrivacy()
-		boolParams["isNewTransaction"] = isNewTransaction
-		boolParams["isNewZKP"] = isNewZKP
+	boolParams := make(map[string]bool)
+	boolParams["hasPrivacy"] = tx.IsPrivacy()
+	boolParams["isNewTransaction"] = isNewTransaction
+	boolParams["isNewZKP"] = isNewZKP
+	boolParams["hasUnconfirmedInputs"] = hasUnconfirmedInputs
 
-		validated, errValidateTxByItself := tx.ValidateTxByItself(boolParams, shardView.GetCopiedTransactionStateDB(), beaconView.GetBeaconFeatureStateDB(), tp.config.BlockChain, shardID, nil, nil)
-		if !validated {
-			return NewMempoolTxError(RejectInvalidTx, errValidateTxByItself)
-		}
-	}
-	// Condition 7: validate tx with data of blockchain
-	err = tx.ValidateTxWithBlockChain(tp.config.BlockChain, shardView, beaconView, shardID, shardView.GetCopiedTransactionStateDB())
-	if err != nil {
-		// parse error
-		e1, ok := err.(*transaction.TransactionError)
-		if ok {
-			switch e1.Code {
-			case transaction.RejectTxMedataWithBlockChain:
-				{
-					return NewMempoolTxError(RejectMetadataWithBlockchainTx, err)
-				}
-			}
-		}
-		return NewMempoolTxError(RejectDoubleSpendWithBlockchainTx, err)
-	}
-	// Condition 9: check duplicate stake public key ONLY with staking transaction
-	pubkey := ""
-	foundPubkey := -1
-	if tx.GetMetadata() != nil {
-		if tx.GetMetadata().GetType() == metadata.ShardStakingMeta || tx.GetMetadata().GetType() == metadata.BeaconStakingMeta {
-			stakingMetadata, ok := tx.GetMetadata().(*metadata.StakingMetadata)
-			if !ok {
-				return NewMempoolTxError(GetStakingMetadataError, fmt.Errorf("Expect metadata type to be *metadata.StakingMetadata but get %+v", reflect.TypeOf(tx.GetMetadata())))
-			}
-			pubkey = stakingMetadata.CommitteePublicKey
-			tp.candidateMtx.RLock()
-			foundPubkey = common.IndexOfStrInHashMap(stakingMetadata.CommitteePublicKey, tp.poolCandidate)
-			tp.candidateMtx.RUnlock()
-		}
-	}
-	if foundPubkey > 0 {
-		return NewMempoolTxError(RejectDuplicateStakePubkey, fmt.Errorf("This public key already stake and still in pool %+v", pubkey))
-	}
-	// Condition 10: check duplicate request stop auto staking
-	requestedPublicKey := ""
-	foundRequestStopAutoStaking := -1
-	if tx.GetMetadata() != nil {
-		if tx.GetMetadata().GetType() == metadata.StopAutoStakingMeta {
-			stopAutoStakingMetadata, ok := tx.GetMetadata().(*metadata.StopAutoStakingMetadata)
-			if !ok {
-				return NewMempoolTxError(GetStakingMetadataError, fmt.Errorf("Expect metadata type to be *metadata.StopAutoStakingMetadata but get %+v", reflect.TypeOf(tx.GetMetadata())))
-			}
-			requestedPublicKey = stopAutoStakingMetadata.CommitteePublicKey
-			tp.requestStopStakingMtx.RLock()
-			foundRequestStopAutoStaking = common.IndexOfStrInHashMap(stopAutoStakingMetadata.CommitteePublicKey, tp.poolRequestStopStaking)
-			tp.requestStopStakingMtx.RUnlock()
-		}
-	}
-	if foundRequestStopAutoStaking > 0 {
-		return NewMempoolTxError(RejectDuplicateRequestStopAutoStaking, fmt.Errorf("This public key already request to stop auto staking and still in pool %+v", requestedPublicKey))
+	validated, errValidateTxByItself := tx.ValidateTxByItself(boolParams, shardView.GetCopiedTransactionStateDB(), beaconView.GetBeaconFeatureStateDB(), tp.config.BlockChain, shardID, nil, nil)
+	if !validated {
+		return NewMempoolTxError(RejectInvalidTx, errValidateTxByItself)
 	}
 	return nil
 }
@@ -622,7 +889,9 @@ func (tp *TxPool) isTxInPool(hash *common.Hash) bool {
 	return false
 }
 
-func (tp *TxPool) validateTransactionReplacement(tx metadata.Transaction) (error, bool) {
+// validateTransactionReplacement checks if tx replaces a tx of pool spending the
+// same coins, the replaced tx is removed from pool unless isDryRun
+func (tp *TxPool) validateTransactionReplacement(tx metadata.Transaction, isDryRun bool) (error, bool) {
 	// calculate match serial number list in pool for replaced tx
 	serialNumberHashList := tx.ListSerialNumbersHashH()
 	hash := common.HashArrayOfHashArray(serialNumberHashList)
@@ -665,6 +934,9 @@ func (tp *TxPool) validateTransactionReplacement(tx metadata.Transaction) (error
 				}
 				isReplaced = true
 			}
+			if isReplaced && isDryRun {
+				return nil, true
+			}
 			if isReplaced {
 				txToBeReplaced := txDescToBeReplaced.Desc.Tx
 				tp.removeTx(txToBeReplaced)
@@ -674,7 +946,7 @@ func (tp *TxPool) validateTransactionReplacement(tx metadata.Transaction) (error
 				tp.TriggerCRemoveTxs(tx)
 				return nil, true
 			} else {
-				return NewMempoolTxError(RejectReplacementTxError, fmt.Errorf("Unexpected error occur")), true
+				return NewMempoolTxError(RejectReplacementTxError, fmt.Errorf("tx %+v to be replaced pays no fee", txHashToBeReplaced.String())), true
 			}
 		} else {
 			//found no tx to be replaced
@@ -712,7 +984,12 @@ func (tp *TxPool) addTx(txD *TxDesc, isStore bool) error {
 			Logger.log.Criticalf("Add tx %+v to mempool database success \n", *txHash)
 		}
 	}
+	if oldTxD, ok := tp.pool[*txHash]; ok {
+		tp.untrackTx(oldTxD)
+	}
 	tp.pool[*txHash] = txD
+	tp.trackTx(txD)
+	tp.indexSNDOutputs(tx)
 	var serialNumberList []common.Hash
 	serialNumberList = append(serialNumberList, txD.Desc.Tx.ListSerialNumbersHashH()...)
 	serialNumberListHash := common.HashArrayOfHashArray(serialNumberList)
@@ -796,6 +1073,12 @@ func (tp *TxPool) checkPublicKeyRole(tx metadata.Transaction) bool {
 func (tp *TxPool) RemoveTx(txs []metadata.Transaction, isInBlock bool) {
 	tp.mtx.Lock()
 	defer tp.mtx.Unlock()
+	// the held txs spending outputs of txs in a block are detached from them
+	// before they are removed, so that they are not dropped
+	var ready []common.Hash
+	if isInBlock {
+		ready = tp.confirmDependencies(txs)
+	}
 	// remove transaction from database mempool
 	for _, tx := range txs {
 		if tp.config.PersistMempool {
@@ -807,6 +1090,7 @@ func (tp *TxPool) RemoveTx(txs []metadata.Transaction, isInBlock bool) {
 		tp.removeTx(tx)
 		tp.TriggerCRemoveTxs(tx)
 	}
+	tp.releaseDependents(ready)
 	return
 }
 
@@ -821,10 +1105,14 @@ func (tp *TxPool) RemoveTx(txs []metadata.Transaction, isInBlock bool) {
 */
 func (tp *TxPool) removeTx(tx metadata.Transaction) {
 	//Logger.log.Infof((*tx).Hash().String())
-	if _, exists := tp.pool[*tx.Hash()]; exists {
+	if txD, exists := tp.pool[*tx.Hash()]; exists {
+		tp.untrackTx(txD)
+		tp.unindexSNDOutputs(txD.Desc.Tx)
 		delete(tp.pool, *tx.Hash())
 		atomic.StoreInt64(&tp.lastUpdated, time.Now().Unix())
 	}
+	// the held txs spending its outputs can not be in a block anymore
+	tp.dropDependents(*tx.Hash())
 	if _, exists := tp.poolSerialNumbersHashList[*tx.Hash()]; exists {
 		delete(tp.poolSerialNumbersHashList, *tx.Hash())
 	}
@@ -834,7 +1122,9 @@ func (tp *TxPool) removeTx(tx metadata.Transaction) {
 		delete(tp.poolSerialNumberHash, hash)
 		// Using the same list serial number to delete new transaction out of pool
 		// this new transaction maybe not exist
-		if _, exists := tp.pool[hash]; exists {
+		if txD, exists := tp.pool[hash]; exists {
+			tp.untrackTx(txD)
+			tp.unindexSNDOutputs(txD.Desc.Tx)
 			delete(tp.pool, hash)
 			atomic.StoreInt64(&tp.lastUpdated, time.Now().Unix())
 		}
@@ -907,6 +1197,14 @@ func (tp *TxPool) RemoveRequestStopStakingList(requestStopStakings []string) {
 }
 
 //=======================Service for other package
+// GetPendingTxs returns the txs of a shard of pool, of every shard if shardID is
+// 255, in the order of the tx selection policy
+func (tp *TxPool) GetPendingTxs(shardID byte) []metadata.Transaction {
+	tp.mtx.RLock()
+	defer tp.mtx.RUnlock()
+	return tp.pendingTxs.get(shardID)
+}
+
 // SendTransactionToBlockGen - push tx into channel and send to Block generate of consensus
 func (tp *TxPool) SendTransactionToBlockGen() {
 	tp.mtx.RLock()
@@ -929,6 +1227,26 @@ func (tp *TxPool) MarkForwardedTransaction(txHash common.Hash) {
 	}
 }
 
+// MarkPrivateTransaction - mark a transaction is relayed to the committee of
+// its shard only, instead of being published to the network
+func (tp *TxPool) MarkPrivateTransaction(txHash common.Hash) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+	if tp.IsTest {
+		return
+	}
+	txDesc, ok := tp.pool[txHash]
+	if !ok || txDesc.IsPrivate {
+		return
+	}
+	txDesc.IsPrivate = true
+	if tp.config.PersistMempool {
+		if err := tp.addTransactionToDatabaseMempool(&txHash, *txDesc); err != nil {
+			Logger.log.Errorf("Fail to mark tx %+v private in mempool database %+v", txHash.String(), err)
+		}
+	}
+}
+
 // GetTx get transaction info by hash
 func (tp *TxPool) GetTx(txHash *common.Hash) (metadata.Transaction, error) {
 	tp.mtx.Lock()
@@ -1098,6 +1416,11 @@ func (tp *TxPool) EmptyPool() bool {
 	tp.poolSerialNumberHash = make(map[common.Hash]common.Hash)
 	tp.poolCandidate = make(map[common.Hash]string)
 	tp.poolRequestStopStaking = make(map[common.Hash]string)
+	tp.shardUsage = make(map[byte]*poolUsage)
+	tp.evictionQueue = newEvictionQueue()
+	tp.shardEvictionQueues = make(map[byte]*evictionQueue)
+	tp.senderTxCount = make(map[string]uint64)
+	tp.pendingTxs = newPendingTxIndex(tp.config.TxSelectionPolicy)
 	if len(tp.pool) == 0 && len(tp.poolSerialNumbersHashList) == 0 && len(tp.poolSerialNumberHash) == 0 && len(tp.poolCandidate) == 0 && len(tp.poolRequestStopStaking) == 0 {
 		return true
 	}
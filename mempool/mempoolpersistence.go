@@ -2,10 +2,15 @@ package mempool
 
 import (
 	"encoding/json"
-	"github.com/incognitochain/incognito-chain/blockchain"
+	"fmt"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/incognitochain/incognito-chain/blockchain"
+
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/databasemp/lvdb"
 	"github.com/incognitochain/incognito-chain/transaction"
@@ -21,64 +26,20 @@ type TempDesc struct {
 
 // addTransactionToDatabaseMempool - Add a transaction data into mempool database
 func (tp *TxPool) addTransactionToDatabaseMempool(txHash *common.Hash, txDesc TxDesc) error {
-	tx := txDesc.Desc.Tx
-	tempDesc := TempDesc{
-		StartTime:     txDesc.StartTime,
-		IsPushMessage: txDesc.IsFowardMessage,
-		Height:        txDesc.Desc.Height,
-		Fee:           txDesc.Desc.Fee,
-		FeePerKB:      txDesc.Desc.FeePerKB,
-	}
-	switch tx.GetType() {
-	//==================For PRV Transfer Only
-	case common.TxNormalType:
-		{
-			normalTx := tx.(*transaction.Tx)
-			valueTx, err := json.Marshal(normalTx)
-			if err != nil {
-				return err
-			}
-			valueDesc, err := json.Marshal(tempDesc)
-			if err != nil {
-				return err
-			}
-			err = tp.config.DataBaseMempool.AddTransaction(txHash, common.TxNormalType, valueTx, valueDesc)
-			if err != nil {
-				return err
-			}
-		}
-	case common.TxCustomTokenPrivacyType:
-		{
-			customTokenPrivacyTx := tx.(*transaction.TxCustomTokenPrivacy)
-			valueTx, err := json.Marshal(customTokenPrivacyTx)
-			if err != nil {
-				return err
-			}
-			valueDesc, err := json.Marshal(tempDesc)
-			if err != nil {
-				return err
-			}
-			err = tp.config.DataBaseMempool.AddTransaction(txHash, common.TxCustomTokenPrivacyType, valueTx, valueDesc)
-			if err != nil {
-				return err
-			}
-		}
+	value, err := encodeTxRecord(&txDesc)
+	if err != nil {
+		return err
 	}
-	return nil
+	return tp.config.DataBaseMempool.PutTransaction(txHash, value)
 }
 
 // getTransactionFromDatabaseMempool - get tx from mempool database
 func (tp *TxPool) getTransactionFromDatabaseMempool(txHash *common.Hash) (*TxDesc, error) {
 	value, err := tp.config.DataBaseMempool.GetTransaction(txHash)
-	values := strings.Split(string(value), string(lvdb.Splitter))
-	if len(values) != 3 {
-		return nil, err
-	}
-	txDesc, err := unMarshallTxDescFromDatabase(values[0], []byte(values[1]), []byte(values[2]))
 	if err != nil {
 		return nil, err
 	}
-	return txDesc, nil
+	return decodeTxDescFromDatabase(value)
 }
 
 // resetDatabaseMempool - reset data in data mempool
@@ -86,52 +47,84 @@ func (tp *TxPool) resetDatabaseMempool() error {
 	return tp.config.DataBaseMempool.Reset()
 }
 
+// loadedTxDesc is a tx of the mempool database being loaded, with the views it
+// is validated against
+type loadedTxDesc struct {
+	txDesc     *TxDesc
+	shardView  *blockchain.ShardBestState
+	beaconView *blockchain.BeaconBestState
+}
+
+// isPoolCheck reports whether a validation check depends on the other txs of
+// pool, such checks run in order when the txs are added to pool
+func isPoolCheck(name string) bool {
+	switch name {
+	case CheckDuplicate, CheckDoubleSpendWithMempool, CheckDuplicateStakePubkey, CheckDuplicateStopAutoStaking:
+		return true
+	}
+	return false
+}
+
 // loadDatabaseMP - Get all tx in mempool database persistence
+// The txs are decoded and validated against the blockchain in parallel, the txs
+// which expired, are already spent on chain or are invalid are removed from
+// database. The others are added to pool in order of arrival.
 func (tp *TxPool) loadDatabaseMP() ([]TxDesc, error) {
 	txDescs := []TxDesc{}
 	allTxHashes, allTxs, err := tp.config.DataBaseMempool.Load()
-	ttl := time.Duration(tp.config.TxLifeTime) * time.Second
 	if err != nil {
 		return txDescs, err
 	}
-	for index, tx := range allTxs {
-		values := strings.Split(string(tx), string(lvdb.Splitter))
-		txDesc, err := unMarshallTxDescFromDatabase(values[0], []byte(values[1]), []byte(values[2]))
-		if err != nil {
-			Logger.log.Error(err)
-			txHash, err := common.Hash{}.NewHash(allTxHashes[index][3:])
-			if err != nil {
-				Logger.log.Error(err)
-				continue
+	loaded := make([]*loadedTxDesc, len(allTxs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				loaded[index] = tp.revalidateTxFromDatabase(allTxHashes[index], allTxs[index])
 			}
-			// fail to ummarshall transaction then remove
-			err1 := tp.removeTransactionFromDatabaseMP(txHash)
-			if err1 != nil {
-				Logger.log.Error(err1)
-			}
-			continue
+		}()
+	}
+	for index := range allTxs {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	valids := []*loadedTxDesc{}
+	for _, loadedTxD := range loaded {
+		if loadedTxD != nil {
+			valids = append(valids, loadedTxD)
 		}
-		//if transaction is timeout then remove
-		if time.Since(txDesc.StartTime) > ttl {
-			err1 := tp.removeTransactionFromDatabaseMP(txDesc.Desc.Tx.Hash())
-			if err1 != nil {
-				Logger.log.Error(err1)
+	}
+	sort.SliceStable(valids, func(i, j int) bool {
+		return valids[i].txDesc.StartTime.Before(valids[j].txDesc.StartTime)
+	})
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+	for _, loadedTxD := range valids {
+		txDesc := loadedTxD.txDesc
+		tx := txDesc.Desc.Tx
+		var err error
+		for _, check := range tp.getValidationChecks(loadedTxD.shardView, loadedTxD.beaconView, tx, -1, false, false, false) {
+			if !isPoolCheck(check.name) {
+				continue
+			}
+			if err = check.validate(); err != nil {
+				break
 			}
 		}
-		//if not validated by current blockchain db then remove
-		senderShardID := common.GetShardIDFromLastByte(txDesc.Desc.Tx.GetSenderAddrLastByte())
-		beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
-		shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
-		err = tp.validateTransaction(shardView, beaconView, txDesc.Desc.Tx, -1, false, false)
 		if err != nil {
 			Logger.log.Error(err)
-			err1 := tp.removeTransactionFromDatabaseMP(txDesc.Desc.Tx.Hash())
+			err1 := tp.removeTransactionFromDatabaseMP(tx.Hash())
 			if err1 != nil {
 				Logger.log.Error(err1)
 			}
 			continue
 		}
-
+		txDesc.FeePerKB = blockchain.GetTxFeePerKBInPRV(tx, int64(loadedTxD.beaconView.BeaconHeight), loadedTxD.beaconView.GetBeaconFeatureStateDB())
 		err = tp.addTx(txDesc, false)
 		if err != nil {
 			Logger.log.Error(err)
@@ -141,6 +134,59 @@ func (tp *TxPool) loadDatabaseMP() ([]TxDesc, error) {
 	return txDescs, nil
 }
 
+// revalidateTxFromDatabase decodes a tx of the mempool database and runs the
+// validation checks which do not depend on the other txs of pool. The tx is
+// removed from database and nil is returned if it can not be decoded, expired
+// or is not valid anymore.
+func (tp *TxPool) revalidateTxFromDatabase(key []byte, value []byte) *loadedTxDesc {
+	txDesc, err := decodeTxDescFromDatabase(value)
+	if err != nil {
+		Logger.log.Error(err)
+		txHash, err := common.Hash{}.NewHash(key[3:])
+		if err != nil {
+			Logger.log.Error(err)
+			return nil
+		}
+		// fail to ummarshall transaction then remove
+		err1 := tp.removeTransactionFromDatabaseMP(txHash)
+		if err1 != nil {
+			Logger.log.Error(err1)
+		}
+		return nil
+	}
+	tx := txDesc.Desc.Tx
+	//if transaction is timeout then remove
+	ttl := time.Duration(tp.config.TxLifeTime) * time.Second
+	if time.Since(txDesc.StartTime) > ttl {
+		err1 := tp.removeTransactionFromDatabaseMP(tx.Hash())
+		if err1 != nil {
+			Logger.log.Error(err1)
+		}
+		return nil
+	}
+	//if not validated by current blockchain db then remove
+	senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	loadedTxD := &loadedTxDesc{
+		txDesc:     txDesc,
+		beaconView: tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState),
+		shardView:  tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState),
+	}
+	for _, check := range tp.getValidationChecks(loadedTxD.shardView, loadedTxD.beaconView, tx, -1, false, false, false) {
+		if isPoolCheck(check.name) {
+			continue
+		}
+		if err := check.validate(); err != nil {
+			Logger.log.Error(err)
+			err1 := tp.removeTransactionFromDatabaseMP(tx.Hash())
+			if err1 != nil {
+				Logger.log.Error(err1)
+			}
+			return nil
+		}
+	}
+	return loadedTxD
+}
+
 // removeTransactionFromDatabaseMP - remove tx from mempool db persistence
 func (tp *TxPool) removeTransactionFromDatabaseMP(txHash *common.Hash) error {
 	if has, _ := tp.config.DataBaseMempool.HasTransaction(txHash); has {
@@ -150,6 +196,19 @@ func (tp *TxPool) removeTransactionFromDatabaseMP(txHash *common.Hash) error {
 	return nil
 }
 
+// decodeTxDescFromDatabase - convert a value of mempool database persistence into TxDesc,
+// in the binary encoding or in the json encoding of the previous versions
+func decodeTxDescFromDatabase(value []byte) (*TxDesc, error) {
+	if len(value) > 0 && value[0] == txRecordVersion {
+		return decodeTxRecord(value)
+	}
+	values := strings.Split(string(value), string(lvdb.Splitter))
+	if len(values) != 3 {
+		return nil, fmt.Errorf("tx value of mempool database is invalid")
+	}
+	return unMarshallTxDescFromDatabase(values[0], []byte(values[1]), []byte(values[2]))
+}
+
 // unMarshallTxDescFromDatabase - convert tx data in mempool database persistence into TxDesc
 func unMarshallTxDescFromDatabase(txType string, valueTx []byte, valueDesc []byte) (*TxDesc, error) {
 	txDesc := TxDesc{}
@@ -0,0 +1,96 @@
+package mempool
+
+import (
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// indexedTx is a transaction of the pool in the pending tx index
+type indexedTx struct {
+	*blockchain.PendingTx
+	sequence uint64 // arrival order, breaks the ties left by the policy
+}
+
+// pendingTxIndex keeps the transactions of the pool sorted by a
+// TxSelectionPolicy, a transaction is inserted at its position on arrival so
+// that a block is filled without sorting the pool
+type pendingTxIndex struct {
+	policy       blockchain.TxSelectionPolicy
+	txs          []*indexedTx
+	txByHash     map[common.Hash]*indexedTx
+	nextSequence uint64
+}
+
+func newPendingTxIndex(policy blockchain.TxSelectionPolicy) *pendingTxIndex {
+	if policy == nil {
+		policy = blockchain.FeePriorityPolicy{}
+	}
+	return &pendingTxIndex{
+		policy:   policy,
+		txByHash: make(map[common.Hash]*indexedTx),
+	}
+}
+
+func (index *pendingTxIndex) less(a, b *indexedTx) bool {
+	if index.policy.Less(a.PendingTx, b.PendingTx) {
+		return true
+	}
+	if index.policy.Less(b.PendingTx, a.PendingTx) {
+		return false
+	}
+	return a.sequence < b.sequence
+}
+
+// search returns the position of tx, or where to insert it
+func (index *pendingTxIndex) search(tx *indexedTx) int {
+	return sort.Search(len(index.txs), func(i int) bool {
+		return !index.less(index.txs[i], tx)
+	})
+}
+
+// add inserts tx, it returns false if tx is already indexed
+func (index *pendingTxIndex) add(pendingTx *blockchain.PendingTx) bool {
+	hash := *pendingTx.Tx.Hash()
+	if _, ok := index.txByHash[hash]; ok {
+		return false
+	}
+	tx := &indexedTx{PendingTx: pendingTx, sequence: index.nextSequence}
+	index.nextSequence++
+	i := index.search(tx)
+	index.txs = append(index.txs, nil)
+	copy(index.txs[i+1:], index.txs[i:])
+	index.txs[i] = tx
+	index.txByHash[hash] = tx
+	return true
+}
+
+func (index *pendingTxIndex) remove(hash common.Hash) {
+	tx, ok := index.txByHash[hash]
+	if !ok {
+		return
+	}
+	delete(index.txByHash, hash)
+	i := index.search(tx)
+	if i >= len(index.txs) || index.txs[i] != tx {
+		return
+	}
+	copy(index.txs[i:], index.txs[i+1:])
+	index.txs[len(index.txs)-1] = nil
+	index.txs = index.txs[:len(index.txs)-1]
+}
+
+// get returns the transactions of a shard, of every shard if shardID is 255,
+// in the order of the policy
+func (index *pendingTxIndex) get(shardID byte) []metadata.Transaction {
+	txs := []metadata.Transaction{}
+	for _, tx := range index.txs {
+		if shardID != 255 && tx.ShardID != shardID {
+			continue
+		}
+		txs = append(txs, tx.Tx)
+	}
+	return txs
+}
@@ -0,0 +1,85 @@
+package mempool
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata/mocks"
+)
+
+type testPendingTx struct {
+	name     string
+	shardID  byte
+	feePerKB uint64
+	arrival  int // seconds after the first tx
+}
+
+func newTestPendingTx(tx testPendingTx, start time.Time) *blockchain.PendingTx {
+	hash := common.HashH([]byte(tx.name))
+	mockTx := &mocks.Transaction{}
+	mockTx.On("Hash").Return(&hash)
+	return &blockchain.PendingTx{
+		Tx:          mockTx,
+		ShardID:     tx.shardID,
+		FeePerKB:    tx.feePerKB,
+		ArrivalTime: start.Add(time.Duration(tx.arrival) * time.Second),
+	}
+}
+
+func TestPendingTxIndex(t *testing.T) {
+	txs := []testPendingTx{
+		{name: "a", shardID: 0, feePerKB: 10, arrival: 2},
+		{name: "b", shardID: 1, feePerKB: 30, arrival: 3},
+		{name: "c", shardID: 0, feePerKB: 10, arrival: 1},
+		{name: "d", shardID: 0, feePerKB: 20, arrival: 4},
+		// same fee and arrival time as a, added after it
+		{name: "e", shardID: 0, feePerKB: 10, arrival: 2},
+	}
+	tests := []struct {
+		name    string
+		policy  blockchain.TxSelectionPolicy
+		shardID byte
+		removed []string
+		want    []string
+	}{
+		{name: "fee priority", policy: blockchain.FeePriorityPolicy{}, shardID: 255, want: []string{"b", "d", "c", "a", "e"}},
+		{name: "default policy", policy: nil, shardID: 255, want: []string{"b", "d", "c", "a", "e"}},
+		{name: "fee priority of a shard", policy: blockchain.FeePriorityPolicy{}, shardID: 0, want: []string{"d", "c", "a", "e"}},
+		{name: "fee priority after removal", policy: blockchain.FeePriorityPolicy{}, shardID: 255, removed: []string{"d", "a"}, want: []string{"b", "c", "e"}},
+		{name: "fifo", policy: blockchain.FIFOPolicy{}, shardID: 255, want: []string{"c", "a", "e", "b", "d"}},
+		{name: "fifo after removal", policy: blockchain.FIFOPolicy{}, shardID: 255, removed: []string{"c", "e", "unknown"}, want: []string{"a", "b", "d"}},
+	}
+	start := time.Now()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := newPendingTxIndex(tt.policy)
+			names := map[common.Hash]string{}
+			for _, tx := range txs {
+				pendingTx := newTestPendingTx(tx, start)
+				names[*pendingTx.Tx.Hash()] = tx.name
+				if !index.add(pendingTx) {
+					t.Fatalf("tx %v not added", tx.name)
+				}
+				if index.add(newTestPendingTx(tx, start)) {
+					t.Fatalf("tx %v added twice", tx.name)
+				}
+			}
+			for _, name := range tt.removed {
+				index.remove(common.HashH([]byte(name)))
+			}
+			got := []string{}
+			for _, tx := range index.get(tt.shardID) {
+				got = append(got, names[*tx.Hash()])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("get() = %v, want %v", got, tt.want)
+			}
+			if len(index.txByHash) != len(index.txs) {
+				t.Errorf("%v txs indexed by hash, %v in order", len(index.txByHash), len(index.txs))
+			}
+		})
+	}
+}
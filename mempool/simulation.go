@@ -0,0 +1,97 @@
+package mempool
+
+import (
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+)
+
+// CheckTxType is the check rejecting the txs only created by block producers
+const CheckTxType = "txtype"
+
+// CheckCapacity is the check of the caps of the pool, a tx passes it if it fits
+// in the pool or pays more than the txs it would evict
+const CheckCapacity = "capacity"
+
+// SimulationCheck is the result of one check of SimulateTransaction. A check is
+// skipped if it can not run on a tx which failed an earlier check.
+type SimulationCheck struct {
+	Name    string
+	Passed  bool
+	Skipped bool
+	Err     error
+}
+
+// SimulateTransaction runs the checks of MaybeAcceptTransaction on tx against
+// the views MaybeAcceptTransaction uses, the best shard view of its sender and
+// the final beacon view, without adding it to the pool nor removing the tx it
+// would replace or the txs it would evict. Every check runs, so that all the
+// reasons of a rejection are reported, except the checks following a failed
+// tx type or sanity check which expect a well-formed tx.
+// Only the checks reading the pool hold its lock, see poolChecks.
+// This function is safe for concurrent access.
+func (tp *TxPool) SimulateTransaction(tx metadata.Transaction, beaconHeight int64) []SimulationCheck {
+	senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+	shardView := tp.config.BlockChain.ShardChain[senderShardID].GetBestView().(*blockchain.ShardBestState)
+
+	checks := []validationCheck{{CheckTxType, func() error {
+		return checkStandaloneTxType(tx)
+	}}}
+	checks = append(checks, tp.getValidationChecks(shardView, beaconView, tx, beaconHeight, false, true, true)...)
+	checks = append(checks, validationCheck{CheckCapacity, func() error {
+		txD := &TxDesc{
+			Desc:     metadata.TxDesc{Tx: tx},
+			FeePerKB: blockchain.GetTxFeePerKBInPRV(tx, beaconHeight, beaconView.GetBeaconFeatureStateDB()),
+		}
+		_, _, err := tp.pickEvictions(txD)
+		return err
+	}})
+	return runSimulationChecks(tp.lockPoolChecks(checks))
+}
+
+// poolChecks are the checks reading the pool: the duplicate check, the double
+// spend check with the tx it would replace and the capacity check, which pops
+// the eviction queues to find the txs tx would evict
+var poolChecks = map[string]bool{
+	CheckDuplicate:              true,
+	CheckDoubleSpendWithMempool: true,
+	CheckCapacity:               true,
+}
+
+// lockPoolChecks makes the pool checks hold the pool lock while they run, the
+// other checks, e.g. the proof verification, run without it
+func (tp *TxPool) lockPoolChecks(checks []validationCheck) []validationCheck {
+	res := make([]validationCheck, 0, len(checks))
+	for _, check := range checks {
+		if poolChecks[check.name] {
+			validate := check.validate
+			check.validate = func() error {
+				tp.mtx.Lock()
+				defer tp.mtx.Unlock()
+				return validate()
+			}
+		}
+		res = append(res, check)
+	}
+	return res
+}
+
+// runSimulationChecks runs checks in order, the checks following a failed tx
+// type or sanity check are skipped
+func runSimulationChecks(checks []validationCheck) []SimulationCheck {
+	results := make([]SimulationCheck, 0, len(checks))
+	skip := false
+	for _, check := range checks {
+		result := SimulationCheck{Name: check.name, Skipped: skip}
+		if !skip {
+			result.Err = check.validate()
+			result.Passed = result.Err == nil
+			if !result.Passed && (check.name == CheckTxType || check.name == CheckSanity) {
+				skip = true
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,153 @@
+package mempool
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+)
+
+func TestTxPool_getValidationChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		isBatch bool
+		want    []string
+	}{
+		{
+			name: "single tx",
+			want: []string{CheckSanity, CheckDuplicate, CheckSalary, CheckFee, CheckDoubleSpendWithMempool, CheckTxByItself, CheckTxWithBlockchain, CheckDuplicateStakePubkey, CheckDuplicateStopAutoStaking},
+		},
+		{
+			name:    "batch verified tx",
+			isBatch: true,
+			want:    []string{CheckSanity, CheckDuplicate, CheckSalary, CheckFee, CheckDoubleSpendWithMempool, CheckTxWithBlockchain, CheckDuplicateStakePubkey, CheckDuplicateStopAutoStaking},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := newTestEvictionPool(Config{MaxTx: 10})
+			txD := newTestTxDesc(testPoolTx{name: "a", shardID: 1, sender: "s1", size: 1}, time.Now())
+			names := []string{}
+			for _, check := range tp.getValidationChecks(nil, nil, txD.Desc.Tx, 0, tt.isBatch, true, true) {
+				names = append(names, check.name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("getValidationChecks() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestTxPool_getValidationChecksDuplicate(t *testing.T) {
+	tp := newTestEvictionPool(Config{MaxTx: 10})
+	txD := newTestTxDesc(testPoolTx{name: "a", shardID: 1, sender: "s1", size: 1}, time.Now())
+	checkDuplicate := func() error {
+		for _, check := range tp.getValidationChecks(nil, nil, txD.Desc.Tx, 0, false, true, true) {
+			if check.name == CheckDuplicate {
+				return check.validate()
+			}
+		}
+		t.Fatal("no duplicate check")
+		return nil
+	}
+	if err := checkDuplicate(); err != nil {
+		t.Fatalf("tx out of pool is a duplicate: %v", err)
+	}
+	tp.pool[*txD.Desc.Tx.Hash()] = txD
+	if err := checkDuplicate(); err == nil {
+		t.Fatal("tx of pool is not a duplicate")
+	}
+}
+
+func Test_runSimulationChecks(t *testing.T) {
+	failed := errors.New("failed")
+	pass := func() error { return nil }
+	fail := func() error { return failed }
+	tests := []struct {
+		name   string
+		checks []validationCheck
+		want   []SimulationCheck
+	}{
+		{
+			name:   "all pass",
+			checks: []validationCheck{{CheckTxType, pass}, {CheckSanity, pass}, {CheckFee, pass}},
+			want:   []SimulationCheck{{Name: CheckTxType, Passed: true}, {Name: CheckSanity, Passed: true}, {Name: CheckFee, Passed: true}},
+		},
+		{
+			name:   "every failure is reported",
+			checks: []validationCheck{{CheckTxType, pass}, {CheckSanity, pass}, {CheckFee, fail}, {CheckCapacity, fail}},
+			want:   []SimulationCheck{{Name: CheckTxType, Passed: true}, {Name: CheckSanity, Passed: true}, {Name: CheckFee, Err: failed}, {Name: CheckCapacity, Err: failed}},
+		},
+		{
+			name:   "failed sanity skips the next checks",
+			checks: []validationCheck{{CheckTxType, pass}, {CheckSanity, fail}, {CheckFee, pass}, {CheckCapacity, pass}},
+			want:   []SimulationCheck{{Name: CheckTxType, Passed: true}, {Name: CheckSanity, Err: failed}, {Name: CheckFee, Skipped: true}, {Name: CheckCapacity, Skipped: true}},
+		},
+		{
+			name:   "failed tx type skips the next checks",
+			checks: []validationCheck{{CheckTxType, fail}, {CheckSanity, pass}},
+			want:   []SimulationCheck{{Name: CheckTxType, Err: failed}, {Name: CheckSanity, Skipped: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runSimulationChecks(tt.checks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runSimulationChecks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// only the checks reading the pool wait for its lock
+func TestTxPool_lockPoolChecks(t *testing.T) {
+	tp := newTestEvictionPool(Config{MaxTx: 10})
+	pass := func() error { return nil }
+	checks := tp.lockPoolChecks([]validationCheck{{CheckSanity, pass}, {CheckDuplicate, pass}, {CheckTxWithBlockchain, pass}, {CheckDoubleSpendWithMempool, pass}, {CheckCapacity, pass}})
+	tp.mtx.Lock()
+	for _, check := range checks {
+		done := make(chan error, 1)
+		go func(check validationCheck) {
+			done <- check.validate()
+		}(check)
+		select {
+		case <-done:
+			if poolChecks[check.name] {
+				t.Errorf("check %v runs without the pool lock", check.name)
+			}
+		case <-time.After(50 * time.Millisecond):
+			if !poolChecks[check.name] {
+				t.Errorf("check %v waits for the pool lock", check.name)
+			}
+			tp.mtx.Unlock()
+			<-done
+			tp.mtx.Lock()
+		}
+	}
+	tp.mtx.Unlock()
+}
+
+// the capacity check of a simulation neither evicts nor counts a rejection
+func TestTxPool_pickEvictionsDryRun(t *testing.T) {
+	tp := newTestEvictionPool(Config{MaxTx: 1, MaxSenderTx: 1})
+	start := time.Now()
+	txD := newTestTxDesc(testPoolTx{name: "a", shardID: 1, sender: "s1", size: 1, feePerKB: 10}, start)
+	tp.pool[*txD.Desc.Tx.Hash()] = txD
+	tp.trackTx(txD)
+
+	candidates, _, err := tp.pickEvictions(newTestTxDesc(testPoolTx{name: "b", shardID: 1, sender: "s2", size: 1, feePerKB: 20}, start))
+	if err != nil || len(candidates) != 1 || !candidates[0].txDesc.Desc.Tx.Hash().IsEqual(txD.Desc.Tx.Hash()) {
+		t.Fatalf("pickEvictions() = %v, %v, want tx a evicted", candidates, err)
+	}
+	_, reason, err := tp.pickEvictions(newTestTxDesc(testPoolTx{name: "c", shardID: 1, sender: "s1", size: 1, feePerKB: 20}, start))
+	if err == nil || reason != RejectSender {
+		t.Fatalf("pickEvictions() = %v, %v, want the sender rejection", reason, err)
+	}
+	if len(tp.pool) != 1 || tp.evictionQueue.Len() != 1 || len(tp.rejected) != 0 {
+		t.Errorf("pickEvictions() changes the pool: %v txs, %v queued, %v rejected", len(tp.pool), tp.evictionQueue.Len(), tp.rejected)
+	}
+	if _, ok := tp.pool[common.HashH([]byte("a"))]; !ok {
+		t.Error("tx a is evicted")
+	}
+}
@@ -0,0 +1,281 @@
+package mempool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	zkp "github.com/incognitochain/incognito-chain/privacy/zeroknowledge"
+	"github.com/incognitochain/incognito-chain/transaction"
+)
+
+// txRecordVersion is the first byte of a tx stored in the mempool database in
+// the binary encoding. The txs stored before, in json, start with their type.
+const txRecordVersion = byte(1)
+
+// flags of the tx of a record, a record without private flag is read as before
+const (
+	txRecordForwarded = byte(1)
+	txRecordPrivate   = byte(2)
+)
+
+// binary type of the tx of a record
+const (
+	txRecordNormal             = byte(0)
+	txRecordCustomTokenPrivacy = byte(1)
+)
+
+var errTxRecordTooShort = errors.New("tx record is too short")
+
+// encodeTxRecord encodes a tx and its description for the mempool database.
+// Record: version | tx type | start time | flags | height | fee | fee token |
+// fee per kb | tx
+// The integers are varints, the byte slices and the strings are prefixed with
+// their length, the proof is encoded with its binary encoding and the metadata
+// in json. A privacy token tx is its normal tx followed by its token data.
+func encodeTxRecord(txDesc *TxDesc) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(txRecordVersion)
+	tx := txDesc.Desc.Tx
+	switch tx.GetType() {
+	case common.TxNormalType:
+		buf.WriteByte(txRecordNormal)
+	case common.TxCustomTokenPrivacyType:
+		buf.WriteByte(txRecordCustomTokenPrivacy)
+	default:
+		return nil, fmt.Errorf("can not encode tx type %+v", tx.GetType())
+	}
+	putVarint(buf, txDesc.StartTime.UnixNano())
+	flags := byte(0)
+	if txDesc.IsFowardMessage {
+		flags |= txRecordForwarded
+	}
+	if txDesc.IsPrivate {
+		flags |= txRecordPrivate
+	}
+	buf.WriteByte(flags)
+	putUvarint(buf, txDesc.Desc.Height)
+	putUvarint(buf, txDesc.Desc.Fee)
+	putUvarint(buf, txDesc.Desc.FeeToken)
+	putVarint(buf, int64(txDesc.Desc.FeePerKB))
+	switch tx := tx.(type) {
+	case *transaction.Tx:
+		if err := encodeTx(buf, tx); err != nil {
+			return nil, err
+		}
+	case *transaction.TxCustomTokenPrivacy:
+		if err := encodeTx(buf, &tx.Tx); err != nil {
+			return nil, err
+		}
+		tokenData := &tx.TxPrivacyTokenData
+		if err := encodeTx(buf, &tokenData.TxNormal); err != nil {
+			return nil, err
+		}
+		buf.Write(tokenData.PropertyID[:])
+		putBytes(buf, []byte(tokenData.PropertyName))
+		putBytes(buf, []byte(tokenData.PropertySymbol))
+		putVarint(buf, int64(tokenData.Type))
+		putBool(buf, tokenData.Mintable)
+		putUvarint(buf, tokenData.Amount)
+	default:
+		return nil, fmt.Errorf("can not encode tx %+v of type %T", tx.Hash().String(), tx)
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeTx(buf *bytes.Buffer, tx *transaction.Tx) error {
+	buf.WriteByte(byte(tx.Version))
+	putBytes(buf, []byte(tx.Type))
+	putVarint(buf, tx.LockTime)
+	putUvarint(buf, tx.Fee)
+	putBytes(buf, tx.Info)
+	putBytes(buf, tx.SigPubKey)
+	putBytes(buf, tx.Sig)
+	if tx.Proof != nil {
+		putBytes(buf, tx.Proof.Bytes())
+	} else {
+		putBytes(buf, nil)
+	}
+	buf.WriteByte(tx.PubKeyLastByteSender)
+	if tx.Metadata != nil {
+		meta, err := json.Marshal(tx.Metadata)
+		if err != nil {
+			return err
+		}
+		putBytes(buf, meta)
+	} else {
+		putBytes(buf, nil)
+	}
+	return nil
+}
+
+// decodeTxRecord decodes a record of encodeTxRecord
+func decodeTxRecord(record []byte) (*TxDesc, error) {
+	reader := &txRecordReader{data: record}
+	if version := reader.byte(); version != txRecordVersion {
+		return nil, fmt.Errorf("unknown tx record version %+v", version)
+	}
+	txType := reader.byte()
+	txDesc := &TxDesc{}
+	txDesc.StartTime = time.Unix(0, reader.varint())
+	flags := reader.byte()
+	txDesc.IsFowardMessage = flags&txRecordForwarded != 0
+	txDesc.IsPrivate = flags&txRecordPrivate != 0
+	txDesc.Desc.Height = reader.uvarint()
+	txDesc.Desc.Fee = reader.uvarint()
+	txDesc.Desc.FeeToken = reader.uvarint()
+	txDesc.Desc.FeePerKB = int32(reader.varint())
+	switch txType {
+	case txRecordNormal:
+		tx := &transaction.Tx{}
+		if err := decodeTx(reader, tx); err != nil {
+			return nil, err
+		}
+		txDesc.Desc.Tx = tx
+	case txRecordCustomTokenPrivacy:
+		tx := &transaction.TxCustomTokenPrivacy{}
+		if err := decodeTx(reader, &tx.Tx); err != nil {
+			return nil, err
+		}
+		tokenData := &tx.TxPrivacyTokenData
+		if err := decodeTx(reader, &tokenData.TxNormal); err != nil {
+			return nil, err
+		}
+		copy(tokenData.PropertyID[:], reader.next(common.HashSize))
+		tokenData.PropertyName = string(reader.bytes())
+		tokenData.PropertySymbol = string(reader.bytes())
+		tokenData.Type = int(reader.varint())
+		tokenData.Mintable = reader.bool()
+		tokenData.Amount = reader.uvarint()
+		txDesc.Desc.Tx = tx
+	default:
+		return nil, fmt.Errorf("unknown tx record type %+v", txType)
+	}
+	if reader.err != nil {
+		return nil, reader.err
+	}
+	return txDesc, nil
+}
+
+func decodeTx(reader *txRecordReader, tx *transaction.Tx) error {
+	tx.Version = int8(reader.byte())
+	tx.Type = string(reader.bytes())
+	tx.LockTime = reader.varint()
+	tx.Fee = reader.uvarint()
+	tx.Info = reader.bytes()
+	tx.SigPubKey = reader.bytes()
+	tx.Sig = reader.bytes()
+	if proofBytes := reader.bytes(); len(proofBytes) > 0 {
+		tx.Proof = new(zkp.PaymentProof)
+		if err := tx.Proof.SetBytes(proofBytes); err != nil {
+			return err
+		}
+	}
+	tx.PubKeyLastByteSender = reader.byte()
+	if metaBytes := reader.bytes(); len(metaBytes) > 0 {
+		rawMeta := json.RawMessage(metaBytes)
+		meta, err := metadata.ParseMetadata(&rawMeta)
+		if err != nil {
+			return err
+		}
+		tx.SetMetadata(meta)
+	}
+	return reader.err
+}
+
+func putUvarint(buf *bytes.Buffer, value uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	buf.Write(b[:binary.PutUvarint(b, value)])
+}
+
+func putVarint(buf *bytes.Buffer, value int64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	buf.Write(b[:binary.PutVarint(b, value)])
+}
+
+func putBool(buf *bytes.Buffer, value bool) {
+	if value {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+}
+
+func putBytes(buf *bytes.Buffer, value []byte) {
+	putUvarint(buf, uint64(len(value)))
+	buf.Write(value)
+}
+
+// txRecordReader reads a record, the first error is kept and the following
+// reads return zero values
+type txRecordReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (reader *txRecordReader) next(n int) []byte {
+	if reader.err != nil || reader.offset+n > len(reader.data) {
+		if reader.err == nil {
+			reader.err = errTxRecordTooShort
+		}
+		return make([]byte, n)
+	}
+	value := reader.data[reader.offset : reader.offset+n]
+	reader.offset += n
+	return value
+}
+
+func (reader *txRecordReader) byte() byte {
+	return reader.next(1)[0]
+}
+
+func (reader *txRecordReader) bool() bool {
+	return reader.byte() != 0
+}
+
+func (reader *txRecordReader) uvarint() uint64 {
+	if reader.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(reader.data[reader.offset:])
+	if n <= 0 {
+		reader.err = errTxRecordTooShort
+		return 0
+	}
+	reader.offset += n
+	return value
+}
+
+func (reader *txRecordReader) varint() int64 {
+	if reader.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(reader.data[reader.offset:])
+	if n <= 0 {
+		reader.err = errTxRecordTooShort
+		return 0
+	}
+	reader.offset += n
+	return value
+}
+
+// bytes returns nil for an empty slice
+func (reader *txRecordReader) bytes() []byte {
+	length := reader.uvarint()
+	if length == 0 || reader.err != nil {
+		return nil
+	}
+	if length > uint64(len(reader.data)-reader.offset) {
+		reader.err = errTxRecordTooShort
+		return nil
+	}
+	value := make([]byte, length)
+	copy(value, reader.next(int(length)))
+	return value
+}
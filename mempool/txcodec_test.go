@@ -0,0 +1,110 @@
+package mempool
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+)
+
+func newTestCodecTx(seed byte) transaction.Tx {
+	return transaction.Tx{
+		Version:              1,
+		Type:                 common.TxNormalType,
+		LockTime:             1600000000 + int64(seed),
+		Fee:                  100 + uint64(seed),
+		Info:                 []byte{seed, 1},
+		SigPubKey:            []byte{seed, 2, 3},
+		Sig:                  []byte{seed, 4, 5, 6},
+		PubKeyLastByteSender: seed,
+	}
+}
+
+func TestTxRecordRoundTrip(t *testing.T) {
+	stopStaking, err := metadata.NewStopAutoStakingMetadata(metadata.StopAutoStakingMeta, "committee key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalTx := newTestCodecTx(1)
+	metadataTx := newTestCodecTx(2)
+	metadataTx.SetMetadata(stopStaking)
+	tokenTx := &transaction.TxCustomTokenPrivacy{Tx: newTestCodecTx(3)}
+	tokenTx.Tx.Type = common.TxCustomTokenPrivacyType
+	tokenTx.TxPrivacyTokenData = transaction.TxPrivacyTokenData{
+		TxNormal:       newTestCodecTx(4),
+		PropertyID:     common.HashH([]byte("token")),
+		PropertyName:   "token",
+		PropertySymbol: "TKN",
+		Type:           transaction.CustomTokenTransfer,
+		Mintable:       true,
+		Amount:         1000,
+	}
+	start := time.Unix(0, time.Now().UnixNano())
+	tests := []struct {
+		name   string
+		txDesc *TxDesc
+	}{
+		{
+			name:   "normal tx",
+			txDesc: &TxDesc{Desc: metadata.TxDesc{Tx: &normalTx, Height: 10, Fee: 101, FeePerKB: 50}, StartTime: start},
+		},
+		{
+			name:   "forwarded private tx with metadata",
+			txDesc: &TxDesc{Desc: metadata.TxDesc{Tx: &metadataTx, Height: 11, Fee: 102}, StartTime: start, IsFowardMessage: true, IsPrivate: true},
+		},
+		{
+			name:   "privacy token tx",
+			txDesc: &TxDesc{Desc: metadata.TxDesc{Tx: tokenTx, Height: 12, Fee: 103, FeeToken: 7, FeePerKB: -1}, StartTime: start},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := encodeTxRecord(tt.txDesc)
+			if err != nil {
+				t.Fatalf("encodeTxRecord() error = %v", err)
+			}
+			got, err := decodeTxRecord(record)
+			if err != nil {
+				t.Fatalf("decodeTxRecord() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.txDesc) {
+				t.Errorf("decodeTxRecord() = %+v, want %+v", got, tt.txDesc)
+			}
+			if !got.Desc.Tx.Hash().IsEqual(tt.txDesc.Desc.Tx.Hash()) {
+				t.Errorf("decoded tx hash %v, want %v", got.Desc.Tx.Hash().String(), tt.txDesc.Desc.Tx.Hash().String())
+			}
+		})
+	}
+}
+
+func TestDecodeTxRecordErrors(t *testing.T) {
+	tx := newTestCodecTx(1)
+	record, err := encodeTxRecord(&TxDesc{Desc: metadata.TxDesc{Tx: &tx}, StartTime: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherVersion := append([]byte{txRecordVersion + 1}, record[1:]...)
+	otherType := append([]byte{}, record...)
+	otherType[1] = 0xff
+	tests := []struct {
+		name   string
+		record []byte
+	}{
+		{name: "empty record", record: []byte{}},
+		// the txs stored in json before the binary encoding start with '{'
+		{name: "json record", record: []byte(`{"Version":1}`)},
+		{name: "other version", record: otherVersion},
+		{name: "unknown tx type", record: otherType},
+		{name: "truncated record", record: record[:len(record)-1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if txDesc, err := decodeTxRecord(tt.record); err == nil {
+				t.Errorf("decodeTxRecord() = %+v, want an error", txDesc)
+			}
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package mempool
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+)
+
+// defaultTxVerifyBatchTimeout is the longest wait of a tx for its batch to be
+// full
+const defaultTxVerifyBatchTimeout = 10 * time.Millisecond
+
+// TxVerificationStageStats is the latency of the transactions through a stage
+// of the verification pipeline
+type TxVerificationStageStats struct {
+	Count uint64
+	Total time.Duration
+	Max   time.Duration
+}
+
+func (stats *TxVerificationStageStats) add(latency time.Duration) {
+	stats.Count++
+	stats.Total += latency
+	if latency > stats.Max {
+		stats.Max = latency
+	}
+}
+
+// TxVerificationStats are the statistics of the verification pipeline of new
+// transactions. Queue is the wait of a tx for its batch and a worker, Verify
+// the verification of the proofs of its batch, Accept the wait for the pool and
+// the checks against the pool and the blockchain, Total all of them.
+type TxVerificationStats struct {
+	Workers     int
+	BatchSize   int
+	Batches     uint64 // batches verified by the workers
+	BatchedTxs  uint64 // txs of which the bulletproofs are verified with the ones of other txs
+	FallbackTxs uint64 // txs of a failed batch verified again one by one
+	RejectedTxs uint64 // txs rejected before being accepted to pool
+	Queue       TxVerificationStageStats
+	Verify      TxVerificationStageStats
+	Accept      TxVerificationStageStats
+	Total       TxVerificationStageStats
+}
+
+type txVerifyRequest struct {
+	tx           metadata.Transaction
+	beaconHeight int64
+	received     time.Time
+	result       chan txVerifyResult
+}
+
+type txVerifyResult struct {
+	hash   *common.Hash
+	txDesc *TxDesc
+	err    error
+}
+
+// txVerifyGroup is the key of the txs of which the proofs can be verified
+// together: against the state of the same shard, with the same version of the
+// zero knowledge proofs
+type txVerifyGroup struct {
+	shardID  byte
+	isNewZKP bool
+}
+
+// txVerifier verifies the proofs of the new txs on a pool of workers, out of
+// the lock of pool, then accepts them in pool. The txs are collected by group
+// in batches, a batch is sent to the workers once it is full or after
+// batchTimeout. The bulletproofs of the normal txs of a batch are verified
+// together, the other proofs tx by tx.
+type txVerifier struct {
+	tp           *TxPool
+	workers      int
+	batchSize    int
+	batchTimeout time.Duration
+	requests     chan *txVerifyRequest
+	batches      chan []*txVerifyRequest
+	quit         chan struct{}
+
+	stats    TxVerificationStats
+	statsMtx sync.Mutex
+}
+
+// StartTxVerifier starts the verification pipeline of new transactions, which
+// MaybeAcceptTransaction goes through until cQuit is closed
+func (tp *TxPool) StartTxVerifier(cQuit chan struct{}) {
+	workers := tp.config.TxVerifyWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	batchSize := tp.config.TxVerifyBatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	verifier := &txVerifier{
+		tp:           tp,
+		workers:      workers,
+		batchSize:    batchSize,
+		batchTimeout: defaultTxVerifyBatchTimeout,
+		requests:     make(chan *txVerifyRequest, workers*batchSize),
+		batches:      make(chan []*txVerifyRequest, workers),
+		quit:         cQuit,
+	}
+	verifier.stats.Workers = workers
+	verifier.stats.BatchSize = batchSize
+	for i := 0; i < workers; i++ {
+		go verifier.work()
+	}
+	tp.verifierMtx.Lock()
+	tp.verifier = verifier
+	tp.verifierMtx.Unlock()
+	Logger.log.Infof("Start tx verification pipeline with %+v workers, batches of %+v txs", workers, batchSize)
+	verifier.collect()
+	tp.verifierMtx.Lock()
+	tp.verifier = nil
+	tp.verifierMtx.Unlock()
+}
+
+func (tp *TxPool) getTxVerifier() *txVerifier {
+	tp.verifierMtx.RLock()
+	defer tp.verifierMtx.RUnlock()
+	return tp.verifier
+}
+
+// GetTxVerificationStats returns the statistics of the verification pipeline,
+// false if it is not started
+// This function is safe for concurrent access.
+func (tp *TxPool) GetTxVerificationStats() (TxVerificationStats, bool) {
+	verifier := tp.getTxVerifier()
+	if verifier == nil {
+		return TxVerificationStats{}, false
+	}
+	verifier.statsMtx.Lock()
+	defer verifier.statsMtx.Unlock()
+	return verifier.stats, true
+}
+
+// precheckNewTx runs the checks of a new tx which do not verify its proofs, so
+// that a tx failing them does not take a worker: the shard of the node, the tx
+// type, the duplicates in pool, the double spends with the txs of pool it does
+// not replace and the fee. They run again on acceptance, against the pool then.
+// This function is safe for concurrent access.
+func (tp *TxPool) precheckNewTx(tx metadata.Transaction, beaconHeight int64) error {
+	senderShardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	if !tp.checkRelayShard(tx) && !tp.checkPublicKeyRole(tx) {
+		return NewMempoolTxError(RejectNotRelayedShardTxError, fmt.Errorf("Unexpected Transaction From Shard %+v", senderShardID))
+	}
+	if err := checkStandaloneTxType(tx); err != nil {
+		return err
+	}
+	tp.mtx.RLock()
+	err := tp.precheckTxWithPool(tx)
+	tp.mtx.RUnlock()
+	if err != nil {
+		return err
+	}
+	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+	if !tp.checkFees(beaconView, tx, senderShardID, beaconHeight) {
+		return NewMempoolTxError(RejectInvalidFee, fmt.Errorf("Transaction %+v has invalid fees.", tx.Hash().String()))
+	}
+	return nil
+}
+
+// precheckTxWithPool is the part of precheckNewTx reading the pool
+// This function MUST be called with the pool locked
+func (tp *TxPool) precheckTxWithPool(tx metadata.Transaction) error {
+	if tp.isTxInPool(tx.Hash()) {
+		return NewMempoolTxError(RejectDuplicateTx, fmt.Errorf("already had transaction %+v in mempool", tx.Hash().String()))
+	}
+	if err := tx.ValidateTxWithCurrentMempool(tp); err != nil {
+		replaceErr, isReplacedTx := tp.validateTransactionReplacement(tx, true)
+		if !isReplacedTx {
+			return NewMempoolTxError(RejectDoubleSpendWithMempoolTx, err)
+		}
+		return replaceErr
+	}
+	return nil
+}
+
+// verifyAndAccept prechecks tx, then sends it to the pipeline and waits until
+// it is accepted or rejected
+func (verifier *txVerifier) verifyAndAccept(tx metadata.Transaction, beaconHeight int64) (*common.Hash, *TxDesc, error) {
+	if err := verifier.tp.precheckNewTx(tx, beaconHeight); err != nil {
+		Logger.log.Error(err)
+		verifier.statsMtx.Lock()
+		verifier.stats.RejectedTxs++
+		verifier.statsMtx.Unlock()
+		return &common.Hash{}, &TxDesc{}, err
+	}
+	request := &txVerifyRequest{
+		tx:           tx,
+		beaconHeight: beaconHeight,
+		received:     time.Now(),
+		result:       make(chan txVerifyResult, 1),
+	}
+	stoppedErr := NewMempoolTxError(TxVerifierStoppedError, fmt.Errorf("can not verify tx %+v", tx.Hash().String()))
+	select {
+	case verifier.requests <- request:
+	case <-verifier.quit:
+		return &common.Hash{}, &TxDesc{}, stoppedErr
+	}
+	select {
+	case result := <-request.result:
+		return result.hash, result.txDesc, result.err
+	case <-verifier.quit:
+		return &common.Hash{}, &TxDesc{}, stoppedErr
+	}
+}
+
+// collect groups the requests in batches
+func (verifier *txVerifier) collect() {
+	pending := make(map[txVerifyGroup][]*txVerifyRequest)
+	ticker := time.NewTicker(verifier.batchTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-verifier.quit:
+			return
+		case request := <-verifier.requests:
+			group := txVerifyGroup{
+				shardID:  common.GetShardIDFromLastByte(request.tx.GetSenderAddrLastByte()),
+				isNewZKP: verifier.tp.config.BlockChain.IsAfterNewZKPCheckPoint(uint64(request.beaconHeight)),
+			}
+			pending[group] = append(pending[group], request)
+			if len(pending[group]) >= verifier.batchSize {
+				verifier.send(pending[group])
+				delete(pending, group)
+			}
+		case <-ticker.C:
+			for group, batch := range pending {
+				verifier.send(batch)
+				delete(pending, group)
+			}
+		}
+	}
+}
+
+func (verifier *txVerifier) send(batch []*txVerifyRequest) {
+	select {
+	case verifier.batches <- batch:
+	case <-verifier.quit:
+	}
+}
+
+func (verifier *txVerifier) work() {
+	for {
+		select {
+		case <-verifier.quit:
+			return
+		case batch := <-verifier.batches:
+			verifier.verifyBatch(batch)
+		}
+	}
+}
+
+// newTxsVerification is the result of the verification of the proofs of new txs
+type newTxsVerification struct {
+	errs           []error
+	isVerified     []bool // false for the txs of which the proofs are verified on acceptance
+	isHoldVerified []bool // true for the txs spending unconfirmed outputs, verified but the commitments of these outputs
+	batched        int    // txs of which the bulletproofs are verified together
+	fallback       int    // txs of a failed batch verified again one by one
+}
+
+// verifyNewTxs verifies the proofs of new txs of the same group, prechecked by
+// precheckNewTx, out of the lock of pool. The bulletproofs of the normal txs are
+// verified together, the other proofs tx by tx. The txs spending outputs of
+// unconfirmed txs, of pool, held or before them in txs, are held until their
+// parents are in a block: they are verified but the commitments of these
+// outputs, which are checked on release.
+func (tp *TxPool) verifyNewTxs(txs []metadata.Transaction, beaconHeights []int64) newTxsVerification {
+	shardID := common.GetShardIDFromLastByte(txs[0].GetSenderAddrLastByte())
+	beaconView := tp.config.BlockChain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+	shardView := tp.config.BlockChain.ShardChain[shardID].GetBestView().(*blockchain.ShardBestState)
+
+	result := newTxsVerification{
+		errs:           make([]error, len(txs)),
+		isVerified:     make([]bool, len(txs)),
+		isHoldVerified: make([]bool, len(txs)),
+	}
+	outputs := make(map[common.Hash]struct{})
+	normalTxs := []metadata.Transaction{}
+	normalIndexes := []int{}
+	for i, tx := range txs {
+		spendsOutputs := false
+		for _, sndHash := range listSNDInputsHashH(tx) {
+			if _, ok := outputs[sndHash]; ok {
+				spendsOutputs = true
+			}
+		}
+		for _, sndHash := range tx.ListSNDOutputsHashH() {
+			outputs[sndHash] = struct{}{}
+		}
+		// do not verify again the proofs of a tx received from several peers
+		if tp.HaveTransaction(tx.Hash()) {
+			result.errs[i] = NewMempoolTxError(RejectDuplicateTx, fmt.Errorf("already had transaction %+v in mempool", tx.Hash().String()))
+			continue
+		}
+		if result.errs[i] = tp.checkSanity(shardView, beaconView, tx, beaconHeights[i], true); result.errs[i] != nil {
+			continue
+		}
+		if spendsOutputs || tp.hasUnconfirmedParents(tx) {
+			result.isHoldVerified[i] = true
+			result.errs[i] = tp.checkTxByItself(shardView, beaconView, tx, beaconHeights[i], true, true)
+			continue
+		}
+		result.isVerified[i] = true
+		// only the proof of PRV of normal txs can be verified in a batch
+		if _, ok := tx.(*transaction.Tx); ok {
+			normalTxs = append(normalTxs, tx)
+			normalIndexes = append(normalIndexes, i)
+		} else {
+			result.errs[i] = tp.checkTxByItself(shardView, beaconView, tx, beaconHeights[i], true, false)
+		}
+	}
+	validateBatch := func() (bool, error) {
+		boolParams := make(map[string]bool)
+		boolParams["isNewTransaction"] = true
+		boolParams["isBatch"] = true
+		boolParams["isNewZKP"] = tp.config.BlockChain.IsAfterNewZKPCheckPoint(uint64(beaconHeights[0]))
+		ok, err, _ := transaction.NewBatchTransaction(normalTxs).Validate(shardView.GetCopiedTransactionStateDB(), beaconView.GetBeaconFeatureStateDB(), boolParams)
+		return ok, err
+	}
+	validateTx := func(i int) error {
+		return tp.checkTxByItself(shardView, beaconView, txs[i], beaconHeights[i], true, false)
+	}
+	result.batched, result.fallback = verifyNormalTxProofs(normalIndexes, result.errs, validateBatch, validateTx)
+	return result
+}
+
+// verifyNormalTxProofs verifies the proofs of the normal txs at indexes with
+// validateBatch if there are several, then one by one with validateTx if the
+// batch fails, so that only the invalid txs are rejected. The errors of the txs
+// are set in errs, it returns the numbers of batched and fallback txs.
+func verifyNormalTxProofs(indexes []int, errs []error, validateBatch func() (bool, error), validateTx func(i int) error) (int, int) {
+	if len(indexes) > 1 {
+		ok, err := validateBatch()
+		if ok && err == nil {
+			return len(indexes), 0
+		}
+		Logger.log.Debugf("Verify batch of %+v txs failed %+v, verify them one by one", len(indexes), err)
+		for _, i := range indexes {
+			errs[i] = validateTx(i)
+		}
+		return len(indexes), len(indexes)
+	}
+	for _, i := range indexes {
+		errs[i] = validateTx(i)
+	}
+	return 0, 0
+}
+
+// verifyBatch verifies the txs of a batch of the same group, then accepts the
+// valid ones in pool
+func (verifier *txVerifier) verifyBatch(batch []*txVerifyRequest) {
+	tp := verifier.tp
+	start := time.Now()
+	txs := make([]metadata.Transaction, len(batch))
+	beaconHeights := make([]int64, len(batch))
+	for i, request := range batch {
+		txs[i] = request.tx
+		beaconHeights[i] = request.beaconHeight
+	}
+	verification := tp.verifyNewTxs(txs, beaconHeights)
+	errs := verification.errs
+	verified := time.Now()
+
+	for i, request := range batch {
+		result := txVerifyResult{hash: &common.Hash{}, txDesc: &TxDesc{}, err: errs[i]}
+		var acceptLatency time.Duration
+		if errs[i] == nil {
+			acceptStart := time.Now()
+			tp.mtx.Lock()
+			result.hash, result.txDesc, result.err = tp.maybeAcceptNewTransaction(request.tx, request.beaconHeight, verification.isVerified[i], verification.isHoldVerified[i])
+			tp.mtx.Unlock()
+			acceptLatency = time.Since(acceptStart)
+		} else {
+			Logger.log.Error(errs[i])
+		}
+		verifier.statsMtx.Lock()
+		verifier.stats.Queue.add(start.Sub(request.received))
+		verifier.stats.Verify.add(verified.Sub(start))
+		if errs[i] == nil {
+			verifier.stats.Accept.add(acceptLatency)
+		}
+		if result.err != nil {
+			verifier.stats.RejectedTxs++
+		}
+		verifier.stats.Total.add(time.Since(request.received))
+		verifier.statsMtx.Unlock()
+		request.result <- result
+	}
+	verifier.statsMtx.Lock()
+	verifier.stats.Batches++
+	verifier.stats.BatchedTxs += uint64(verification.batched)
+	verifier.stats.FallbackTxs += uint64(verification.fallback)
+	verifier.statsMtx.Unlock()
+}
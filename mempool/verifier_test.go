@@ -0,0 +1,275 @@
+package mempool
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/metadata/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = func() (_ struct{}) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	return
+}()
+
+type testConsensusEngine struct {
+	committeeShards []byte
+}
+
+func (engine *testConsensusEngine) IsCommitteeInShard(shardID byte) bool {
+	return common.IndexOfByte(shardID, engine.committeeShards) > -1
+}
+
+type testPrecheckTx struct {
+	name          string
+	shardID       byte
+	txType        string
+	fee           uint64
+	serialNumbers []common.Hash
+	doubleSpend   bool // spends an input of a tx of pool
+}
+
+func newTestPrecheckTx(tx testPrecheckTx) *mocks.Transaction {
+	hash := common.HashH([]byte(tx.name))
+	txType := tx.txType
+	if txType == "" {
+		txType = common.TxNormalType
+	}
+	mockTx := &mocks.Transaction{}
+	mockTx.On("Hash").Return(&hash)
+	mockTx.On("GetSenderAddrLastByte").Return(tx.shardID)
+	mockTx.On("GetType").Return(txType)
+	mockTx.On("GetTxFee").Return(tx.fee)
+	mockTx.On("GetTxFeeToken").Return(uint64(0))
+	mockTx.On("ListSerialNumbersHashH").Return(tx.serialNumbers)
+	if tx.doubleSpend {
+		mockTx.On("ValidateTxWithCurrentMempool", mock.Anything).Return(errors.New("double spend"))
+	} else {
+		mockTx.On("ValidateTxWithCurrentMempool", mock.Anything).Return(nil)
+	}
+	return mockTx
+}
+
+// the txs failing the cheap checks are rejected before their proofs are
+// verified, they never reach the workers
+func TestTxVerifier_verifyAndAcceptPrecheck(t *testing.T) {
+	serialNumbers := []common.Hash{common.HashH([]byte("serial number"))}
+	tests := []struct {
+		name    string
+		tx      testPrecheckTx
+		wantErr int
+	}{
+		{
+			name:    "shard neither relayed nor validated",
+			tx:      testPrecheckTx{name: "new", shardID: 2},
+			wantErr: RejectNotRelayedShardTxError,
+		},
+		{
+			name:    "return staking tx",
+			tx:      testPrecheckTx{name: "new", shardID: 0, txType: common.TxReturnStakingType},
+			wantErr: RejectInvalidTx,
+		},
+		{
+			name:    "tx of pool",
+			tx:      testPrecheckTx{name: "pool", shardID: 1},
+			wantErr: RejectDuplicateTx,
+		},
+		{
+			name:    "double spend with pool",
+			tx:      testPrecheckTx{name: "new", shardID: 0, serialNumbers: []common.Hash{common.HashH([]byte("other"))}, doubleSpend: true},
+			wantErr: RejectDoubleSpendWithMempoolTx,
+		},
+		{
+			name:    "replacement paying too low a fee",
+			tx:      testPrecheckTx{name: "new", shardID: 0, fee: 105, serialNumbers: serialNumbers, doubleSpend: true},
+			wantErr: RejectReplacementTxError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := newTestEvictionPool(Config{
+				MaxTx:           10,
+				RelayShards:     []byte{0},
+				ConsensusEngine: &testConsensusEngine{committeeShards: []byte{1}},
+			})
+			tp.ReplaceFeeRatio = defaultReplaceFeeRatio
+			tp.poolSerialNumberHash = make(map[common.Hash]common.Hash)
+			poolTx := newTestPrecheckTx(testPrecheckTx{name: "pool", shardID: 1, fee: 100, serialNumbers: serialNumbers})
+			tp.pool[*poolTx.Hash()] = &TxDesc{Desc: metadata.TxDesc{Tx: poolTx, Fee: 100}}
+			tp.poolSerialNumberHash[common.HashArrayOfHashArray(serialNumbers)] = *poolTx.Hash()
+
+			verifier := &txVerifier{tp: tp, requests: make(chan *txVerifyRequest, 1), quit: make(chan struct{})}
+			_, _, err := verifier.verifyAndAccept(newTestPrecheckTx(tt.tx), 0)
+			mempoolErr, ok := err.(*MempoolTxError)
+			if !ok || mempoolErr.Code != ErrCodeMessage[tt.wantErr].Code {
+				t.Fatalf("verifyAndAccept() error = %v, want code %v", err, ErrCodeMessage[tt.wantErr].Code)
+			}
+			if len(verifier.requests) != 0 {
+				t.Error("tx failing the prechecks is sent to the workers")
+			}
+			if verifier.stats.RejectedTxs != 1 {
+				t.Errorf("%v rejected txs, want 1", verifier.stats.RejectedTxs)
+			}
+		})
+	}
+}
+
+func Test_verifyNormalTxProofs(t *testing.T) {
+	invalid := errors.New("invalid proof")
+	tests := []struct {
+		name         string
+		indexes      []int
+		invalidTxs   []int
+		batchErr     error
+		wantErrs     []error
+		wantBatchRun bool
+		wantTxRuns   []int
+		wantBatched  int
+		wantFallback int
+	}{
+		{
+			name:       "single tx is verified by itself",
+			indexes:    []int{1},
+			wantErrs:   []error{nil, nil, nil},
+			wantTxRuns: []int{1},
+		},
+		{
+			name:         "valid batch",
+			indexes:      []int{0, 2},
+			wantErrs:     []error{nil, nil, nil},
+			wantBatchRun: true,
+			wantTxRuns:   []int{},
+			wantBatched:  2,
+		},
+		{
+			name:         "failed batch rejects the invalid txs only",
+			indexes:      []int{0, 1, 2},
+			invalidTxs:   []int{1},
+			wantErrs:     []error{nil, invalid, nil},
+			wantBatchRun: true,
+			wantTxRuns:   []int{0, 1, 2},
+			wantBatched:  3,
+			wantFallback: 3,
+		},
+		{
+			name:         "batch error falls back to the txs",
+			indexes:      []int{0, 2},
+			batchErr:     errors.New("batch error"),
+			wantErrs:     []error{nil, nil, nil},
+			wantBatchRun: true,
+			wantTxRuns:   []int{0, 2},
+			wantBatched:  2,
+			wantFallback: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := make([]error, 3)
+			batchRun := false
+			txRuns := []int{}
+			validateBatch := func() (bool, error) {
+				batchRun = true
+				return len(tt.invalidTxs) == 0 && tt.batchErr == nil, tt.batchErr
+			}
+			validateTx := func(i int) error {
+				txRuns = append(txRuns, i)
+				for _, invalidTx := range tt.invalidTxs {
+					if invalidTx == i {
+						return invalid
+					}
+				}
+				return nil
+			}
+			batched, fallback := verifyNormalTxProofs(tt.indexes, errs, validateBatch, validateTx)
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("errs = %v, want %v", errs, tt.wantErrs)
+			}
+			if batchRun != tt.wantBatchRun || !reflect.DeepEqual(txRuns, tt.wantTxRuns) {
+				t.Errorf("batch verified %v, txs verified %v, want %v, %v", batchRun, txRuns, tt.wantBatchRun, tt.wantTxRuns)
+			}
+			if batched != tt.wantBatched || fallback != tt.wantFallback {
+				t.Errorf("verifyNormalTxProofs() = %v, %v, want %v, %v", batched, fallback, tt.wantBatched, tt.wantFallback)
+			}
+		})
+	}
+}
+
+// a tx rejected by any step of a batch does not reject the others
+func Test_acceptNewTxBatch(t *testing.T) {
+	prechecked, verified, accepted := errors.New("precheck"), errors.New("verify"), errors.New("accept")
+	txs := []metadata.Transaction{}
+	names := map[common.Hash]string{}
+	for _, tx := range []testPrecheckTx{
+		{name: "a", shardID: 0},
+		{name: "b", shardID: 1},
+		{name: "c", shardID: 0},
+		{name: "d", shardID: 1},
+		{name: "e", shardID: 0},
+	} {
+		mockTx := newTestPrecheckTx(tx)
+		txs = append(txs, mockTx)
+		names[*mockTx.Hash()] = tx.name
+	}
+	nameOf := func(tx metadata.Transaction) string { return names[*tx.Hash()] }
+
+	var mtx sync.Mutex
+	groups := [][]string{}
+	acceptedTxs := []string{}
+	errs := acceptNewTxBatch(txs,
+		func(tx metadata.Transaction) error {
+			if nameOf(tx) == "b" {
+				return prechecked
+			}
+			return nil
+		},
+		func(groupTxs []metadata.Transaction) newTxsVerification {
+			verification := newTxsVerification{
+				errs:           make([]error, len(groupTxs)),
+				isVerified:     make([]bool, len(groupTxs)),
+				isHoldVerified: make([]bool, len(groupTxs)),
+			}
+			group := []string{}
+			for i, tx := range groupTxs {
+				group = append(group, nameOf(tx))
+				switch nameOf(tx) {
+				case "c":
+					verification.errs[i] = verified
+				case "e":
+					verification.isHoldVerified[i] = true
+				default:
+					verification.isVerified[i] = true
+				}
+			}
+			mtx.Lock()
+			groups = append(groups, group)
+			mtx.Unlock()
+			return verification
+		},
+		func(tx metadata.Transaction, isVerified bool, isHoldVerified bool) error {
+			if isVerified == isHoldVerified {
+				t.Errorf("tx %v accepted with isVerified %v, isHoldVerified %v", nameOf(tx), isVerified, isHoldVerified)
+			}
+			acceptedTxs = append(acceptedTxs, nameOf(tx))
+			if nameOf(tx) == "d" {
+				return accepted
+			}
+			return nil
+		},
+	)
+	if want := []error{nil, prechecked, verified, accepted, nil}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("acceptNewTxBatch() = %v, want %v", errs, want)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	if want := [][]string{{"a", "c", "e"}, {"d"}}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("verified groups %v, want %v", groups, want)
+	}
+	if want := []string{"a", "d", "e"}; !reflect.DeepEqual(acceptedTxs, want) {
+		t.Errorf("accepted txs %v, want %v in order", acceptedTxs, want)
+	}
+}
@@ -50,6 +50,7 @@ const (
 
 	// eth utils
 	VerifyProofAndParseReceiptError
+	ParseETHLogDataError
 
 	// portal v3
 	PortalCustodianDepositV3ValidateWithBCError
@@ -109,6 +110,7 @@ var ErrCodeMessage = map[int]struct {
 
 	// eth utils
 	VerifyProofAndParseReceiptError: {-8001, "Verify proof and parse receipt eth error"},
+	ParseETHLogDataError:            {-8002, "Parse log data of eth receipt error"},
 
 	// portal v3
 	PortalCustodianDepositV3ValidateWithBCError:     {-9001, "Validate with blockchain tx portal custodian deposit v3 error"},
@@ -129,9 +131,13 @@ func (e MetadataTxError) Error() string {
 }
 
 func NewMetadataTxError(key int, err error, params ...interface{}) *MetadataTxError {
-	return &MetadataTxError{
+	e := &MetadataTxError{
 		Code:    ErrCodeMessage[key].Code,
-		Message: fmt.Sprintf(ErrCodeMessage[key].Message, params),
+		Message: ErrCodeMessage[key].Message,
 		Err:     errors.Wrap(err, ErrCodeMessage[key].Message),
 	}
+	if len(params) > 0 {
+		e.Message = fmt.Sprintf(ErrCodeMessage[key].Message, params)
+	}
+	return e
 }
@@ -101,7 +101,7 @@ func ParseETHLogDataByEventName(data []byte, name string) (map[string]interface{
 	}
 	dataMap := map[string]interface{}{}
 	if err = abiIns.UnpackIntoMap(dataMap, name, data); err != nil {
-		return nil, NewMetadataTxError(UnexpectedError, err)
+		return nil, NewMetadataTxError(ParseETHLogDataError, err)
 	}
 	return dataMap, nil
 }
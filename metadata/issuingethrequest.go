@@ -266,7 +266,7 @@ func ParseETHLogData(data []byte) (map[string]interface{}, error) {
 	}
 	dataMap := map[string]interface{}{}
 	if err = abiIns.UnpackIntoMap(dataMap, "Deposit", data); err != nil {
-		return nil, NewMetadataTxError(UnexpectedError, err)
+		return nil, NewMetadataTxError(ParseETHLogDataError, err)
 	}
 	return dataMap, nil
 }
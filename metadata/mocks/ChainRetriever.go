@@ -18,6 +18,20 @@ type ChainRetriever struct {
 	mock.Mock
 }
 
+// GetBCHeightBreakPointPortalV3 provides a mock function with given fields:
+func (_m *ChainRetriever) GetBCHeightBreakPointPortalV3() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
 // GetBNBChainID provides a mock function with given fields:
 func (_m *ChainRetriever) GetBNBChainID() string {
 	ret := _m.Called()
@@ -104,6 +118,20 @@ func (_m *ChainRetriever) GetCentralizedWebsitePaymentAddress(_a0 uint64) string
 	return r0
 }
 
+// GetETHRemoveBridgeSigEpoch provides a mock function with given fields:
+func (_m *ChainRetriever) GetETHRemoveBridgeSigEpoch() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
 // GetFixedRandomForShardIDCommitment provides a mock function with given fields: beaconHeight
 func (_m *ChainRetriever) GetFixedRandomForShardIDCommitment(beaconHeight uint64) *privacy.Scalar {
 	ret := _m.Called(beaconHeight)
@@ -120,6 +148,20 @@ func (_m *ChainRetriever) GetFixedRandomForShardIDCommitment(beaconHeight uint64
 	return r0
 }
 
+// GetPortalETHContractAddrStr provides a mock function with given fields:
+func (_m *ChainRetriever) GetPortalETHContractAddrStr() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // GetPortalFeederAddress provides a mock function with given fields:
 func (_m *ChainRetriever) GetPortalFeederAddress() string {
 	ret := _m.Called()
@@ -148,6 +190,22 @@ func (_m *ChainRetriever) GetStakingAmountShard() uint64 {
 	return r0
 }
 
+// GetSupportedCollateralTokenIDs provides a mock function with given fields: beaconHeight
+func (_m *ChainRetriever) GetSupportedCollateralTokenIDs(beaconHeight uint64) []string {
+	ret := _m.Called(beaconHeight)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(uint64) []string); ok {
+		r0 = rf(beaconHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
 // GetTransactionByHash provides a mock function with given fields: _a0
 func (_m *ChainRetriever) GetTransactionByHash(_a0 common.Hash) (byte, common.Hash, uint64, int, metadata.Transaction, error) {
 	ret := _m.Called(_a0)
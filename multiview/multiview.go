@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/pubsub"
 	"time"
 )
 
@@ -24,6 +25,29 @@ type MultiView struct {
 	//state
 	finalView View
 	bestView  View
+
+	//reorg notification
+	chainID   int
+	publisher Publisher
+}
+
+// Publisher publishes the ChainReorg events of a multiview
+type Publisher interface {
+	PublishMessage(message *pubsub.Message)
+}
+
+// ChainReorg is published to the ChainReorgTopic when the best view moves to a
+// view which does not extend the previous best view. The views between the
+// common ancestor and the previous best view leave the best chain, the views
+// between the common ancestor and the new best view join it.
+type ChainReorg struct {
+	ChainID         int
+	CommonAncestor  common.Hash
+	AncestorHeight  uint64
+	RemovedHashes   []common.Hash // from the previous best view down to the ancestor
+	AddedHashes     []common.Hash // from the ancestor up to the new best view
+	FinalViewHash   common.Hash
+	FinalViewHeight uint64
 }
 
 func NewMultiView() *MultiView {
@@ -51,6 +75,18 @@ func NewMultiView() *MultiView {
 
 }
 
+// SetPublisher publishes the reorgs of the multiview of chain chainID, -1 for
+// beacon, to publisher
+func (multiView *MultiView) SetPublisher(chainID int, publisher Publisher) {
+	done := make(chan struct{})
+	multiView.actionCh <- func() {
+		multiView.chainID = chainID
+		multiView.publisher = publisher
+		close(done)
+	}
+	<-done
+}
+
 func (multiView *MultiView) Reset() {
 	multiView.viewByHash = make(map[common.Hash]View)
 	multiView.viewByPrevHash = make(map[common.Hash][]View)
@@ -125,6 +161,7 @@ func (multiView *MultiView) updateViewState(newView View) {
 		return
 	}
 
+	prevBestView := multiView.bestView
 	//update bestView
 	if newView.GetHeight() > multiView.bestView.GetHeight() {
 		multiView.bestView = newView
@@ -135,6 +172,11 @@ func (multiView *MultiView) updateViewState(newView View) {
 		multiView.bestView = newView
 	}
 
+	if reorg := multiView.getReorg(prevBestView, multiView.bestView); reorg != nil {
+		//publish once the final view is updated
+		defer multiView.publishReorg(reorg)
+	}
+
 	if newView.GetBlock().GetVersion() == 1 {
 		//update finalView: consensus 1
 		prev1Hash := multiView.bestView.GetPreviousHash()
@@ -166,6 +208,50 @@ func (multiView *MultiView) updateViewState(newView View) {
 	return
 }
 
+// getReorg returns the reorg from the best view prevBest to bestView, nil if
+// bestView extends prevBest or if their common ancestor is not a known view,
+// e.g. after Reset
+func (multiView *MultiView) getReorg(prevBest View, bestView View) *ChainReorg {
+	if *prevBest.GetHash() == *bestView.GetHash() || *bestView.GetPreviousHash() == *prevBest.GetHash() {
+		return nil
+	}
+	if multiView.viewByHash[*prevBest.GetHash()] == nil {
+		return nil
+	}
+	reorg := &ChainReorg{ChainID: multiView.chainID}
+	added := []common.Hash{}
+	removedView, addedView := prevBest, bestView
+	for addedView != nil && removedView != nil && addedView.GetHeight() > removedView.GetHeight() {
+		added = append(added, *addedView.GetHash())
+		addedView = multiView.viewByHash[*addedView.GetPreviousHash()]
+	}
+	for addedView != nil && removedView != nil && removedView.GetHeight() > addedView.GetHeight() {
+		reorg.RemovedHashes = append(reorg.RemovedHashes, *removedView.GetHash())
+		removedView = multiView.viewByHash[*removedView.GetPreviousHash()]
+	}
+	for addedView != nil && removedView != nil && *addedView.GetHash() != *removedView.GetHash() {
+		added = append(added, *addedView.GetHash())
+		reorg.RemovedHashes = append(reorg.RemovedHashes, *removedView.GetHash())
+		addedView = multiView.viewByHash[*addedView.GetPreviousHash()]
+		removedView = multiView.viewByHash[*removedView.GetPreviousHash()]
+	}
+	if addedView == nil || removedView == nil || len(reorg.RemovedHashes) == 0 {
+		return nil
+	}
+	reorg.CommonAncestor, reorg.AncestorHeight = *addedView.GetHash(), addedView.GetHeight()
+	for i := len(added) - 1; i >= 0; i-- {
+		reorg.AddedHashes = append(reorg.AddedHashes, added[i])
+	}
+	return reorg
+}
+
+func (multiView *MultiView) publishReorg(reorg *ChainReorg) {
+	reorg.FinalViewHash, reorg.FinalViewHeight = *multiView.finalView.GetHash(), multiView.finalView.GetHeight()
+	if multiView.publisher != nil {
+		multiView.publisher.PublishMessage(pubsub.NewMessage(pubsub.ChainReorgTopic, reorg))
+	}
+}
+
 func (multiView *MultiView) GetAllViewsWithBFS() []View {
 	queue := []View{multiView.finalView}
 	resCh := make(chan []View)
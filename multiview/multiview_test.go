@@ -3,6 +3,9 @@ package multiview
 import (
 	"fmt"
 	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/pubsub"
+	"reflect"
 	"testing"
 )
 
@@ -34,6 +37,46 @@ func (s *FakeView) GetBlockTime() int64 {
 	return s.time
 }
 
+func (s *FakeView) GetCommittee() []incognitokey.CommitteePublicKey {
+	return nil
+}
+
+func (s *FakeView) GetProposerByTimeSlot(ts int64, version int) (incognitokey.CommitteePublicKey, int) {
+	return incognitokey.CommitteePublicKey{}, 0
+}
+
+func (s *FakeView) GetBlock() common.BlockInterface {
+	return &FakeBlock{s}
+}
+
+// FakeBlock is a consensus 1 block of a FakeView
+type FakeBlock struct {
+	view *FakeView
+}
+
+func (b *FakeBlock) GetVersion() int             { return 1 }
+func (b *FakeBlock) GetHeight() uint64           { return b.view.height }
+func (b *FakeBlock) Hash() *common.Hash          { return b.view.hash }
+func (b *FakeBlock) GetProducer() string         { return "" }
+func (b *FakeBlock) GetValidationField() string  { return "" }
+func (b *FakeBlock) GetRound() int               { return 1 }
+func (b *FakeBlock) GetRoundKey() string         { return "" }
+func (b *FakeBlock) GetInstructions() [][]string { return nil }
+func (b *FakeBlock) GetConsensusType() string    { return "" }
+func (b *FakeBlock) GetCurrentEpoch() uint64     { return 1 }
+func (b *FakeBlock) GetProduceTime() int64       { return b.view.time }
+func (b *FakeBlock) GetProposeTime() int64       { return b.view.time }
+func (b *FakeBlock) GetPrevHash() common.Hash    { return *b.view.prevHash }
+func (b *FakeBlock) GetProposer() string         { return "" }
+
+type fakePublisher struct {
+	messages []*pubsub.Message
+}
+
+func (p *fakePublisher) PublishMessage(message *pubsub.Message) {
+	p.messages = append(p.messages, message)
+}
+
 func TestNewMultiView(t *testing.T) {
 
 	multiView := NewMultiView()
@@ -94,3 +137,53 @@ func TestNewMultiView(t *testing.T) {
 		panic("Wrong")
 	}
 }
+
+func TestMultiView_ChainReorg(t *testing.T) {
+	multiView := NewMultiView()
+	publisher := &fakePublisher{}
+	multiView.SetPublisher(0, publisher)
+	views := []*FakeView{
+		{&common.Hash{1}, &common.Hash{0}, 1, 1, 1},
+		{&common.Hash{2}, &common.Hash{1}, 2, 2, 2},
+		{&common.Hash{3}, &common.Hash{2}, 3, 3, 3},
+		{&common.Hash{4, 'a'}, &common.Hash{3}, 4, 4, 4},
+	}
+	for _, view := range views {
+		multiView.AddView(view)
+	}
+	if len(publisher.messages) != 0 {
+		t.Fatalf("want no reorg, got %v", len(publisher.messages))
+	}
+
+	// a sibling produced earlier becomes the best view
+	multiView.AddView(&FakeView{&common.Hash{4, 'b'}, &common.Hash{3}, 4, 4, 3})
+	// the first branch grows higher
+	multiView.AddView(&FakeView{&common.Hash{5, 'a'}, &common.Hash{4, 'a'}, 5, 5, 5})
+
+	want := []*ChainReorg{
+		{
+			CommonAncestor:  common.Hash{3},
+			AncestorHeight:  3,
+			RemovedHashes:   []common.Hash{{4, 'a'}},
+			AddedHashes:     []common.Hash{{4, 'b'}},
+			FinalViewHash:   common.Hash{3},
+			FinalViewHeight: 3,
+		},
+		{
+			CommonAncestor:  common.Hash{3},
+			AncestorHeight:  3,
+			RemovedHashes:   []common.Hash{{4, 'b'}},
+			AddedHashes:     []common.Hash{{4, 'a'}, {5, 'a'}},
+			FinalViewHash:   common.Hash{4, 'a'},
+			FinalViewHeight: 4,
+		},
+	}
+	if len(publisher.messages) != len(want) {
+		t.Fatalf("want %v reorgs, got %v", len(want), len(publisher.messages))
+	}
+	for i, message := range publisher.messages {
+		if !reflect.DeepEqual(message.Value, want[i]) {
+			t.Fatalf("reorg %v: want %+v, got %+v", i, want[i], message.Value)
+		}
+	}
+}
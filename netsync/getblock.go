@@ -380,3 +380,11 @@ func (netSync *NetSync) streamBlkByHash(
 	close(blkCh)
 	return
 }
+
+func (netSync *NetSync) GetStateSyncView(chainID int, fromBeaconHeight uint64) (*blockchain.StateSyncView, error) {
+	return netSync.config.BlockChain.GetStateSyncView(chainID, fromBeaconHeight)
+}
+
+func (netSync *NetSync) GetTrieNode(chainID int, hash common.Hash) ([]byte, error) {
+	return netSync.config.BlockChain.GetTrieNode(chainID, hash)
+}
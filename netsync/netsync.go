@@ -11,6 +11,7 @@ import (
 	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/peer"
 	"github.com/incognitochain/incognito-chain/pubsub"
 	"github.com/incognitochain/incognito-chain/wire"
@@ -53,6 +54,7 @@ type NetSyncConfig struct {
 		// list functions callback which are assigned from Server struct
 		PushMessageToPeer(wire.Message, libp2p.ID) error
 		PushMessageToAll(wire.Message) error
+		PushMessageToShardCommittee(wire.Message, byte) error
 	}
 	Consensus interface {
 		OnBFTMsg(*wire.MessageBFT)
@@ -80,7 +82,7 @@ func (netSync *NetSync) Init(cfg *NetSyncConfig) {
 	}
 
 	// register pubsub channel
-	_, subChanTx, err := netSync.config.PubSubManager.RegisterNewSubscriber(pubsub.TransactionHashEnterNodeTopic)
+	_, subChanTx, err := netSync.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.TransactionHashEnterNodeTopic, pubsub.InternalSubscribeOptions)
 	if err != nil {
 		Logger.log.Error(err)
 	}
@@ -90,12 +92,12 @@ func (netSync *NetSync) Init(cfg *NetSyncConfig) {
 	// 	Logger.log.Error(err)
 	// }
 	// netSync.config.RoleInCommitteesEvent = subChanRole
-	_, subChanBeaconBlock, err := netSync.config.PubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+	_, subChanBeaconBlock, err := netSync.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, pubsub.InternalSubscribeOptions)
 	if err != nil {
 		Logger.log.Error(err)
 	}
 	netSync.config.BeaconBlockEvent = subChanBeaconBlock
-	_, subChanShardBlock, err := netSync.config.PubSubManager.RegisterNewSubscriber(pubsub.NewShardblockTopic)
+	_, subChanShardBlock, err := netSync.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewShardblockTopic, pubsub.InternalSubscribeOptions)
 	if err != nil {
 		Logger.log.Error(err)
 	}
@@ -276,12 +278,7 @@ func (netSync *NetSync) handleMessageTx(msg *wire.MessageTx, beaconHeight int64)
 				metrics.TagValue:         msg.Transaction.Hash().String(),
 			})*/
 			Logger.log.Debugf("there is hash of transaction %s", hash.String())
-			err := netSync.config.Server.PushMessageToAll(msg)
-			if err != nil {
-				Logger.log.Error(err)
-			} else {
-				netSync.config.TxMemPool.MarkForwardedTransaction(*msg.Transaction.Hash())
-			}
+			netSync.relayTxMessage(msg, msg.Transaction, msg.IsPrivate)
 		}
 	}
 	Logger.log.Debug("Transaction %+v found in cache", *msg.Transaction.Hash())
@@ -300,17 +297,30 @@ func (netSync *NetSync) handleMessageTxPrivacyToken(msg *wire.MessageTxPrivacyTo
 		} else {
 			Logger.log.Debugf("Node got hash of transaction %s", hash.String())
 			// Broadcast to network
-			err := netSync.config.Server.PushMessageToAll(msg)
-			if err != nil {
-				Logger.log.Error(err)
-			} else {
-				netSync.config.TxMemPool.MarkForwardedTransaction(*msg.Transaction.Hash())
-			}
+			netSync.relayTxMessage(msg, msg.Transaction, msg.IsPrivate)
 		}
 	}
 	Logger.log.Debug("Transaction %+v found in cache", *msg.Transaction.Hash())
 }
 
+// relayTxMessage publishes the message of an accepted tx to the network. A
+// private tx is kept private in pool and relayed to the other nodes of the
+// committee of its shard only.
+func (netSync *NetSync) relayTxMessage(msg wire.Message, tx metadata.Transaction, isPrivate bool) {
+	var err error
+	if isPrivate {
+		netSync.config.TxMemPool.MarkPrivateTransaction(*tx.Hash())
+		err = netSync.config.Server.PushMessageToShardCommittee(msg, common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()))
+	} else {
+		err = netSync.config.Server.PushMessageToAll(msg)
+	}
+	if err != nil {
+		Logger.log.Error(err)
+	} else {
+		netSync.config.TxMemPool.MarkForwardedTransaction(*tx.Hash())
+	}
+}
+
 func (netSync *NetSync) handleMessageBFTMsg(msg *wire.MessageBFT) {
 	// go metrics.AnalyzeTimeSeriesMetricData(map[string]interface{}{
 	// 	metrics.Measurement:      metrics.HandleMessageBFTMsg,
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	p2pgrpc "github.com/incognitochain/go-libp2p-grpc"
+	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/peerv2/proto"
 	"github.com/incognitochain/incognito-chain/peerv2/wrapper"
@@ -137,6 +138,61 @@ func (bp *BlockProvider) StreamBlockByHash(
 	return nil
 }
 
+func (bp *BlockProvider) GetStateSyncView(ctx context.Context, req *proto.StateSyncViewRequest) (*proto.StateSyncViewResponse, error) {
+	uuid := req.GetUUID()
+	chainID := stateSyncChainID(req.ChainID)
+	Logger.Infof("[statesync] Receive GetStateSyncView request chain %v, uuid = %s", chainID, uuid)
+	syncView, err := bp.NetSync.GetStateSyncView(chainID, req.GetFromBeaconHeight())
+	if err != nil {
+		Logger.Errorf("[statesync] Get state sync view of chain %v return error %v, uuid = %s", chainID, err, uuid)
+		return nil, err
+	}
+	return &proto.StateSyncViewResponse{
+		View:           syncView.View,
+		Block:          syncView.Block,
+		NextBlock:      syncView.NextBlock,
+		CommitteeSwaps: syncView.CommitteeSwaps,
+	}, nil
+}
+
+func (bp *BlockProvider) StreamTrieNodes(
+	req *proto.TrieNodesRequest,
+	stream proto.HighwayService_StreamTrieNodesServer,
+) error {
+	uuid := req.GetUUID()
+	chainID := stateSyncChainID(req.ChainID)
+	Logger.Infof("[statesync] Trie node provider received request chain %v, %v nodes, uuid = %s", chainID, len(req.Hashes), uuid)
+	cnt := 0
+	for _, hashBytes := range req.Hashes {
+		hash := common.Hash{}
+		if err := hash.SetBytes(hashBytes); err != nil {
+			continue
+		}
+		// nodes not found are skipped, the client requests them again later
+		data, err := bp.NetSync.GetTrieNode(chainID, hash)
+		if err != nil {
+			continue
+		}
+		if err := stream.Send(&proto.TrieNodeData{Hash: hashBytes, Data: data}); err != nil {
+			Logger.Infof("[statesync] Server send trie node to client return err %v, uuid = %s", err, uuid)
+			Logger.Infof("[statesync] Successfully sent %v trie nodes to client, uuid %v", cnt, uuid)
+			return err
+		}
+		cnt++
+	}
+	Logger.Infof("[statesync] Successfully sent %v trie nodes to client, uuid %v", cnt, uuid)
+	return nil
+}
+
+// stateSyncChainID converts the chain id of a state sync request, HighwayBeaconID
+// for the beacon chain like the block requests, to a database id
+func stateSyncChainID(chainID int32) int {
+	if chainID == int32(HighwayBeaconID) {
+		return common.BeaconChainDataBaseID
+	}
+	return int(chainID)
+}
+
 type BlockProvider struct {
 	proto.UnimplementedHighwayServiceServer
 	NetSync NetSync
@@ -147,4 +203,6 @@ type NetSync interface {
 	GetBlockBeaconByHash(blkHashes []common.Hash) []wire.Message
 	StreamBlockByHeight(fromPool bool, req *proto.BlockByHeightRequest) chan interface{}
 	StreamBlockByHash(fromPool bool, req *proto.BlockByHashRequest) chan interface{}
+	GetStateSyncView(chainID int, fromBeaconHeight uint64) (*blockchain.StateSyncView, error)
+	GetTrieNode(chainID int, hash common.Hash) ([]byte, error)
 }
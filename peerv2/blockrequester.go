@@ -295,6 +295,43 @@ func (c *BlockRequester) GetBlockBeaconByHash(
 	return res, nil
 }
 
+func (c *BlockRequester) GetStateSyncView(
+	ctx context.Context,
+	req *proto.StateSyncViewRequest,
+) (*proto.StateSyncViewResponse, error) {
+	uuid := genUUID()
+	Logger.Infof("[statesync] Requesting state sync view of chain %v, uuid = %s", req.ChainID, uuid)
+	c.RLock()
+	defer c.RUnlock()
+	if !c.ready() {
+		return nil, errors.New("requester not ready")
+	}
+	req.UUID = uuid
+	client := proto.NewHighwayServiceClient(c.conn)
+	return client.GetStateSyncView(ctx, req, grpc.MaxCallRecvMsgSize(MaxCallRecvMsgSize))
+}
+
+func (c *BlockRequester) StreamTrieNodes(
+	ctx context.Context,
+	req *proto.TrieNodesRequest,
+) (proto.HighwayService_StreamTrieNodesClient, error) {
+	uuid := genUUID()
+	Logger.Infof("[statesync] Requesting stream %v trie nodes of chain %v, uuid = %s", len(req.Hashes), req.ChainID, uuid)
+	c.RLock()
+	defer c.RUnlock()
+	if !c.ready() {
+		return nil, errors.New("requester not ready")
+	}
+	req.UUID = uuid
+	client := proto.NewHighwayServiceClient(c.conn)
+	stream, err := client.StreamTrieNodes(ctx, req, grpc.MaxCallRecvMsgSize(MaxCallRecvMsgSize))
+	if err != nil {
+		Logger.Infof("[statesync] This client not return stream for this request %v, got error %v ", req.ChainID, err)
+		return nil, err
+	}
+	return stream, nil
+}
+
 type syncBlkInfo struct {
 	bySpecHeights bool
 	byHash        bool
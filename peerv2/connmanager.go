@@ -14,6 +14,7 @@ import (
 	"github.com/incognitochain/incognito-chain/incognitokey"
 	"github.com/incognitochain/incognito-chain/peerv2/proto"
 	"github.com/incognitochain/incognito-chain/peerv2/rpcclient"
+	"github.com/incognitochain/incognito-chain/trie"
 	"github.com/incognitochain/incognito-chain/wire"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -106,6 +107,23 @@ func (cm *ConnManager) PublishMessageToShard(msg wire.Message, shardID byte) err
 	return nil
 }
 
+// PublishTxToShard publishes a tx message to the tx topic of shardID only, the
+// highway delivers it to the nodes subscribed to the txs of this shard and not
+// to the nodes of the other shards
+func (cm *ConnManager) PublishTxToShard(msg wire.Message, shardID byte) error {
+	msgType := msg.MessageType()
+	if msgType != wire.CmdTx && msgType != wire.CmdPrivacyCustomToken {
+		return errors.New("Can not publish message type " + msgType + " to the txs of a shard")
+	}
+	for _, availableTopic := range cm.subscriber.GetMsgToTopics()[msgType] {
+		cID := GetCommitteeIDOfTopic(availableTopic.Name)
+		if (cID == int(shardID)) && ((availableTopic.Act == proto.MessageTopicPair_PUB) || (availableTopic.Act == proto.MessageTopicPair_PUBSUB)) {
+			return broadcastMessage(msg, availableTopic.Name, cm.ps)
+		}
+	}
+	return errors.Errorf("Can not find topic of message type %v of shard %v for publish", msgType, shardID)
+}
+
 func (cm *ConnManager) Start(ns NetSync) {
 	// Pubsub
 	var err error
@@ -569,3 +587,68 @@ func (conn *ConnManager) requestBlocksByHashViaStream(ctx context.Context, peerI
 
 	return blockCh, nil
 }
+
+// highwayChainID returns the chain id of a request, HighwayBeaconID for the
+// beacon chain
+func highwayChainID(chainID int) int32 {
+	if chainID == common.BeaconChainDataBaseID {
+		return int32(HighwayBeaconID)
+	}
+	return int32(chainID)
+}
+
+// RequestStateSyncView returns the final view of a chain of a peer, with the
+// beacon committee swaps after fromBeaconHeight
+func (conn *ConnManager) RequestStateSyncView(ctx context.Context, peerID string, chainID int, fromBeaconHeight uint64) (*blockchain.StateSyncView, error) {
+	req := &proto.StateSyncViewRequest{
+		ChainID:          highwayChainID(chainID),
+		SyncFromPeer:     peerID,
+		FromBeaconHeight: fromBeaconHeight,
+	}
+	resp, err := conn.Requester.GetStateSyncView(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &blockchain.StateSyncView{
+		View:           resp.View,
+		Block:          resp.Block,
+		NextBlock:      resp.NextBlock,
+		CommitteeSwaps: resp.CommitteeSwaps,
+	}, nil
+}
+
+// RequestTrieNodesViaStream streams the trie nodes of a chain state from a peer.
+// The data of a node is not checked against its hash, the caller must do it.
+func (conn *ConnManager) RequestTrieNodesViaStream(ctx context.Context, peerID string, chainID int, hashes [][]byte) (nodeCh chan trie.SyncResult, err error) {
+	req := &proto.TrieNodesRequest{
+		ChainID:      highwayChainID(chainID),
+		Hashes:       hashes,
+		SyncFromPeer: peerID,
+	}
+	nodeCh = make(chan trie.SyncResult, len(hashes))
+	stream, err := conn.Requester.StreamTrieNodes(ctx, req)
+	if err != nil {
+		Logger.Errorf("[statesync] %v", err)
+		return nil, err
+	}
+
+	go func(stream proto.HighwayService_StreamTrieNodesClient, ctx context.Context) {
+		defer close(nodeCh)
+		for {
+			node, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					Logger.Errorf("[statesync] %v", err)
+				}
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case nodeCh <- trie.SyncResult{Hash: common.BytesToHash(node.Hash), Data: node.Data}:
+			}
+		}
+	}(stream, ctx)
+
+	return nodeCh, nil
+}
@@ -1049,6 +1049,242 @@ func (m *GetHighwayInfosResponse) GetHighways() []*HighwayInfo {
 	return nil
 }
 
+type StateSyncViewRequest struct {
+	ChainID              int32    `protobuf:"varint,1,opt,name=ChainID,proto3" json:"ChainID,omitempty"`
+	UUID                 string   `protobuf:"bytes,2,opt,name=UUID,proto3" json:"UUID,omitempty"`
+	SyncFromPeer         string   `protobuf:"bytes,3,opt,name=SyncFromPeer,proto3" json:"SyncFromPeer,omitempty"`
+	FromBeaconHeight     uint64   `protobuf:"varint,4,opt,name=FromBeaconHeight,proto3" json:"FromBeaconHeight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateSyncViewRequest) Reset()         { *m = StateSyncViewRequest{} }
+func (m *StateSyncViewRequest) String() string { return proto.CompactTextString(m) }
+func (*StateSyncViewRequest) ProtoMessage()    {}
+func (*StateSyncViewRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a48762df9e8cc53a, []int{18}
+}
+
+func (m *StateSyncViewRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateSyncViewRequest.Unmarshal(m, b)
+}
+func (m *StateSyncViewRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateSyncViewRequest.Marshal(b, m, deterministic)
+}
+func (m *StateSyncViewRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateSyncViewRequest.Merge(m, src)
+}
+func (m *StateSyncViewRequest) XXX_Size() int {
+	return xxx_messageInfo_StateSyncViewRequest.Size(m)
+}
+func (m *StateSyncViewRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateSyncViewRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateSyncViewRequest proto.InternalMessageInfo
+
+func (m *StateSyncViewRequest) GetChainID() int32 {
+	if m != nil {
+		return m.ChainID
+	}
+	return 0
+}
+
+func (m *StateSyncViewRequest) GetUUID() string {
+	if m != nil {
+		return m.UUID
+	}
+	return ""
+}
+
+func (m *StateSyncViewRequest) GetSyncFromPeer() string {
+	if m != nil {
+		return m.SyncFromPeer
+	}
+	return ""
+}
+
+func (m *StateSyncViewRequest) GetFromBeaconHeight() uint64 {
+	if m != nil {
+		return m.FromBeaconHeight
+	}
+	return 0
+}
+
+type StateSyncViewResponse struct {
+	View                 []byte   `protobuf:"bytes,1,opt,name=View,proto3" json:"View,omitempty"`
+	Block                []byte   `protobuf:"bytes,2,opt,name=Block,proto3" json:"Block,omitempty"`
+	NextBlock            []byte   `protobuf:"bytes,3,opt,name=NextBlock,proto3" json:"NextBlock,omitempty"`
+	CommitteeSwaps       [][]byte `protobuf:"bytes,4,rep,name=CommitteeSwaps,proto3" json:"CommitteeSwaps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateSyncViewResponse) Reset()         { *m = StateSyncViewResponse{} }
+func (m *StateSyncViewResponse) String() string { return proto.CompactTextString(m) }
+func (*StateSyncViewResponse) ProtoMessage()    {}
+func (*StateSyncViewResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a48762df9e8cc53a, []int{19}
+}
+
+func (m *StateSyncViewResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateSyncViewResponse.Unmarshal(m, b)
+}
+func (m *StateSyncViewResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateSyncViewResponse.Marshal(b, m, deterministic)
+}
+func (m *StateSyncViewResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateSyncViewResponse.Merge(m, src)
+}
+func (m *StateSyncViewResponse) XXX_Size() int {
+	return xxx_messageInfo_StateSyncViewResponse.Size(m)
+}
+func (m *StateSyncViewResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateSyncViewResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateSyncViewResponse proto.InternalMessageInfo
+
+func (m *StateSyncViewResponse) GetView() []byte {
+	if m != nil {
+		return m.View
+	}
+	return nil
+}
+
+func (m *StateSyncViewResponse) GetBlock() []byte {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *StateSyncViewResponse) GetNextBlock() []byte {
+	if m != nil {
+		return m.NextBlock
+	}
+	return nil
+}
+
+func (m *StateSyncViewResponse) GetCommitteeSwaps() [][]byte {
+	if m != nil {
+		return m.CommitteeSwaps
+	}
+	return nil
+}
+
+type TrieNodesRequest struct {
+	ChainID              int32    `protobuf:"varint,1,opt,name=ChainID,proto3" json:"ChainID,omitempty"`
+	Hashes               [][]byte `protobuf:"bytes,2,rep,name=Hashes,proto3" json:"Hashes,omitempty"`
+	UUID                 string   `protobuf:"bytes,3,opt,name=UUID,proto3" json:"UUID,omitempty"`
+	SyncFromPeer         string   `protobuf:"bytes,4,opt,name=SyncFromPeer,proto3" json:"SyncFromPeer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrieNodesRequest) Reset()         { *m = TrieNodesRequest{} }
+func (m *TrieNodesRequest) String() string { return proto.CompactTextString(m) }
+func (*TrieNodesRequest) ProtoMessage()    {}
+func (*TrieNodesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a48762df9e8cc53a, []int{20}
+}
+
+func (m *TrieNodesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrieNodesRequest.Unmarshal(m, b)
+}
+func (m *TrieNodesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrieNodesRequest.Marshal(b, m, deterministic)
+}
+func (m *TrieNodesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrieNodesRequest.Merge(m, src)
+}
+func (m *TrieNodesRequest) XXX_Size() int {
+	return xxx_messageInfo_TrieNodesRequest.Size(m)
+}
+func (m *TrieNodesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrieNodesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrieNodesRequest proto.InternalMessageInfo
+
+func (m *TrieNodesRequest) GetChainID() int32 {
+	if m != nil {
+		return m.ChainID
+	}
+	return 0
+}
+
+func (m *TrieNodesRequest) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+func (m *TrieNodesRequest) GetUUID() string {
+	if m != nil {
+		return m.UUID
+	}
+	return ""
+}
+
+func (m *TrieNodesRequest) GetSyncFromPeer() string {
+	if m != nil {
+		return m.SyncFromPeer
+	}
+	return ""
+}
+
+type TrieNodeData struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=Hash,proto3" json:"Hash,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrieNodeData) Reset()         { *m = TrieNodeData{} }
+func (m *TrieNodeData) String() string { return proto.CompactTextString(m) }
+func (*TrieNodeData) ProtoMessage()    {}
+func (*TrieNodeData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a48762df9e8cc53a, []int{21}
+}
+
+func (m *TrieNodeData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrieNodeData.Unmarshal(m, b)
+}
+func (m *TrieNodeData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrieNodeData.Marshal(b, m, deterministic)
+}
+func (m *TrieNodeData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrieNodeData.Merge(m, src)
+}
+func (m *TrieNodeData) XXX_Size() int {
+	return xxx_messageInfo_TrieNodeData.Size(m)
+}
+func (m *TrieNodeData) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrieNodeData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrieNodeData proto.InternalMessageInfo
+
+func (m *TrieNodeData) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *TrieNodeData) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("BlkType", BlkType_name, BlkType_value)
 	proto.RegisterEnum("MessageTopicPair_Action", MessageTopicPair_Action_name, MessageTopicPair_Action_value)
@@ -1070,73 +1306,88 @@ func init() {
 	proto.RegisterType((*GetHighwayInfosRequest)(nil), "GetHighwayInfosRequest")
 	proto.RegisterType((*HighwayInfo)(nil), "HighwayInfo")
 	proto.RegisterType((*GetHighwayInfosResponse)(nil), "GetHighwayInfosResponse")
+	proto.RegisterType((*StateSyncViewRequest)(nil), "StateSyncViewRequest")
+	proto.RegisterType((*StateSyncViewResponse)(nil), "StateSyncViewResponse")
+	proto.RegisterType((*TrieNodesRequest)(nil), "TrieNodesRequest")
+	proto.RegisterType((*TrieNodeData)(nil), "TrieNodeData")
 }
 
 func init() { proto.RegisterFile("highway.proto", fileDescriptor_a48762df9e8cc53a) }
 
 var fileDescriptor_a48762df9e8cc53a = []byte{
-	// 963 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x56, 0xdd, 0x8e, 0xdb, 0x44,
-	0x14, 0x8e, 0xe3, 0x38, 0x3f, 0x67, 0xb3, 0x69, 0xf6, 0xec, 0xd2, 0x75, 0xdd, 0xad, 0x1a, 0x2c,
-	0xa8, 0xa2, 0x5e, 0x0c, 0x34, 0x48, 0x88, 0x0b, 0xb8, 0xa8, 0x13, 0xba, 0xbb, 0x50, 0x44, 0x34,
-	0x49, 0x44, 0xc5, 0x9d, 0xeb, 0xce, 0x26, 0xd6, 0x66, 0x3d, 0xc1, 0xf6, 0x82, 0x22, 0xf1, 0x1c,
-	0xbc, 0x00, 0x0f, 0xc0, 0x4b, 0x70, 0x83, 0x78, 0x0d, 0x1e, 0x04, 0xcd, 0x78, 0xec, 0x38, 0x89,
-	0x9d, 0xbd, 0xca, 0x9c, 0x73, 0x3c, 0x33, 0xdf, 0x77, 0x7e, 0xbe, 0x09, 0x1c, 0x2f, 0xfc, 0xf9,
-	0xe2, 0x37, 0x77, 0x4d, 0x56, 0x21, 0x8f, 0xb9, 0xfd, 0xaf, 0x06, 0x8f, 0x28, 0x9b, 0xfb, 0x51,
-	0xcc, 0x42, 0xca, 0x7e, 0xb9, 0x67, 0x51, 0x8c, 0x04, 0x70, 0xc8, 0xef, 0xee, 0xfc, 0x38, 0x66,
-	0x6c, 0x7c, 0xff, 0x7e, 0xe9, 0x7b, 0xdf, 0xb3, 0xb5, 0xa9, 0xf5, 0xb4, 0x7e, 0x8b, 0x16, 0x44,
-	0xf0, 0x05, 0x74, 0x7e, 0x72, 0x83, 0x98, 0x7d, 0xf8, 0x81, 0x45, 0x91, 0x3b, 0x67, 0x91, 0x59,
-	0xed, 0xe9, 0xfd, 0x16, 0xdd, 0xf1, 0x62, 0x0f, 0x8e, 0xb2, 0xdd, 0xd7, 0x23, 0x53, 0xef, 0x69,
-	0xfd, 0x36, 0xcd, 0xbb, 0xf0, 0x31, 0xd4, 0xc7, 0x8c, 0x85, 0xd7, 0x23, 0xb3, 0x26, 0x6f, 0x53,
-	0x16, 0x22, 0xd4, 0x28, 0x5f, 0x32, 0xd3, 0x90, 0x5e, 0xb9, 0x16, 0xbe, 0xd9, 0xec, 0x7a, 0x64,
-	0xd6, 0x13, 0x9f, 0x58, 0xdb, 0xdf, 0x41, 0x73, 0x16, 0xb1, 0x50, 0xc6, 0xcf, 0xc0, 0x78, 0xeb,
-	0xae, 0x59, 0xa8, 0x80, 0x27, 0x46, 0x76, 0x52, 0x35, 0x77, 0xd2, 0x19, 0x18, 0x93, 0x85, 0x1b,
-	0x7e, 0x90, 0x88, 0x0c, 0x9a, 0x18, 0xf6, 0x3b, 0xe8, 0x6e, 0x12, 0x13, 0xad, 0x78, 0x10, 0x31,
-	0xfc, 0x14, 0x6a, 0x63, 0xd7, 0x17, 0x47, 0xea, 0xfd, 0xa3, 0xc1, 0x09, 0x51, 0xd4, 0xa6, 0x7c,
-	0xe5, 0x7b, 0x22, 0x40, 0x65, 0x18, 0x9f, 0xe5, 0x2e, 0x39, 0x1a, 0xb4, 0x48, 0x8a, 0x29, 0xb9,
-	0xcf, 0xfe, 0x43, 0x83, 0xee, 0xee, 0x4e, 0x34, 0xa1, 0xa1, 0x7c, 0x0a, 0x70, 0x6a, 0x0a, 0x78,
-	0xf2, 0x33, 0x95, 0xd5, 0xc4, 0xc0, 0x97, 0xa0, 0xbf, 0xf6, 0x62, 0x53, 0xef, 0xe9, 0xfd, 0xce,
-	0xc0, 0xdc, 0x43, 0x42, 0x5e, 0x7b, 0xb1, 0xcf, 0x03, 0x2a, 0x3e, 0xb2, 0x5f, 0x40, 0x3d, 0x31,
-	0x11, 0xa0, 0x3e, 0x9e, 0x39, 0x93, 0x99, 0xd3, 0xad, 0x60, 0x03, 0xf4, 0xf1, 0xcc, 0xe9, 0x6a,
-	0x62, 0x21, 0x3c, 0x55, 0xfb, 0x77, 0xb0, 0x2e, 0x59, 0xec, 0x2c, 0xb9, 0x77, 0x2b, 0x73, 0xe0,
-	0xac, 0xaf, 0xdc, 0x68, 0x91, 0xb6, 0x45, 0x96, 0x26, 0x2d, 0x97, 0x26, 0x51, 0x32, 0xf1, 0x91,
-	0x2a, 0x7a, 0x9b, 0x2a, 0x0b, 0x2f, 0xa0, 0x35, 0x74, 0x97, 0xcb, 0x11, 0x5b, 0xc5, 0x0b, 0x95,
-	0xd8, 0x8d, 0x23, 0x2b, 0x5e, 0x2d, 0x57, 0xbc, 0x57, 0xf0, 0xb4, 0xf0, 0x76, 0x95, 0x7b, 0x84,
-	0xda, 0xc8, 0x8d, 0x5d, 0x99, 0xfb, 0x36, 0x95, 0x6b, 0x7b, 0xbe, 0xd9, 0xe2, 0x30, 0xd7, 0xe3,
-	0xc1, 0x36, 0xe2, 0x0d, 0x36, 0xad, 0x1c, 0x5b, 0xb5, 0x0c, 0x9b, 0x9e, 0xc3, 0x36, 0x80, 0x8b,
-	0xe2, 0x8b, 0x0e, 0x80, 0xfb, 0x53, 0x83, 0xe7, 0xe9, 0xa6, 0x61, 0xc8, 0xa3, 0xa8, 0x20, 0xa7,
-	0x17, 0xd0, 0x7a, 0x13, 0xf2, 0xbb, 0x7c, 0x5e, 0x37, 0x0e, 0xd1, 0x13, 0x53, 0x9e, 0xc4, 0x12,
-	0x94, 0xa9, 0x99, 0x63, 0xa6, 0x97, 0x33, 0xab, 0x95, 0x31, 0x33, 0x72, 0xcc, 0xbe, 0x84, 0x5e,
-	0x39, 0xc8, 0x03, 0xec, 0xfe, 0xd3, 0xe0, 0x2c, 0xc9, 0xc7, 0xfa, 0x8a, 0xf9, 0xf3, 0x45, 0xbc,
-	0xa1, 0x54, 0x9b, 0xae, 0x57, 0x49, 0x17, 0x77, 0x06, 0x4d, 0xe2, 0x2c, 0x6f, 0x85, 0x4d, 0xa5,
-	0x17, 0x2d, 0x68, 0x4e, 0x56, 0xcc, 0xf3, 0x6f, 0x64, 0x3f, 0x6b, 0xfd, 0x26, 0xcd, 0x6c, 0x41,
-	0x37, 0x39, 0x2a, 0x61, 0x55, 0xa3, 0xa9, 0x29, 0x00, 0x88, 0xac, 0x28, 0x46, 0x72, 0x8d, 0x1d,
-	0xa8, 0x4e, 0xb9, 0xa4, 0x62, 0xd0, 0xea, 0x94, 0x6f, 0x53, 0xaf, 0x97, 0x51, 0x6f, 0x6c, 0xa8,
-	0xa3, 0x0d, 0xed, 0xc9, 0x3a, 0xf0, 0xc4, 0x69, 0x42, 0x67, 0xcc, 0xa6, 0x8c, 0x6d, 0xf9, 0xec,
-	0xbf, 0x35, 0xc0, 0x94, 0xe6, 0x56, 0xdd, 0x0e, 0x91, 0x2c, 0x9b, 0x89, 0x94, 0x86, 0xbe, 0x47,
-	0xa3, 0x56, 0x4c, 0xc3, 0x28, 0xa3, 0x51, 0x3f, 0x40, 0xa3, 0x51, 0x40, 0xe3, 0x39, 0xb4, 0x24,
-	0x0b, 0x51, 0xba, 0x5c, 0x39, 0xb5, 0xac, 0x9c, 0x14, 0xcc, 0x4b, 0x16, 0x0f, 0x17, 0xae, 0x1f,
-	0x64, 0x82, 0x9c, 0x1b, 0xfc, 0x6f, 0x57, 0xdc, 0x5b, 0xa4, 0x83, 0x2f, 0x8d, 0x5d, 0x35, 0x4f,
-	0x1a, 0x34, 0xef, 0xb2, 0x3f, 0x83, 0x27, 0x05, 0x67, 0xee, 0xf5, 0xd4, 0x06, 0x84, 0x09, 0x8f,
-	0x2f, 0x59, 0x7c, 0x95, 0x3c, 0x50, 0xd7, 0xc1, 0x0d, 0x8f, 0x14, 0x04, 0xfb, 0x47, 0x38, 0xca,
-	0xb9, 0x45, 0x17, 0xc9, 0x97, 0x21, 0xb8, 0xe1, 0x4a, 0x2d, 0x33, 0x1b, 0x3f, 0x81, 0xe3, 0xc9,
-	0xfd, 0x6a, 0xc5, 0xc3, 0x58, 0xb6, 0x72, 0x52, 0x03, 0x83, 0x6e, 0x3b, 0xed, 0x21, 0x9c, 0xef,
-	0x5d, 0xa5, 0x90, 0xf5, 0xa1, 0xa9, 0xfc, 0x91, 0x12, 0xfa, 0x36, 0xc9, 0x7d, 0x48, 0xb3, 0xe8,
-	0xcb, 0x6f, 0xa0, 0xa1, 0x0a, 0x8f, 0x6d, 0x68, 0x3a, 0xcb, 0x44, 0xb7, 0xba, 0x15, 0x3c, 0x16,
-	0xe9, 0xbe, 0x7d, 0x97, 0x98, 0x9a, 0x50, 0x5d, 0x11, 0x1c, 0x38, 0xdd, 0x2a, 0xb6, 0xc0, 0x70,
-	0x96, 0xb7, 0x8e, 0xd7, 0xd5, 0x07, 0xff, 0xe8, 0xd0, 0x51, 0x67, 0x4d, 0x58, 0xf8, 0xab, 0xef,
-	0x31, 0x7c, 0x05, 0xcd, 0xf4, 0xd1, 0xc1, 0x2e, 0xd9, 0x79, 0x98, 0xad, 0x13, 0xb2, 0xfb, 0x22,
-	0xd9, 0x15, 0xa4, 0x70, 0x5a, 0x20, 0x9b, 0xf8, 0x94, 0x94, 0x4b, 0xb9, 0x75, 0x41, 0x0e, 0x28,
-	0xad, 0x5d, 0xc1, 0x19, 0x9c, 0x15, 0xc9, 0x1d, 0x6e, 0xf6, 0x15, 0xc8, 0xad, 0xf5, 0x8c, 0x1c,
-	0xd2, 0x48, 0xbb, 0x82, 0xae, 0x6c, 0xb2, 0x42, 0xad, 0xc1, 0x1e, 0x79, 0x40, 0x2b, 0xad, 0x8f,
-	0xc9, 0x43, 0x42, 0x65, 0x57, 0xf0, 0x6b, 0x38, 0x9d, 0xc4, 0x21, 0x73, 0xef, 0xb6, 0xb4, 0x09,
-	0x3f, 0x22, 0x45, 0x5a, 0x65, 0x01, 0xc9, 0xa6, 0xc2, 0xae, 0x7c, 0xae, 0xe1, 0x57, 0x70, 0xb2,
-	0xbd, 0x5b, 0x20, 0x3b, 0x25, 0xfb, 0x02, 0xb0, 0xbb, 0x73, 0xf0, 0x97, 0x06, 0xe7, 0xaa, 0x96,
-	0x43, 0x1e, 0x04, 0xcc, 0x8b, 0x79, 0x98, 0x16, 0xf5, 0x2d, 0x9c, 0xec, 0xcd, 0x01, 0x3e, 0x21,
-	0x65, 0xf3, 0x66, 0x59, 0xa4, 0x74, 0x6c, 0xec, 0x0a, 0xbe, 0x81, 0x47, 0x3b, 0x9d, 0x8b, 0xe7,
-	0xa4, 0x78, 0x6c, 0x2c, 0x93, 0x94, 0x34, 0xb9, 0x5d, 0x71, 0x1a, 0x3f, 0x1b, 0xf2, 0x2f, 0xe0,
-	0xfb, 0xba, 0xfc, 0xf9, 0xe2, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd9, 0xf0, 0xe1, 0x18, 0x1a,
-	0x0a, 0x00, 0x00,
+	// 1144 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x57, 0xdb, 0x6e, 0xdb, 0x46,
+	0x13, 0x26, 0x45, 0xea, 0x34, 0x96, 0x1d, 0x7a, 0x7d, 0x62, 0x18, 0x07, 0xd1, 0xbf, 0xf8, 0x1b,
+	0x08, 0xbe, 0xd8, 0x36, 0x2a, 0x90, 0xf6, 0xa2, 0xbd, 0x08, 0xa5, 0xc6, 0x76, 0x9b, 0xa6, 0x06,
+	0x25, 0xb5, 0x41, 0xef, 0x18, 0x7a, 0x6d, 0x11, 0x96, 0x45, 0x95, 0xa4, 0xeb, 0x0a, 0xc8, 0x7d,
+	0x5f, 0xa0, 0xe8, 0x0b, 0xf4, 0x01, 0xfa, 0x12, 0x05, 0x7a, 0xd1, 0xd7, 0xe8, 0x83, 0x14, 0x7b,
+	0xe0, 0x41, 0x14, 0x29, 0x5f, 0x79, 0x67, 0x96, 0xbb, 0xfb, 0x7d, 0x33, 0xdf, 0xcc, 0xc8, 0xb0,
+	0x3d, 0xf5, 0xaf, 0xa7, 0xf7, 0xee, 0x92, 0x2c, 0xc2, 0x20, 0x0e, 0x90, 0x82, 0xff, 0x51, 0xe1,
+	0x91, 0x43, 0xaf, 0xfd, 0x28, 0xa6, 0xa1, 0x43, 0x7f, 0xba, 0xa3, 0x51, 0x8c, 0x08, 0xa0, 0x41,
+	0x70, 0x7b, 0xeb, 0xc7, 0x31, 0xa5, 0x17, 0x77, 0xef, 0x67, 0xbe, 0xf7, 0x0d, 0x5d, 0x9a, 0x6a,
+	0x57, 0xed, 0xb5, 0x9d, 0x92, 0x1d, 0xf4, 0x1c, 0x76, 0x7e, 0x70, 0xe7, 0x31, 0xbd, 0xfc, 0x96,
+	0x46, 0x91, 0x7b, 0x4d, 0x23, 0xb3, 0xd6, 0xd5, 0x7a, 0x6d, 0xa7, 0xe0, 0x45, 0x5d, 0xd8, 0x4a,
+	0x4f, 0x9f, 0x0f, 0x4d, 0xad, 0xab, 0xf6, 0x3a, 0x4e, 0xde, 0x85, 0x0e, 0xa1, 0x71, 0x41, 0x69,
+	0x78, 0x3e, 0x34, 0x75, 0xfe, 0x9a, 0xb4, 0x10, 0x02, 0xdd, 0x09, 0x66, 0xd4, 0xac, 0x73, 0x2f,
+	0x5f, 0x33, 0xdf, 0x64, 0x72, 0x3e, 0x34, 0x1b, 0xc2, 0xc7, 0xd6, 0xf8, 0x6b, 0x68, 0x4d, 0x22,
+	0x1a, 0xf2, 0xfd, 0x7d, 0xa8, 0xbf, 0x71, 0x97, 0x34, 0x94, 0xc0, 0x85, 0x91, 0xde, 0x54, 0xcb,
+	0xdd, 0xb4, 0x0f, 0xf5, 0xd1, 0xd4, 0x0d, 0x2f, 0x39, 0xa2, 0xba, 0x23, 0x0c, 0xfc, 0x0e, 0x8c,
+	0x2c, 0x30, 0xd1, 0x22, 0x98, 0x47, 0x14, 0x7d, 0x04, 0xfa, 0x85, 0xeb, 0xb3, 0x2b, 0xb5, 0xde,
+	0x56, 0x7f, 0x97, 0x48, 0x6a, 0xe3, 0x60, 0xe1, 0x7b, 0x6c, 0xc3, 0xe1, 0xdb, 0xe8, 0x69, 0xee,
+	0x91, 0xad, 0x7e, 0x9b, 0x24, 0x98, 0xc4, 0x7b, 0xf8, 0x77, 0x15, 0x8c, 0xe2, 0x49, 0x64, 0x42,
+	0x53, 0xfa, 0x24, 0xe0, 0xc4, 0x64, 0xf0, 0xf8, 0x67, 0x32, 0xaa, 0xc2, 0x40, 0x27, 0xa0, 0xbd,
+	0xf2, 0x62, 0x53, 0xeb, 0x6a, 0xbd, 0x9d, 0xbe, 0xb9, 0x86, 0x84, 0xbc, 0xf2, 0x62, 0x3f, 0x98,
+	0x3b, 0xec, 0x23, 0xfc, 0x1c, 0x1a, 0xc2, 0x44, 0x00, 0x8d, 0x8b, 0x89, 0x3d, 0x9a, 0xd8, 0x86,
+	0x82, 0x9a, 0xa0, 0x5d, 0x4c, 0x6c, 0x43, 0x65, 0x0b, 0xe6, 0xa9, 0xe1, 0x0f, 0x60, 0x9d, 0xd2,
+	0xd8, 0x9e, 0x05, 0xde, 0x0d, 0x8f, 0x81, 0xbd, 0x3c, 0x73, 0xa3, 0x69, 0x22, 0x8b, 0x34, 0x4c,
+	0x6a, 0x2e, 0x4c, 0x2c, 0x65, 0xec, 0x23, 0x99, 0xf4, 0x8e, 0x23, 0x2d, 0x74, 0x0c, 0xed, 0x81,
+	0x3b, 0x9b, 0x0d, 0xe9, 0x22, 0x9e, 0xca, 0xc0, 0x66, 0x8e, 0x34, 0x79, 0x7a, 0x2e, 0x79, 0x2f,
+	0xe0, 0x49, 0xe9, 0xeb, 0x32, 0xf6, 0x08, 0xf4, 0xa1, 0x1b, 0xbb, 0x3c, 0xf6, 0x1d, 0x87, 0xaf,
+	0xf1, 0x75, 0x76, 0xc4, 0xa6, 0xae, 0x17, 0xcc, 0x57, 0x11, 0x67, 0xd8, 0xd4, 0x6a, 0x6c, 0xb5,
+	0x2a, 0x6c, 0x5a, 0x0e, 0x5b, 0x1f, 0x8e, 0xcb, 0x1f, 0xda, 0x00, 0xee, 0x0f, 0x15, 0x9e, 0x25,
+	0x87, 0x06, 0x61, 0x10, 0x45, 0x25, 0x31, 0x3d, 0x86, 0xf6, 0xeb, 0x30, 0xb8, 0xcd, 0xc7, 0x35,
+	0x73, 0x30, 0x4d, 0x8c, 0x03, 0xb1, 0x27, 0x50, 0x26, 0x66, 0x8e, 0x99, 0x56, 0xcd, 0x4c, 0xaf,
+	0x62, 0x56, 0xcf, 0x31, 0x7b, 0x09, 0xdd, 0x6a, 0x90, 0x1b, 0xd8, 0xfd, 0xab, 0xc2, 0xbe, 0x88,
+	0xc7, 0xf2, 0x8c, 0xfa, 0xd7, 0xd3, 0x38, 0xa3, 0xa4, 0x8f, 0x97, 0x0b, 0xa1, 0xe2, 0x9d, 0x7e,
+	0x8b, 0xd8, 0xb3, 0x1b, 0x66, 0x3b, 0xdc, 0x8b, 0x2c, 0x68, 0x8d, 0x16, 0xd4, 0xf3, 0xaf, 0xb8,
+	0x9e, 0xd5, 0x5e, 0xcb, 0x49, 0x6d, 0x46, 0x57, 0x5c, 0x25, 0x58, 0xe9, 0x4e, 0x62, 0x32, 0x00,
+	0x2c, 0x2a, 0x92, 0x11, 0x5f, 0xa3, 0x1d, 0xa8, 0x8d, 0x03, 0x4e, 0xa5, 0xee, 0xd4, 0xc6, 0xc1,
+	0x2a, 0xf5, 0x46, 0x15, 0xf5, 0x66, 0x46, 0x1d, 0x61, 0xe8, 0x8c, 0x96, 0x73, 0x8f, 0xdd, 0xc6,
+	0xfa, 0x8c, 0xd9, 0xe2, 0x7b, 0x2b, 0x3e, 0xfc, 0x97, 0x0a, 0x28, 0xa1, 0xb9, 0x92, 0xb7, 0x4d,
+	0x24, 0xab, 0x6a, 0x22, 0xa1, 0xa1, 0xad, 0xd1, 0xd0, 0xcb, 0x69, 0xd4, 0xab, 0x68, 0x34, 0x36,
+	0xd0, 0x68, 0x96, 0xd0, 0x78, 0x06, 0x6d, 0xce, 0x82, 0xa5, 0x2e, 0x97, 0x4e, 0x35, 0x4d, 0xa7,
+	0x03, 0xe6, 0x29, 0x8d, 0x07, 0x53, 0xd7, 0x9f, 0xa7, 0x0d, 0x39, 0x57, 0xf8, 0x5f, 0x2d, 0x02,
+	0x6f, 0x9a, 0x14, 0x3e, 0x37, 0x8a, 0xdd, 0x5c, 0x08, 0x34, 0xef, 0xc2, 0x1f, 0xc3, 0xe3, 0x92,
+	0x3b, 0xd7, 0x34, 0x95, 0x81, 0x30, 0xe1, 0xf0, 0x94, 0xc6, 0x67, 0x62, 0x44, 0x9d, 0xcf, 0xaf,
+	0x82, 0x48, 0x42, 0xc0, 0xdf, 0xc1, 0x56, 0xce, 0xcd, 0x54, 0xc4, 0x27, 0xc3, 0xfc, 0x2a, 0x90,
+	0xdd, 0x32, 0xb5, 0xd1, 0xff, 0x61, 0x7b, 0x74, 0xb7, 0x58, 0x04, 0x61, 0xcc, 0xa5, 0x2c, 0x72,
+	0x50, 0x77, 0x56, 0x9d, 0x78, 0x00, 0x47, 0x6b, 0x4f, 0x49, 0x64, 0x3d, 0x68, 0x49, 0x7f, 0x24,
+	0x1b, 0x7d, 0x87, 0xe4, 0x3e, 0x74, 0xd2, 0x5d, 0xfc, 0x9b, 0x0a, 0xfb, 0xa3, 0xd8, 0x8d, 0x29,
+	0x8b, 0xf5, 0xf7, 0x3e, 0xbd, 0x4f, 0x22, 0x66, 0x42, 0x93, 0xd3, 0x3e, 0x1f, 0xca, 0x98, 0x25,
+	0x66, 0x9a, 0xc0, 0xda, 0x86, 0x04, 0x6a, 0xeb, 0x09, 0x44, 0x27, 0x60, 0xb0, 0xb5, 0x68, 0x3e,
+	0xa2, 0x2c, 0xb8, 0x68, 0x74, 0x67, 0xcd, 0x8f, 0x7f, 0x55, 0xe1, 0xa0, 0x00, 0x2b, 0x0b, 0x3a,
+	0xb3, 0x93, 0xa0, 0xb3, 0x35, 0xcb, 0x2e, 0x97, 0x06, 0x87, 0xd4, 0x71, 0x84, 0xc1, 0x64, 0xf8,
+	0x96, 0xfe, 0x22, 0xfa, 0x82, 0x9c, 0xd4, 0x99, 0x83, 0x4d, 0xfc, 0x34, 0xa3, 0xa3, 0x7b, 0x77,
+	0x11, 0x99, 0x3a, 0x17, 0x7a, 0xc1, 0x8b, 0x3f, 0x80, 0x31, 0x0e, 0x7d, 0xfa, 0x36, 0xb8, 0xa4,
+	0xd1, 0xc3, 0xb1, 0xd9, 0x50, 0x36, 0xc5, 0x86, 0xbc, 0x16, 0x33, 0xbd, 0x44, 0xf4, 0x2f, 0xa1,
+	0x93, 0xbc, 0x9e, 0xe8, 0x9e, 0xdd, 0x98, 0xb0, 0x67, 0xeb, 0x54, 0x86, 0xb5, 0x4c, 0x86, 0x27,
+	0x5f, 0x42, 0x53, 0xd6, 0x33, 0xea, 0x40, 0xcb, 0x9e, 0x89, 0x71, 0x64, 0x28, 0x68, 0x9b, 0x55,
+	0xd1, 0xcd, 0x3b, 0x61, 0xaa, 0x6c, 0x98, 0xb2, 0xcd, 0xbe, 0x6d, 0xd4, 0x50, 0x9b, 0x45, 0xf1,
+	0xc6, 0xf6, 0x0c, 0xad, 0xff, 0xb7, 0x0e, 0x3b, 0x52, 0x22, 0x23, 0x1a, 0xfe, 0xec, 0x7b, 0x14,
+	0xbd, 0x80, 0x56, 0xf2, 0x5b, 0x02, 0x19, 0xa4, 0xf0, 0x7b, 0xcb, 0xda, 0x25, 0xc5, 0x1f, 0x1a,
+	0x58, 0x41, 0x0e, 0xec, 0x95, 0x4c, 0x43, 0xf4, 0x84, 0x54, 0x4f, 0x68, 0xeb, 0x98, 0x6c, 0x18,
+	0xa0, 0x58, 0x41, 0x13, 0xd8, 0x2f, 0x9b, 0x62, 0xe8, 0x98, 0x94, 0xb9, 0x93, 0x5b, 0x9f, 0x92,
+	0x4d, 0xa3, 0x0f, 0x2b, 0xc8, 0x05, 0x33, 0xf9, 0xa2, 0x38, 0x42, 0x50, 0x97, 0x3c, 0x30, 0x02,
+	0xad, 0xff, 0x91, 0x87, 0xe6, 0x0f, 0x56, 0xd0, 0x17, 0xb0, 0x37, 0x8a, 0x43, 0xea, 0xde, 0xae,
+	0x8c, 0x1c, 0x74, 0x40, 0xca, 0x46, 0x90, 0x05, 0x24, 0x6d, 0x76, 0x58, 0xf9, 0x44, 0x45, 0x9f,
+	0xc3, 0xee, 0xea, 0x69, 0x86, 0x6c, 0x8f, 0xac, 0xf7, 0xf5, 0xb5, 0x93, 0x03, 0x30, 0x4e, 0x69,
+	0xbc, 0x52, 0x4c, 0xe8, 0x80, 0x94, 0xd5, 0xbc, 0x75, 0x48, 0x4a, 0x6b, 0x0e, 0x2b, 0xe8, 0x33,
+	0x78, 0x24, 0x9e, 0x4f, 0x6b, 0x01, 0xed, 0x92, 0x62, 0x5d, 0x58, 0xdb, 0x24, 0x2f, 0x56, 0xf6,
+	0x7a, 0xff, 0x4f, 0x15, 0x8e, 0xa4, 0x92, 0x06, 0xc1, 0x7c, 0x4e, 0xbd, 0x38, 0x08, 0x13, 0x49,
+	0xbd, 0x81, 0xdd, 0xb5, 0xe6, 0x8a, 0x1e, 0x93, 0xaa, 0x26, 0x6e, 0x59, 0xa4, 0xb2, 0x17, 0x63,
+	0x05, 0xbd, 0x86, 0x47, 0x85, 0x76, 0x88, 0x8e, 0x48, 0x79, 0x2f, 0xb6, 0x4c, 0x52, 0xd1, 0x39,
+	0xb1, 0x62, 0x37, 0x7f, 0xac, 0xf3, 0xff, 0x2c, 0xde, 0x37, 0xf8, 0x9f, 0x4f, 0xff, 0x1b, 0x00,
+	0xd3, 0x80, 0xf9, 0x63, 0x71, 0x0c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1157,6 +1408,8 @@ type HighwayServiceClient interface {
 	GetBlockCrossShardByHash(ctx context.Context, in *GetBlockCrossShardByHashRequest, opts ...grpc.CallOption) (*GetBlockCrossShardByHashResponse, error)
 	StreamBlockByHeight(ctx context.Context, in *BlockByHeightRequest, opts ...grpc.CallOption) (HighwayService_StreamBlockByHeightClient, error)
 	StreamBlockByHash(ctx context.Context, in *BlockByHashRequest, opts ...grpc.CallOption) (HighwayService_StreamBlockByHashClient, error)
+	GetStateSyncView(ctx context.Context, in *StateSyncViewRequest, opts ...grpc.CallOption) (*StateSyncViewResponse, error)
+	StreamTrieNodes(ctx context.Context, in *TrieNodesRequest, opts ...grpc.CallOption) (HighwayService_StreamTrieNodesClient, error)
 }
 
 type highwayServiceClient struct {
@@ -1267,6 +1520,47 @@ func (x *highwayServiceStreamBlockByHashClient) Recv() (*BlockData, error) {
 	return m, nil
 }
 
+func (c *highwayServiceClient) GetStateSyncView(ctx context.Context, in *StateSyncViewRequest, opts ...grpc.CallOption) (*StateSyncViewResponse, error) {
+	out := new(StateSyncViewResponse)
+	err := c.cc.Invoke(ctx, "/HighwayService/GetStateSyncView", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *highwayServiceClient) StreamTrieNodes(ctx context.Context, in *TrieNodesRequest, opts ...grpc.CallOption) (HighwayService_StreamTrieNodesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_HighwayService_serviceDesc.Streams[2], "/HighwayService/StreamTrieNodes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &highwayServiceStreamTrieNodesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HighwayService_StreamTrieNodesClient interface {
+	Recv() (*TrieNodeData, error)
+	grpc.ClientStream
+}
+
+type highwayServiceStreamTrieNodesClient struct {
+	grpc.ClientStream
+}
+
+func (x *highwayServiceStreamTrieNodesClient) Recv() (*TrieNodeData, error) {
+	m := new(TrieNodeData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // HighwayServiceServer is the server API for HighwayService service.
 type HighwayServiceServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
@@ -1275,6 +1569,8 @@ type HighwayServiceServer interface {
 	GetBlockCrossShardByHash(context.Context, *GetBlockCrossShardByHashRequest) (*GetBlockCrossShardByHashResponse, error)
 	StreamBlockByHeight(*BlockByHeightRequest, HighwayService_StreamBlockByHeightServer) error
 	StreamBlockByHash(*BlockByHashRequest, HighwayService_StreamBlockByHashServer) error
+	GetStateSyncView(context.Context, *StateSyncViewRequest) (*StateSyncViewResponse, error)
+	StreamTrieNodes(*TrieNodesRequest, HighwayService_StreamTrieNodesServer) error
 }
 
 // UnimplementedHighwayServiceServer can be embedded to have forward compatible implementations.
@@ -1299,6 +1595,12 @@ func (*UnimplementedHighwayServiceServer) StreamBlockByHeight(req *BlockByHeight
 func (*UnimplementedHighwayServiceServer) StreamBlockByHash(req *BlockByHashRequest, srv HighwayService_StreamBlockByHashServer) error {
 	return status.Errorf(codes.Unimplemented, "method StreamBlockByHash not implemented")
 }
+func (*UnimplementedHighwayServiceServer) GetStateSyncView(ctx context.Context, req *StateSyncViewRequest) (*StateSyncViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStateSyncView not implemented")
+}
+func (*UnimplementedHighwayServiceServer) StreamTrieNodes(req *TrieNodesRequest, srv HighwayService_StreamTrieNodesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTrieNodes not implemented")
+}
 
 func RegisterHighwayServiceServer(s *grpc.Server, srv HighwayServiceServer) {
 	s.RegisterService(&_HighwayService_serviceDesc, srv)
@@ -1418,6 +1720,45 @@ func (x *highwayServiceStreamBlockByHashServer) Send(m *BlockData) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _HighwayService_GetStateSyncView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateSyncViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HighwayServiceServer).GetStateSyncView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/HighwayService/GetStateSyncView",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HighwayServiceServer).GetStateSyncView(ctx, req.(*StateSyncViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HighwayService_StreamTrieNodes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TrieNodesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HighwayServiceServer).StreamTrieNodes(m, &highwayServiceStreamTrieNodesServer{stream})
+}
+
+type HighwayService_StreamTrieNodesServer interface {
+	Send(*TrieNodeData) error
+	grpc.ServerStream
+}
+
+type highwayServiceStreamTrieNodesServer struct {
+	grpc.ServerStream
+}
+
+func (x *highwayServiceStreamTrieNodesServer) Send(m *TrieNodeData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _HighwayService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "HighwayService",
 	HandlerType: (*HighwayServiceServer)(nil),
@@ -1438,6 +1779,10 @@ var _HighwayService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetBlockCrossShardByHash",
 			Handler:    _HighwayService_GetBlockCrossShardByHash_Handler,
 		},
+		{
+			MethodName: "GetStateSyncView",
+			Handler:    _HighwayService_GetStateSyncView_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1450,6 +1795,11 @@ var _HighwayService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _HighwayService_StreamBlockByHash_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamTrieNodes",
+			Handler:       _HighwayService_StreamTrieNodes_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "highway.proto",
 }
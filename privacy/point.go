@@ -260,7 +260,7 @@ func HashToPointFromIndex(index int64, padStr string) *Point {
 	array := C25519.GBASE.ToBytes()
 	msg := array[:]
 	msg = append(msg, []byte(padStr)...)
-	msg = append(msg, []byte(string(index))...)
+	msg = append(msg, []byte(string(rune(index)))...)
 
 	keyHash := C25519.Key(C25519.Keccak256(msg))
 	keyPoint := keyHash.HashToPoint()
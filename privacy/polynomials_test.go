@@ -212,7 +212,7 @@ func TestAdd(t *testing.T) {
 	}
 }
 
-func ExampleRandPoly() {
+func Example_randomPoly() {
 	p := randomPoly(10, 128) // 계수의 크기가 0~2^128인 임의의 10차 다항식 생성
 	fmt.Println(p)
 }
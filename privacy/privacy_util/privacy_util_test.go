@@ -1,3 +1,9 @@
+//go:build legacytest
+// +build legacytest
+
+// The tests of this file use the privacy/operation package, which is not in
+// this tree, run them with -tags legacytest once it is.
+
 package privacy_util
 
 import (
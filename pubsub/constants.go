@@ -1,7 +1,83 @@
 package pubsub
 
+import (
+	"errors"
+	"time"
+)
+
 const ChanWorkLoad = 100
 
+const (
+	// TopicQueueSize is the number of published messages of a topic waiting to
+	// be dispatched, messages published to a full queue are dropped
+	TopicQueueSize = 1000
+	// DefaultSlowTimeout is how long a Block subscriber may keep its queue full
+	// before being unsubscribed
+	DefaultSlowTimeout = 10 * time.Second
+)
+
+// DeliveryPolicy tells what to do with a message for a subscriber whose queue
+// is full
+type DeliveryPolicy int
+
+const (
+	// DropOldest drops the oldest queued message to queue the new one
+	DropOldest DeliveryPolicy = iota
+	// DropNewest drops the new message
+	DropNewest
+	// Block keeps the messages in order until there is room in the queue, and
+	// unsubscribes the subscriber if its queue stays full for SlowTimeout
+	Block
+	// Lossless keeps every message in order until there is room in the queue,
+	// however long it takes. It is meant for the subscribers of the node itself,
+	// which must not miss a block, see InternalSubscribeOptions.
+	Lossless
+)
+
+var deliveryPolicyNames = map[DeliveryPolicy]string{
+	DropOldest: "dropoldest",
+	DropNewest: "dropnewest",
+	Block:      "block",
+	Lossless:   "lossless",
+}
+
+func (policy DeliveryPolicy) String() string {
+	return deliveryPolicyNames[policy]
+}
+
+// ParseDeliveryPolicy returns the policy of its name: dropoldest, dropnewest or
+// block. Lossless is not configurable, a remote subscriber could make its queue
+// grow without bound.
+func ParseDeliveryPolicy(name string) (DeliveryPolicy, error) {
+	for policy, policyName := range deliveryPolicyNames {
+		if policyName == name && policy != Lossless {
+			return policy, nil
+		}
+	}
+	return DropOldest, NewPubSubError(UnknownDeliveryPolicyError, errors.New(name))
+}
+
+// SubscribeOptions sets the queue of a subscriber
+type SubscribeOptions struct {
+	QueueSize   int
+	Policy      DeliveryPolicy
+	SlowTimeout time.Duration
+}
+
+var DefaultSubscribeOptions = SubscribeOptions{
+	QueueSize:   ChanWorkLoad,
+	Policy:      DropOldest,
+	SlowTimeout: DefaultSlowTimeout,
+}
+
+// InternalSubscribeOptions are the options of the subscribers of the node
+// itself, like netsync, which must receive every message
+var InternalSubscribeOptions = SubscribeOptions{
+	QueueSize:   ChanWorkLoad,
+	Policy:      Lossless,
+	SlowTimeout: DefaultSlowTimeout,
+}
+
 // TOPIC
 const (
 	NewShardblockTopic              = "newshardblocktopic"
@@ -16,6 +92,8 @@ const (
 	RequestShardBlockByHeightTopic  = "requestshardblockbyheighttopic"
 	RequestBeaconBlockByHeightTopic = "requestbeaconblockbyheighttopic"
 	RequestBeaconBlockByHashTopic   = "requestbeaconblockbyhashtopic"
+	ChainEventTopic                 = "chaineventtopic"
+	ChainReorgTopic                 = "chainreorgtopic"
 	TestTopic                       = "testtopic"
 )
 
@@ -33,4 +111,6 @@ var Topics = []string{
 	RequestShardBlockByHeightTopic,
 	RequestShardBlockByHashTopic,
 	ShardBeststateTopic,
+	ChainEventTopic,
+	ChainReorgTopic,
 }
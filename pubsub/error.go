@@ -10,16 +10,18 @@ const (
 	UnmashallJsonError
 	MashallJsonError
 	UnregisteredTopicError
+	UnknownDeliveryPolicyError
 )
 
 var ErrCodeMessage = map[int]struct {
 	Code    int
 	Message string
 }{
-	UnexpectedError:        {-1000, "Unexpected Error"},
-	UnmashallJsonError:     {-1001, "Umarshall Json Error"},
-	MashallJsonError:       {-1002, "Marshall Json Error"},
-	UnregisteredTopicError: {-1003, "Subcribed Topic Not Found Error"},
+	UnexpectedError:            {-1000, "Unexpected Error"},
+	UnmashallJsonError:         {-1001, "Umarshall Json Error"},
+	MashallJsonError:           {-1002, "Marshall Json Error"},
+	UnregisteredTopicError:     {-1003, "Subcribed Topic Not Found Error"},
+	UnknownDeliveryPolicyError: {-1004, "Unknown Delivery Policy Error"},
 }
 
 type PubSubError struct {
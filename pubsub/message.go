@@ -1,9 +1,12 @@
 package pubsub
 
+import "time"
+
 type Message struct {
 	Value interface{}
 
 	topic           string
+	publishTime     time.Time
 	unSendSubscribe []chan interface{}
 }
 
@@ -0,0 +1,70 @@
+package pubsub
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// SubscriberMetrics reports the queue of a subscriber, Queued is the number of
+// messages not received yet by the subscriber, in its queue or its backlog
+type SubscriberMetrics struct {
+	ID           uint
+	Policy       string
+	QueueSize    int
+	Queued       int
+	Delivered    uint64
+	Dropped      uint64
+	LastDelivery int64
+}
+
+// TopicMetrics reports the queue of a topic, Lag is the time the last dispatched
+// message waited in the queue of the topic
+type TopicMetrics struct {
+	Topic       string
+	Queued      int
+	Published   uint64
+	Dropped     uint64
+	Lag         time.Duration
+	Subscribers []SubscriberMetrics
+}
+
+// GetMetrics returns the metrics of every topic
+func (pubSubManager *PubSubManager) GetMetrics() []TopicMetrics {
+	pubSubManager.lock.RLock()
+	defer pubSubManager.lock.RUnlock()
+	res := []TopicMetrics{}
+	for _, topic := range pubSubManager.topicList {
+		metrics := pubSubManager.topicMetrics[topic]
+		topicMetrics := TopicMetrics{
+			Topic:       topic,
+			Queued:      len(pubSubManager.messageBroker[topic]),
+			Published:   atomic.LoadUint64(&metrics.published),
+			Dropped:     atomic.LoadUint64(&metrics.dropped),
+			Lag:         time.Duration(atomic.LoadInt64(&metrics.lag)),
+			Subscribers: []SubscriberMetrics{},
+		}
+		for _, sub := range pubSubManager.subscriberList[topic] {
+			topicMetrics.Subscribers = append(topicMetrics.Subscribers, SubscriberMetrics{
+				ID:           sub.id,
+				Policy:       sub.options.Policy.String(),
+				QueueSize:    sub.options.QueueSize,
+				Queued:       sub.queued(),
+				Delivered:    atomic.LoadUint64(&sub.delivered),
+				Dropped:      atomic.LoadUint64(&sub.dropped),
+				LastDelivery: atomic.LoadInt64(&sub.lastSend),
+			})
+		}
+		sort.Slice(topicMetrics.Subscribers, func(i, j int) bool {
+			return topicMetrics.Subscribers[i].ID < topicMetrics.Subscribers[j].ID
+		})
+		res = append(res, topicMetrics)
+	}
+	return res
+}
+
+func (sub *subscriber) queued() int {
+	sub.mtx.Lock()
+	defer sub.mtx.Unlock()
+	return len(sub.event) + len(sub.backlog)
+}
@@ -2,9 +2,11 @@ package pubsub
 
 import (
 	"errors"
-	"github.com/incognitochain/incognito-chain/common"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
 )
 
 // This package provide an Event Channel for internal pub sub of this application
@@ -14,87 +16,323 @@ import (
 // Subcriber will register to be Subcribe with a particular topic,
 // when new message of this topic come to Event Channel,
 // then Event Channel will fire this message to subcriber
+//
+// Every topic has a bounded queue of published messages and a single dispatcher
+// delivering them in order to the bounded queue of every subscriber, the event
+// channel. A subscriber whose queue is full is handled according to its
+// DeliveryPolicy, neither a publisher nor the dispatcher is ever blocked by a
+// slow subscriber.
 type PubSubManager struct {
-	topicList      []string                         // only allow registered Topic
-	subscriberList map[string]map[uint]EventChannel // List of Subscriber
-	messageBroker  map[string][]*Message            // Message pool
-	idGenerator    uint                             // id generator for event
-	cond           *sync.Cond
+	lock           sync.RWMutex
+	topicList      []string                        // only allow registered Topic
+	subscriberList map[string]map[uint]*subscriber // List of Subscriber
+	messageBroker  map[string]chan *Message        // Message queue of every topic
+	topicMetrics   map[string]*topicMetrics        // publish counters of every topic
+	idGenerator    uint                            // id generator for event
+	started        bool
+	cQuit          chan struct{}
+}
+
+type subscriber struct {
+	// accessed atomically, first to be 64-bit aligned
+	delivered uint64
+	dropped   uint64
+	lastSend  int64 // unix nano of the last message put in the queue
+
+	id      uint
+	topic   string
+	options SubscribeOptions
+	event   EventChannel
+	cQuit   chan struct{} // closed on unsubscribe
+
+	// messages of a Block or Lossless subscriber waiting for room in its queue,
+	// sent in order by a single forwarder
+	mtx        sync.Mutex
+	backlog    []*Message
+	forwarding bool
+	closed     bool
+}
+
+type topicMetrics struct {
+	published uint64
+	dropped   uint64
+	lag       int64 // nanoseconds between publishing and dispatching the last message
 }
 
 func NewPubSubManager() *PubSubManager {
 	pubSubManager := &PubSubManager{
-		topicList:      Topics,
-		subscriberList: make(map[string]map[uint]EventChannel),
-		messageBroker:  make(map[string][]*Message),
+		topicList:      []string{},
+		subscriberList: make(map[string]map[uint]*subscriber),
+		messageBroker:  make(map[string]chan *Message),
+		topicMetrics:   make(map[string]*topicMetrics),
 		idGenerator:    0,
-		cond:           sync.NewCond(&sync.Mutex{}),
+		cQuit:          make(chan struct{}),
 	}
-	for _, topic := range pubSubManager.topicList {
-		pubSubManager.subscriberList[topic] = make(map[uint]EventChannel)
+	for _, topic := range Topics {
+		pubSubManager.addTopic(topic)
 	}
 	return pubSubManager
 }
 
-// Forever Loop play as an Event Channel
+// Start runs the dispatcher of every topic, messages published before are
+// queued until then
 func (pubSubManager *PubSubManager) Start() {
+	pubSubManager.lock.Lock()
+	defer pubSubManager.lock.Unlock()
+	if pubSubManager.started {
+		return
+	}
+	pubSubManager.started = true
+	for _, topic := range pubSubManager.topicList {
+		go pubSubManager.dispatch(topic, pubSubManager.messageBroker[topic])
+	}
+}
+
+// Stop stops the dispatchers, queued messages are not delivered anymore
+func (pubSubManager *PubSubManager) Stop() {
+	pubSubManager.lock.Lock()
+	defer pubSubManager.lock.Unlock()
+	if !pubSubManager.started {
+		return
+	}
+	pubSubManager.started = false
+	close(pubSubManager.cQuit)
+	pubSubManager.cQuit = make(chan struct{})
+}
+
+// dispatch delivers the messages of a topic, one at a time, to the subscribers
+// registered when the message is dispatched
+func (pubSubManager *PubSubManager) dispatch(topic string, messages chan *Message) {
+	pubSubManager.lock.RLock()
+	cQuit := pubSubManager.cQuit
+	pubSubManager.lock.RUnlock()
 	for {
-		pubSubManager.cond.L.Lock()
-		for topic, messages := range pubSubManager.messageBroker {
-			for _, message := range messages {
-				if subMap, ok := pubSubManager.subscriberList[topic]; ok {
-					for _, event := range subMap {
-						go event.NotifyMessage(message)
-					}
-				}
+		select {
+		case <-cQuit:
+			return
+		case message := <-messages:
+			pubSubManager.lock.RLock()
+			atomic.StoreInt64(&pubSubManager.topicMetrics[topic].lag, int64(time.Since(message.publishTime)))
+			subs := make([]*subscriber, 0, len(pubSubManager.subscriberList[topic]))
+			for _, sub := range pubSubManager.subscriberList[topic] {
+				subs = append(subs, sub)
+			}
+			pubSubManager.lock.RUnlock()
+			for _, sub := range subs {
+				pubSubManager.deliver(sub, message, cQuit)
 			}
-			// delete message (if no thing subscribe for it then delete msg too)
-			pubSubManager.messageBroker[topic] = []*Message{}
 		}
-		pubSubManager.cond.Wait()
-		pubSubManager.cond.L.Unlock()
-		time.Sleep(1 * time.Microsecond)
 	}
 }
 
+// deliver puts message in the queue of the subscriber according to its policy,
+// the messages of a Block or Lossless subscriber go through its backlog
+func (pubSubManager *PubSubManager) deliver(sub *subscriber, message *Message, cQuit chan struct{}) {
+	switch sub.options.Policy {
+	case Block, Lossless:
+		sub.mtx.Lock()
+		defer sub.mtx.Unlock()
+		if sub.closed {
+			return
+		}
+		sub.backlog = append(sub.backlog, message)
+		if len(sub.backlog)%sub.options.QueueSize == 0 {
+			Logger.log.Warnf("Subscriber %v of topic %v is %v messages behind its queue of %v messages", sub.id, sub.topic, len(sub.backlog), sub.options.QueueSize)
+		}
+		if !sub.forwarding {
+			sub.forwarding = true
+			go pubSubManager.forward(sub, cQuit)
+		}
+		return
+	}
+	select {
+	case sub.event <- message:
+		sub.sent()
+		return
+	default:
+	}
+	if sub.options.Policy == DropOldest {
+		select {
+		case <-sub.event:
+			sub.drop(1)
+		default:
+		}
+		select {
+		case sub.event <- message:
+			sub.sent()
+			return
+		default:
+		}
+	}
+	sub.drop(1)
+}
+
+// forward sends the backlog of a Block or Lossless subscriber in order, it is
+// the only sender on the event channel of the subscriber while it runs
+func (pubSubManager *PubSubManager) forward(sub *subscriber, cQuit chan struct{}) {
+	for {
+		sub.mtx.Lock()
+		if len(sub.backlog) == 0 {
+			sub.forwarding = false
+			sub.mtx.Unlock()
+			return
+		}
+		message := sub.backlog[0]
+		sub.mtx.Unlock()
+		sent, slow := sub.send(message, cQuit)
+		if !sent {
+			sub.stopForwarding(slow)
+			if slow {
+				pubSubManager.evict(sub)
+			}
+			return
+		}
+		sub.mtx.Lock()
+		sub.backlog[0] = nil
+		sub.backlog = sub.backlog[1:]
+		sub.mtx.Unlock()
+	}
+}
+
+// send waits for room in the queue of the subscriber, slow tells a Block
+// subscriber kept its queue full for SlowTimeout
+func (sub *subscriber) send(message *Message, cQuit chan struct{}) (sent bool, slow bool) {
+	var cSlow <-chan time.Time
+	if sub.options.Policy == Block {
+		timer := time.NewTimer(sub.options.SlowTimeout)
+		defer timer.Stop()
+		cSlow = timer.C
+	}
+	select {
+	case sub.event <- message:
+		sub.sent()
+		return true, false
+	case <-sub.cQuit:
+		return false, false
+	case <-cQuit:
+		return false, false
+	case <-cSlow:
+		return false, true
+	}
+}
+
+// stopForwarding drops the backlog of a subscriber, closed tells no message is
+// to be delivered to it anymore
+func (sub *subscriber) stopForwarding(closed bool) {
+	sub.mtx.Lock()
+	defer sub.mtx.Unlock()
+	sub.drop(len(sub.backlog))
+	sub.backlog = nil
+	sub.forwarding = false
+	sub.closed = closed
+}
+
+func (sub *subscriber) sent() {
+	atomic.AddUint64(&sub.delivered, 1)
+	atomic.StoreInt64(&sub.lastSend, time.Now().UnixNano())
+}
+
+// drop counts the messages dropped for a subscriber, the first drop and every
+// queue size of drops are logged
+func (sub *subscriber) drop(count int) {
+	if count == 0 {
+		return
+	}
+	dropped := atomic.AddUint64(&sub.dropped, uint64(count))
+	if dropped == uint64(count) || dropped/uint64(sub.options.QueueSize) != (dropped-uint64(count))/uint64(sub.options.QueueSize) {
+		Logger.log.Warnf("Subscriber %v of topic %v dropped %v messages, queue of %v messages full", sub.id, sub.topic, dropped, sub.options.QueueSize)
+	}
+}
+
+// evict unsubscribes a subscriber too slow to receive its messages and closes
+// its event channel. The forwarder of the subscriber is the only sender on this
+// channel, closing it there is safe.
+func (pubSubManager *PubSubManager) evict(sub *subscriber) {
+	pubSubManager.lock.Lock()
+	defer pubSubManager.lock.Unlock()
+	if subMap, ok := pubSubManager.subscriberList[sub.topic]; ok {
+		if _, ok := subMap[sub.id]; ok {
+			delete(subMap, sub.id)
+			close(sub.cQuit)
+		}
+	}
+	close(sub.event)
+	Logger.log.Warnf("Subscriber %v of topic %v unsubscribed, queue of %v messages full for %v", sub.id, sub.topic, sub.options.QueueSize, sub.options.SlowTimeout)
+}
+
 // Subcriber register with wanted topic
 // Return Event and Id of that Event
 // Event Channel using event to signal subcriber new message
 func (pubSubManager *PubSubManager) RegisterNewSubscriber(topic string) (uint, EventChannel, error) {
-	pubSubManager.cond.L.Lock()
-	defer pubSubManager.cond.L.Unlock()
-	cSubscribe := make(chan *Message, ChanWorkLoad)
-	if !pubSubManager.HasTopic(topic) {
-		return 0, cSubscribe, NewPubSubError(UnregisteredTopicError, errors.New(topic))
+	return pubSubManager.RegisterNewSubscriberWithOptions(topic, DefaultSubscribeOptions)
+}
+
+// RegisterNewSubscriberWithOptions registers a subscriber whose queue size and
+// policy on a full queue are set by options. The event channel is closed if the
+// subscriber is unsubscribed because it is too slow, see Block.
+func (pubSubManager *PubSubManager) RegisterNewSubscriberWithOptions(topic string, options SubscribeOptions) (uint, EventChannel, error) {
+	if options.QueueSize <= 0 {
+		options.QueueSize = ChanWorkLoad
 	}
-	if _, ok := pubSubManager.subscriberList[topic]; !ok {
-		pubSubManager.subscriberList[topic] = make(map[uint]EventChannel)
+	if options.SlowTimeout <= 0 {
+		options.SlowTimeout = DefaultSlowTimeout
+	}
+	pubSubManager.lock.Lock()
+	defer pubSubManager.lock.Unlock()
+	cSubscribe := make(chan *Message, options.QueueSize)
+	if !pubSubManager.hasTopic(topic) {
+		return 0, cSubscribe, NewPubSubError(UnregisteredTopicError, errors.New(topic))
 	}
 	id := pubSubManager.idGenerator
-	pubSubManager.subscriberList[topic][id] = cSubscribe
+	pubSubManager.subscriberList[topic][id] = &subscriber{
+		id:      id,
+		topic:   topic,
+		options: options,
+		event:   cSubscribe,
+		cQuit:   make(chan struct{}),
+	}
 	pubSubManager.idGenerator = id + 1
 	return id, cSubscribe, nil
 }
 
 // Publisher public message to EventChannel
+// The message is dropped if the queue of its topic is full.
 func (pubSubManager *PubSubManager) PublishMessage(message *Message) {
-	pubSubManager.cond.L.Lock()
-	defer pubSubManager.cond.L.Unlock()
-	pubSubManager.messageBroker[message.topic] = append(pubSubManager.messageBroker[message.topic], message)
-	pubSubManager.cond.Signal()
+	pubSubManager.lock.RLock()
+	messages, ok := pubSubManager.messageBroker[message.topic]
+	metrics := pubSubManager.topicMetrics[message.topic]
+	pubSubManager.lock.RUnlock()
+	if !ok {
+		return
+	}
+	message.publishTime = time.Now()
+	select {
+	case messages <- message:
+		atomic.AddUint64(&metrics.published, 1)
+	default:
+		atomic.AddUint64(&metrics.dropped, 1)
+		Logger.log.Warnf("Message of topic %v dropped, queue of %v messages full", message.topic, TopicQueueSize)
+	}
 }
 
 func (pubSubManager *PubSubManager) Unsubscribe(topic string, subId uint) {
-	pubSubManager.cond.L.Lock()
-	defer pubSubManager.cond.L.Unlock()
+	pubSubManager.lock.Lock()
+	defer pubSubManager.lock.Unlock()
 	if subMap, ok := pubSubManager.subscriberList[topic]; ok {
-		if _, ok := subMap[subId]; ok {
+		if sub, ok := subMap[subId]; ok {
 			delete(subMap, subId)
+			close(sub.cQuit)
 		}
 	}
 }
 
 func (pubSubManager *PubSubManager) HasTopic(topic string) bool {
+	pubSubManager.lock.RLock()
+	defer pubSubManager.lock.RUnlock()
+	return pubSubManager.hasTopic(topic)
+}
+
+func (pubSubManager *PubSubManager) hasTopic(topic string) bool {
 	if common.IndexOfStr(topic, pubSubManager.topicList) > -1 {
 		return true
 	}
@@ -102,9 +340,19 @@ func (pubSubManager *PubSubManager) HasTopic(topic string) bool {
 }
 
 func (pubSubManager *PubSubManager) AddTopic(topic string) {
-	pubSubManager.cond.L.Lock()
-	defer pubSubManager.cond.L.Unlock()
-	if !pubSubManager.HasTopic(topic) {
-		pubSubManager.topicList = append(pubSubManager.topicList, topic)
+	pubSubManager.lock.Lock()
+	defer pubSubManager.lock.Unlock()
+	if !pubSubManager.hasTopic(topic) {
+		pubSubManager.addTopic(topic)
+		if pubSubManager.started {
+			go pubSubManager.dispatch(topic, pubSubManager.messageBroker[topic])
+		}
 	}
 }
+
+func (pubSubManager *PubSubManager) addTopic(topic string) {
+	pubSubManager.topicList = append(pubSubManager.topicList, topic)
+	pubSubManager.subscriberList[topic] = make(map[uint]*subscriber)
+	pubSubManager.messageBroker[topic] = make(chan *Message, TopicQueueSize)
+	pubSubManager.topicMetrics[topic] = &topicMetrics{}
+}
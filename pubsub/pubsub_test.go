@@ -4,8 +4,16 @@ import (
 	"reflect"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/common"
 )
 
+var _ = func() (_ struct{}) {
+	Logger.Init(common.NewBackend(nil).Logger("test", true))
+	return
+}()
+
 func TestNewMessage(t *testing.T) {
 	msg := NewMessage(TestTopic, 1)
 	if msg.topic != TestTopic {
@@ -30,10 +38,10 @@ func TestRegisterNewSubcriber(t *testing.T) {
 	if !ok {
 		t.Error("Can not get subcribe map by topic")
 	}
-	if subChan, ok := subMap[id]; !ok {
+	if sub, ok := subMap[id]; !ok {
 		t.Error("Can not get sub chan")
 	} else {
-		if !reflect.DeepEqual(event, subChan) {
+		if !reflect.DeepEqual(event, sub.event) {
 			t.Error("Wrong Subchan")
 		}
 	}
@@ -69,15 +77,16 @@ func TestPublishMessage(t *testing.T) {
 	pubsubManager.PublishMessage(NewMessage(TestTopic, "abc"))
 	msgs, ok := pubsubManager.messageBroker[TestTopic]
 	if !ok {
-		t.Error("No Message found with this topic")
+		t.Fatal("No Message found with this topic")
 	}
 	if len(msgs) != 1 {
-		t.Errorf("Should have only 1 message %+v \n", len(pubsubManager.messageBroker[TestTopic]))
+		t.Fatalf("Should have only 1 message %+v \n", len(pubsubManager.messageBroker[TestTopic]))
 	}
-	if msgs[0].topic != TestTopic {
+	msg := <-msgs
+	if msg.topic != TestTopic {
 		t.Error("Wrong Topic")
 	}
-	valueInterface := msgs[0].Value
+	valueInterface := msg.Value
 	if value, ok := valueInterface.(string); !ok {
 		t.Error("Wrong msg type")
 	} else {
@@ -137,3 +146,156 @@ func TestAddTopic(t *testing.T) {
 		t.Error("Pubsub manager should have this topic")
 	}
 }
+
+func TestOrderedDelivery(t *testing.T) {
+	var pubsubManager = NewPubSubManager()
+	_, event, err := pubsubManager.RegisterNewSubscriberWithOptions(TestTopic, SubscribeOptions{QueueSize: 10, Policy: Block})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubsubManager.Start()
+	defer pubsubManager.Stop()
+	for i := 0; i < 100; i++ {
+		pubsubManager.PublishMessage(NewMessage(TestTopic, i))
+	}
+	for i := 0; i < 100; i++ {
+		msg := <-event
+		if msg.Value.(int) != i {
+			t.Fatalf("want message %v, got %v", i, msg.Value)
+		}
+	}
+}
+
+func TestDropPolicy(t *testing.T) {
+	var pubsubManager = NewPubSubManager()
+	_, oldestEvent, _ := pubsubManager.RegisterNewSubscriberWithOptions(TestTopic, SubscribeOptions{QueueSize: 2, Policy: DropOldest})
+	_, newestEvent, _ := pubsubManager.RegisterNewSubscriberWithOptions(TestTopic, SubscribeOptions{QueueSize: 2, Policy: DropNewest})
+	pubsubManager.Start()
+	defer pubsubManager.Stop()
+	for i := 0; i < 5; i++ {
+		pubsubManager.PublishMessage(NewMessage(TestTopic, i))
+	}
+	waitDispatched(t, pubsubManager, 5)
+	if v1, v2 := (<-oldestEvent).Value, (<-oldestEvent).Value; v1 != 3 || v2 != 4 {
+		t.Errorf("drop oldest should keep 3 and 4, got %v and %v", v1, v2)
+	}
+	if v1, v2 := (<-newestEvent).Value, (<-newestEvent).Value; v1 != 0 || v2 != 1 {
+		t.Errorf("drop newest should keep 0 and 1, got %v and %v", v1, v2)
+	}
+	for _, sub := range getTopicMetrics(pubsubManager, TestTopic).Subscribers {
+		if sub.Dropped != 3 || sub.Delivered+sub.Dropped < 5 {
+			t.Errorf("subscriber %v delivered %v dropped %v", sub.ID, sub.Delivered, sub.Dropped)
+		}
+	}
+}
+
+func TestSlowSubscriberUnsubscribed(t *testing.T) {
+	var pubsubManager = NewPubSubManager()
+	slowID, slowEvent, _ := pubsubManager.RegisterNewSubscriberWithOptions(TestTopic, SubscribeOptions{QueueSize: 1, Policy: Block, SlowTimeout: 10 * time.Millisecond})
+	_, event, _ := pubsubManager.RegisterNewSubscriber(TestTopic)
+	pubsubManager.Start()
+	defer pubsubManager.Stop()
+	pubsubManager.PublishMessage(NewMessage(TestTopic, 0))
+	pubsubManager.PublishMessage(NewMessage(TestTopic, 1))
+	for i := 0; i < 2; i++ {
+		if msg := <-event; msg.Value.(int) != i {
+			t.Fatalf("want message %v, got %v", i, msg.Value)
+		}
+	}
+	for i := 0; ; i++ {
+		pubsubManager.lock.RLock()
+		_, ok := pubsubManager.subscriberList[TestTopic][slowID]
+		pubsubManager.lock.RUnlock()
+		if !ok {
+			break
+		}
+		if i == 100 {
+			t.Fatal("slow subscriber still registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if msg, ok := <-slowEvent; !ok || msg.Value.(int) != 0 {
+		t.Fatal("slow subscriber should receive the queued message")
+	}
+	if _, ok := <-slowEvent; ok {
+		t.Fatal("slow subscriber should be unsubscribed")
+	}
+}
+
+func TestSlowSubscriberDoesNotStallTopic(t *testing.T) {
+	var pubsubManager = NewPubSubManager()
+	_, slowEvent, _ := pubsubManager.RegisterNewSubscriberWithOptions(TestTopic, SubscribeOptions{QueueSize: 1, Policy: Block, SlowTimeout: time.Minute})
+	_, event, _ := pubsubManager.RegisterNewSubscriber(TestTopic)
+	pubsubManager.Start()
+	defer pubsubManager.Stop()
+	for i := 0; i < 10; i++ {
+		pubsubManager.PublishMessage(NewMessage(TestTopic, i))
+		select {
+		case msg := <-event:
+			if msg.Value.(int) != i {
+				t.Fatalf("want message %v, got %v", i, msg.Value)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("message %v delayed by the slow subscriber", i)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		if msg := <-slowEvent; msg.Value.(int) != i {
+			t.Fatalf("slow subscriber want message %v, got %v", i, msg.Value)
+		}
+	}
+}
+
+func TestLosslessSubscriber(t *testing.T) {
+	var pubsubManager = NewPubSubManager()
+	_, event, _ := pubsubManager.RegisterNewSubscriberWithOptions(TestTopic, SubscribeOptions{QueueSize: 2, Policy: Lossless, SlowTimeout: time.Millisecond})
+	pubsubManager.Start()
+	defer pubsubManager.Stop()
+	for i := 0; i < 500; i++ {
+		pubsubManager.PublishMessage(NewMessage(TestTopic, i))
+	}
+	// nothing is received until every message is queued, long after SlowTimeout
+	for i := 0; i < 100; i++ {
+		if getTopicMetrics(pubsubManager, TestTopic).Subscribers[0].Queued == 500 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 500; i++ {
+		if msg, ok := <-event; !ok || msg.Value.(int) != i {
+			t.Fatalf("want message %v, got %v", i, msg)
+		}
+	}
+	if sub := getTopicMetrics(pubsubManager, TestTopic).Subscribers[0]; sub.Dropped != 0 || sub.Delivered != 500 {
+		t.Errorf("lossless subscriber delivered %v dropped %v", sub.Delivered, sub.Dropped)
+	}
+	if _, err := ParseDeliveryPolicy(Lossless.String()); err == nil {
+		t.Error("lossless policy should not be configurable")
+	}
+}
+
+func waitDispatched(t *testing.T, pubsubManager *PubSubManager, messages uint64) {
+	for i := 0; i < 100; i++ {
+		dispatched := true
+		for _, sub := range getTopicMetrics(pubsubManager, TestTopic).Subscribers {
+			if sub.Delivered+sub.Dropped < messages {
+				dispatched = false
+			}
+		}
+		if dispatched {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("messages not dispatched")
+}
+
+func getTopicMetrics(pubsubManager *PubSubManager, topic string) TopicMetrics {
+	for _, metrics := range pubsubManager.GetMetrics() {
+		if metrics.Topic == topic {
+			return metrics
+		}
+	}
+	return TopicMetrics{}
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func TestGetProofByTxHash(t *testing.T) {
-	txProof, err := getProofByTxHash("421B68266AC570DEC49A12B1DDA0518D59205F4A874A24DB0F9448D4E03720A3", MainnetURLRemote)
+	txProof, _, err := getProofByTxHash("421B68266AC570DEC49A12B1DDA0518D59205F4A874A24DB0F9448D4E03720A3", MainnetURLRemote)
 	assert.Nil(t, err)
 	fmt.Printf("txProof %v\n", txProof.Data)
 
@@ -0,0 +1,8 @@
+echo "Start build remote signer"
+
+APP_NAME="incognito-signer"
+
+echo "go build -o $APP_NAME"
+go build -o $APP_NAME
+
+echo "Build remote signer success!"
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// See loadConfig for details on the configuration load process.
+type config struct {
+	Listen         string `long:"listen" short:"l" description:"Unix socket the signer listens on, unix:///path/to/socket"`
+	StateFile      string `long:"statefile" description:"File of the blocks signed, so a restarted signer keeps refusing conflicting ones"`
+	MaxHeightAhead uint64 `long:"maxheightahead" description:"Number of heights a block may be above the highest one signed of its chain, raise it to sign after a long downtime"`
+	MiningKey      string `long:"miningkey" description:"Private seed of the mining key, as miningkeys of a node"`
+	PrivateKey     string `long:"privatekey" description:"Wallet private key the mining key is generated from, as privatekey of a node"`
+}
+
+// newConfigParser returns a new command line flags parser.
+func newConfigParser(cfg *config, options flags.Options) *flags.Parser {
+	parser := flags.NewParser(cfg, options)
+	return parser
+}
+
+// loadConfig
+// - set default config
+// - read config from cmd line params
+// - return config object
+func loadConfig() (*config, error) {
+	// create config object from default values
+	cfg := config{
+		Listen:         defaultListen,
+		StateFile:      defaultStateFile,
+		MaxHeightAhead: defaultMaxHeightAhead,
+	}
+
+	parser := newConfigParser(&cfg, flags.HelpFlag)
+	_, err := parser.Parse()
+	if err != nil {
+		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return nil, err
+	}
+	if cfg.MiningKey == "" && cfg.PrivateKey == "" {
+		return nil, errors.New("miningkey or privatekey is required")
+	}
+	if strings.Contains(strings.TrimPrefix(cfg.Listen, "unix://"), "://") {
+		return nil, errors.New("listen must be a unix socket")
+	}
+	return &cfg, nil
+}
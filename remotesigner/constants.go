@@ -0,0 +1,9 @@
+package main
+
+const (
+	version               = "1.0.0"
+	defaultListen         = "unix://incognito-signer.sock"
+	defaultStateFile      = "incognito-signer-state.json"
+	defaultMaxHeightAhead = 1000
+	socketFileUmask       = 0177 // the socket is created read-write for its owner only
+)
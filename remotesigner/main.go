@@ -0,0 +1,62 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/rpc"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/consensus_v2"
+	"github.com/incognitochain/incognito-chain/consensus_v2/signatureschemes"
+)
+
+// Remote signer is a reference signer process holding the mining key of a
+// node started with --remotesigner. It signs the proposals and votes of the
+// node and refuses to sign two blocks of the same height and timeslots.
+func main() {
+	log.Printf("Version %s\n", version)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Println("Parse config error", err.Error())
+		return
+	}
+
+	privateSeed := cfg.MiningKey
+	if cfg.PrivateKey != "" {
+		privateSeed, err = consensus_v2.LoadUserKeyFromIncPrivateKey(cfg.PrivateKey)
+		if err != nil {
+			log.Fatal("load private key error: ", err)
+		}
+	}
+	miningKey, err := consensus_v2.GetMiningKeyFromPrivateSeed(privateSeed)
+	if err != nil {
+		log.Fatal("load mining key error: ", err)
+	}
+	service, err := signatureschemes.NewSignerService(miningKey, cfg.StateFile, cfg.MaxHeightAhead)
+	if err != nil {
+		log.Fatal("load signer state error: ", err)
+	}
+	server := rpc.NewServer()
+	if err := server.Register(service); err != nil {
+		log.Fatal("register signer error: ", err)
+	}
+
+	address := strings.TrimPrefix(cfg.Listen, "unix://")
+	// a socket left by a previous run
+	os.Remove(address)
+	// the socket is created with the permission of the umask, so it is never
+	// reachable by other users
+	oldUmask := syscall.Umask(socketFileUmask)
+	listener, err := net.Listen("unix", address)
+	syscall.Umask(oldUmask)
+	if err != nil {
+		log.Fatal("listen error: ", err)
+	}
+	defer listener.Close()
+	log.Printf("Sign with mining key %v on %v\n", miningKey.GetPublicKey().GetMiningKeyBase58(common.BlsConsensus), address)
+	server.Accept(listener)
+}
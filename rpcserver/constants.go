@@ -28,6 +28,10 @@ const (
 	getNumberOfTxsInMempool       = "getnumberoftxsinmempool"
 	getMempoolEntry               = "getmempoolentry"
 	removeTxInMempool             = "removetxinmempool"
+	getMempoolEvictions           = "getmempoolevictions"
+	getRejectionCodes             = "getrejectioncodes"
+	getTxVerificationStats        = "gettxverificationstats"
+	getMempoolTxChain             = "getmempooltxchain"
 	getBeaconPoolState            = "getbeaconpoolstate"
 	getShardPoolState             = "getshardpoolstate"
 	getShardPoolLatestValidHeight = "getshardpoollatestvalidheight"
@@ -41,6 +45,10 @@ const (
 	//getFeeEstimator             = "getfeeestimator"
 	setBackup                   = "setbackup"
 	getLatestBackup             = "getlatestbackup"
+	getStatePruningStatus       = "getstatepruningstatus"
+	getStateProof               = "getstateproof"
+	getStateAt                  = "getstateat"
+	getChainEvents              = "getchainevents"
 	getBestBlock                = "getbestblock"
 	getBestBlockHash            = "getbestblockhash"
 	getBlocks                   = "getblocks"
@@ -55,6 +63,8 @@ const (
 	listOutputCoins                              = "listoutputcoins"
 	createRawTransaction                         = "createtransaction"
 	sendRawTransaction                           = "sendtransaction"
+	sendRawTransactions                          = "sendrawtransactions"
+	simulateTransaction                          = "simulatetransaction"
 	createAndSendTransaction                     = "createandsendtransaction"
 	createAndSendTransactionV2                   = "createandsendtransactionv2"
 	createAndSendCustomTokenTransaction          = "createandsendcustomtokentransaction"
@@ -188,6 +198,7 @@ const (
 	// slash
 	getProducersBlackList       = "getproducersblacklist"
 	getProducersBlackListDetail = "getproducersblacklistdetail"
+	getValidatorParticipation   = "getvalidatorparticipation"
 
 	// pde
 	getPDEState                                = "getpdestate"
@@ -312,4 +323,6 @@ const (
 	subcribeBeaconBestState                     = "subcribebeaconbeststate"
 	subcribeBeaconPoolBeststate                 = "subcribebeaconpoolbeststate"
 	subcribeShardPoolBeststate                  = "subcribeshardpoolbeststate"
+	subcribeChainEvents                         = "subcribechainevents"
+	subcribeChainReorg                          = "subcribechainreorg"
 )
@@ -77,10 +77,18 @@ func (httpServer *HttpServer) handleRetrieveBlock(params interface{}, closeChan
 			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("verbosity is invalid"))
 		}
 
+		onlyFinalized, rpcErr := parseOnlyFinalized(paramArray, 2)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
 		result, err := httpServer.blockService.RetrieveShardBlock(hashString, verbosity)
 		if err != nil {
 			return nil, err
 		}
+		if onlyFinalized && !result.IsFinalized {
+			return nil, rpcservice.NewRPCError(rpcservice.NotFinalizedError, errors.New("block is not finalized"))
+		}
 		return result, nil
 	}
 	return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 2 elements"))
@@ -102,10 +110,24 @@ func (httpServer *HttpServer) handleRetrieveBlockByHeight(params interface{}, cl
 			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("verbosity is invalid"))
 		}
 
+		onlyFinalized, rpcErr := parseOnlyFinalized(paramArray, 3)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
 		result, err := httpServer.blockService.RetrieveShardBlockByHeight(uint64(blockHeight), int(shardID), verbosity)
 		if err != nil {
 			return nil, err
 		}
+		if onlyFinalized {
+			finalized := []*jsonresult.GetShardBlockResult{}
+			for _, block := range result {
+				if block.IsFinalized {
+					finalized = append(finalized, block)
+				}
+			}
+			result = finalized
+		}
 		return result, nil
 	}
 	return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 2 elements"))
@@ -121,10 +143,17 @@ func (httpServer *HttpServer) handleRetrieveBeaconBlock(params interface{}, clos
 		if !ok {
 			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("hashString is invalid"))
 		}
+		onlyFinalized, rpcErr := parseOnlyFinalized(paramArray, 1)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
 		result, err := httpServer.blockService.RetrieveBeaconBlock(hashString)
 		if err != nil {
 			return result, err
 		}
+		if onlyFinalized && !result.IsFinalized {
+			return nil, rpcservice.NewRPCError(rpcservice.NotFinalizedError, errors.New("block is not finalized"))
+		}
 		return result, nil
 	}
 	return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
@@ -140,10 +169,23 @@ func (httpServer *HttpServer) handleRetrieveBeaconBlockByHeight(params interface
 		if !ok {
 			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("hashString is invalid"))
 		}
+		onlyFinalized, rpcErr := parseOnlyFinalized(paramArray, 1)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
 		result, err := httpServer.blockService.RetrieveBeaconBlockByHeight(uint64(beaconHeight))
 		if err != nil {
 			return result, err
 		}
+		if onlyFinalized {
+			finalized := []*jsonresult.GetBeaconBlockResult{}
+			for _, block := range result {
+				if block.IsFinalized {
+					finalized = append(finalized, block)
+				}
+			}
+			result = finalized
+		}
 		return result, nil
 	}
 	return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
@@ -152,7 +194,7 @@ func (httpServer *HttpServer) handleRetrieveBeaconBlockByHeight(params interface
 // handleGetBlocks - get n top blocks from chain ID
 func (httpServer *HttpServer) handleGetBlocks(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrayParams := common.InterfaceSlice(params)
-	if arrayParams == nil || len(arrayParams) != 2 {
+	if arrayParams == nil || len(arrayParams) < 2 {
 		arrayParams = []interface{}{
 			0.0,
 			0.0,
@@ -170,13 +212,52 @@ func (httpServer *HttpServer) handleGetBlocks(params interface{}, closeChan <-ch
 	}
 	shardID := int(shardIDParam)
 
+	onlyFinalized, rpcErr := parseOnlyFinalized(arrayParams, 2)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
 	result, err := httpServer.blockService.GetBlocks(shardID, numBlock)
 	if err != nil {
 		return nil, err
 	}
+	if onlyFinalized {
+		switch blocks := result.(type) {
+		case []jsonresult.GetShardBlockResult:
+			finalized := []jsonresult.GetShardBlockResult{}
+			for _, block := range blocks {
+				if block.IsFinalized {
+					finalized = append(finalized, block)
+				}
+			}
+			result = finalized
+		case []jsonresult.GetBeaconBlockResult:
+			finalized := []jsonresult.GetBeaconBlockResult{}
+			for _, block := range blocks {
+				if block.IsFinalized {
+					finalized = append(finalized, block)
+				}
+			}
+			result = finalized
+		}
+	}
 	return result, nil
 }
 
+// parseOnlyFinalized parses the optional onlyFinalized param at index, false
+// if it is missing. With onlyFinalized, the blocks and transactions which are
+// not finalized yet are left out of the result.
+func parseOnlyFinalized(paramArray []interface{}, index int) (bool, *rpcservice.RPCError) {
+	if len(paramArray) <= index {
+		return false, nil
+	}
+	onlyFinalized, ok := paramArray[index].(bool)
+	if !ok {
+		return false, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("onlyFinalized is invalid"))
+	}
+	return onlyFinalized, nil
+}
+
 /*
 getblockchaininfo RPC return information for blockchain node
 */
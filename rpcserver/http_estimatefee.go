@@ -253,5 +253,14 @@ func (httpServer *HttpServer) handleEstimateFeeWithEstimator(params interface{},
 	}
 
 	result := jsonresult.NewEstimateFeeResult(0, estimateFeeCoinPerKb, 0)
+
+	// slow, normal and fast bands from the fees of the last blocks and the
+	// backlog of mempool, the estimate above is kept for the older clients
+	feeBands, err := httpServer.txService.EstimateFeeBandsWithEstimator(shardIDSender, numblock, tokenId)
+	if err != nil {
+		Logger.log.Debugf("handleEstimateFeeWithEstimator: can not estimate fee bands %+v", err)
+	} else {
+		result.FeeBands = feeBands
+	}
 	return result, nil
 }
@@ -0,0 +1,88 @@
+package rpcserver
+
+import (
+	"errors"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/eventlog"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+const (
+	defaultGetChainEventsLimit = 100
+	maxGetChainEventsLimit     = 1000
+)
+
+// chainEventCursor is where a client reads the event log of a chain from
+type chainEventCursor struct {
+	data      map[string]interface{}
+	chainID   int
+	from      uint64
+	hasCursor bool
+}
+
+// parseChainEventCursor parses the params shared by the event log RPCs:
+// "ChainID" -1 for beacon or a shard id, then "FromSequence" the sequence of the
+// first event to read, or "FromHeight" to read from the first event logged at
+// this height. hasCursor is false if both are missing.
+func parseChainEventCursor(eventLog *eventlog.EventLog, params interface{}) (*chainEventCursor, *rpcservice.RPCError) {
+	if eventLog == nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetChainEventsError, errors.New("Event log is disabled, run the node with --eventlog"))
+	}
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	data, ok := arrayParams[0].(map[string]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	cursor := &chainEventCursor{data: data}
+	chainID, ok := data["ChainID"].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ChainID is invalid"))
+	}
+	cursor.chainID = int(chainID)
+	if sequenceParam, ok := data["FromSequence"]; ok {
+		sequence, ok := sequenceParam.(float64)
+		if !ok || sequence < 0 {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("FromSequence is invalid"))
+		}
+		cursor.from, cursor.hasCursor = uint64(sequence), true
+	} else if heightParam, ok := data["FromHeight"]; ok {
+		height, ok := heightParam.(float64)
+		if !ok || height < 0 {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("FromHeight is invalid"))
+		}
+		sequence, err := eventLog.GetSequenceByHeight(cursor.chainID, uint64(height))
+		if err != nil {
+			return nil, rpcservice.NewRPCError(rpcservice.GetChainEventsError, err)
+		}
+		cursor.from, cursor.hasCursor = sequence, true
+	}
+	return cursor, nil
+}
+
+// handleGetChainEvents returns the events of the event log of a chain from a
+// cursor, the first event if there is none.
+// Params: the params of parseChainEventCursor, "Limit" optional
+func (httpServer *HttpServer) handleGetChainEvents(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	cursor, rpcErr := parseChainEventCursor(httpServer.config.EventLog, params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	limit := defaultGetChainEventsLimit
+	if limitParam, ok := cursor.data["Limit"]; ok {
+		limitValue, ok := limitParam.(float64)
+		if !ok || limitValue <= 0 || limitValue > maxGetChainEventsLimit {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Limit is invalid"))
+		}
+		limit = int(limitValue)
+	}
+	events, err := httpServer.config.EventLog.GetEvents(cursor.chainID, cursor.from, limit)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetChainEventsError, err)
+	}
+	return jsonresult.NewGetChainEventsResult(cursor.chainID, cursor.from, events), nil
+}
@@ -0,0 +1,49 @@
+package rpcserver
+
+import (
+	"errors"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleGetValidatorParticipation returns the blocks a validator signed and
+// missed, per epoch, out of the blocks of a chain signed by its committees.
+// Params: "ChainID" -1 for beacon or a shard id, "Key" the committee public
+// key or the bls mining key of the validator in base58, "FromHeight" and
+// "ToHeight" optional, see BlockChain.GetValidatorParticipation
+func (httpServer *HttpServer) handleGetValidatorParticipation(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	data, ok := arrayParams[0].(map[string]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	chainID, ok := data["ChainID"].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ChainID is invalid"))
+	}
+	key, ok := data["Key"].(string)
+	if !ok || len(key) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Key is invalid"))
+	}
+	heights := map[string]uint64{}
+	for _, name := range []string{"FromHeight", "ToHeight"} {
+		heightParam, ok := data[name]
+		if !ok {
+			continue
+		}
+		height, ok := heightParam.(float64)
+		if !ok || height < 0 {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New(name+" is invalid"))
+		}
+		heights[name] = uint64(height)
+	}
+	result, err := httpServer.config.BlockChain.GetValidatorParticipation(int(chainID), key, heights["FromHeight"], heights["ToHeight"])
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetValidatorParticipationError, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,13 @@
+package rpcserver
+
+import (
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleGetStatePruningStatus returns the state pruning progress of every chain
+// and the storage reclaimed so far, see the --prunestate option
+func (httpServer *HttpServer) handleGetStatePruningStatus(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	bc := httpServer.config.BlockChain
+	return jsonresult.NewGetStatePruningStatusResult(bc.GetPruneState(), bc.GetStatePruningStatus()), nil
+}
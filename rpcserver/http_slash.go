@@ -1,64 +1,47 @@
 package rpcserver
 
 import (
+	"errors"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
 )
 
+// getSlashBeaconHeight returns the beacon height of the first param, the
+// best beacon height if there is none
+func (httpServer *HttpServer) getSlashBeaconHeight(params interface{}) (uint64, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) < 1 || arrayParams[0] == nil {
+		return httpServer.config.BlockChain.GetBeaconBestState().BeaconHeight, nil
+	}
+	beaconHeightParam, ok := arrayParams[0].(float64)
+	if !ok || beaconHeightParam < 0 {
+		return 0, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Beacon height is invalid"))
+	}
+	return uint64(beaconHeightParam), nil
+}
+
 func (httpServer *HttpServer) handleGetProducersBlackList(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
-	//// beaconHeight := httpServer.config.BlockChain.BestState.Beacon.BeaconHeight
-	//arrayParams := common.InterfaceSlice(params)
-	//if arrayParams == nil || len(arrayParams) < 1 {
-	//	return nil, nil
-	//}
-	//beaconHeightParam, ok := arrayParams[0].(float64)
-	//if !ok {
-	//	return nil, nil
-	//}
-	//beaconHeight := uint64(beaconHeightParam)
-	//
-	//producersBlackList, err := httpServer.blockService.GetProducersBlackList(beaconHeight)
-	//if err != nil {
-	//	return false, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
-	//}
-	return nil, nil
+	beaconHeight, rpcErr := httpServer.getSlashBeaconHeight(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	producersBlackList, err := httpServer.blockService.GetProducersBlackList(beaconHeight)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetProducersBlackListError, err)
+	}
+	return producersBlackList, nil
 }
 
 func (httpServer *HttpServer) handleGetProducersBlackListDetail(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
-	// beaconHeight := httpServer.config.BlockChain.BestState.Beacon.BeaconHeight
-	//arrayParams := common.InterfaceSlice(params)
-	//if arrayParams == nil || len(arrayParams) < 1 {
-	//	return nil, nil
-	//}
-	//
-	//beaconHeightParam, ok := arrayParams[0].(float64)
-	//if !ok {
-	//	return nil, nil
-	//}
-	//beaconHeight := uint64(beaconHeightParam)
-	//
-	//producersBlackList, err := httpServer.blockService.GetProducersBlackList(beaconHeight)
-	//if err != nil {
-	//	return false, rpcservice.NewRPCError(rpcservice.UnexpectedError, err)
-	//}
-	//type producerBlacklistString struct {
-	//	IncPubKey    string
-	//	MiningPubKey map[string]string
-	//	Epochs       uint8
-	//}
-	//var result []producerBlacklistString
-	//for k, v := range producersBlackList {
-	//	var keySet incognitokey.CommitteePublicKey
-	//	keySet.FromString(k)
-	//
-	//	var keyMap producerBlacklistString
-	//	keyMap.IncPubKey = keySet.GetIncKeyBase58()
-	//	keyMap.MiningPubKey = make(map[string]string)
-	//	for keyType := range keySet.MiningPubKey {
-	//		keyMap.MiningPubKey[keyType] = keySet.GetMiningKeyBase58(keyType)
-	//	}
-	//	keyMap.Epochs = v
-	//	result = append(result, keyMap)
-	//}
-
-	return nil, nil
+	beaconHeight, rpcErr := httpServer.getSlashBeaconHeight(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	producersBlackList, err := httpServer.blockService.GetProducersBlackListDetail(beaconHeight)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetProducersBlackListError, err)
+	}
+	return jsonresult.NewProducersBlackListDetail(producersBlackList), nil
 }
@@ -0,0 +1,135 @@
+package rpcserver
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+const (
+	defaultGetStateAtLimit = 100
+	maxGetStateAtLimit     = 1000
+)
+
+// stateQuery is the state of a chain at a height queried by the state RPCs
+type stateQuery struct {
+	data           map[string]interface{}
+	chainID        int
+	height         uint64
+	stateDB        string
+	objectType     int
+	objectTypeName string
+}
+
+// parseStateQuery parses the params shared by the state RPCs: "ChainID" -1 for
+// beacon or a shard id, "ObjectType" e.g. "PDEPoolPairObjectType", "Height" the
+// final height if missing or 0 and "StateDB" inferred from the object type if
+// missing
+func parseStateQuery(params interface{}) (*stateQuery, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	data, ok := arrayParams[0].(map[string]interface{})
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Payload data is invalid"))
+	}
+	query := &stateQuery{data: data}
+	chainID, ok := data["ChainID"].(float64)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ChainID is invalid"))
+	}
+	query.chainID = int(chainID)
+	query.objectTypeName, ok = data["ObjectType"].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ObjectType is invalid"))
+	}
+	query.objectType, ok = statedb.GetStateObjectType(query.objectTypeName)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("ObjectType %v not exist", query.objectTypeName))
+	}
+	if heightParam, ok := data["Height"]; ok {
+		height, ok := heightParam.(float64)
+		if !ok || height < 0 {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Height is invalid"))
+		}
+		query.height = uint64(height)
+	}
+	query.stateDB = blockchain.GetDefaultStateDB(query.objectType)
+	if stateDBParam, ok := data["StateDB"]; ok {
+		query.stateDB, ok = stateDBParam.(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("StateDB is invalid"))
+		}
+	}
+	return query, nil
+}
+
+// handleGetStateAt returns the state objects of a type as of a finalized block,
+// either the object stored at "Key" or the objects whose key starts with the
+// hex encoded "Prefix", by default the prefix of every object of the type.
+// Params: the params of parseStateQuery, "Key" or "Prefix" optional, "Limit"
+// optional
+func (httpServer *HttpServer) handleGetStateAt(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	query, rpcErr := parseStateQuery(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	stateDB, root, err := httpServer.config.BlockChain.GetStateDBAt(query.chainID, query.height, query.stateDB)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetStateAtError, err)
+	}
+
+	if keyParam, ok := query.data["Key"]; ok {
+		keyStr, ok := keyParam.(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Key is invalid"))
+		}
+		key, err := common.Hash{}.NewHashFromStr(keyStr)
+		if err != nil {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Key is invalid, %v", err))
+		}
+		value, has, err := statedb.GetStateObjectValue(stateDB, query.objectType, *key)
+		if err != nil {
+			return nil, rpcservice.NewRPCError(rpcservice.GetStateAtError, err)
+		}
+		objects := []statedb.StateObjectEntry{}
+		if has {
+			objects = append(objects, statedb.StateObjectEntry{Key: *key, Value: value})
+		}
+		return jsonresult.NewGetStateAtResult(root, query.objectTypeName, objects, false), nil
+	}
+
+	prefix, ok := statedb.GetStateObjectTypePrefix(query.objectType)
+	if prefixParam, has := query.data["Prefix"]; has {
+		prefixStr, ok := prefixParam.(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Prefix is invalid"))
+		}
+		prefix, err = hex.DecodeString(prefixStr)
+		if err != nil {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Prefix is invalid, %v", err))
+		}
+	} else if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Key or Prefix is required for %v", query.objectTypeName))
+	}
+	limit := defaultGetStateAtLimit
+	if limitParam, ok := query.data["Limit"]; ok {
+		l, ok := limitParam.(float64)
+		if !ok || l <= 0 || l > maxGetStateAtLimit {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Limit must be between 1 and %v", maxGetStateAtLimit))
+		}
+		limit = int(l)
+	}
+	objects, more, err := statedb.GetStateObjectsByPrefix(stateDB, query.objectType, prefix, limit)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetStateAtError, err)
+	}
+	return jsonresult.NewGetStateAtResult(root, query.objectTypeName, objects, more), nil
+}
@@ -0,0 +1,45 @@
+package rpcserver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleGetStateProof returns a state object, its merkle proof to a state root
+// of a finalized block and the signed block after it, whose header commits to
+// the state roots.
+// Params: {"ChainID": -1 for beacon or shard id, "ObjectType": e.g. "PDEPoolPairObjectType",
+// "Key": object key, "Height": optional, final height if 0, "StateDB": optional}
+func (httpServer *HttpServer) handleGetStateProof(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	query, rpcErr := parseStateQuery(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	keyStr, ok := query.data["Key"].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Key is invalid"))
+	}
+	key, err := common.Hash{}.NewHashFromStr(keyStr)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("Key is invalid, %v", err))
+	}
+
+	stateProof, err := httpServer.config.BlockChain.GetStateProof(query.chainID, query.height, query.stateDB, *key)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetStateProofError, err)
+	}
+	object, err := statedb.DecodeStateObjectValue(query.objectType, stateProof.Proof.Key, stateProof.Proof.Value)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetStateProofError, fmt.Errorf("Value of key %v is not a %v, %v", keyStr, query.objectTypeName, err))
+	}
+	result, err := jsonresult.NewGetStateProofResult(stateProof, query.objectTypeName, object)
+	if err != nil {
+		return nil, rpcservice.NewRPCError(rpcservice.GetStateProofError, err)
+	}
+	return result, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
 	"github.com/incognitochain/incognito-chain/wallet"
+	"github.com/incognitochain/incognito-chain/wire"
 )
 
 // handleCreateTransaction handles createtransaction commands.
@@ -37,6 +38,8 @@ func (httpServer *HttpServer) handleCreateRawTransaction(params interface{}, clo
 // handleSendTransaction implements the sendtransaction command.
 // Parameter #1—a serialized transaction to broadcast
 // Parameter #2–whether to allow high fees
+// Parameter #3–optional, whether to relay the transaction privately, to the
+// nodes of the committee of its shard only instead of publishing it
 // Result—a TXID or error Message
 func (httpServer *HttpServer) handleSendRawTransaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrayParams := common.InterfaceSlice(params)
@@ -49,23 +52,168 @@ func (httpServer *HttpServer) handleSendRawTransaction(params interface{}, close
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("base58 check data is invalid"))
 	}
 
+	isPrivate, err1 := getPrivateRelayParam(arrayParams, 2)
+	if err1 != nil {
+		return nil, err1
+	}
+
 	txMsg, txHash, LastBytePubKeySender, err := httpServer.txService.SendRawTransaction(base58CheckData)
 	if err != nil {
 		return nil, err
 	}
 
-	err2 := httpServer.config.Server.PushMessageToAll(txMsg)
-	if err2 == nil {
-		Logger.log.Info("handleSendRawTransaction broadcast message to all successfully")
-		httpServer.config.TxMemPool.MarkForwardedTransaction(*txHash)
-	} else {
-		Logger.log.Errorf("handleSendRawTransaction broadcast message to all with error %+v", err2)
+	err2 := httpServer.pushTxMessage(txMsg, txHash, common.GetShardIDFromLastByte(LastBytePubKeySender), isPrivate)
+	if err2 != nil {
+		Logger.log.Errorf("handleSendRawTransaction broadcast message with error %+v", err2)
+		return nil, newTxNotRelayedError(txHash, err2)
 	}
+	Logger.log.Info("handleSendRawTransaction broadcast message successfully")
 
 	result := jsonresult.NewCreateTransactionResult(txHash, common.EmptyString, nil, common.GetShardIDFromLastByte(LastBytePubKeySender))
 	return result, nil
 }
 
+// handleSendRawTransactions implements the sendrawtransactions command.
+// Parameter #1—an array of serialized transactions, normal or privacy token
+// transactions, to broadcast
+// Parameter #2–optional, whether to relay the transactions privately, like
+// sendtransaction
+// Result—the TXID and whether it is accepted and relayed of each transaction,
+// with the reason of a rejection
+func (httpServer *HttpServer) handleSendRawTransactions(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
+	}
+	rawTxsParam := common.InterfaceSlice(arrayParams[0])
+	if len(rawTxsParam) == 0 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("raw transactions param must be a non empty array"))
+	}
+	if len(rawTxsParam) > rpcservice.MaxRawTransactionsPerBatch {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("can not send more than %d transactions at once", rpcservice.MaxRawTransactionsPerBatch))
+	}
+	base58CheckDatas := make([]string, len(rawTxsParam))
+	for i, rawTxParam := range rawTxsParam {
+		base58CheckData, ok := rawTxParam.(string)
+		if !ok {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, fmt.Errorf("base58 check data of transaction %d is invalid", i))
+		}
+		base58CheckDatas[i] = base58CheckData
+	}
+	isPrivate, err := getPrivateRelayParam(arrayParams, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions := httpServer.txService.SendRawTransactions(base58CheckDatas)
+	result := jsonresult.SendRawTransactionsResult{
+		Results: make([]jsonresult.SendRawTransactionResult, len(submissions)),
+	}
+	for i, submission := range submissions {
+		txResult := jsonresult.SendRawTransactionResult{Index: i}
+		if submission.Tx != nil {
+			txResult.TxID = submission.Tx.Hash().String()
+			txResult.ShardID = common.GetShardIDFromLastByte(submission.Tx.GetSenderAddrLastByte())
+		}
+		if submission.Err != nil {
+			result.Rejected++
+			txResult.Error = submission.Err.Error()
+			if reason := rpcservice.GetRejectionReason(submission.Err); reason != nil {
+				txResult.Reason = reason
+			}
+		} else {
+			result.Accepted++
+			txResult.Accepted = true
+			if submission.Msg != nil {
+				if err := httpServer.pushTxMessage(submission.Msg, submission.Tx.Hash(), txResult.ShardID, isPrivate); err != nil {
+					Logger.log.Errorf("handleSendRawTransactions broadcast message of tx %+v with error %+v", txResult.TxID, err)
+					txResult.RelayError = err.Error()
+				} else {
+					txResult.Relayed = true
+				}
+			}
+		}
+		result.Results[i] = txResult
+	}
+	return result, nil
+}
+
+// getPrivateRelayParam returns the optional param at index of the send
+// transaction commands, whether to relay the tx privately
+func getPrivateRelayParam(arrayParams []interface{}, index int) (bool, *rpcservice.RPCError) {
+	if len(arrayParams) <= index || arrayParams[index] == nil {
+		return false, nil
+	}
+	isPrivate, ok := arrayParams[index].(bool)
+	if !ok {
+		return false, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("private relay param is invalid"))
+	}
+	return isPrivate, nil
+}
+
+// newTxNotRelayedError is the error of a send transaction command whose tx is
+// accepted to mempool but not relayed, the tx is not to be sent again: it is
+// relayed again by the broadcast loop of mempool
+func newTxNotRelayedError(txHash *common.Hash, err error) *rpcservice.RPCError {
+	return rpcservice.NewRPCError(rpcservice.SendTxDataError, fmt.Errorf("transaction %v is accepted to mempool but not relayed, it is relayed again later: %v", txHash.String(), err))
+}
+
+// pushTxMessage publishes the message of a tx accepted to mempool to the
+// network. A private tx is pushed to the connected nodes of the committee of
+// its shard only, they do not publish it either: it can not be watched on the
+// network before it is in a block. It is marked private in mempool first so
+// that it is never published when it is broadcast again.
+func (httpServer *HttpServer) pushTxMessage(txMsg wire.Message, txHash *common.Hash, shardID byte, isPrivate bool) error {
+	var err error
+	if isPrivate {
+		switch msg := txMsg.(type) {
+		case *wire.MessageTx:
+			msg.IsPrivate = true
+		case *wire.MessageTxPrivacyToken:
+			msg.IsPrivate = true
+		}
+		httpServer.config.TxMemPool.MarkPrivateTransaction(*txHash)
+		err = httpServer.config.Server.PushMessageToShardCommittee(txMsg, shardID)
+	} else {
+		err = httpServer.config.Server.PushMessageToAll(txMsg)
+	}
+	if err != nil {
+		return err
+	}
+	httpServer.config.TxMemPool.MarkForwardedTransaction(*txHash)
+	return nil
+}
+
+// handleSimulateTransaction - RPC runs the mempool checks on a raw transaction,
+// normal or privacy token, without adding it to mempool nor broadcasting it
+// Params: base58 check data of tx, optional number of blocks of the fee estimation (default 8)
+func (httpServer *HttpServer) handleSimulateTransaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	arrayParams := common.InterfaceSlice(params)
+	if arrayParams == nil || len(arrayParams) < 1 {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("param must be an array at least 1 element"))
+	}
+
+	base58CheckData, ok := arrayParams[0].(string)
+	if !ok {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("base58 check data is invalid"))
+	}
+
+	numBlock := uint64(8)
+	if len(arrayParams) >= 2 {
+		numBlockParam, ok := arrayParams[1].(float64)
+		if !ok || numBlockParam <= 0 {
+			return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("num block param is invalid"))
+		}
+		numBlock = uint64(numBlockParam)
+	}
+
+	result, err := httpServer.txService.SimulateTransaction(base58CheckData, numBlock)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // handleCreateAndSendTx - RPC creates transaction and send to network
 func (httpServer *HttpServer) handleCreateAndSendTx(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	var err error
@@ -299,7 +447,19 @@ func (httpServer *HttpServer) handleGetTransactionByHash(params interface{}, clo
 	if !ok {
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Tx hash is invalid"))
 	}
-	return httpServer.txService.GetTransactionByHash(txHashStr)
+	// param #2: onlyFinalized, optional
+	onlyFinalized, rpcErr := parseOnlyFinalized(arrayParams, 1)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	result, rpcErr := httpServer.txService.GetTransactionByHash(txHashStr)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if onlyFinalized && !result.IsFinalized {
+		return nil, rpcservice.NewRPCError(rpcservice.NotFinalizedError, errors.New("transaction is not finalized"))
+	}
+	return result, nil
 }
 
 // handleGetListPrivacyCustomTokenBalance - return list privacy token + balance for one account payment address
@@ -693,6 +853,8 @@ func (httpServer *HttpServer) handleCreateRawPrivacyCustomTokenTransaction(param
 }
 
 // handleSendRawTransaction...
+// Parameter #3–optional, whether to relay the transaction privately, like
+// sendtransaction
 func (httpServer *HttpServer) handleSendRawPrivacyCustomTokenTransaction(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrayParams := common.InterfaceSlice(params)
 	if arrayParams == nil || len(arrayParams) < 1 {
@@ -704,15 +866,20 @@ func (httpServer *HttpServer) handleSendRawPrivacyCustomTokenTransaction(params
 		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Param is invalid"))
 	}
 
+	isPrivate, err1 := getPrivateRelayParam(arrayParams, 2)
+	if err1 != nil {
+		return nil, err1
+	}
+
 	txMsg, tx, err1 := httpServer.txService.SendRawPrivacyCustomTokenTransaction(base58CheckData)
 	if err1 != nil {
 		return nil, err1
 	}
 
-	err := httpServer.config.Server.PushMessageToAll(txMsg)
-	//Mark forwarded message
-	if err == nil {
-		httpServer.config.TxMemPool.MarkForwardedTransaction(*tx.Hash())
+	err := httpServer.pushTxMessage(txMsg, tx.Hash(), common.GetShardIDFromLastByte(tx.Tx.PubKeyLastByteSender), isPrivate)
+	if err != nil {
+		Logger.log.Errorf("handleSendRawPrivacyCustomTokenTransaction broadcast message with error %+v", err)
+		return nil, newTxNotRelayedError(tx.Hash(), err)
 	}
 	result := jsonresult.CreateTransactionTokenResult{
 		TxID:        tx.Hash().String(),
@@ -0,0 +1,32 @@
+package rpcserver
+
+import "testing"
+
+func Test_getPrivateRelayParam(t *testing.T) {
+	tests := []struct {
+		name        string
+		arrayParams []interface{}
+		index       int
+		want        bool
+		wantErr     bool
+	}{
+		{name: "no param", arrayParams: []interface{}{"tx"}, index: 2, want: false},
+		{name: "allow high fees is not the private relay", arrayParams: []interface{}{"tx", true}, index: 2, want: false},
+		{name: "private relay", arrayParams: []interface{}{"tx", false, true}, index: 2, want: true},
+		{name: "public relay", arrayParams: []interface{}{"tx", true, false}, index: 2, want: false},
+		{name: "null private relay", arrayParams: []interface{}{"tx", true, nil}, index: 2, want: false},
+		{name: "invalid private relay", arrayParams: []interface{}{"tx", true, "true"}, index: 2, wantErr: true},
+		{name: "private relay of a batch", arrayParams: []interface{}{[]interface{}{"tx"}, true}, index: 1, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getPrivateRelayParam(tt.arrayParams, tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getPrivateRelayParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getPrivateRelayParam() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -33,6 +33,33 @@ func (httpServer *HttpServer) handleGetPendingTxsInBlockgen(params interface{},
 	return result, nil
 }
 
+/*
+handleGetMempoolEvictions - RPC returns the transactions evicted from the memory pool and the
+transactions rejected by its caps, by reason
+*/
+func (httpServer *HttpServer) handleGetMempoolEvictions(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	result := httpServer.txMemPoolService.GetMempoolEvictions()
+	return result, nil
+}
+
+/*
+handleGetRejectionCodes - RPC returns the catalogue of the codes of the Data of the errors
+of a rejected transaction, with what a client can do after each rejection
+*/
+func (httpServer *HttpServer) handleGetRejectionCodes(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	result := rpcservice.GetRejectionCodes()
+	return result, nil
+}
+
+/*
+handleGetTxVerificationStats - RPC returns the latency of the new transactions through the
+stages of the verification pipeline of the memory pool
+*/
+func (httpServer *HttpServer) handleGetTxVerificationStats(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	result := httpServer.txMemPoolService.GetTxVerificationStats()
+	return result, nil
+}
+
 func (httpServer *HttpServer) handleGetNumberOfTxsInMempool(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	result := httpServer.txMemPoolService.GetNumberOfTxsInMempool()
 	return result, nil
@@ -62,6 +89,20 @@ func (httpServer *HttpServer) handleMempoolEntry(params interface{}, closeChan <
 	return tx, nil
 }
 
+/*
+handleGetMempoolTxChain - RPC returns the chain of unconfirmed spends of a transaction of the memory
+pool: the transaction, its unconfirmed ancestors, then its descendants held until their parents are in a block
+*/
+func (httpServer *HttpServer) handleGetMempoolTxChain(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
+	// Param #1: hash string of tx(tx id)
+	txIDParam, ok := params.(string)
+	if !ok || txIDParam == "" {
+		return nil, rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("transaction id is invalid"))
+	}
+
+	return httpServer.txMemPoolService.GetMempoolTxChain(txIDParam)
+}
+
 // handleRemoveTxInMempool - try to remove tx from tx mempool
 func (httpServer *HttpServer) handleRemoveTxInMempool(params interface{}, closeChan <-chan struct{}) (interface{}, *rpcservice.RPCError) {
 	arrays := common.InterfaceSlice(params)
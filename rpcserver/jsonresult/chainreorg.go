@@ -0,0 +1,37 @@
+package jsonresult
+
+import (
+	"github.com/incognitochain/incognito-chain/multiview"
+)
+
+// ChainReorgResult is a switch of the best view of a chain to another branch,
+// RemovedHashes are the blocks leaving the best chain from the previous best
+// block down, AddedHashes the blocks joining it up to the new best block
+type ChainReorgResult struct {
+	ChainID         int      `json:"ChainID"`
+	CommonAncestor  string   `json:"CommonAncestor"`
+	AncestorHeight  uint64   `json:"AncestorHeight"`
+	RemovedHashes   []string `json:"RemovedHashes"`
+	AddedHashes     []string `json:"AddedHashes"`
+	FinalViewHash   string   `json:"FinalViewHash"`
+	FinalViewHeight uint64   `json:"FinalViewHeight"`
+}
+
+func NewChainReorgResult(reorg *multiview.ChainReorg) *ChainReorgResult {
+	result := &ChainReorgResult{
+		ChainID:         reorg.ChainID,
+		CommonAncestor:  reorg.CommonAncestor.String(),
+		AncestorHeight:  reorg.AncestorHeight,
+		RemovedHashes:   []string{},
+		AddedHashes:     []string{},
+		FinalViewHash:   reorg.FinalViewHash.String(),
+		FinalViewHeight: reorg.FinalViewHeight,
+	}
+	for _, hash := range reorg.RemovedHashes {
+		result.RemovedHashes = append(result.RemovedHashes, hash.String())
+	}
+	for _, hash := range reorg.AddedHashes {
+		result.AddedHashes = append(result.AddedHashes, hash.String())
+	}
+	return result
+}
@@ -4,6 +4,15 @@ type EstimateFeeResult struct {
 	EstimateFee          uint64
 	EstimateFeeCoinPerKb uint64
 	EstimateTxSizeInKb   uint64
+	FeeBands             *FeeBandsResult `json:",omitempty"`
+}
+
+// FeeBandsResult are the fees per kb for a tx to be confirmed slowly, normally
+// and fast
+type FeeBandsResult struct {
+	SlowCoinPerKb   uint64
+	NormalCoinPerKb uint64
+	FastCoinPerKb   uint64
 }
 
 func NewEstimateFeeResult(estimateFee uint64, estimateFeeCoinPerKb uint64, estimateTxSizeInKb uint64) *EstimateFeeResult {
@@ -20,6 +20,9 @@ type GetBeaconBlockResult struct {
 	Instructions      [][]string  `json:"Instructions"`
 	Size              uint64      `json:"Size"`
 	ShardStates       interface{} `json:"ShardStates"`
+	IsFinalized       bool        `json:"IsFinalized"`
+	Confirmations     int64       `json:"Confirmations"`
+	FinalHeight       uint64      `json:"FinalHeight"`
 }
 
 type GetShardBlockResult struct {
@@ -48,6 +51,8 @@ type GetShardBlockResult struct {
 	Size              uint64             `json:"Size"`
 	Instruction       [][]string         `json:"Instruction"`
 	CrossShardBitMap  []int              `json:"CrossShardBitMap"`
+	IsFinalized       bool               `json:"IsFinalized"`
+	FinalHeight       uint64             `json:"FinalHeight"`
 }
 
 type GetBlockTxResult struct {
@@ -116,6 +121,26 @@ func NewGetBlockResult(block *blockchain.ShardBlock, size uint64, nextBlockHash
 	return getBlockResult
 }
 
+// SetFinality sets the finality fields, they are left unset if finality is nil
+func (result *GetBeaconBlockResult) SetFinality(finality *blockchain.BlockFinality) {
+	if finality == nil {
+		return
+	}
+	result.IsFinalized = finality.IsFinalized
+	result.Confirmations = int64(finality.Confirmations)
+	result.FinalHeight = finality.FinalHeight
+}
+
+// SetFinality sets the finality fields, they are left unset if finality is nil
+func (result *GetShardBlockResult) SetFinality(finality *blockchain.BlockFinality) {
+	if finality == nil {
+		return
+	}
+	result.IsFinalized = finality.IsFinalized
+	result.Confirmations = int64(finality.Confirmations)
+	result.FinalHeight = finality.FinalHeight
+}
+
 type GetViewResult struct {
 	Hash              string `json:"Hash"`
 	Height            uint64 `json:"Height"`
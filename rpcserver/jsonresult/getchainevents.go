@@ -0,0 +1,55 @@
+package jsonresult
+
+import (
+	"github.com/incognitochain/incognito-chain/eventlog"
+)
+
+type ChainEventResult struct {
+	ChainID           int    `json:"ChainID"`
+	Sequence          uint64 `json:"Sequence"`
+	Height            uint64 `json:"Height"`
+	Type              string `json:"Type"`
+	BlockHash         string `json:"BlockHash"`
+	PreviousBlockHash string `json:"PreviousBlockHash"`
+	BlockTime         int64  `json:"BlockTime"`
+	TxHash            string `json:"TxHash,omitempty"`
+	TxIndex           int    `json:"TxIndex,omitempty"`
+}
+
+func NewChainEventResult(event *eventlog.Event) *ChainEventResult {
+	result := &ChainEventResult{
+		ChainID:           event.ChainID,
+		Sequence:          event.Sequence,
+		Height:            event.Height,
+		Type:              event.Type,
+		BlockHash:         event.BlockHash.String(),
+		PreviousBlockHash: event.PreviousBlockHash.String(),
+		BlockTime:         event.BlockTime,
+		TxIndex:           event.TxIndex,
+	}
+	if event.TxHash != nil {
+		result.TxHash = event.TxHash.String()
+	}
+	return result
+}
+
+// GetChainEventsResult lists events of the event log of a chain, NextSequence
+// is the cursor to resume from
+type GetChainEventsResult struct {
+	ChainID      int                `json:"ChainID"`
+	Events       []ChainEventResult `json:"Events"`
+	NextSequence uint64             `json:"NextSequence"`
+}
+
+func NewGetChainEventsResult(chainID int, from uint64, events []*eventlog.Event) *GetChainEventsResult {
+	result := &GetChainEventsResult{
+		ChainID:      chainID,
+		Events:       []ChainEventResult{},
+		NextSequence: from,
+	}
+	for _, event := range events {
+		result.Events = append(result.Events, *NewChainEventResult(event))
+		result.NextSequence = event.Sequence + 1
+	}
+	return result
+}
@@ -69,6 +69,57 @@ func NewGetRawMempoolResult(txMemPool interface{ ListTxs() []string }) *GetRawMe
 	return result
 }
 
+type GetMempoolEvictionsResult struct {
+	Evicted  map[string]uint64 `json:"Evicted"`
+	Rejected map[string]uint64 `json:"Rejected"`
+	Recent   []EvictionResult  `json:"Recent"`
+}
+
+type EvictionResult struct {
+	TxHash    string `json:"TxHash"`
+	ShardID   byte   `json:"ShardID"`
+	FeePerKB  uint64 `json:"FeePerKB"`
+	Reason    string `json:"Reason"`
+	EvictedBy string `json:"EvictedBy,omitempty"`
+	Time      int64  `json:"Time"`
+}
+
+type GetTxVerificationStatsResult struct {
+	Started     bool                      `json:"Started"`
+	Workers     int                       `json:"Workers"`
+	BatchSize   int                       `json:"BatchSize"`
+	Batches     uint64                    `json:"Batches"`
+	BatchedTxs  uint64                    `json:"BatchedTxs"`
+	FallbackTxs uint64                    `json:"FallbackTxs"`
+	RejectedTxs uint64                    `json:"RejectedTxs"`
+	Queue       TxVerificationStageResult `json:"Queue"`
+	Verify      TxVerificationStageResult `json:"Verify"`
+	Accept      TxVerificationStageResult `json:"Accept"`
+	Total       TxVerificationStageResult `json:"Total"`
+}
+
+// TxVerificationStageResult is the latency of a stage in milliseconds
+type TxVerificationStageResult struct {
+	Count     uint64  `json:"Count"`
+	AverageMs float64 `json:"AverageMs"`
+	MaxMs     float64 `json:"MaxMs"`
+}
+
+
+type GetMempoolTxChainResult struct {
+	TxID  string               `json:"TxID"`
+	Chain []TxChainEntryResult `json:"Chain"`
+}
+
+// TxChainEntryResult is a tx of a chain of unconfirmed spends, its State is
+// pending in mempool, or held until its parents are in a block
+type TxChainEntryResult struct {
+	TxID     string   `json:"TxID"`
+	State    string   `json:"State"`
+	Parents  []string `json:"Parents"`
+	Children []string `json:"Children"`
+}
+
 type GetPendingTxsInBlockgenResult struct {
 	TxHashes []string
 }
@@ -0,0 +1,42 @@
+package jsonresult
+
+import (
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+	"github.com/incognitochain/incognito-chain/incognitokey"
+)
+
+type ProducerBlackListDetail struct {
+	CommitteePublicKey string            `json:"CommitteePublicKey"`
+	IncPubKey          string            `json:"IncPubKey"`
+	MiningPubKey       map[string]string `json:"MiningPubKey"`
+	Epochs             uint8             `json:"Epochs"`
+	BeaconHeight       uint64            `json:"BeaconHeight"`
+	Reason             string            `json:"Reason"`
+}
+
+func NewProducersBlackListDetail(states []*statedb.BlackListProducerState) []ProducerBlackListDetail {
+	result := []ProducerBlackListDetail{}
+	for _, state := range states {
+		detail := ProducerBlackListDetail{
+			CommitteePublicKey: state.ProducerCommitteePublicKey(),
+			MiningPubKey:       make(map[string]string),
+			Epochs:             state.PunishedEpoches(),
+			BeaconHeight:       state.BeaconHeight(),
+			Reason:             state.Reason(),
+		}
+		var keySet incognitokey.CommitteePublicKey
+		if err := keySet.FromBase58(state.ProducerCommitteePublicKey()); err == nil {
+			detail.IncPubKey = keySet.GetIncKeyBase58()
+			for keyType := range keySet.MiningPubKey {
+				detail.MiningPubKey[keyType] = keySet.GetMiningKeyBase58(keyType)
+			}
+		}
+		result = append(result, detail)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CommitteePublicKey < result[j].CommitteePublicKey
+	})
+	return result
+}
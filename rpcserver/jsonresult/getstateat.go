@@ -0,0 +1,41 @@
+package jsonresult
+
+import (
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+)
+
+type StateObjectResult struct {
+	Key   string      `json:"Key"`
+	Value interface{} `json:"Value"`
+}
+
+// GetStateAtResult lists state objects of a chain at a finalized block, More is
+// true if the limit of objects has been reached before the last object
+type GetStateAtResult struct {
+	ChainID    int                 `json:"ChainID"`
+	Height     uint64              `json:"Height"`
+	BlockHash  string              `json:"BlockHash"`
+	StateDB    string              `json:"StateDB"`
+	StateRoot  string              `json:"StateRoot"`
+	ObjectType string              `json:"ObjectType"`
+	Objects    []StateObjectResult `json:"Objects"`
+	More       bool                `json:"More"`
+}
+
+func NewGetStateAtResult(root *blockchain.StateRootAt, objectType string, objects []statedb.StateObjectEntry, more bool) *GetStateAtResult {
+	result := &GetStateAtResult{
+		ChainID:    root.ChainID,
+		Height:     root.Height,
+		BlockHash:  root.BlockHash.String(),
+		StateDB:    root.StateDB,
+		StateRoot:  root.Root.String(),
+		ObjectType: objectType,
+		Objects:    []StateObjectResult{},
+		More:       more,
+	}
+	for _, object := range objects {
+		result.Objects = append(result.Objects, StateObjectResult{Key: object.Key.String(), Value: object.Value})
+	}
+	return result
+}
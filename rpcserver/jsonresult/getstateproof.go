@@ -0,0 +1,102 @@
+package jsonresult
+
+import (
+	"encoding/json"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/dataaccessobject/statedb"
+)
+
+// GetStateProofResult is the proof of a state object, Value is the object as
+// stored in the state trie and Proof the trie nodes from StateRoot to Key.
+// StateRoots are the roots of every state database of the chain at BlockHash,
+// their hash is committed by the header of NextBlock, the signed block after
+// BlockHash. StateProof rebuilds the proof, a client checks it with
+// blockchain.VerifyStateProof against the committee of the chain at NextBlock.
+type GetStateProofResult struct {
+	ChainID    int             `json:"ChainID"`
+	Height     uint64          `json:"Height"`
+	BlockHash  string          `json:"BlockHash"`
+	StateDB    string          `json:"StateDB"`
+	StateRoot  string          `json:"StateRoot"`
+	StateRoots interface{}     `json:"StateRoots"`
+	NextBlock  json.RawMessage `json:"NextBlock"`
+	ObjectType string          `json:"ObjectType"`
+	Key        string          `json:"Key"`
+	Exist      bool            `json:"Exist"`
+	Value      []byte          `json:"Value"`
+	Object     interface{}     `json:"Object"`
+	Proof      [][]byte        `json:"Proof"`
+}
+
+func NewGetStateProofResult(stateProof *blockchain.StateProof, objectType string, object interface{}) (*GetStateProofResult, error) {
+	target, proof := stateProof.Root, stateProof.Proof
+	nextBlock, err := json.Marshal(stateProof.NextBlock)
+	if err != nil {
+		return nil, err
+	}
+	result := &GetStateProofResult{
+		ChainID:    target.ChainID,
+		Height:     target.Height,
+		BlockHash:  target.BlockHash.String(),
+		StateDB:    target.StateDB,
+		StateRoot:  target.Root.String(),
+		NextBlock:  nextBlock,
+		ObjectType: objectType,
+		Key:        proof.Key.String(),
+		Exist:      len(proof.Value) > 0,
+		Value:      proof.Value,
+		Object:     object,
+		Proof:      proof.Nodes,
+	}
+	if target.ChainID == common.BeaconChainDataBaseID {
+		result.StateRoots = target.BeaconRootHash
+	} else {
+		result.StateRoots = target.ShardRootHash
+	}
+	return result, nil
+}
+
+// StateProof rebuilds the proof of a result
+func (result *GetStateProofResult) StateProof() (*blockchain.StateProof, error) {
+	target := &blockchain.StateRootAt{ChainID: result.ChainID, Height: result.Height, StateDB: result.StateDB}
+	blockHash, err := common.Hash{}.NewHashFromStr(result.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	root, err := common.Hash{}.NewHashFromStr(result.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+	key, err := common.Hash{}.NewHashFromStr(result.Key)
+	if err != nil {
+		return nil, err
+	}
+	target.BlockHash, target.Root = *blockHash, *root
+	roots, err := json.Marshal(result.StateRoots)
+	if err != nil {
+		return nil, err
+	}
+	var nextBlock common.BlockInterface
+	if result.ChainID == common.BeaconChainDataBaseID {
+		target.BeaconRootHash = &blockchain.BeaconRootHash{}
+		err = json.Unmarshal(roots, target.BeaconRootHash)
+		nextBlock = blockchain.NewBeaconBlock()
+	} else {
+		target.ShardRootHash = &blockchain.ShardRootHash{}
+		err = json.Unmarshal(roots, target.ShardRootHash)
+		nextBlock = blockchain.NewShardBlock()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(result.NextBlock, nextBlock); err != nil {
+		return nil, err
+	}
+	return &blockchain.StateProof{
+		Root:      target,
+		NextBlock: nextBlock,
+		Proof:     &statedb.StateProof{Key: *key, Value: result.Value, Nodes: result.Proof},
+	}, nil
+}
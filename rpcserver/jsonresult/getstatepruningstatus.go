@@ -0,0 +1,24 @@
+package jsonresult
+
+import "github.com/incognitochain/incognito-chain/blockchain"
+
+type GetStatePruningStatusResult struct {
+	Enabled            bool                            `json:"Enabled"`
+	PruneState         uint64                          `json:"PruneState"`
+	TotalPrunedNodes   uint64                          `json:"TotalPrunedNodes"`
+	TotalReclaimedSize uint64                          `json:"TotalReclaimedSize"`
+	Chains             []blockchain.StatePruningStatus `json:"Chains"`
+}
+
+func NewGetStatePruningStatusResult(pruneState uint64, chains []blockchain.StatePruningStatus) *GetStatePruningStatusResult {
+	result := &GetStatePruningStatusResult{
+		Enabled:    pruneState > 0,
+		PruneState: pruneState,
+		Chains:     chains,
+	}
+	for _, chain := range chains {
+		result.TotalPrunedNodes += chain.TotalPrunedNodes
+		result.TotalReclaimedSize += chain.TotalReclaimedSize
+	}
+	return result
+}
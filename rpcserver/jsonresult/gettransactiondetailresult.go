@@ -3,6 +3,7 @@ package jsonresult
 import (
 	"encoding/json"
 	"errors"
+	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
 	"github.com/incognitochain/incognito-chain/metadata"
@@ -45,6 +46,10 @@ type TransactionDetail struct {
 	IsInMempool bool `json:"IsInMempool"`
 	IsInBlock   bool `json:"IsInBlock"`
 
+	IsFinalized   bool   `json:"IsFinalized"`
+	Confirmations int64  `json:"Confirmations"`
+	FinalHeight   uint64 `json:"FinalHeight"`
+
 	Info string `json:"Info"`
 }
 
@@ -140,6 +145,17 @@ type ProofDetail struct {
 	OutputCoins []*CoinDetail
 }
 
+// SetFinality sets the finality fields of the block of the transaction, they are
+// left unset if finality is nil
+func (txDetail *TransactionDetail) SetFinality(finality *blockchain.BlockFinality) {
+	if finality == nil {
+		return
+	}
+	txDetail.IsFinalized = finality.IsFinalized
+	txDetail.Confirmations = int64(finality.Confirmations)
+	txDetail.FinalHeight = finality.FinalHeight
+}
+
 func (proofDetail *ProofDetail) ConvertFromProof(proof *zkp.PaymentProof) {
 	proofDetail.InputCoins = make([]*CoinDetail, 0)
 	for _, input := range proof.GetInputCoins() {
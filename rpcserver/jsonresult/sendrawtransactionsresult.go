@@ -0,0 +1,23 @@
+package jsonresult
+
+// SendRawTransactionResult is the result of a tx of sendrawtransactions, in the
+// order of the params. Reason is the machine-readable reason of a rejection by
+// mempool, like the Data of the error of sendtransaction.
+type SendRawTransactionResult struct {
+	Index    int
+	TxID     string `json:",omitempty"`
+	ShardID  byte
+	Accepted bool
+	// Relayed tells whether an accepted tx is relayed to the network, a tx not
+	// relayed is relayed again later by the broadcast loop of mempool
+	Relayed    bool
+	Error      string      `json:",omitempty"`
+	Reason     interface{} `json:",omitempty"`
+	RelayError string      `json:",omitempty"`
+}
+
+type SendRawTransactionsResult struct {
+	Accepted int
+	Rejected int
+	Results  []SendRawTransactionResult
+}
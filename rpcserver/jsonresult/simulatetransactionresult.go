@@ -0,0 +1,26 @@
+package jsonresult
+
+type SimulationCheckResult struct {
+	Name    string
+	Passed  bool
+	Skipped bool
+	Code    int    `json:",omitempty"`
+	Error   string `json:",omitempty"`
+}
+
+type SimulateTransactionResult struct {
+	TxID     string
+	ShardID  byte
+	Accepted bool
+	Checks   []SimulationCheckResult
+	Fee      uint64
+	FeeToken uint64
+	TxSize   uint64
+	// EstimatedFeePerKB and EstimatedFee are in the fee token if FeeTokenID is set, in PRV otherwise
+	FeeTokenID        string `json:",omitempty"`
+	EstimatedFeePerKB uint64
+	EstimatedFee      uint64
+	// EstimatedFeeError is set if the fee can not be estimated, the estimated
+	// fees are 0 then
+	EstimatedFeeError string `json:",omitempty"`
+}
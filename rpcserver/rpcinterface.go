@@ -36,6 +36,10 @@ var HttpHandler = map[string]httpHandler{
 	removeTxInMempool:       (*HttpServer).handleRemoveTxInMempool,
 	getMempoolInfo:          (*HttpServer).handleGetMempoolInfo,
 	getPendingTxsInBlockgen: (*HttpServer).handleGetPendingTxsInBlockgen,
+	getMempoolEvictions:     (*HttpServer).handleGetMempoolEvictions,
+	getRejectionCodes:       (*HttpServer).handleGetRejectionCodes,
+	getTxVerificationStats:  (*HttpServer).handleGetTxVerificationStats,
+	getMempoolTxChain:       (*HttpServer).handleGetMempoolTxChain,
 
 	// block pool ver.2
 	// getCrossShardPoolStateV2:    (*HttpServer).handleGetCrossShardPoolStateV2,
@@ -48,6 +52,13 @@ var HttpHandler = map[string]httpHandler{
 	//backup and preload
 	setBackup:       (*HttpServer).handleSetBackup,
 	getLatestBackup: (*HttpServer).handleGetLatestBackup,
+	// state pruning
+	getStatePruningStatus: (*HttpServer).handleGetStatePruningStatus,
+	// historical state and state proof
+	getStateAt:    (*HttpServer).handleGetStateAt,
+	getStateProof: (*HttpServer).handleGetStateProof,
+	// event log
+	getChainEvents: (*HttpServer).handleGetChainEvents,
 	// block
 	getBestBlock:                (*HttpServer).handleGetBestBlock,
 	getBestBlockHash:            (*HttpServer).handleGetBestBlockHash,
@@ -67,6 +78,8 @@ var HttpHandler = map[string]httpHandler{
 	listOutputCoins:                           (*HttpServer).handleListOutputCoins,
 	createRawTransaction:                      (*HttpServer).handleCreateRawTransaction,
 	sendRawTransaction:                        (*HttpServer).handleSendRawTransaction,
+	sendRawTransactions:                       (*HttpServer).handleSendRawTransactions,
+	simulateTransaction:                       (*HttpServer).handleSimulateTransaction,
 	createAndSendTransaction:                  (*HttpServer).handleCreateAndSendTx,
 	createAndSendTransactionV2:                (*HttpServer).handleCreateAndSendTxV2,
 	getTransactionByHash:                      (*HttpServer).handleGetTransactionByHash,
@@ -172,6 +185,7 @@ var HttpHandler = map[string]httpHandler{
 	getMinerRewardFromMiningKey: (*HttpServer).handleGetMinerRewardFromMiningKey,
 	getProducersBlackList:       (*HttpServer).handleGetProducersBlackList,
 	getProducersBlackListDetail: (*HttpServer).handleGetProducersBlackListDetail,
+	getValidatorParticipation:   (*HttpServer).handleGetValidatorParticipation,
 
 	// pde
 	getPDEState:                                (*HttpServer).handleGetPDEState,
@@ -315,4 +329,6 @@ var WsHandler = map[string]wsHandler{
 	subcribeBeaconBestState:                     (*WsServer).handleSubscribeBeaconBestState,
 	subcribeBeaconPoolBeststate:                 (*WsServer).handleSubscribeBeaconPoolBestState,
 	subcribeShardPoolBeststate:                  (*WsServer).handleSubscribeShardPoolBeststate,
+	subcribeChainEvents:                         (*WsServer).handleSubscribeChainEvents,
+	subcribeChainReorg:                          (*WsServer).handleSubscribeChainReorg,
 }
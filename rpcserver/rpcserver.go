@@ -11,6 +11,7 @@ import (
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/consensus"
 	"github.com/incognitochain/incognito-chain/connmanager"
+	"github.com/incognitochain/incognito-chain/eventlog"
 	"github.com/incognitochain/incognito-chain/incdb"
 	"github.com/incognitochain/incognito-chain/memcache"
 	"github.com/incognitochain/incognito-chain/mempool"
@@ -78,6 +79,7 @@ type RpcServerConfig struct {
 		// Push TxNormal Message
 		PushMessageToAll(message wire.Message) error
 		PushMessageToPeer(message wire.Message, id peer2.ID) error
+		PushMessageToShardCommittee(message wire.Message, shardID byte) error
 		GetNodeRole() string
 		// GetUserKeySet() *incognitokey.KeySet
 		EnableMining(enable bool) error
@@ -97,6 +99,7 @@ type RpcServerConfig struct {
 	TxMemPool                   rpcservice.MempoolInterface
 	RPCMaxClients               int
 	RPCMaxWSClients             int
+	WsSubscribeOptions          pubsub.SubscribeOptions // queue of the websocket subscribers
 	RPCLimitRequestPerDay       int
 	RPCLimitRequestErrorPerHour int
 	RPCQuirks                   bool
@@ -112,6 +115,7 @@ type RpcServerConfig struct {
 	// IsMiningNode    bool   // flag mining node. True: mining, False: not mining
 	MiningKeys    string // encode of mining key
 	PubSubManager *pubsub.PubSubManager
+	EventLog      *eventlog.EventLog // nil if the event log is disabled
 }
 
 func (rpcServer *RpcServer) Init(config *RpcServerConfig) {
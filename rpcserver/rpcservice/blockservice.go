@@ -106,7 +106,6 @@ func (blockService BlockService) RetrieveShardBlock(hashString string, verbosity
 			}
 		}
 		result.Hash = shardBlock.Hash().String()
-		result.Confirmations = int64(1 + best.Header.Height - blockHeight)
 		result.Height = shardBlock.Header.Height
 		result.Version = shardBlock.Header.Version
 		result.TxRoot = shardBlock.Header.TxRoot.String()
@@ -150,7 +149,6 @@ func (blockService BlockService) RetrieveShardBlock(hashString string, verbosity
 			}
 		}
 		result.Hash = shardBlock.Hash().String()
-		result.Confirmations = int64(1 + best.Header.Height - blockHeight)
 		result.Height = shardBlock.Header.Height
 		result.Version = shardBlock.Header.Version
 		result.TxRoot = shardBlock.Header.TxRoot.String()
@@ -191,6 +189,7 @@ func (blockService BlockService) RetrieveShardBlock(hashString string, verbosity
 			result.Txs = append(result.Txs, transactionResult)
 		}
 	}
+	result.SetFinality(blockService.getBlockFinality(int(shardID), *shardBlock.Hash(), shardBlock.Header.Height))
 	return &result, nil
 }
 
@@ -228,7 +227,6 @@ func (blockService BlockService) RetrieveShardBlockByHeight(blockHeight uint64,
 				}
 			}
 			res.Hash = shardBlock.Hash().String()
-			res.Confirmations = int64(1 + best.Header.Height - blockHeight)
 			res.Height = shardBlock.Header.Height
 			res.Version = shardBlock.Header.Version
 			res.TxRoot = shardBlock.Header.TxRoot.String()
@@ -271,7 +269,6 @@ func (blockService BlockService) RetrieveShardBlockByHeight(blockHeight uint64,
 			}
 
 			res.Hash = shardBlock.Hash().String()
-			res.Confirmations = int64(1 + best.Header.Height - blockHeight)
 			res.Height = shardBlock.Header.Height
 			res.Version = shardBlock.Header.Version
 			res.TxRoot = shardBlock.Header.TxRoot.String()
@@ -312,6 +309,7 @@ func (blockService BlockService) RetrieveShardBlockByHeight(blockHeight uint64,
 				res.Txs = append(res.Txs, transactionT)
 			}
 		}
+		res.SetFinality(blockService.getBlockFinality(int(shardID), *shardBlock.Hash(), shardBlock.Header.Height))
 		result = append(result, &res)
 	}
 	return result, nil
@@ -347,6 +345,7 @@ func (blockService BlockService) RetrieveBeaconBlock(hashString string) (*jsonre
 		return nil, NewRPCError(UnexpectedError, errS)
 	}
 	result := jsonresult.NewGetBlocksBeaconResult(block, uint64(len(blockBytes)), nextHashString)
+	result.SetFinality(blockService.getBlockFinality(common.BeaconChainDataBaseID, block.Header.Hash(), blockHeight))
 	return result, nil
 }
 
@@ -381,6 +380,7 @@ func (blockService BlockService) RetrieveBeaconBlockByHeight(blockHeight uint64)
 			}
 		}
 		res := jsonresult.NewGetBlocksBeaconResult(beaconBlock, uint64(len(beaconBlockBytes)), nextHashString)
+		res.SetFinality(blockService.getBlockFinality(common.BeaconChainDataBaseID, beaconBlock.Header.Hash(), beaconBlock.Header.Height))
 		result = append(result, res)
 	}
 	return result, nil
@@ -398,6 +398,7 @@ func (blockService BlockService) GetBlocks(shardIDParam int, numBlock int) (inte
 			if err1 != nil {
 				Logger.log.Error("Json Unmarshal cache of get shard blocks error", err1)
 			} else {
+				blockService.setShardBlocksFinality(shardIDParam, resultShard)
 				return resultShard, nil
 			}
 		}
@@ -409,6 +410,7 @@ func (blockService BlockService) GetBlocks(shardIDParam int, numBlock int) (inte
 			if err1 != nil {
 				Logger.log.Error("Json Unmarshal cache of get beacon blocks error", err1)
 			} else {
+				blockService.setBeaconBlocksFinality(resultBeacon)
 				return resultBeacon, nil
 			}
 		}
@@ -448,6 +450,7 @@ func (blockService BlockService) GetBlocks(shardIDParam int, numBlock int) (inte
 				}
 			}
 		}
+		blockService.setShardBlocksFinality(shardIDParam, resultShard)
 		return resultShard, nil
 	} else {
 		if len(resultBeacon) == 0 {
@@ -481,10 +484,44 @@ func (blockService BlockService) GetBlocks(shardIDParam int, numBlock int) (inte
 				}
 			}
 		}
+		blockService.setBeaconBlocksFinality(resultBeacon)
 		return resultBeacon, nil
 	}
 }
 
+// getBlockFinality returns the finality of a block, nil if it can not be computed
+func (blockService BlockService) getBlockFinality(chainID int, hash common.Hash, height uint64) *blockchain.BlockFinality {
+	finality, err := blockService.BlockChain.GetBlockFinality(chainID, hash, height)
+	if err != nil {
+		Logger.log.Errorf("Get finality of block %v of chain %v error: %v", hash.String(), chainID, err)
+		return nil
+	}
+	return finality
+}
+
+// setShardBlocksFinality sets the finality of shard block results, computed on
+// every call since cached results get stale as the chain moves on
+func (blockService BlockService) setShardBlocksFinality(shardID int, blocks []jsonresult.GetShardBlockResult) {
+	for i := range blocks {
+		hash, err := common.Hash{}.NewHashFromStr(blocks[i].Hash)
+		if err != nil {
+			continue
+		}
+		blocks[i].SetFinality(blockService.getBlockFinality(shardID, *hash, blocks[i].Height))
+	}
+}
+
+// setBeaconBlocksFinality sets the finality of beacon block results
+func (blockService BlockService) setBeaconBlocksFinality(blocks []jsonresult.GetBeaconBlockResult) {
+	for i := range blocks {
+		hash, err := common.Hash{}.NewHashFromStr(blocks[i].Hash)
+		if err != nil {
+			continue
+		}
+		blocks[i].SetFinality(blockService.getBlockFinality(common.BeaconChainDataBaseID, *hash, blocks[i].Height))
+	}
+}
+
 func (blockService BlockService) IsBeaconBestStateNil() bool {
 	return blockService.BlockChain.GetBeaconBestState() == nil
 }
@@ -917,15 +954,30 @@ func (blockService BlockService) GetPDEStatus(pdePrefix []byte, pdeSuffix []byte
 	return statedb.GetPDEStatus(pdexStateDB, pdePrefix, pdeSuffix)
 }
 
-////============================= Slash ===============================
-//func (blockService BlockService) GetProducersBlackList(beaconHeight uint64) (map[string]uint8, error) {
-//	slashRootHash, err := blockService.BlockChain.GetBeaconSlashRootHash(blockService.BlockChain.GetBeaconBestState().GetBeaconConsensusStateDB(), beaconHeight)
-//	if err != nil {
-//		return nil, fmt.Errorf("Beacon Slash Root Hash of Height %+v not found ,error %+v", beaconHeight, err)
-//	}
-//	slashStateDB, err := statedb.NewWithPrefixTrie(slashRootHash, statedb.NewDatabaseAccessWarper(blockService.BlockChain.GetBeaconChainDatabase()))
-//	return statedb.GetProducersBlackList(slashStateDB, beaconHeight), nil
-//}
+//============================= Slash ===============================
+func (blockService BlockService) getBeaconSlashStateDB(beaconHeight uint64) (*statedb.StateDB, error) {
+	beaconRootHash, err := blockService.BlockChain.GetBeaconRootsHashFromBlockHeight(beaconHeight)
+	if err != nil {
+		return nil, fmt.Errorf("Beacon Slash Root Hash of Height %+v not found ,error %+v", beaconHeight, err)
+	}
+	return statedb.NewWithPrefixTrie(beaconRootHash.SlashStateDBRootHash, statedb.NewDatabaseAccessWarper(blockService.BlockChain.GetBeaconChainDatabase()))
+}
+
+func (blockService BlockService) GetProducersBlackList(beaconHeight uint64) (map[string]uint8, error) {
+	slashStateDB, err := blockService.getBeaconSlashStateDB(beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetProducersBlackList(slashStateDB, beaconHeight), nil
+}
+
+func (blockService BlockService) GetProducersBlackListDetail(beaconHeight uint64) ([]*statedb.BlackListProducerState, error) {
+	slashStateDB, err := blockService.getBeaconSlashStateDB(beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetProducersBlackListDetail(slashStateDB), nil
+}
 
 //============================= Portal ===============================
 func (blockService BlockService) GetCustodianDepositStatus(depositTxID string) (*metadata.PortalCustodianDepositStatus, error) {
@@ -128,6 +128,20 @@ const (
 	RestoreCandidateShardWaitingForNextRandom
 
 	GetTotalStakerError
+
+	// state proof
+	GetStateProofError
+	GetStateAtError
+
+	// event log
+	GetChainEventsError
+
+	// finality
+	NotFinalizedError
+
+	// slash
+	GetProducersBlackListError
+	GetValidatorParticipationError
 )
 
 // Standard JSON-RPC 2.0 errors.
@@ -256,6 +270,20 @@ var ErrCodeMessage = map[int]struct {
 	RestoreCandidateShardWaitingForNextRandom:     {-12008, "Restore candidate shard waiting for next random"},
 	GetAllBeaconViews:                             {-12009, "Get all beacon views"},
 	GetTotalStakerError:                           {-12010, "Get total staker return error"},
+
+	// state proof
+	GetStateProofError: {-13001, "Get state proof error"},
+	GetStateAtError:    {-13002, "Get state at height error"},
+
+	// event log
+	GetChainEventsError: {-14001, "Get chain events error"},
+
+	// finality
+	NotFinalizedError: {-15001, "Block or transaction is not finalized"},
+
+	// slash
+	GetProducersBlackListError:     {-16001, "Get producers black list error"},
+	GetValidatorParticipationError: {-16002, "Get validator participation error"},
 }
 
 // RPCError represents an error that is used as a part of a JSON-RPC JsonResponse
@@ -264,6 +292,8 @@ type RPCError struct {
 	Code       int    `json:"Code,omitempty"`
 	Message    string `json:"Message,omitempty"`
 	StackTrace string `json:"StackTrace"`
+	// Data is the reason of a rejected transaction, see GetRejectionReason
+	Data *RejectionReason `json:"Data,omitempty"`
 
 	err error `json:"Err"`
 }
@@ -291,6 +321,7 @@ func NewRPCError(key int, err error, param ...interface{}) *RPCError {
 	e := &RPCError{
 		Code: ErrCodeMessage[key].Code,
 		err:  errors.Wrap(err, ErrCodeMessage[key].Message),
+		Data: GetRejectionReason(err),
 	}
 	if len(param) > 0 {
 		e.Message = fmt.Sprintf(ErrCodeMessage[key].Message, param)
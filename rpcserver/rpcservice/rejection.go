@@ -0,0 +1,135 @@
+package rpcservice
+
+import (
+	"sort"
+
+	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+	"github.com/pkg/errors"
+)
+
+// packages a rejection code comes from, the codes of different packages overlap
+const (
+	RejectionSourceMempool     = "mempool"
+	RejectionSourceTransaction = "transaction"
+	RejectionSourceMetadata    = "metadata"
+)
+
+// rejectionCodeRange is the width of the range of the rejection codes of a
+// source, the code of a package is at most rejectionCodeRange-1 in magnitude
+const rejectionCodeRange = 10000
+
+// rejectionCodeOffsets move the codes of each source to its own range: -1xxxx
+// for mempool, -2xxxx for transaction, -3xxxx for metadata
+var rejectionCodeOffsets = map[string]int{
+	RejectionSourceMempool:     -1 * rejectionCodeRange,
+	RejectionSourceTransaction: -2 * rejectionCodeRange,
+	RejectionSourceMetadata:    -3 * rejectionCodeRange,
+}
+
+// what a client can do after a rejection
+const (
+	RetryNever         = "never"     // the tx is invalid, or already known
+	RetryLater         = "later"     // the same tx may be accepted later
+	RetryWithHigherFee = "higherfee" // the tx may be accepted if it pays a higher fee
+)
+
+// retry hints of the codes which are not RetryNever
+var rejectionRetries = map[string]map[int]string{
+	RejectionSourceMempool: {
+		mempool.ErrCodeMessage[mempool.RejectInvalidFee].Code:             RetryWithHigherFee,
+		mempool.ErrCodeMessage[mempool.MaxPoolSizeError].Code:             RetryWithHigherFee,
+		mempool.ErrCodeMessage[mempool.RejectReplacementTxError].Code:     RetryWithHigherFee,
+		mempool.ErrCodeMessage[mempool.RejectSenderLimitError].Code:       RetryLater,
+		mempool.ErrCodeMessage[mempool.CanNotCheckDoubleSpend].Code:       RetryLater,
+		mempool.ErrCodeMessage[mempool.DatabaseError].Code:                RetryLater,
+		mempool.ErrCodeMessage[mempool.CouldNotGetExchangeRateError].Code: RetryLater,
+		mempool.ErrCodeMessage[mempool.TxVerifierStoppedError].Code:       RetryLater,
+		mempool.ErrCodeMessage[mempool.RejectDependentTxError].Code:       RetryLater,
+	},
+	RejectionSourceMetadata: {
+		metadata.ErrCodeMessage[metadata.RejectInvalidFee].Code:             RetryWithHigherFee,
+		metadata.ErrCodeMessage[metadata.CouldNotGetExchangeRateError].Code: RetryLater,
+	},
+}
+
+// RejectionReason is the machine-readable reason of a rejected transaction,
+// sent as the Data of the RPC error. Code is unique among the sources,
+// SourceCode is the code of the error in the package of its source. Cause is
+// the reason of the reason when the error wraps another coded error, e.g. the
+// transaction error of a mempool error.
+type RejectionReason struct {
+	Source     string
+	Code       int
+	SourceCode int
+	Message    string
+	Retry      string
+	Cause      *RejectionReason `json:",omitempty"`
+}
+
+func newRejectionReason(source string, sourceCode int, message string) *RejectionReason {
+	retry, ok := rejectionRetries[source][sourceCode]
+	if !ok {
+		retry = RetryNever
+	}
+	return &RejectionReason{
+		Source:     source,
+		Code:       rejectionCodeOffsets[source] + sourceCode,
+		SourceCode: sourceCode,
+		Message:    message,
+		Retry:      retry,
+	}
+}
+
+// GetRejectionReason returns the reason of err if it is a mempool, transaction
+// or metadata error, nil otherwise
+func GetRejectionReason(err error) *RejectionReason {
+	var reason *RejectionReason
+	var cause error
+	switch e := errors.Cause(err).(type) {
+	case *mempool.MempoolTxError:
+		reason, cause = newRejectionReason(RejectionSourceMempool, e.Code, e.Message), e.Err
+	case *transaction.TransactionError:
+		reason, cause = newRejectionReason(RejectionSourceTransaction, e.Code, e.Message), e.GetErr()
+	case *metadata.MetadataTxError:
+		reason, cause = newRejectionReason(RejectionSourceMetadata, e.Code, e.Message), e.Err
+	default:
+		return nil
+	}
+	if cause != nil {
+		reason.Cause = GetRejectionReason(cause)
+	}
+	return reason
+}
+
+// GetRejectionCodes returns the catalogue of the rejection codes, by source,
+// then by code
+func GetRejectionCodes() []RejectionReason {
+	sources := []string{RejectionSourceMempool, RejectionSourceTransaction, RejectionSourceMetadata}
+	codes := map[string]map[int]string{}
+	for _, source := range sources {
+		codes[source] = make(map[int]string)
+	}
+	for _, codeMessage := range mempool.ErrCodeMessage {
+		codes[RejectionSourceMempool][codeMessage.Code] = codeMessage.Message
+	}
+	for _, codeMessage := range transaction.ErrCodeMessage {
+		codes[RejectionSourceTransaction][codeMessage.Code] = codeMessage.Message
+	}
+	for _, codeMessage := range metadata.ErrCodeMessage {
+		codes[RejectionSourceMetadata][codeMessage.Code] = codeMessage.Message
+	}
+	result := []RejectionReason{}
+	for _, source := range sources {
+		reasons := []RejectionReason{}
+		for code, message := range codes[source] {
+			reasons = append(reasons, *newRejectionReason(source, code, message))
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			return reasons[i].SourceCode > reasons[j].SourceCode
+		})
+		result = append(result, reasons...)
+	}
+	return result
+}
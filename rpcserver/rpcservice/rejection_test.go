@@ -0,0 +1,89 @@
+package rpcservice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/metadata"
+	"github.com/incognitochain/incognito-chain/transaction"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestGetRejectionReason(t *testing.T) {
+	mempoolReason := func(key int, retry string) *RejectionReason {
+		code := mempool.ErrCodeMessage[key]
+		return &RejectionReason{Source: RejectionSourceMempool, Code: -10000 + code.Code, SourceCode: code.Code, Message: code.Message, Retry: retry}
+	}
+	transactionReason := func(key int) *RejectionReason {
+		code := transaction.ErrCodeMessage[key]
+		return &RejectionReason{Source: RejectionSourceTransaction, Code: -20000 + code.Code, SourceCode: code.Code, Message: code.Message, Retry: RetryNever}
+	}
+	metadataReason := func(key int, retry string) *RejectionReason {
+		code := metadata.ErrCodeMessage[key]
+		return &RejectionReason{Source: RejectionSourceMetadata, Code: -30000 + code.Code, SourceCode: code.Code, Message: code.Message, Retry: retry}
+	}
+	withCause := func(reason, cause *RejectionReason) *RejectionReason {
+		reason.Cause = cause
+		return reason
+	}
+	failed := errors.New("failed")
+	tests := []struct {
+		name string
+		err  error
+		want *RejectionReason
+	}{
+		{name: "not a coded error", err: failed, want: nil},
+		{name: "duplicate tx", err: mempool.NewMempoolTxError(mempool.RejectDuplicateTx, failed), want: mempoolReason(mempool.RejectDuplicateTx, RetryNever)},
+		{name: "low fee", err: mempool.NewMempoolTxError(mempool.RejectInvalidFee, failed), want: mempoolReason(mempool.RejectInvalidFee, RetryWithHigherFee)},
+		{name: "pool full", err: mempool.NewMempoolTxError(mempool.MaxPoolSizeError, failed), want: mempoolReason(mempool.MaxPoolSizeError, RetryWithHigherFee)},
+		{name: "replacement", err: mempool.NewMempoolTxError(mempool.RejectReplacementTxError, failed), want: mempoolReason(mempool.RejectReplacementTxError, RetryWithHigherFee)},
+		{name: "sender limit", err: mempool.NewMempoolTxError(mempool.RejectSenderLimitError, failed), want: mempoolReason(mempool.RejectSenderLimitError, RetryLater)},
+		{name: "dependent txs", err: mempool.NewMempoolTxError(mempool.RejectDependentTxError, failed), want: mempoolReason(mempool.RejectDependentTxError, RetryLater)},
+		{name: "verifier stopped", err: mempool.NewMempoolTxError(mempool.TxVerifierStoppedError, failed), want: mempoolReason(mempool.TxVerifierStoppedError, RetryLater)},
+		{name: "not relayed shard", err: mempool.NewMempoolTxError(mempool.RejectNotRelayedShardTxError, failed), want: mempoolReason(mempool.RejectNotRelayedShardTxError, RetryNever)},
+		{name: "wrapped mempool error", err: pkgerrors.Wrap(mempool.NewMempoolTxError(mempool.RejectSalaryTx, failed), "wrapped"), want: mempoolReason(mempool.RejectSalaryTx, RetryNever)},
+		{name: "invalid metadata", err: transaction.NewTransactionErr(transaction.RejectTxMetadataError, failed), want: transactionReason(transaction.RejectTxMetadataError)},
+		{name: "wrong output commitment", err: transaction.NewTransactionErr(transaction.WrongOutputCoinCommitmentError, failed), want: transactionReason(transaction.WrongOutputCoinCommitmentError)},
+		{name: "eth log data", err: metadata.NewMetadataTxError(metadata.ParseETHLogDataError, failed), want: metadataReason(metadata.ParseETHLogDataError, RetryNever)},
+		{name: "metadata low fee", err: metadata.NewMetadataTxError(metadata.RejectInvalidFee, failed), want: metadataReason(metadata.RejectInvalidFee, RetryWithHigherFee)},
+		{
+			name: "invalid tx caused by a transaction error",
+			err:  mempool.NewMempoolTxError(mempool.RejectInvalidTx, transaction.NewTransactionErr(transaction.TxProofVerifyFailError, failed)),
+			want: withCause(mempoolReason(mempool.RejectInvalidTx, RetryNever), transactionReason(transaction.TxProofVerifyFailError)),
+		},
+		{
+			name: "metadata rejection caused by a metadata error",
+			err:  mempool.NewMempoolTxError(mempool.RejectMetadataWithBlockchainTx, metadata.NewMetadataTxError(metadata.CouldNotGetExchangeRateError, failed)),
+			want: withCause(mempoolReason(mempool.RejectMetadataWithBlockchainTx, RetryNever), metadataReason(metadata.CouldNotGetExchangeRateError, RetryLater)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetRejectionReason(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetRejectionReason() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRejectionCodes(t *testing.T) {
+	codes := map[int]RejectionReason{}
+	for _, reason := range GetRejectionCodes() {
+		offset, ok := rejectionCodeOffsets[reason.Source]
+		if !ok {
+			t.Fatalf("unknown source %v", reason.Source)
+		}
+		if reason.Code > offset || reason.Code <= offset-rejectionCodeRange {
+			t.Errorf("code %v of %v %v is out of its range", reason.Code, reason.Source, reason.SourceCode)
+		}
+		if other, ok := codes[reason.Code]; ok {
+			t.Errorf("code %v of %v %v is the one of %v %v", reason.Code, reason.Source, reason.SourceCode, other.Source, other.SourceCode)
+		}
+		codes[reason.Code] = reason
+	}
+	if len(codes) != len(mempool.ErrCodeMessage)+len(transaction.ErrCodeMessage)+len(metadata.ErrCodeMessage) {
+		t.Errorf("%v codes in catalogue", len(codes))
+	}
+}
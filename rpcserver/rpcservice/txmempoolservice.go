@@ -2,10 +2,14 @@ package rpcservice
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/common/base58"
+	"github.com/incognitochain/incognito-chain/mempool"
 	"github.com/incognitochain/incognito-chain/metadata"
 	"github.com/incognitochain/incognito-chain/privacy"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
 )
 
 type TxMemPoolService struct {
@@ -48,6 +52,49 @@ func (txMemPoolService TxMemPoolService) MempoolEntry(txIDString string) (metada
 	return txInPool, shardIDTemp, nil
 }
 
+// GetMempoolTxChain returns the chain of unconfirmed spends of a tx of mempool,
+// or held until the txs of which it spends outputs are in a block
+func (txMemPoolService TxMemPoolService) GetMempoolTxChain(txIDString string) (*jsonresult.GetMempoolTxChainResult, *RPCError) {
+	txID, err := common.Hash{}.NewHashFromStr(txIDString)
+	if err != nil {
+		Logger.log.Debugf("GetMempoolTxChain result: nil %+v", err)
+		return nil, NewRPCError(RPCInvalidParamsError, err)
+	}
+	chain, err := txMemPoolService.TxMemPool.GetTxChain(*txID)
+	if err != nil {
+		return nil, NewRPCError(GeTxFromPoolError, err)
+	}
+	return newGetMempoolTxChainResult(chain), nil
+}
+
+func newGetMempoolTxChainResult(chain []mempool.TxChainEntry) *jsonresult.GetMempoolTxChainResult {
+	result := &jsonresult.GetMempoolTxChainResult{
+		Chain: make([]jsonresult.TxChainEntryResult, 0, len(chain)),
+	}
+	for _, entry := range chain {
+		entryResult := jsonresult.TxChainEntryResult{
+			TxID:     entry.TxHash.String(),
+			State:    "pending",
+			Parents:  make([]string, 0, len(entry.Parents)),
+			Children: make([]string, 0, len(entry.Children)),
+		}
+		if entry.Held {
+			entryResult.State = "held"
+		}
+		for _, parent := range entry.Parents {
+			entryResult.Parents = append(entryResult.Parents, parent.String())
+		}
+		for _, child := range entry.Children {
+			entryResult.Children = append(entryResult.Children, child.String())
+		}
+		result.Chain = append(result.Chain, entryResult)
+	}
+	if len(chain) > 0 {
+		result.TxID = chain[0].TxHash.String()
+	}
+	return result
+}
+
 func (txMemPoolService *TxMemPoolService) RemoveTxInMempool(txIDString string) (bool, *RPCError) {
 	txID, err := common.Hash{}.NewHashFromStr(txIDString)
 	if err != nil {
@@ -81,4 +128,58 @@ func (txMemPoolService TxMemPoolService) CheckListSerialNumbersExistedInMempool(
 		isExisteds = append(isExisteds, isExisted)
 	}
 	return isExisteds, nil
-}
\ No newline at end of file
+}
+// GetMempoolEvictions returns the transactions evicted from mempool and the
+// transactions rejected by its caps, by reason
+func (txMemPoolService TxMemPoolService) GetMempoolEvictions() *jsonresult.GetMempoolEvictionsResult {
+	metrics := txMemPoolService.TxMemPool.GetEvictionMetrics()
+	result := &jsonresult.GetMempoolEvictionsResult{
+		Evicted:  metrics.Evicted,
+		Rejected: metrics.Rejected,
+		Recent:   make([]jsonresult.EvictionResult, 0, len(metrics.Recent)),
+	}
+	for _, eviction := range metrics.Recent {
+		evictionResult := jsonresult.EvictionResult{
+			TxHash:   eviction.TxHash.String(),
+			ShardID:  eviction.ShardID,
+			FeePerKB: eviction.FeePerKB,
+			Reason:   eviction.Reason,
+			Time:     eviction.Time,
+		}
+		if eviction.EvictedBy != nil {
+			evictionResult.EvictedBy = eviction.EvictedBy.String()
+		}
+		result.Recent = append(result.Recent, evictionResult)
+	}
+	return result
+}
+
+// GetTxVerificationStats returns the statistics of the verification pipeline of
+// new transactions, Started is false if it is not started
+func (txMemPoolService TxMemPoolService) GetTxVerificationStats() *jsonresult.GetTxVerificationStatsResult {
+	stats, started := txMemPoolService.TxMemPool.GetTxVerificationStats()
+	return &jsonresult.GetTxVerificationStatsResult{
+		Started:     started,
+		Workers:     stats.Workers,
+		BatchSize:   stats.BatchSize,
+		Batches:     stats.Batches,
+		BatchedTxs:  stats.BatchedTxs,
+		FallbackTxs: stats.FallbackTxs,
+		RejectedTxs: stats.RejectedTxs,
+		Queue:       newTxVerificationStageResult(stats.Queue),
+		Verify:      newTxVerificationStageResult(stats.Verify),
+		Accept:      newTxVerificationStageResult(stats.Accept),
+		Total:       newTxVerificationStageResult(stats.Total),
+	}
+}
+
+func newTxVerificationStageResult(stats mempool.TxVerificationStageStats) jsonresult.TxVerificationStageResult {
+	result := jsonresult.TxVerificationStageResult{
+		Count: stats.Count,
+		MaxMs: float64(stats.Max) / float64(time.Millisecond),
+	}
+	if stats.Count > 0 {
+		result.AverageMs = float64(stats.Total) / float64(stats.Count) / float64(time.Millisecond)
+	}
+	return result
+}
@@ -36,17 +36,24 @@ type TxService struct {
 type MempoolInterface interface {
 	ValidateSerialNumberHashH(serialNumber []byte) error
 	MaybeAcceptTransaction(tx metadata.Transaction, beaconHeight int64) (*common.Hash, *mempool.TxDesc, error)
+	MaybeAcceptBatchTransaction(txs []metadata.Transaction, beaconHeight int64) []error
 	GetTx(txHash *common.Hash) (metadata.Transaction, error)
 	GetClonedPoolCandidate() map[common.Hash]string
 	ListTxs() []string
 	RemoveTx(txs []metadata.Transaction, isInBlock bool)
 	TriggerCRemoveTxs(tx metadata.Transaction)
 	MarkForwardedTransaction(txHash common.Hash)
+	MarkPrivateTransaction(txHash common.Hash)
 	MaxFee() uint64
 	ListTxsDetail() []metadata.Transaction
 	Count() int
 	Size() uint64
 	SendTransactionToBlockGen()
+	GetEvictionMetrics() mempool.EvictionMetrics
+	GetTxVerificationStats() (mempool.TxVerificationStats, bool)
+	GetTxChain(txHash common.Hash) ([]mempool.TxChainEntry, error)
+	EstimateFeeBands(shardID byte, numBlocks uint64, tokenID *common.Hash) (mempool.FeeBands, error)
+	SimulateTransaction(tx metadata.Transaction, beaconHeight int64) []mempool.SimulationCheck
 }
 
 type TxInfo struct {
@@ -404,6 +411,21 @@ func (txService TxService) EstimateFeeWithEstimator(defaultFee int64, shardID by
 	}
 }
 
+// EstimateFeeBandsWithEstimator - estimate the slow, normal and fast fee per kb
+// from the fees of the last blocks converted to PRV and from the txs waiting in
+// mempool, in pToken if tokenID != nil
+func (txService TxService) EstimateFeeBandsWithEstimator(shardID byte, numBlock uint64, tokenId *common.Hash) (*jsonresult.FeeBandsResult, error) {
+	bands, err := txService.TxMemPool.EstimateFeeBands(shardID, numBlock, tokenId)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonresult.FeeBandsResult{
+		SlowCoinPerKb:   uint64(bands.Slow),
+		NormalCoinPerKb: uint64(bands.Normal),
+		FastCoinPerKb:   uint64(bands.Fast),
+	}, nil
+}
+
 func (txService TxService) BuildRawTransaction(params *bean.CreateRawTxParam, meta metadata.Metadata) (*transaction.Tx, *RPCError) {
 	// get output coins to spend and real fee
 	inputCoins, realFee, err1 := txService.chooseOutsCoinByKeyset(
@@ -529,6 +551,171 @@ func (txService TxService) SendRawTransaction(txB58Check string) (wire.Message,
 	return txMsg, hash, tx.PubKeyLastByteSender, nil
 }
 
+// decodeRawTransaction decodes the base58 check data of a normal or privacy
+// token tx
+func decodeRawTransaction(txB58Check string) (metadata.Transaction, *RPCError) {
+	rawTxBytes, _, err := base58.Base58Check{}.Decode(txB58Check)
+	if err != nil {
+		return nil, NewRPCError(Base58ChedkDataOfTxInvalid, err)
+	}
+	txType := struct {
+		Type string `json:"Type"`
+	}{}
+	err = json.Unmarshal(rawTxBytes, &txType)
+	if err != nil {
+		return nil, NewRPCError(JsonDataOfTxInvalid, err)
+	}
+	var tx metadata.Transaction
+	if txType.Type == common.TxCustomTokenPrivacyType {
+		tx = &transaction.TxCustomTokenPrivacy{}
+	} else {
+		tx = &transaction.Tx{}
+	}
+	err = json.Unmarshal(rawTxBytes, tx)
+	if err != nil {
+		return nil, NewRPCError(JsonDataOfTxInvalid, err)
+	}
+	return tx, nil
+}
+
+// MaxRawTransactionsPerBatch is the max number of txs of SendRawTransactions
+const MaxRawTransactionsPerBatch = 1000
+
+// RawTxSubmission is a tx of a batch of SendRawTransactions. Err is nil if it
+// is accepted to mempool, Msg is then its message to broadcast.
+type RawTxSubmission struct {
+	Tx  metadata.Transaction // nil if the raw tx can not be decoded
+	Msg wire.Message
+	Err error
+}
+
+// SendRawTransactions decodes raw txs, normal or privacy token txs, and adds
+// them to mempool together. A tx which is rejected does not reject the others,
+// the txs are accepted in order.
+func (txService TxService) SendRawTransactions(txB58Checks []string) []RawTxSubmission {
+	submissions := make([]RawTxSubmission, len(txB58Checks))
+	txs := []metadata.Transaction{}
+	indexes := []int{}
+	for i, txB58Check := range txB58Checks {
+		tx, rpcErr := decodeRawTransaction(txB58Check)
+		if rpcErr != nil {
+			submissions[i].Err = rpcErr
+			continue
+		}
+		submissions[i].Tx = tx
+		txs = append(txs, tx)
+		indexes = append(indexes, i)
+	}
+	if len(txs) == 0 {
+		return submissions
+	}
+
+	beaconHeigh := int64(-1)
+	beaconBestState, err := txService.BlockChain.GetClonedBeaconBestState()
+	if err == nil {
+		beaconHeigh = int64(beaconBestState.BeaconHeight)
+	} else {
+		Logger.log.Errorf("Send Raw Transactions can not get beacon best state with error %+v", err)
+	}
+	errs := txService.TxMemPool.MaybeAcceptBatchTransaction(txs, beaconHeigh)
+	for j, i := range indexes {
+		if errs[j] != nil {
+			submissions[i].Err = errs[j]
+			continue
+		}
+		// Create tx message for broadcasting
+		var msgErr error
+		switch tx := txs[j].(type) {
+		case *transaction.TxCustomTokenPrivacy:
+			submissions[i].Msg, msgErr = wire.MakeEmptyMessage(wire.CmdPrivacyCustomToken)
+			if msgErr == nil {
+				submissions[i].Msg.(*wire.MessageTxPrivacyToken).Transaction = tx
+			}
+		default:
+			submissions[i].Msg, msgErr = wire.MakeEmptyMessage(wire.CmdTx)
+			if msgErr == nil {
+				submissions[i].Msg.(*wire.MessageTx).Transaction = tx
+			}
+		}
+		if msgErr != nil {
+			Logger.log.Errorf("Send Raw Transactions Error, Create tx message for broadcasting: %+v", msgErr)
+			submissions[i].Msg = nil
+		}
+	}
+	return submissions
+}
+
+// SimulateTransaction decodes a raw tx, normal or privacy token tx, and runs the
+// checks of the mempool on it without adding it to the mempool. The fee of the
+// tx is returned with the fee the estimator suggests for its size over numBlock
+// blocks, in the token if the tx pays its fee in token, unless the fee can not
+// be estimated which does not fail the simulation.
+func (txService TxService) SimulateTransaction(txB58Check string, numBlock uint64) (*jsonresult.SimulateTransactionResult, *RPCError) {
+	tx, rpcErr := decodeRawTransaction(txB58Check)
+	if rpcErr != nil {
+		Logger.log.Errorf("Simulate Transaction Error: %+v", rpcErr)
+		return nil, rpcErr
+	}
+
+	beaconHeigh := int64(-1)
+	beaconBestState, err := txService.BlockChain.GetClonedBeaconBestState()
+	if err == nil {
+		beaconHeigh = int64(beaconBestState.BeaconHeight)
+	} else {
+		Logger.log.Errorf("Simulate Transaction can not get beacon best state with error %+v", err)
+	}
+	checks, accepted := newSimulationCheckResults(txService.TxMemPool.SimulateTransaction(tx, beaconHeigh))
+	shardID := common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte())
+	result := &jsonresult.SimulateTransactionResult{
+		TxID:     tx.Hash().String(),
+		ShardID:  shardID,
+		Accepted: accepted,
+		Checks:   checks,
+		Fee:      tx.GetTxFee(),
+		FeeToken: tx.GetTxFeeToken(),
+		TxSize:   tx.GetTxActualSize(),
+	}
+	var tokenID *common.Hash
+	if tx.GetType() == common.TxCustomTokenPrivacyType && tx.GetTxFeeToken() > 0 {
+		tokenID = tx.GetTokenID()
+		result.FeeTokenID = tokenID.String()
+	}
+	feePerKB, err := txService.EstimateFeeWithEstimator(-1, shardID, numBlock, tokenID, beaconHeigh)
+	if err != nil {
+		Logger.log.Errorf("Simulate Transaction can not estimate fee with error %+v", err)
+		result.EstimatedFeeError = err.Error()
+		return result, nil
+	}
+	result.EstimatedFeePerKB = feePerKB
+	result.EstimatedFee = feePerKB * result.TxSize
+	return result, nil
+}
+
+// newSimulationCheckResults returns the results of the checks of a simulated
+// tx, and whether the tx passes them all
+func newSimulationCheckResults(checks []mempool.SimulationCheck) ([]jsonresult.SimulationCheckResult, bool) {
+	results := make([]jsonresult.SimulationCheckResult, 0, len(checks))
+	accepted := true
+	for _, check := range checks {
+		result := jsonresult.SimulationCheckResult{
+			Name:    check.Name,
+			Passed:  check.Passed,
+			Skipped: check.Skipped,
+		}
+		if check.Err != nil {
+			result.Error = check.Err.Error()
+			if mempoolErr, ok := check.Err.(*mempool.MempoolTxError); ok {
+				result.Code = mempoolErr.Code
+			}
+		}
+		if !check.Passed {
+			accepted = false
+		}
+		results = append(results, result)
+	}
+	return results, accepted
+}
+
 func (txService TxService) BuildTokenParam(tokenParamsRaw map[string]interface{}, senderKeySet *incognitokey.KeySet, shardIDSender byte) (*transaction.CustomTokenPrivacyParamTx, *RPCError) {
 	var privacyTokenParam *transaction.CustomTokenPrivacyParamTx
 	var err *RPCError
@@ -960,6 +1147,11 @@ func (txService TxService) GetTransactionByHash(txHashStr string) (*jsonresult.T
 		return nil, NewRPCError(UnexpectedError, err)
 	}
 	result.IsInBlock = true
+	finality, err := txService.BlockChain.GetBlockFinality(int(shardID), blockHash, blockHeight)
+	if err != nil {
+		Logger.log.Errorf("Get finality of block %v of tx %v error: %v", blockHash.String(), txHashStr, err)
+	}
+	result.SetFinality(finality)
 	Logger.log.Debugf("handleGetTransactionByHash result: %+v", result)
 	return result, nil
 }
@@ -0,0 +1,45 @@
+package rpcservice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/mempool"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+)
+
+func Test_newSimulationCheckResults(t *testing.T) {
+	feeErr := mempool.NewMempoolTxError(mempool.RejectInvalidFee, errors.New("low fee"))
+	tests := []struct {
+		name         string
+		checks       []mempool.SimulationCheck
+		want         []jsonresult.SimulationCheckResult
+		wantAccepted bool
+	}{
+		{
+			name:         "accepted",
+			checks:       []mempool.SimulationCheck{{Name: mempool.CheckSanity, Passed: true}, {Name: mempool.CheckCapacity, Passed: true}},
+			want:         []jsonresult.SimulationCheckResult{{Name: mempool.CheckSanity, Passed: true}, {Name: mempool.CheckCapacity, Passed: true}},
+			wantAccepted: true,
+		},
+		{
+			name:   "mempool error has a code",
+			checks: []mempool.SimulationCheck{{Name: mempool.CheckSanity, Passed: true}, {Name: mempool.CheckFee, Err: feeErr}},
+			want:   []jsonresult.SimulationCheckResult{{Name: mempool.CheckSanity, Passed: true}, {Name: mempool.CheckFee, Code: feeErr.Code, Error: feeErr.Error()}},
+		},
+		{
+			name:   "other error and skipped check",
+			checks: []mempool.SimulationCheck{{Name: mempool.CheckSanity, Err: errors.New("bad tx")}, {Name: mempool.CheckFee, Skipped: true}},
+			want:   []jsonresult.SimulationCheckResult{{Name: mempool.CheckSanity, Error: "bad tx"}, {Name: mempool.CheckFee, Skipped: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, accepted := newSimulationCheckResults(tt.checks)
+			if !reflect.DeepEqual(got, tt.want) || accepted != tt.wantAccepted {
+				t.Errorf("newSimulationCheckResults() = %+v, %v, want %+v, %v", got, accepted, tt.want, tt.wantAccepted)
+			}
+		})
+	}
+}
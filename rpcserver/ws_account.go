@@ -35,7 +35,7 @@ func (wsServer *WsServer) handleSubcribeCrossOutputCoinByPrivateKey(params inter
 		cResult <- RpcSubResult{Error: err}
 		return
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewShardblockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewShardblockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -48,8 +48,12 @@ func (wsServer *WsServer) handleSubcribeCrossOutputCoinByPrivateKey(params inter
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				shardBlock, ok := msg.Value.(*blockchain.ShardBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -122,7 +126,7 @@ func (wsServer *WsServer) handleSubcribeCrossCustomTokenPrivacyByPrivateKey(para
 		cResult <- RpcSubResult{Error: err}
 		return
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewShardblockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewShardblockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -135,8 +139,12 @@ func (wsServer *WsServer) handleSubcribeCrossCustomTokenPrivacyByPrivateKey(para
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				shardBlock, ok := msg.Value.(*blockchain.ShardBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -18,7 +18,7 @@ func (wsServer *WsServer) handleSubscribeShardBestState(params interface{}, subc
 		return
 	}
 	shardID := byte(arrayParams[0].(float64))
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.ShardBeststateTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.ShardBeststateTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -31,8 +31,12 @@ func (wsServer *WsServer) handleSubscribeShardBestState(params interface{}, subc
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				_, ok := msg.Value.(*blockchain.ShardBestState)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.ShardBestState, have %+v", reflect.TypeOf(msg.Value))
@@ -62,7 +66,7 @@ func (wsServer *WsServer) handleSubscribeBeaconBestState(params interface{}, sub
 		cResult <- RpcSubResult{Error: err}
 		return
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.BeaconBeststateTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.BeaconBeststateTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -75,8 +79,12 @@ func (wsServer *WsServer) handleSubscribeBeaconBestState(params interface{}, sub
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				_, ok := msg.Value.(*blockchain.BeaconBestState)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBestState, have %+v", reflect.TypeOf(msg.Value))
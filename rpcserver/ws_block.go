@@ -20,7 +20,7 @@ func (wsServer *WsServer) handleSubscribeNewShardBlock(params interface{}, subcr
 		return
 	}
 	shardID := byte(arrayParams[0].(float64))
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewShardblockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewShardblockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -33,8 +33,12 @@ func (wsServer *WsServer) handleSubscribeNewShardBlock(params interface{}, subcr
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				shardBlock, ok := msg.Value.(*blockchain.ShardBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.ShardBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -68,7 +72,7 @@ func (wsServer *WsServer) handleSubscribeNewBeaconBlock(params interface{}, subc
 		cResult <- RpcSubResult{Error: err}
 		return
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -81,8 +85,12 @@ func (wsServer *WsServer) handleSubscribeNewBeaconBlock(params interface{}, subc
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				beaconBlock, ok := msg.Value.(*blockchain.BeaconBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
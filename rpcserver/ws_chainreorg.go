@@ -0,0 +1,68 @@
+package rpcserver
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/multiview"
+	"github.com/incognitochain/incognito-chain/pubsub"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleSubscribeChainReorg notifies the switches of the best view of a chain
+// to another branch.
+// Params: chain id, -1 for beacon
+func (wsServer *WsServer) handleSubscribeChainReorg(params interface{}, subcription string, cResult chan RpcSubResult, closeChan <-chan struct{}) {
+	Logger.log.Info("Handle Subscribe Chain Reorg", params, subcription)
+	arrayParams := common.InterfaceSlice(params)
+	if len(arrayParams) != 1 {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("Methods should only contain 1 params"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	chainIDParam, ok := arrayParams[0].(float64)
+	if !ok {
+		err := rpcservice.NewRPCError(rpcservice.RPCInvalidParamsError, errors.New("ChainID is invalid"))
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	chainID := int(chainIDParam)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.ChainReorgTopic, wsServer.config.WsSubscribeOptions)
+	if err != nil {
+		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	defer func() {
+		Logger.log.Info("Finish Subscribe Chain Reorg ChainID ", chainID)
+		wsServer.config.PubSubManager.Unsubscribe(pubsub.ChainReorgTopic, subId)
+		close(cResult)
+	}()
+	for {
+		select {
+		case msg, subscribed := <-subChan:
+			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
+				reorg, ok := msg.Value.(*multiview.ChainReorg)
+				if !ok {
+					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *multiview.ChainReorg, have %+v", reflect.TypeOf(msg.Value))
+					continue
+				}
+				if reorg.ChainID != chainID {
+					continue
+				}
+				cResult <- RpcSubResult{Result: jsonresult.NewChainReorgResult(reorg)}
+			}
+		case <-closeChan:
+			{
+				cResult <- RpcSubResult{Result: jsonresult.UnsubcribeResult{Message: "Unsubscribe Chain Reorg"}}
+				return
+			}
+		}
+	}
+}
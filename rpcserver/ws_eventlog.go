@@ -0,0 +1,105 @@
+package rpcserver
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/incognitochain/incognito-chain/eventlog"
+	"github.com/incognitochain/incognito-chain/pubsub"
+	"github.com/incognitochain/incognito-chain/rpcserver/jsonresult"
+	"github.com/incognitochain/incognito-chain/rpcserver/rpcservice"
+)
+
+// handleSubscribeChainEvents streams the events of the event log of a chain.
+// Events from the cursor are read from the database first, then the new events
+// are sent as they are logged. Without cursor, only the new events are sent.
+// A client resumes after a disconnection with the sequence following the last
+// event it received, no event is missed.
+// Params: the params of parseChainEventCursor
+func (wsServer *WsServer) handleSubscribeChainEvents(params interface{}, subcription string, cResult chan RpcSubResult, closeChan <-chan struct{}) {
+	Logger.log.Info("Handle Subscribe Chain Events", params, subcription)
+	eventLog := wsServer.config.EventLog
+	cursor, rpcErr := parseChainEventCursor(eventLog, params)
+	if rpcErr != nil {
+		cResult <- RpcSubResult{Error: rpcErr}
+		return
+	}
+	// subscribe before reading the log not to miss the events logged meanwhile
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.ChainEventTopic, wsServer.config.WsSubscribeOptions)
+	if err != nil {
+		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
+		cResult <- RpcSubResult{Error: err}
+		return
+	}
+	defer func() {
+		Logger.log.Info("Finish Subscribe Chain Events ChainID ", cursor.chainID)
+		wsServer.config.PubSubManager.Unsubscribe(pubsub.ChainEventTopic, subId)
+		close(cResult)
+	}()
+	next := cursor.from
+	if !cursor.hasCursor {
+		tip, err := eventLog.GetTip(cursor.chainID)
+		if err != nil {
+			cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.GetChainEventsError, err)}
+			return
+		}
+		if tip != nil {
+			next = tip.NextSequence
+		}
+	}
+	// sendLogged sends the logged events from next, it returns false once the
+	// subscription is closed
+	sendLogged := func() bool {
+		for {
+			events, err := eventLog.GetEvents(cursor.chainID, next, defaultGetChainEventsLimit)
+			if err != nil {
+				cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.GetChainEventsError, err)}
+				return false
+			}
+			for _, event := range events {
+				select {
+				case cResult <- RpcSubResult{Result: jsonresult.NewChainEventResult(event)}:
+					next = event.Sequence + 1
+				case <-closeChan:
+					cResult <- RpcSubResult{Result: jsonresult.UnsubcribeResult{Message: "Unsubscribe Chain Events"}}
+					return false
+				}
+			}
+			if len(events) < defaultGetChainEventsLimit {
+				return true
+			}
+		}
+	}
+	if !sendLogged() {
+		return
+	}
+	for {
+		select {
+		case msg, subscribed := <-subChan:
+			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
+				event, ok := msg.Value.(*eventlog.Event)
+				if !ok {
+					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *eventlog.Event, have %+v", reflect.TypeOf(msg.Value))
+					continue
+				}
+				if event.ChainID != cursor.chainID || event.Sequence < next {
+					continue
+				}
+				// events are published once written, read them from the log so
+				// that the events dropped from the queue are sent too
+				if !sendLogged() {
+					return
+				}
+			}
+		case <-closeChan:
+			{
+				cResult <- RpcSubResult{Result: jsonresult.UnsubcribeResult{Message: "Unsubscribe Chain Events"}}
+				return
+			}
+		}
+	}
+}
@@ -34,7 +34,7 @@ func (wsServer *WsServer) handleSubcribeShardCandidateByPublickey(params interfa
 		cResult <- RpcSubResult{Result: true, Error: nil}
 		return
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -47,8 +47,12 @@ func (wsServer *WsServer) handleSubcribeShardCandidateByPublickey(params interfa
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				_, ok := msg.Value.(*blockchain.BeaconBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -102,7 +106,7 @@ func (wsServer *WsServer) handleSubcribeShardPendingValidatorByPublickey(params
 			return
 		}
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -115,8 +119,12 @@ func (wsServer *WsServer) handleSubcribeShardPendingValidatorByPublickey(params
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				_, ok := msg.Value.(*blockchain.BeaconBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -174,7 +182,7 @@ func (wsServer *WsServer) handleSubcribeShardCommitteeByPublickey(params interfa
 			return
 		}
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -187,8 +195,12 @@ func (wsServer *WsServer) handleSubcribeShardCommitteeByPublickey(params interfa
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				_, ok := msg.Value.(*blockchain.BeaconBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -240,7 +252,7 @@ func (wsServer *WsServer) handleSubcribeBeaconCandidateByPublickey(params interf
 		cResult <- RpcSubResult{Result: true, Error: nil}
 		return
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -253,8 +265,12 @@ func (wsServer *WsServer) handleSubcribeBeaconCandidateByPublickey(params interf
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				_, ok := msg.Value.(*blockchain.BeaconBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -300,7 +316,7 @@ func (wsServer *WsServer) handleSubcribeBeaconPendingValidatorByPublickey(params
 		cResult <- RpcSubResult{Result: true, Error: nil}
 		return
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -313,8 +329,12 @@ func (wsServer *WsServer) handleSubcribeBeaconPendingValidatorByPublickey(params
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				_, ok := msg.Value.(*blockchain.BeaconBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -360,7 +380,7 @@ func (wsServer *WsServer) handleSubcribeBeaconCommitteeByPublickey(params interf
 		cResult <- RpcSubResult{Result: true, Error: nil}
 		return
 	}
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewBeaconBlockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewBeaconBlockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -373,8 +393,12 @@ func (wsServer *WsServer) handleSubcribeBeaconCommitteeByPublickey(params interf
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				_, ok := msg.Value.(*blockchain.BeaconBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.BeaconBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -41,7 +41,7 @@ func (wsServer *WsServer) handleSubscribePendingTransaction(params interface{},
 		}
 	}
 	// transaction not in database yet then subscribe new shard event block and watch
-	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriber(pubsub.NewShardblockTopic)
+	subId, subChan, err := wsServer.config.PubSubManager.RegisterNewSubscriberWithOptions(pubsub.NewShardblockTopic, wsServer.config.WsSubscribeOptions)
 	if err != nil {
 		err := rpcservice.NewRPCError(rpcservice.SubcribeError, err)
 		cResult <- RpcSubResult{Error: err}
@@ -54,8 +54,12 @@ func (wsServer *WsServer) handleSubscribePendingTransaction(params interface{},
 	}()
 	for {
 		select {
-		case msg := <-subChan:
+		case msg, subscribed := <-subChan:
 			{
+				if !subscribed {
+					cResult <- RpcSubResult{Error: rpcservice.NewRPCError(rpcservice.SubcribeError, errors.New("Subscriber too slow, unsubscribed"))}
+					return
+				}
 				shardBlock, ok := msg.Value.(*blockchain.ShardBlock)
 				if !ok {
 					Logger.log.Errorf("Wrong Message Type from Pubsub Manager, wanted *blockchain.ShardBlock, have %+v", reflect.TypeOf(msg.Value))
@@ -34,6 +34,7 @@ import (
 	"github.com/incognitochain/incognito-chain/connmanager"
 	consensus "github.com/incognitochain/incognito-chain/consensus_v2"
 	"github.com/incognitochain/incognito-chain/databasemp"
+	"github.com/incognitochain/incognito-chain/eventlog"
 	"github.com/incognitochain/incognito-chain/incdb"
 	"github.com/incognitochain/incognito-chain/incognitokey"
 	"github.com/incognitochain/incognito-chain/memcache"
@@ -75,10 +76,12 @@ type Server struct {
 	// userKeySet        *incognitokey.KeySet
 	miningKeys      string
 	privateKey      string
+	remoteSigner    string
 	wallet          *wallet.Wallet
 	consensusEngine *consensus.Engine
 	blockgen        *blockchain.BlockGenerator
 	pusubManager    *pubsub.PubSubManager
+	eventLog        *eventlog.EventLog
 	// The fee estimator keeps track of how long transactions are left in
 	// the mempool before they are mined into blocks.
 	feeEstimator map[byte]*mempool.FeeEstimator
@@ -222,6 +225,7 @@ func (serverObj *Server) NewServer(
 
 	serverObj.miningKeys = cfg.MiningKeys
 	serverObj.privateKey = cfg.PrivateKey
+	serverObj.remoteSigner = cfg.RemoteSigner
 	// if serverObj.miningKeys == "" && serverObj.privateKey == "" {
 	// 	if cfg.NodeMode == common.NodeModeAuto || cfg.NodeMode == common.NodeModeBeacon || cfg.NodeMode == common.NodeModeShard {
 	// 		panic("miningkeys can't be empty in this node mode")
@@ -262,6 +266,10 @@ func (serverObj *Server) NewServer(
 		randomClient = btc.NewBTCClient(cfg.BtcClientUsername, cfg.BtcClientPassword, cfg.BtcClientIP, cfg.BtcClientPort)
 	}
 	// Init block template generator
+	txSelectionPolicy, err := blockchain.NewTxSelectionPolicy(cfg.TxSelectionPolicy)
+	if err != nil {
+		return err
+	}
 	serverObj.blockgen, err = blockchain.NewBlockGenerator(serverObj.memPool, serverObj.blockChain, serverObj.syncker, cPendingTxs, cRemovedTxs)
 	if err != nil {
 		return err
@@ -331,6 +339,8 @@ func (serverObj *Server) NewServer(
 		ConsensusEngine: serverObj.consensusEngine,
 		Highway:         serverObj.highway,
 		GenesisParams:   blockchain.GenesisParam,
+		PruneState:      cfg.PruneState,
+		StateSync:       cfg.StateSync,
 	})
 	if err != nil {
 		return err
@@ -343,6 +353,13 @@ func (serverObj *Server) NewServer(
 	//set bc obj for monitor
 	monitor.SetBlockChainObj(serverObj.blockChain)
 
+	if cfg.EventLog {
+		serverObj.eventLog = eventlog.NewEventLog(eventlog.Config{
+			Chains:        eventlog.NewChains(serverObj.blockChain),
+			PubSubManager: serverObj.pusubManager,
+		})
+	}
+
 	// or if it cannot be loaded, create a new one.
 	if cfg.FastStartup {
 		Logger.log.Debug("Load chain dependencies from DB")
@@ -400,6 +417,12 @@ func (serverObj *Server) NewServer(
 		FeeEstimator:      serverObj.feeEstimator,
 		TxLifeTime:        cfg.TxPoolTTL,
 		MaxTx:             cfg.TxPoolMaxTx,
+		MaxShardTx:        cfg.TxPoolMaxShardTx,
+		MaxShardSize:      cfg.TxPoolMaxShardSize,
+		MaxSenderTx:       cfg.TxPoolMaxSenderTx,
+		TxVerifyWorkers:   cfg.TxVerifyWorkers,
+		TxVerifyBatchSize: cfg.TxVerifyBatchSize,
+		TxSelectionPolicy: txSelectionPolicy,
 		DataBaseMempool:   dbmp,
 		IsLoadFromMempool: cfg.LoadMempool,
 		PersistMempool:    cfg.PersistMempool,
@@ -505,12 +528,19 @@ func (serverObj *Server) NewServer(
 			return errors.New("RPCS: No valid listen address")
 		}
 
+		// checked by loadConfig
+		wsSlowPolicy, _ := pubsub.ParseDeliveryPolicy(cfg.RPCWSSlowPolicy)
 		rpcConfig := rpcserver.RpcServerConfig{
-			HttpListenters:              httpListeners,
-			WsListenters:                wsListeners,
-			RPCQuirks:                   cfg.RPCQuirks,
-			RPCMaxClients:               cfg.RPCMaxClients,
-			RPCMaxWSClients:             cfg.RPCMaxWSClients,
+			HttpListenters:  httpListeners,
+			WsListenters:    wsListeners,
+			RPCQuirks:       cfg.RPCQuirks,
+			RPCMaxClients:   cfg.RPCMaxClients,
+			RPCMaxWSClients: cfg.RPCMaxWSClients,
+			WsSubscribeOptions: pubsub.SubscribeOptions{
+				QueueSize:   cfg.RPCWSQueueSize,
+				Policy:      wsSlowPolicy,
+				SlowTimeout: pubsub.DefaultSlowTimeout,
+			},
 			RPCLimitRequestPerDay:       cfg.RPCLimitRequestPerDay,
 			RPCLimitRequestErrorPerHour: cfg.RPCLimitRequestErrorPerHour,
 			ChainParams:                 chainParams,
@@ -533,6 +563,7 @@ func (serverObj *Server) NewServer(
 			MiningKeys:      cfg.MiningKeys,
 			NetSync:         serverObj.netSync,
 			PubSubManager:   pubsubManager,
+			EventLog:        serverObj.eventLog,
 			ConsensusEngine: serverObj.consensusEngine,
 			MemCache:        serverObj.memCache,
 			Syncker:         serverObj.syncker,
@@ -630,6 +661,10 @@ func (serverObj *Server) Stop() error {
 	if err != nil {
 		Logger.log.Error(err)
 	}
+	if serverObj.eventLog != nil {
+		serverObj.eventLog.Stop()
+	}
+	serverObj.pusubManager.Stop()
 	// Signal the remaining goroutines to cQuit.
 	close(serverObj.cQuit)
 	return nil
@@ -721,7 +756,7 @@ func (serverObj Server) Start() {
 		serverObj.rpcServer.Start()
 	}
 
-	if cfg.MiningKeys != "" || cfg.PrivateKey != "" {
+	if cfg.MiningKeys != "" || cfg.PrivateKey != "" || cfg.RemoteSigner != "" {
 		serverObj.memPool.IsBlockGenStarted = true
 		serverObj.blockChain.SetIsBlockGenStarted(true)
 	}
@@ -738,8 +773,16 @@ func (serverObj Server) Start() {
 		go serverObj.TransactionPoolBroadcastLoop()
 		go serverObj.memPool.Start(serverObj.cQuit)
 		go serverObj.memPool.MonitorPool()
+		if cfg.TxVerifyWorkers >= 0 {
+			go serverObj.memPool.StartTxVerifier(serverObj.cQuit)
+		}
+	}
+	serverObj.pusubManager.Start()
+	if serverObj.eventLog != nil {
+		if err := serverObj.eventLog.Start(); err != nil {
+			Logger.log.Error(err)
+		}
 	}
-	go serverObj.pusubManager.Start()
 
 	err := serverObj.consensusEngine.Start()
 	if err != nil {
@@ -781,7 +824,8 @@ func (serverObj *Server) TransactionPoolBroadcastLoop() {
 						}
 						normalTx := tx.(*transaction.Tx)
 						txMsg.(*wire.MessageTx).Transaction = normalTx
-						err = serverObj.PushMessageToAll(txMsg)
+						txMsg.(*wire.MessageTx).IsPrivate = txDesc.IsPrivate
+						err = serverObj.pushTxMessage(txMsg, tx, txDesc.IsPrivate)
 						if err == nil {
 							serverObj.memPool.MarkForwardedTransaction(*tx.Hash())
 						}
@@ -794,7 +838,8 @@ func (serverObj *Server) TransactionPoolBroadcastLoop() {
 						}
 						customPrivacyTokenTx := tx.(*transaction.TxCustomTokenPrivacy)
 						txMsg.(*wire.MessageTxPrivacyToken).Transaction = customPrivacyTokenTx
-						err = serverObj.PushMessageToAll(txMsg)
+						txMsg.(*wire.MessageTxPrivacyToken).IsPrivate = txDesc.IsPrivate
+						err = serverObj.pushTxMessage(txMsg, tx, txDesc.IsPrivate)
 						if err == nil {
 							serverObj.memPool.MarkForwardedTransaction(*tx.Hash())
 						}
@@ -805,6 +850,15 @@ func (serverObj *Server) TransactionPoolBroadcastLoop() {
 	}
 }
 
+// pushTxMessage publishes the message of tx to the network, or pushes it to
+// the committee of the shard of tx only if it is private
+func (serverObj *Server) pushTxMessage(txMsg wire.Message, tx metadata.Transaction, isPrivate bool) error {
+	if isPrivate {
+		return serverObj.PushMessageToShardCommittee(txMsg, common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()))
+	}
+	return serverObj.PushMessageToAll(txMsg)
+}
+
 // CheckForceUpdateSourceCode - loop to check current version with update version is equal
 // Force source code to be updated and remove data
 func (serverObject Server) CheckForceUpdateSourceCode() {
@@ -1271,7 +1325,7 @@ func (serverObj *Server) GetPeerIDsFromPublicKey(pubKey string) []libp2p.ID {
 }
 
 func (serverObj *Server) GetNodeRole() string {
-	if serverObj.miningKeys == "" && serverObj.privateKey == "" {
+	if serverObj.miningKeys == "" && serverObj.privateKey == "" && serverObj.remoteSigner == "" {
 		return "RELAY"
 	}
 	role, shardID := serverObj.GetUserMiningState()
@@ -1350,6 +1404,50 @@ func (serverObj *Server) PushMessageToShard(msg wire.Message, shard byte, exclus
 	return nil
 }
 
+/*
+PushMessageToShardCommittee push msg to the connected nodes of the current
+committee of shard, and publishes it on highway to the tx topic of shard only,
+which the nodes of the other shards and the public tx topics do not get. It
+fails if no node of the committee is reached either way, even if this node is
+in the committee: a private tx stays unforwarded in mempool and is pushed again
+by the broadcast loop.
+*/
+func (serverObj *Server) PushMessageToShardCommittee(msg wire.Message, shard byte) error {
+	Logger.log.Debugf("Push msg to committee of shard %d", shard)
+	committee := make(map[string]bool)
+	for _, key := range serverObj.blockChain.GetBestStateShard(shard).GetShardCommittee() {
+		committee[string(key.MiningPubKey[common.BlsConsensus])] = true
+	}
+	pushed := 0
+	for _, peerConn := range serverObj.connManager.GetPeerConnOfAll() {
+		pbk, _ := peerConn.GetRemotePeer().GetPublicKey()
+		if pbk == common.EmptyString {
+			continue
+		}
+		// the public key of a peer is the json of its mining keys by scheme
+		miningKeys := make(map[string][]byte)
+		if err := json.Unmarshal([]byte(pbk), &miningKeys); err != nil {
+			continue
+		}
+		blsKey := miningKeys[common.BlsConsensus]
+		if len(blsKey) == 0 || !committee[string(blsKey)] {
+			continue
+		}
+		msg.SetSenderID(peerConn.GetListenerPeer().GetPeerID())
+		peerConn.QueueMessageWithEncoding(msg, nil, peer.MessageToPeer, nil)
+		pushed++
+	}
+	errHighway := serverObj.highway.PublishTxToShard(msg, shard)
+	if errHighway != nil {
+		Logger.log.Debugf("Publish msg to highway topic of shard %d error %v", shard, errHighway)
+	}
+	if pushed == 0 && errHighway != nil {
+		return fmt.Errorf("no node of the committee of shard %d is reached, highway: %v", shard, errHighway)
+	}
+	Logger.log.Debugf("Pushed msg to %d nodes of the committee of shard %d, published to highway %v", pushed, shard, errHighway == nil)
+	return nil
+}
+
 func (serverObj *Server) PushRawBytesToShard(p *peer.PeerConn, msgBytes *[]byte, shard byte) error {
 	Logger.log.Debugf("Push raw bytes to shard %d", shard)
 	peerConns := serverObj.connManager.GetPeerConnOfShard(shard)
@@ -1673,7 +1771,7 @@ func (serverObj *Server) GetChainMiningStatus(chain int) string {
 	if chain >= common.MaxShardNumber || chain < -1 {
 		return notmining
 	}
-	if cfg.MiningKeys != "" || cfg.PrivateKey != "" {
+	if cfg.MiningKeys != "" || cfg.PrivateKey != "" || cfg.RemoteSigner != "" {
 		//Beacon: chain = -1
 		role, chainID := serverObj.GetUserMiningState()
 		layer := ""
@@ -1737,6 +1835,10 @@ func (serverObj *Server) GetPrivateKey() string {
 	return serverObj.privateKey
 }
 
+func (serverObj *Server) GetRemoteSigner() string {
+	return serverObj.remoteSigner
+}
+
 func (serverObj *Server) PushMessageToChain(msg wire.Message, chain common.ChainInterface) error {
 	chainID := chain.GetShardID()
 	if chainID == -1 {
@@ -34,6 +34,7 @@ type BeaconSyncProcess struct {
 	beaconPool          *BlkPool
 	actionCh            chan func()
 	lastCrossShardState map[byte]map[byte]uint64
+	stateSyncAttempt    int
 }
 
 func NewBeaconSyncProcess(network Network, bc *blockchain.BlockChain, chain BeaconChainInterface) *BeaconSyncProcess {
@@ -145,6 +146,14 @@ func (s *BeaconSyncProcess) updateConfirmCrossShard() {
 		beaconBlock, err := s.blockchain.FetchConfirmBeaconBlockByHeight(lastBeaconHeightConfirmCrossX)
 		if err != nil || beaconBlock == nil {
 			//fmt.Println("DEBUG: cannot find beacon block", lastBeaconHeightConfirmCrossX)
+			//the beacon state may have been synced from a peer, skipping this block
+			state := rawdbv2.GetLastBeaconStateConfirmCrossShard(s.chain.GetDatabase())
+			lastBeaconStateConfirmCrossX := new(LastCrossShardBeaconProcess)
+			if json.Unmarshal(state, &lastBeaconStateConfirmCrossX) == nil && lastBeaconStateConfirmCrossX.BeaconHeight > lastBeaconHeightConfirmCrossX {
+				s.lastCrossShardState = lastBeaconStateConfirmCrossX.LastCrossShardState
+				lastBeaconHeightConfirmCrossX = lastBeaconStateConfirmCrossX.BeaconHeight
+				continue
+			}
 			time.Sleep(time.Second * 5)
 			continue
 		}
@@ -261,7 +270,9 @@ func (s *BeaconSyncProcess) syncBeacon() {
 			continue
 		}
 
-		for peerID, pState := range s.getBeaconPeerStates() {
+		beaconPeerStates := s.getBeaconPeerStates()
+		s.syncStateFromPeers(beaconPeerStates)
+		for peerID, pState := range beaconPeerStates {
 			requestCnt += s.streamFromPeer(peerID, pState)
 		}
 
@@ -277,6 +288,30 @@ func (s *BeaconSyncProcess) syncBeacon() {
 	}
 }
 
+// syncStateFromPeers syncs the beacon state of the highest peer when the node
+// runs in state sync mode and its beacon chain is still at genesis
+func (s *BeaconSyncProcess) syncStateFromPeers(beaconPeerStates map[string]BeaconPeerState) {
+	peerID, peerHeight := "", uint64(0)
+	for id, pState := range beaconPeerStates {
+		if pState.BestViewHeight > peerHeight {
+			peerID, peerHeight = id, pState.BestViewHeight
+		}
+	}
+	if !shouldSyncState(s.blockchain, s.chain, s.stateSyncAttempt, peerHeight) {
+		return
+	}
+	s.stateSyncAttempt++
+	target, err := syncState(s.network, s.blockchain, peerID, common.BeaconChainDataBaseID, s.chain.GetDatabase())
+	if err != nil {
+		Logger.Errorf("[statesync] Sync beacon state from peer %v failed, attempt %v: %v", peerID, s.stateSyncAttempt, err)
+		return
+	}
+	// the beacon blocks before the synced view will never be stored, confirm
+	// the cross shard blocks from the next one
+	view := s.blockchain.BeaconChain.GetFinalView().(*blockchain.BeaconBestState)
+	rawdbv2.StoreLastBeaconStateConfirmCrossShard(s.chain.GetDatabase(), LastCrossShardBeaconProcess{target.Height + 1, view.LastCrossShardState})
+}
+
 func (s *BeaconSyncProcess) streamFromPeer(peerID string, pState BeaconPeerState) (requestCnt int) {
 	if pState.processed {
 		return
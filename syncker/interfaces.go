@@ -5,8 +5,10 @@ import (
 
 	"github.com/incognitochain/incognito-chain/incdb"
 
+	"github.com/incognitochain/incognito-chain/blockchain"
 	"github.com/incognitochain/incognito-chain/common"
 	"github.com/incognitochain/incognito-chain/incognitokey"
+	"github.com/incognitochain/incognito-chain/trie"
 )
 
 type Network interface {
@@ -17,6 +19,8 @@ type Network interface {
 	RequestCrossShardBlocksByHashViaStream(ctx context.Context, peerID string, fromSID int, toSID int, hashes [][]byte) (blockCh chan common.BlockInterface, err error)
 	RequestBeaconBlocksByHashViaStream(ctx context.Context, peerID string, hashes [][]byte) (blockCh chan common.BlockInterface, err error)
 	RequestShardBlocksByHashViaStream(ctx context.Context, peerID string, fromSID int, hashes [][]byte) (blockCh chan common.BlockInterface, err error)
+	RequestStateSyncView(ctx context.Context, peerID string, chainID int, fromBeaconHeight uint64) (*blockchain.StateSyncView, error)
+	RequestTrieNodesViaStream(ctx context.Context, peerID string, chainID int, hashes [][]byte) (nodeCh chan trie.SyncResult, err error)
 }
 
 type BeaconChainInterface interface {
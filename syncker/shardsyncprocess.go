@@ -36,6 +36,7 @@ type ShardSyncProcess struct {
 	shardPool             *BlkPool
 	actionCh              chan func()
 	lock                  *sync.RWMutex
+	stateSyncAttempt      int
 }
 
 func NewShardSyncProcess(shardID int, network Network, bc *blockchain.BlockChain, beaconChain BeaconChainInterface, chain ShardChainInterface) *ShardSyncProcess {
@@ -181,7 +182,9 @@ func (s *ShardSyncProcess) syncShardProcess() {
 			continue
 		}
 
-		for peerID, pState := range s.getShardPeerStates() {
+		shardPeerStates := s.getShardPeerStates()
+		s.syncStateFromPeers(shardPeerStates)
+		for peerID, pState := range shardPeerStates {
 			requestCnt += s.streamFromPeer(peerID, pState)
 		}
 
@@ -198,6 +201,28 @@ func (s *ShardSyncProcess) syncShardProcess() {
 
 }
 
+// syncStateFromPeers syncs the shard state of the highest peer when the node
+// runs in state sync mode and the shard chain is still at genesis. The beacon
+// chain must be synced first, a failure because the beacon is behind the shard
+// view of the peer is not counted as an attempt.
+func (s *ShardSyncProcess) syncStateFromPeers(shardPeerStates map[string]ShardPeerState) {
+	peerID, peerHeight := "", uint64(0)
+	for id, pState := range shardPeerStates {
+		if pState.BestViewHeight > peerHeight {
+			peerID, peerHeight = id, pState.BestViewHeight
+		}
+	}
+	if !shouldSyncState(s.blockchain, s.Chain, s.stateSyncAttempt, peerHeight) || s.beaconChain.GetBestViewHeight() == 1 {
+		return
+	}
+	if _, err := syncState(s.Network, s.blockchain, peerID, s.shardID, s.Chain.GetDatabase()); err != nil {
+		if err != errStateSyncBeaconBehind {
+			s.stateSyncAttempt++
+		}
+		Logger.Errorf("[statesync] Sync shard %v state from peer %v failed, attempt %v: %v", s.shardID, peerID, s.stateSyncAttempt, err)
+	}
+}
+
 func (s *ShardSyncProcess) streamFromPeer(peerID string, pState ShardPeerState) (requestCnt int) {
 	if pState.processed {
 		return
@@ -0,0 +1,166 @@
+package syncker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/incognitochain/incognito-chain/blockchain"
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incdb"
+	"github.com/incognitochain/incognito-chain/trie"
+)
+
+const (
+	// MAX_STATE_SYNC_ATTEMPT is the number of failed state syncs of a chain
+	// before falling back to processing every block
+	MAX_STATE_SYNC_ATTEMPT = 3
+	// MAX_TRIE_NODE_PER_REQUEST is the number of trie nodes requested at once
+	MAX_TRIE_NODE_PER_REQUEST = 1024
+	// STATE_SYNC_BLOOM_SIZE is the memory in megabytes of the bloom filter of
+	// the trie nodes already stored
+	STATE_SYNC_BLOOM_SIZE = 64
+	// STATE_SYNC_TIMEOUT bounds a whole state sync of a chain
+	STATE_SYNC_TIMEOUT = time.Hour
+)
+
+// errStateSyncBeaconBehind is returned when the shard view of the peer is
+// processed against a beacon height not finalized yet, it is retried later
+var errStateSyncBeaconBehind = errors.New("beacon chain behind the shard view of the peer")
+
+// shouldSyncState tells whether a chain still at genesis should sync the state
+// of a peer instead of processing every block up to its best height
+func shouldSyncState(bc *blockchain.BlockChain, chain Chain, attempt int, peerHeight uint64) bool {
+	if !bc.GetConfig().StateSync || attempt >= MAX_STATE_SYNC_ATTEMPT {
+		return false
+	}
+	return chain.GetBestViewHeight() == 1 && peerHeight > chain.GetBestViewHeight()+bc.GetConfig().ChainParams.Epoch
+}
+
+// syncState downloads the state of the final view of a peer into db then
+// replaces the views of the chain by this view. Every trie node is checked
+// against its hash, the view is checked against its block by the blockchain.
+// Nodes already stored are not downloaded again, an interrupted state sync
+// resumes where it stopped.
+func syncState(network Network, bc *blockchain.BlockChain, peerID string, chainID int, db incdb.Database) (*blockchain.StateSyncTarget, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), STATE_SYNC_TIMEOUT)
+	defer cancel()
+	syncView, anchor, err := requestStateSyncView(ctx, network, bc, peerID, chainID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := bc.NewStateSyncTarget(chainID, syncView)
+	if err != nil {
+		return nil, err
+	}
+	if chainID != common.BeaconChainDataBaseID && target.BeaconHeight > bc.BeaconChain.GetFinalView().GetHeight() {
+		return nil, errStateSyncBeaconBehind
+	}
+	Logger.Infof("[statesync] Sync state of chain %v at height %v, block %v from peer %v", chainID, target.Height, target.BlockHash.String(), peerID)
+
+	bloom := trie.NewSyncBloom(STATE_SYNC_BLOOM_SIZE, db)
+	defer bloom.Close()
+	for _, root := range target.Roots {
+		if root == (common.Hash{}) {
+			continue
+		}
+		if err := syncTrie(ctx, network, peerID, chainID, root, db, bloom); err != nil {
+			return nil, fmt.Errorf("sync state root %v: %v", root.String(), err)
+		}
+	}
+	if err := bc.InstallStateSyncTarget(target, anchor, db); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// requestStateSyncView requests the final view of a chain of a peer. For the
+// beacon chain the committee swaps returned with it are verified from the
+// final beacon view of this node, page after page, into the anchor of the
+// committee signing the block after the view.
+func requestStateSyncView(ctx context.Context, network Network, bc *blockchain.BlockChain, peerID string, chainID int) (*blockchain.StateSyncView, *blockchain.StateSyncAnchor, error) {
+	if chainID != common.BeaconChainDataBaseID {
+		syncView, err := network.RequestStateSyncView(ctx, peerID, chainID, 0)
+		return syncView, nil, err
+	}
+	anchor := bc.NewBeaconStateSyncAnchor()
+	for {
+		syncView, err := network.RequestStateSyncView(ctx, peerID, chainID, anchor.Height)
+		if err != nil {
+			return nil, nil, err
+		}
+		if anchor, err = bc.VerifyBeaconCommitteeSwaps(anchor, syncView.CommitteeSwaps); err != nil {
+			return nil, nil, err
+		}
+		if len(syncView.View) != 0 {
+			return syncView, anchor, nil
+		}
+		if len(syncView.CommitteeSwaps) == 0 {
+			return nil, nil, fmt.Errorf("peer %v returned neither view nor committee swap", peerID)
+		}
+		Logger.Infof("[statesync] Verified beacon committee swaps up to height %v from peer %v", anchor.Height, peerID)
+	}
+}
+
+func syncTrie(ctx context.Context, network Network, peerID string, chainID int, root common.Hash, db incdb.Database, bloom *trie.SyncBloom) error {
+	sched := trie.NewSync(root, db, nil, bloom)
+	// nodes handed out by the scheduler but not received yet, they are not
+	// returned by Missing anymore
+	retry := []common.Hash{}
+	nodes := 0
+	for sched.Pending() > 0 {
+		missing := retry
+		if len(missing) < MAX_TRIE_NODE_PER_REQUEST {
+			missing = append(missing, sched.Missing(MAX_TRIE_NODE_PER_REQUEST-len(missing))...)
+		}
+		if len(missing) == 0 {
+			return errors.New("no trie node to request but trie incomplete")
+		}
+		requested := make(map[common.Hash]bool)
+		hashes := [][]byte{}
+		for _, hash := range missing {
+			requested[hash] = true
+			hashes = append(hashes, hash.Bytes())
+		}
+		nodeCh, err := network.RequestTrieNodesViaStream(ctx, peerID, chainID, hashes)
+		if err != nil {
+			return err
+		}
+		results := []trie.SyncResult{}
+		for node := range nodeCh {
+			if !requested[node.Hash] || common.Keccak256Hash(node.Data) != node.Hash {
+				continue
+			}
+			delete(requested, node.Hash)
+			results = append(results, node)
+		}
+		if len(results) == 0 {
+			return fmt.Errorf("peer %v returned none of %v trie nodes", peerID, len(missing))
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			return fmt.Errorf("process trie node %v: %v", results[index].Hash.String(), err)
+		}
+		batch := db.NewBatch()
+		if err := sched.Commit(batch); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		retry = []common.Hash{}
+		for _, hash := range missing {
+			if requested[hash] {
+				retry = append(retry, hash)
+			}
+		}
+		nodes += len(results)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+	Logger.Infof("[statesync] Synced trie %v of chain %v, downloaded %v nodes", root.String(), chainID, nodes)
+	return nil
+}
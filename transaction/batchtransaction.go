@@ -44,11 +44,11 @@ func (b *batchTransaction) validateBatchTxsByItself(txList []metadata.Transactio
 		}
 		if tx.GetMetadata() != nil {
 			if hasPrivacy {
-				return false, errors.New("Metadata can not exist in privacy tx"), i
+				return false, NewTransactionErr(RejectTxMetadataError, errors.New("Metadata can not exist in privacy tx")), i
 			}
 			validateMetadata := tx.GetMetadata().ValidateMetadataByItself()
 			if !validateMetadata {
-				return validateMetadata, NewTransactionErr(UnexpectedError, errors.New("Metadata is invalid")), i
+				return validateMetadata, NewTransactionErr(RejectTxMetadataError, errors.New("Metadata is invalid")), i
 			}
 		}
 
@@ -65,6 +65,8 @@ const (
 	RejectTxType
 	RejectTxInfoSize
 	RejectTxMedataWithBlockChain
+	RejectTxMetadataError
+	WrongOutputCoinCommitmentError
 )
 
 var ErrCodeMessage = map[int]struct {
@@ -114,6 +116,8 @@ var ErrCodeMessage = map[int]struct {
 	RejectTxMedataWithBlockChain:                  {-1039, "Reject invalid metadata with blockchain"},
 	BatchTxProofVerifyFailError:                   {-1040, "Can not verify proof of batch txs %s"},
 	VerifyOneOutOfManyProofFailedErr:              {-1041, "Verify one out of many proof failed"},
+	RejectTxMetadataError:                         {-1042, "Reject invalid metadata of tx"},
+	WrongOutputCoinCommitmentError:                {-1043, "Wrong commitment of output coin"},
 
 	// for PRV
 	InvalidSanityDataPRVError:  {-2000, "Invalid sanity data for PRV"},
@@ -143,6 +147,10 @@ func (e TransactionError) Error() string {
 	return fmt.Sprintf("%+v: %+v %+v", e.Code, e.Message, e.err)
 }
 
+func (e TransactionError) GetErr() error {
+	return e.err
+}
+
 func NewTransactionErr(key int, err error, params ...interface{}) *TransactionError {
 	e := &TransactionError{
 		err:  errors.Wrap(err, common.EmptyString),
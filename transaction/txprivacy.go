@@ -499,6 +499,12 @@ func (tx *Tx) ValidateTransaction(boolParams map[string]bool, transactionStateDB
 	if !ok {
 		isNewTransaction = false
 	}
+	// the input coins of a tx held in mempool are outputs of txs which are not
+	// in a block yet, their commitments are checked once they are
+	hasUnconfirmedInputs, ok := boolParams["hasUnconfirmedInputs"]
+	if !ok {
+		hasUnconfirmedInputs = false
+	}
 
 	if tx.Proof != nil {
 		if tokenID == nil {
@@ -533,7 +539,7 @@ func (tx *Tx) ValidateTransaction(boolParams map[string]bool, transactionStateDB
 			}
 		}
 
-		if !hasPrivacy {
+		if !hasPrivacy && !hasUnconfirmedInputs {
 			// Check input coins' commitment is exists in cm list (Database)
 			for i := 0; i < len(tx.Proof.GetInputCoins()); i++ {
 				ok, err := tx.CheckCMExistence(tx.Proof.GetInputCoins()[i].CoinDetails.GetCoinCommitment().ToBytesS(), transactionStateDB, shardID, tokenID)
@@ -1227,13 +1233,13 @@ func (tx Tx) ValidateTxByItself(boolParams map[string]bool, transactionStateDB *
 	}
 	if tx.Metadata != nil {
 		if hasPrivacy {
-			return false, errors.New("metadata can not exist in  privacy tx")
+			return false, NewTransactionErr(RejectTxMetadataError, errors.New("metadata can not exist in  privacy tx"))
 		}
 		validateMetadata := tx.Metadata.ValidateMetadataByItself()
 		if validateMetadata {
 			return validateMetadata, nil
 		} else {
-			return validateMetadata, NewTransactionErr(UnexpectedError, errors.New("Metadata is invalid"))
+			return validateMetadata, NewTransactionErr(RejectTxMetadataError, errors.New("Metadata is invalid"))
 		}
 	}
 	return true, nil
@@ -1460,7 +1466,7 @@ func (tx Tx) ValidateTxSalary(stateDB *statedb.StateDB) (bool, error) {
 
 	ok := privacy.IsPointEqual(cmTmp2, tx.Proof.GetOutputCoins()[0].CoinDetails.GetCoinCommitment())
 	if !ok {
-		return ok, NewTransactionErr(UnexpectedError, errors.New("check output coin's coin commitment isn't calculated correctly"))
+		return ok, NewTransactionErr(WrongOutputCoinCommitmentError, errors.New("check output coin's coin commitment isn't calculated correctly"))
 	}
 	return ok, nil
 }
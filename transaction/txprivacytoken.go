@@ -537,7 +537,7 @@ func (txCustomTokenPrivacy TxCustomTokenPrivacy) ValidateTxByItself(boolParams m
 	if txCustomTokenPrivacy.Metadata != nil {
 		validateMetadata := txCustomTokenPrivacy.Metadata.ValidateMetadataByItself()
 		if !validateMetadata {
-			return validateMetadata, NewTransactionErr(UnexpectedError, errors.New("Metadata is invalid"))
+			return validateMetadata, NewTransactionErr(RejectTxMetadataError, errors.New("Metadata is invalid"))
 		}
 		return validateMetadata, nil
 	}
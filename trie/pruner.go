@@ -0,0 +1,160 @@
+package trie
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incdb"
+)
+
+// Pruner is a mark-and-sweep garbage collector of the trie nodes persisted by
+// IntermediateWriter. Every root that must stay readable is marked first, then
+// Sweep deletes every node of the disk database that is not reachable from any
+// marked root.
+//
+// Trie nodes share the database with other chain data, a key is only considered
+// a trie node if it is a 32 byte hash and the keccak256 of its value equals the
+// key, so data stored under a hash of something else is never touched.
+type Pruner struct {
+	diskdb incdb.Database
+	marked map[common.Hash]struct{}
+}
+
+// NewPruner creates a pruner of the trie nodes stored in diskdb.
+func NewPruner(diskdb incdb.Database) *Pruner {
+	return &Pruner{
+		diskdb: diskdb,
+		marked: make(map[common.Hash]struct{}),
+	}
+}
+
+// Marked returns the number of trie nodes marked as reachable.
+func (p *Pruner) Marked() int {
+	return len(p.marked)
+}
+
+// Mark walks the trie rooted at root and marks every node as reachable.
+// Subtries already marked by a previous call are not walked again, marking many
+// roots that share most of their nodes is therefore cheap.
+func (p *Pruner) Mark(root common.Hash) error {
+	if root == (common.Hash{}) || root == emptyRoot {
+		return nil
+	}
+	stack := []common.Hash{root}
+	for len(stack) > 0 {
+		hash := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := p.marked[hash]; ok {
+			continue
+		}
+		blob, err := p.diskdb.Get(hash[:])
+		if err != nil || len(blob) == 0 {
+			return &MissingNodeError{NodeHash: hash}
+		}
+		n, err := decodeNode(hash[:], blob)
+		if err != nil {
+			return fmt.Errorf("decode node %x: %v", hash, err)
+		}
+		p.marked[hash] = struct{}{}
+		stack = appendHashChildren(stack, n)
+	}
+	return nil
+}
+
+// appendHashChildren appends the hashes of the children of n stored as separate
+// database entries, children embedded in their parent are walked in place.
+func appendHashChildren(stack []common.Hash, n node) []common.Hash {
+	switch n := n.(type) {
+	case *shortNode:
+		return appendHashChildren(stack, n.Val)
+	case *fullNode:
+		for _, child := range &n.Children {
+			if child != nil {
+				stack = appendHashChildren(stack, child)
+			}
+		}
+	case hashNode:
+		stack = append(stack, common.BytesToHash(n))
+	}
+	return stack
+}
+
+// Sweep deletes every trie node of the disk database which has not been marked.
+// Nodes are deleted in batches, beforeWrite is called before a batch is written
+// and afterWrite once it is done. The caller is expected to block new trie
+// commits in beforeWrite and mark the roots created since the mark phase, nodes
+// marked by then are kept even if they were collected as garbage. An error
+// returned by beforeWrite aborts the sweep, afterWrite is not called then.
+//
+// Sweep returns the number of deleted nodes and the reclaimed storage size.
+func (p *Pruner) Sweep(beforeWrite func() error, afterWrite func()) (uint64, common.StorageSize, error) {
+	type garbage struct {
+		hash common.Hash
+		size int
+	}
+	var (
+		nodes       uint64
+		size        common.StorageSize
+		pending     []garbage
+		pendingSize int
+		batch       = p.diskdb.NewBatch()
+	)
+	flush := func() error {
+		if err := beforeWrite(); err != nil {
+			return err
+		}
+		defer afterWrite()
+		deletedNodes, deletedSize := uint64(0), 0
+		for _, g := range pending {
+			if _, ok := p.marked[g.hash]; ok {
+				continue
+			}
+			if err := batch.Delete(g.hash[:]); err != nil {
+				return err
+			}
+			deletedNodes++
+			deletedSize += g.size
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		nodes += deletedNodes
+		size += common.StorageSize(deletedSize)
+		pending, pendingSize = pending[:0], 0
+		return nil
+	}
+
+	iter := p.diskdb.NewIterator()
+	defer iter.Release()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != common.HashSize {
+			continue
+		}
+		hash := common.BytesToHash(key)
+		if _, ok := p.marked[hash]; ok {
+			continue
+		}
+		value := iter.Value()
+		if common.Keccak256Hash(value) != hash {
+			continue
+		}
+		pending = append(pending, garbage{hash: hash, size: len(key) + len(value)})
+		pendingSize += len(key) + len(value)
+		if pendingSize >= incdb.IdealBatchSize {
+			if err := flush(); err != nil {
+				return nodes, size, err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nodes, size, err
+	}
+	if len(pending) > 0 {
+		if err := flush(); err != nil {
+			return nodes, size, err
+		}
+	}
+	return nodes, size, nil
+}
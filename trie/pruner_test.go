@@ -0,0 +1,94 @@
+package trie
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/incognitochain/incognito-chain/common"
+	"github.com/incognitochain/incognito-chain/incdb"
+	_ "github.com/incognitochain/incognito-chain/incdb/lvdb"
+)
+
+func commitTestTrie(t *testing.T, writer *IntermediateWriter, root common.Hash, from, to int) common.Hash {
+	tr, err := New(root, writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := from; i < to; i++ {
+		tr.Update([]byte(fmt.Sprintf("key-%04d", i)), []byte(fmt.Sprintf("value-%04d-padded-beyond-embedding", i)))
+	}
+	newRoot, err := tr.Commit(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Commit(newRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	return newRoot
+}
+
+func TestPrunerMarkAndSweep(t *testing.T) {
+	dbPath, err := ioutil.TempDir(os.TempDir(), "pruner_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dbPath)
+	diskdb, err := incdb.Open("leveldb", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer diskdb.Close()
+	// other chain data stored under a 32 byte key must never be swept
+	otherKey := common.HashH([]byte("other")).Bytes()
+	if err := diskdb.Put(otherKey, []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+
+	writer := NewIntermediateWriter(diskdb)
+	oldRoot := commitTestTrie(t, writer, common.Hash{}, 0, 200)
+	midRoot := commitTestTrie(t, writer, oldRoot, 100, 300)
+	newRoot := commitTestTrie(t, writer, midRoot, 250, 400)
+
+	pruner := NewPruner(diskdb)
+	if err := pruner.Mark(newRoot); err != nil {
+		t.Fatal(err)
+	}
+	batches := 0
+	nodes, size, err := pruner.Sweep(func() error {
+		batches++
+		// a root committed while sweeping is marked before the write
+		return pruner.Mark(midRoot)
+	}, func() {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batches == 0 || nodes == 0 || size == 0 {
+		t.Fatalf("nothing swept, batches %v nodes %v size %v", batches, nodes, size)
+	}
+
+	for root, keys := range map[common.Hash][2]int{newRoot: {0, 400}, midRoot: {0, 300}} {
+		tr, err := New(root, NewIntermediateWriter(diskdb))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := keys[0]; i < keys[1]; i++ {
+			if _, err := tr.TryGet([]byte(fmt.Sprintf("key-%04d", i))); err != nil {
+				t.Fatalf("root %x key %v: %v", root, i, err)
+			}
+		}
+	}
+	if _, err := New(oldRoot, NewIntermediateWriter(diskdb)); err == nil {
+		t.Fatalf("root %x should have been pruned", oldRoot)
+	}
+	if value, err := diskdb.Get(otherKey); err != nil || string(value) != "other" {
+		t.Fatalf("non trie data removed, %v", err)
+	}
+
+	// a second sweep finds nothing left to delete
+	nodes, _, err = pruner.Sweep(func() error { return nil }, func() {})
+	if err != nil || nodes != 0 {
+		t.Fatalf("second sweep deleted %v nodes, %v", nodes, err)
+	}
+}
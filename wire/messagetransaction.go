@@ -16,6 +16,9 @@ const (
 
 type MessageTx struct {
 	Transaction metadata.Transaction
+	// IsPrivate is set when the tx is relayed to the nodes of the committee of
+	// its shard only, they must not publish it to the network
+	IsPrivate bool `json:",omitempty"`
 }
 
 func (msg *MessageTx) Hash() string {